@@ -0,0 +1,252 @@
+/*
+Package auth implements role based authorization of the RPCs of a RegServer.
+
+Each caller is identified by the common name of its verified TLS client certificate, or by a bearer token
+sent in the "authorization" metadata. A Policy maps identities to roles, and grpc interceptors reject
+calls to RPCs, that need a higher role than the caller has.
+
+Data clients may read and write registers, learn configurations from the replies, and pass them on.
+Operators may additionally propose and install configurations, and query the status of servers.
+*/
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+	"github.com/relab/smartmerge/regserver"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Role is the set of RPCs a caller may use. Each role includes the RPCs of the smaller roles.
+type Role int
+
+const (
+	None     Role = iota // May not call any RPC.
+	Data                 // May read and write registers.
+	Operator             // May also change the configuration, and query the status.
+)
+
+func (r Role) String() string {
+	switch r {
+	case None:
+		return "none"
+	case Data:
+		return "data"
+	case Operator:
+		return "operator"
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+func parseRole(s string) (Role, error) {
+	switch s {
+	case "data":
+		return Data, nil
+	case "operator":
+		return Operator, nil
+	case "none":
+		return None, nil
+	}
+	return None, fmt.Errorf("unknown role %q", s)
+}
+
+const service = "/proto.SMandConsRegister/"
+
+// dataMethods are the RPCs allowed to data clients. All other RPCs need the Operator role.
+//
+// DynaStore and SpSnStore clients call DWriteNext and DSetState in every read and write, to traverse
+// the views and pass on the successors they learned. For data clients, the server only accepts successors
+// it already recorded, see regserver.Restrict. Thus, only operators can propose a successor in DynaStore.
+// A data client, that passes on a successor not yet recorded at a quorum, fails, until the proposal completed.
+// Proposals of SpSnStore go through SpSnOneShot, and are restricted to operators.
+//
+// SmartMerge clients call SetCur, when they learned a new current configuration. For data clients,
+// the server ignores configurations, that were not proposed or decided at the server. Thus, data clients
+// can pass on, but not install a configuration.
+var dataMethods = map[string]bool{
+	service + "Read":        true,
+	service + "Write":       true,
+	service + "LAPropValue": true, // Lattice agreement on values, used by the replicated state machines.
+	service + "DWriteNext":  true,
+	service + "DSetState":   true,
+	service + "Watch":       true,
+	service + "SetCur":      true,
+}
+
+// Required returns the role needed to call the RPC method, given by its full name, e.g. "/proto.SMandConsRegister/Read".
+func Required(method string) Role {
+	if dataMethods[method] {
+		return Data
+	}
+	return Operator
+}
+
+// Policy maps identities to roles.
+type Policy struct {
+	certs     map[string]Role // Indexed by the common name of the client certificate.
+	tokens    map[string]Role
+	anonymous Role // The role of callers without a known identity.
+}
+
+// LoadPolicy reads a policy from file, see ParsePolicy.
+func LoadPolicy(file string) (*Policy, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParsePolicy(f)
+}
+
+// ParsePolicy reads a policy with one rule per line. Empty lines and lines starting with # are ignored.
+//
+//	<role> cert <common name>
+//	<role> token <token>
+//	<role> anonymous
+//
+// role is data or operator. The common name may contain spaces.
+// A caller with several identities gets the largest of their roles.
+// Without an anonymous rule, callers without a known identity are rejected.
+func ParsePolicy(r io.Reader) (*Policy, error) {
+	p := &Policy{certs: make(map[string]Role), tokens: make(map[string]Role)}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fs := strings.SplitN(line, " ", 3)
+		role, err := parseRole(fs[0])
+		if err != nil {
+			return nil, fmt.Errorf("auth: line %d: %v", n, err)
+		}
+		switch {
+		case len(fs) == 2 && fs[1] == "anonymous":
+			p.anonymous = role
+		case len(fs) == 3 && fs[1] == "cert":
+			p.certs[strings.TrimSpace(fs[2])] = role
+		case len(fs) == 3 && fs[1] == "token":
+			p.tokens[strings.TrimSpace(fs[2])] = role
+		default:
+			return nil, fmt.Errorf("auth: line %d: expected <role> cert <name>, <role> token <token> or <role> anonymous", n)
+		}
+	}
+	return p, sc.Err()
+}
+
+// RoleOf returns the role of the caller of the RPC with context ctx, and a description of its identity.
+func (p *Policy) RoleOf(ctx context.Context) (role Role, id string) {
+	role, id = p.anonymous, "anonymous"
+	if pr, ok := peer.FromContext(ctx); ok {
+		if ti, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			for _, chain := range ti.State.VerifiedChains {
+				if len(chain) == 0 {
+					continue
+				}
+				cn := chain[0].Subject.CommonName
+				if r, ok := p.certs[cn]; ok && r > role {
+					role, id = r, "cert "+cn
+				}
+			}
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, a := range md["authorization"] {
+			if tok := strings.TrimPrefix(a, "Bearer "); tok != a {
+				if r, ok := p.tokens[tok]; ok && r > role {
+					role, id = r, "token"
+				}
+			}
+		}
+	}
+	return role, id
+}
+
+// authorize returns the role of the caller, and an error, if the caller may not call method.
+func (p *Policy) authorize(ctx context.Context, method string) (Role, error) {
+	role, id := p.RoleOf(ctx)
+	need := Required(method)
+	if role >= need {
+		return role, nil
+	}
+	glog.V(2).Infof("Rejecting call to %s by %s with role %v.\n", method, id, role)
+	if role == None {
+		return role, status.Errorf(codes.Unauthenticated, "%s needs role %v", method, need)
+	}
+	return role, status.Errorf(codes.PermissionDenied, "%s needs role %v, %s has role %v", method, need, id, role)
+}
+
+// UnaryInterceptor returns a grpc interceptor, that enforces the policy for unary RPCs.
+func (p *Policy) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		role, err := p.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if role < Operator {
+			ctx = regserver.Restrict(ctx)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a grpc interceptor, that enforces the policy for streaming RPCs.
+func (p *Policy) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, err := p.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// ServerOptions returns the grpc server options, that install the interceptors of the policy.
+func (p *Policy) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(p.UnaryInterceptor()),
+		grpc.StreamInterceptor(p.StreamInterceptor()),
+	}
+}
+
+// bearer sends a token with every RPC.
+type bearer string
+
+func (b bearer) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+
+// RequireTransportSecurity is true, such that tokens are never sent unencrypted.
+func (b bearer) RequireTransportSecurity() bool {
+	return true
+}
+
+// WithToken returns a dial option, that sends token with every RPC. It needs TLS.
+func WithToken(token string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(bearer(token))
+}
+
+// TokenFile reads a token from file, and returns a dial option sending it, see WithToken.
+func TokenFile(file string) (grpc.DialOption, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tok := strings.TrimSpace(string(b))
+	if tok == "" {
+		return nil, fmt.Errorf("auth: no token in %s", file)
+	}
+	return WithToken(tok), nil
+}
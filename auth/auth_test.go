@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/regserver"
+	"github.com/relab/smartmerge/smclient"
+	"github.com/relab/smartmerge/tlsconf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `
+# The test CA's client certificate may only read and write.
+data cert smartmerge client
+operator token op-secret
+`
+
+func TestParsePolicy(t *testing.T) {
+	if _, err := ParsePolicy(strings.NewReader("admin token x")); err == nil {
+		t.Error("accepted an unknown role")
+	}
+	if _, err := ParsePolicy(strings.NewReader("data password x")); err == nil {
+		t.Error("accepted an unknown identity kind")
+	}
+	p, err := ParsePolicy(strings.NewReader(testPolicy + "data anonymous\n"))
+	if err != nil {
+		t.Fatal("ParsePolicy returned error:", err)
+	}
+	if r := p.certs["smartmerge client"]; r != Data {
+		t.Errorf("got role %v for the client certificate, want data", r)
+	}
+	if r := p.tokens["op-secret"]; r != Operator {
+		t.Errorf("got role %v for the token, want operator", r)
+	}
+	if p.anonymous != Data {
+		t.Errorf("got role %v for anonymous callers, want data", p.anonymous)
+	}
+}
+
+func TestRequired(t *testing.T) {
+	for m, want := range map[string]Role{
+		"Read":        Data,
+		"Write":       Data,
+		"DWriteNext":  Data,
+		"Watch":       Data,
+		"SetCur":      Data,
+		"LAProp":      Operator,
+		"Fwd":         Operator,
+		"SpSnOneShot": Operator,
+		"Status":      Operator,
+		"Unknown":     Operator,
+	} {
+		if got := Required(service + m); got != want {
+			t.Errorf("Required(%s) = %v, want %v", m, got, want)
+		}
+	}
+}
+
+// calls dials addr with opts, and calls Read and Status. It returns the status codes of both calls.
+func calls(t *testing.T, addr string, opts ...grpc.DialOption) (read, stat codes.Code) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		t.Fatal("could not dial:", err)
+	}
+	defer conn.Close()
+	cl := pb.NewSMandConsRegisterClient(conn)
+	_, err = cl.Read(ctx, &pb.Read{})
+	read = status.Code(err)
+	_, err = cl.Status(ctx, &pb.StatusRequest{})
+	return read, status.Code(err)
+}
+
+func TestInterceptors(t *testing.T) {
+	server, client, err := tlsconf.WriteTestCA(t.TempDir())
+	if err != nil {
+		t.Fatal("could not create test CA:", err)
+	}
+	opts, err := server.ServerOptions()
+	if err != nil {
+		t.Fatal("could not create server options:", err)
+	}
+	p, err := ParsePolicy(strings.NewReader(testPolicy))
+	if err != nil {
+		t.Fatal("ParsePolicy returned error:", err)
+	}
+	srv := regserver.NewServer("localhost:0", regserver.NewRegServer(false), append(opts, p.ServerOptions()...)...)
+	if err := srv.Start(); err != nil {
+		t.Fatal("could not start server:", err)
+	}
+	defer srv.Stop()
+	addr := srv.Addr().String()
+
+	withCert, err := client.DialOption()
+	if err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	noCert, err := (&tlsconf.Files{CA: client.CA}).DialOption()
+	if err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		opts       []grpc.DialOption
+		read, stat codes.Code
+	}{
+		{"anonymous", []grpc.DialOption{noCert}, codes.Unauthenticated, codes.Unauthenticated},
+		{"data certificate", []grpc.DialOption{withCert}, codes.OK, codes.PermissionDenied},
+		{"unknown token", []grpc.DialOption{noCert, WithToken("guess")}, codes.Unauthenticated, codes.Unauthenticated},
+		{"operator token", []grpc.DialOption{noCert, WithToken("op-secret")}, codes.OK, codes.OK},
+		{"data certificate and operator token", []grpc.DialOption{withCert, WithToken("op-secret")}, codes.OK, codes.OK},
+	} {
+		read, stat := calls(t, addr, tc.opts...)
+		if read != tc.read || stat != tc.stat {
+			t.Errorf("%s: Read returned %v and Status %v, want %v and %v", tc.name, read, stat, tc.read, tc.stat)
+		}
+	}
+
+	// Data clients may pass on the recorded successors of a view, but not propose new ones.
+	view := &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}}}
+	succ := &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 2}}}
+	for _, tc := range []struct {
+		name string
+		opts []grpc.DialOption
+		want codes.Code
+	}{
+		{"data certificate proposing", []grpc.DialOption{withCert}, codes.PermissionDenied},
+		{"operator token proposing", []grpc.DialOption{noCert, WithToken("op-secret")}, codes.OK},
+		{"data certificate passing on", []grpc.DialOption{withCert}, codes.OK},
+	} {
+		if got := writeNext(t, addr, view, succ, tc.opts...); got != tc.want {
+			t.Errorf("%s: DWriteNext returned %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// writeNext dials addr with opts, and records next as successor of view with DWriteNext. It returns the status code.
+func writeNext(t *testing.T, addr string, view, next *bp.Blueprint, opts ...grpc.DialOption) codes.Code {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		t.Fatal("could not dial:", err)
+	}
+	defer conn.Close()
+	_, err = pb.NewSMandConsRegisterClient(conn).DWriteNext(ctx, &pb.DWriteN{CurC: view.ID(), Next: []*bp.Blueprint{next}})
+	return status.Code(err)
+}
+
+// TestDataClientReconf runs a data client across a reconfiguration by an operator.
+// The data client learns the new configuration, and passes it on, without being denied any call.
+func TestDataClientReconf(t *testing.T) {
+	server, client, err := tlsconf.WriteTestCA(t.TempDir())
+	if err != nil {
+		t.Fatal("could not create test CA:", err)
+	}
+	opts, err := server.ServerOptions()
+	if err != nil {
+		t.Fatal("could not create server options:", err)
+	}
+	p, err := ParsePolicy(strings.NewReader(testPolicy))
+	if err != nil {
+		t.Fatal("ParsePolicy returned error:", err)
+	}
+	var mu sync.Mutex
+	var denied []string
+	intercept := p.UnaryInterceptor()
+	record := grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		rep, err := intercept(ctx, req, info, handler)
+		if status.Code(err) == codes.PermissionDenied {
+			mu.Lock()
+			denied = append(denied, info.FullMethod)
+			mu.Unlock()
+		}
+		return rep, err
+	})
+	addrs := make([]string, 4)
+	for i := range addrs {
+		srv := regserver.NewServer("localhost:0", regserver.NewRegServer(false), append(opts, record)...)
+		if err := srv.Start(); err != nil {
+			t.Fatal("could not start server:", err)
+		}
+		defer srv.Stop()
+		addrs[i] = srv.Addr().String()
+	}
+
+	withCert, err := client.DialOption()
+	if err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	noCert, err := (&tlsconf.Files{CA: client.CA}).DialOption()
+	if err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	opMgr, err := pb.NewManager(addrs, pb.WithGrpcDialOptions(noCert, WithToken("op-secret"), grpc.WithBlock()))
+	if err != nil {
+		t.Fatal("could not create manager:", err)
+	}
+	defer opMgr.Close()
+	dataMgr, err := pb.NewManager(addrs, pb.WithGrpcDialOptions(withCert, grpc.WithBlock()))
+	if err != nil {
+		t.Fatal("could not create manager:", err)
+	}
+	defer dataMgr.Close()
+
+	// The data client passes on the new configuration synchronously, such that it is done when the read returns.
+	defer func(g func(func())) { smclient.Go = g }(smclient.Go)
+	smclient.Go = func(f func()) { f() }
+
+	ids := opMgr.NodeIDs()
+	blp := &bp.Blueprint{FaultTolerance: 1}
+	for _, id := range ids[:3] {
+		blp.Nodes = append(blp.Nodes, &bp.Node{Id: id})
+	}
+	opCp, dataCp := conf.NewProvider(opMgr, 1), conf.NewProvider(dataMgr, 2)
+	op, err := smclient.New(blp, 1, opCp)
+	if err != nil {
+		t.Fatal("could not create operator client:", err)
+	}
+	data, err := smclient.New(blp, 2, dataCp)
+	if err != nil {
+		t.Fatal("could not create data client:", err)
+	}
+	if _, err := data.Write(context.Background(), dataCp, "a", []byte("x")); err != nil {
+		t.Fatal("Write returned error:", err)
+	}
+
+	target := &bp.Blueprint{FaultTolerance: 1, Nodes: append(blp.Nodes, &bp.Node{Id: ids[3]})}
+	if _, err := op.Reconf(context.Background(), opCp, target); err != nil {
+		t.Fatal("Reconf returned error:", err)
+	}
+
+	val, _, err := data.Read(context.Background(), dataCp, "a")
+	if err != nil || string(val) != "x" {
+		t.Errorf("Read returned %q and %v, want \"x\"", val, err)
+	}
+	if !data.GetCur().LearnedEquals(target) {
+		t.Errorf("data client has current configuration %v, want %v", data.GetCur(), target)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(denied) > 0 {
+		t.Errorf("calls by the data client were denied: %v", denied)
+	}
+}
@@ -40,9 +40,15 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 // 			also on removal, the version is increased.
 // 			nodes with even version are part of the configuration, nodes with odd
 // 			version have been removed.
+// 			Weight is the nodes vote weight in quorums. Weight 0 counts as 1.
+// 			To change the weight of a node, its version is increased by two.
+// 			Addr is the nodes network address host:port, if known. The id of a node with
+// 			an address is the FNV-1a hash of the resolved address, see util.NodeID.
 type Node struct {
 	Id      uint32 `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`
 	Version uint32 `protobuf:"varint,2,opt,name=Version,proto3" json:"Version,omitempty"`
+	Weight  uint32 `protobuf:"varint,3,opt,name=Weight,proto3" json:"Weight,omitempty"`
+	Addr    string `protobuf:"bytes,4,opt,name=Addr,proto3" json:"Addr,omitempty"`
 }
 
 func (m *Node) Reset()                    { *m = Node{} }
@@ -55,10 +61,14 @@ func (*Node) Descriptor() ([]byte, []int) { return fileDescriptorBlueprints, []i
 // 			 and a FaultTolerance parameter that is used to determine quorum size.
 // 			 epoch is used to determine, which FaultTolerance should be chosen, when two
 // 				 blueprints are combined/merged.
+// 			 ReadFaultTolerance, if not 0, is the number of failures tolerated by read quorums.
+// 				 FaultTolerance is then the number of failures tolerated by write quorums,
+// 				 and write quorums may be smaller than a majority (flexible quorums).
 type Blueprint struct {
-	Nodes          []*Node `protobuf:"bytes,1,rep,name=Nodes" json:"Nodes,omitempty"`
-	FaultTolerance uint32  `protobuf:"varint,3,opt,name=FaultTolerance,proto3" json:"FaultTolerance,omitempty"`
-	Epoch          uint32  `protobuf:"varint,4,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	Nodes              []*Node `protobuf:"bytes,1,rep,name=Nodes" json:"Nodes,omitempty"`
+	FaultTolerance     uint32  `protobuf:"varint,3,opt,name=FaultTolerance,proto3" json:"FaultTolerance,omitempty"`
+	Epoch              uint32  `protobuf:"varint,4,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	ReadFaultTolerance uint32  `protobuf:"varint,5,opt,name=ReadFaultTolerance,proto3" json:"ReadFaultTolerance,omitempty"`
 }
 
 func (m *Blueprint) Reset()                    { *m = Blueprint{} }
@@ -107,6 +117,12 @@ func (this *Node) VerboseEqual(that interface{}) error {
 	if this.Version != that1.Version {
 		return fmt.Errorf("Version this(%v) Not Equal that(%v)", this.Version, that1.Version)
 	}
+	if this.Weight != that1.Weight {
+		return fmt.Errorf("Weight this(%v) Not Equal that(%v)", this.Weight, that1.Weight)
+	}
+	if this.Addr != that1.Addr {
+		return fmt.Errorf("Addr this(%v) Not Equal that(%v)", this.Addr, that1.Addr)
+	}
 	return nil
 }
 func (this *Node) Equal(that interface{}) bool {
@@ -140,6 +156,12 @@ func (this *Node) Equal(that interface{}) bool {
 	if this.Version != that1.Version {
 		return false
 	}
+	if this.Weight != that1.Weight {
+		return false
+	}
+	if this.Addr != that1.Addr {
+		return false
+	}
 	return true
 }
 func (this *Blueprint) VerboseEqual(that interface{}) error {
@@ -181,6 +203,9 @@ func (this *Blueprint) VerboseEqual(that interface{}) error {
 	if this.Epoch != that1.Epoch {
 		return fmt.Errorf("Epoch this(%v) Not Equal that(%v)", this.Epoch, that1.Epoch)
 	}
+	if this.ReadFaultTolerance != that1.ReadFaultTolerance {
+		return fmt.Errorf("ReadFaultTolerance this(%v) Not Equal that(%v)", this.ReadFaultTolerance, that1.ReadFaultTolerance)
+	}
 	return nil
 }
 func (this *Blueprint) Equal(that interface{}) bool {
@@ -222,6 +247,9 @@ func (this *Blueprint) Equal(that interface{}) bool {
 	if this.Epoch != that1.Epoch {
 		return false
 	}
+	if this.ReadFaultTolerance != that1.ReadFaultTolerance {
+		return false
+	}
 	return true
 }
 func (m *Node) Marshal() (dAtA []byte, err error) {
@@ -249,6 +277,17 @@ func (m *Node) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintBlueprints(dAtA, i, uint64(m.Version))
 	}
+	if m.Weight != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintBlueprints(dAtA, i, uint64(m.Weight))
+	}
+	if len(m.Addr) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintBlueprints(dAtA, i, uint64(len(m.Addr)))
+		i += copy(dAtA[i:], m.Addr)
+	}
 	return i, nil
 }
 
@@ -289,6 +328,11 @@ func (m *Blueprint) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintBlueprints(dAtA, i, uint64(m.Epoch))
 	}
+	if m.ReadFaultTolerance != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintBlueprints(dAtA, i, uint64(m.ReadFaultTolerance))
+	}
 	return i, nil
 }
 
@@ -328,6 +372,13 @@ func (m *Node) Size() (n int) {
 	if m.Version != 0 {
 		n += 1 + sovBlueprints(uint64(m.Version))
 	}
+	if m.Weight != 0 {
+		n += 1 + sovBlueprints(uint64(m.Weight))
+	}
+	l = len(m.Addr)
+	if l > 0 {
+		n += 1 + l + sovBlueprints(uint64(l))
+	}
 	return n
 }
 
@@ -346,6 +397,9 @@ func (m *Blueprint) Size() (n int) {
 	if m.Epoch != 0 {
 		n += 1 + sovBlueprints(uint64(m.Epoch))
 	}
+	if m.ReadFaultTolerance != 0 {
+		n += 1 + sovBlueprints(uint64(m.ReadFaultTolerance))
+	}
 	return n
 }
 
@@ -369,6 +423,8 @@ func (this *Node) String() string {
 	s := strings.Join([]string{`&Node{`,
 		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
 		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`Weight:` + fmt.Sprintf("%v", this.Weight) + `,`,
+		`Addr:` + fmt.Sprintf("%v", this.Addr) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -381,6 +437,7 @@ func (this *Blueprint) String() string {
 		`Nodes:` + strings.Replace(fmt.Sprintf("%v", this.Nodes), "Node", "Node", 1) + `,`,
 		`FaultTolerance:` + fmt.Sprintf("%v", this.FaultTolerance) + `,`,
 		`Epoch:` + fmt.Sprintf("%v", this.Epoch) + `,`,
+		`ReadFaultTolerance:` + fmt.Sprintf("%v", this.ReadFaultTolerance) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -460,6 +517,54 @@ func (m *Node) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Weight", wireType)
+			}
+			m.Weight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlueprints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Weight |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlueprints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBlueprints
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBlueprints(dAtA[iNdEx:])
@@ -579,6 +684,25 @@ func (m *Blueprint) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadFaultTolerance", wireType)
+			}
+			m.ReadFaultTolerance = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlueprints
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadFaultTolerance |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBlueprints(dAtA[iNdEx:])
@@ -708,20 +832,21 @@ var (
 func init() { proto.RegisterFile("blueprints.proto", fileDescriptorBlueprints) }
 
 var fileDescriptorBlueprints = []byte{
-	// 225 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x12, 0x48, 0xca, 0x29, 0x4d,
+	// 246 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe3, 0x12, 0x48, 0xca, 0x29, 0x4d,
 	0x2d, 0x28, 0xca, 0xcc, 0x2b, 0x29, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x42, 0x88,
 	0x48, 0xe9, 0xa6, 0x67, 0x96, 0x64, 0x94, 0x26, 0xe9, 0x25, 0xe7, 0xe7, 0xea, 0xa7, 0xe7, 0xa7,
-	0xe7, 0xeb, 0x83, 0x95, 0x24, 0x95, 0xa6, 0x81, 0x79, 0x60, 0x0e, 0x98, 0x05, 0xd1, 0xaa, 0xa4,
-	0xcc, 0xc5, 0xe2, 0x97, 0x9f, 0x92, 0x2a, 0xc4, 0xc5, 0xc5, 0xe4, 0x99, 0x22, 0xc1, 0xa8, 0xc0,
+	0xe7, 0xeb, 0x83, 0x95, 0x24, 0x95, 0xa6, 0x81, 0x79, 0x60, 0x0e, 0x98, 0x05, 0xd1, 0xaa, 0xe4,
+	0xc8, 0xc5, 0xe2, 0x97, 0x9f, 0x92, 0x2a, 0xc4, 0xc5, 0xc5, 0xe4, 0x99, 0x22, 0xc1, 0xa8, 0xc0,
 	0xa8, 0xc1, 0x2b, 0xc4, 0xcf, 0xc5, 0x1e, 0x96, 0x5a, 0x54, 0x9c, 0x99, 0x9f, 0x27, 0xc1, 0x04,
-	0x12, 0x50, 0x0a, 0xe6, 0xe2, 0x74, 0x82, 0xd9, 0x20, 0x24, 0xcf, 0xc5, 0x0a, 0xd2, 0x51, 0x2c,
-	0xc1, 0xa8, 0xc0, 0xac, 0xc1, 0x6d, 0x24, 0xa0, 0x87, 0xe4, 0x1c, 0xb0, 0x51, 0x62, 0x5c, 0x7c,
-	0x6e, 0x89, 0xa5, 0x39, 0x25, 0x21, 0xf9, 0x39, 0xa9, 0x45, 0x89, 0x79, 0xc9, 0xa9, 0x12, 0xcc,
-	0x60, 0x63, 0x79, 0xb9, 0x58, 0x5d, 0x0b, 0xf2, 0x93, 0x33, 0x24, 0x58, 0x40, 0x5c, 0x27, 0x9d,
-	0x0b, 0x0f, 0xe5, 0x18, 0x6e, 0x3c, 0x94, 0x63, 0x78, 0xf0, 0x50, 0x8e, 0xb1, 0xe1, 0x91, 0x1c,
-	0xe3, 0x8a, 0x47, 0x72, 0x8c, 0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24, 0xc7, 0xf8, 0xe0, 0x91,
-	0x1c, 0xe3, 0x8b, 0x47, 0x72, 0x0c, 0x1f, 0x1e, 0xc9, 0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x90, 0xc4,
-	0x06, 0x76, 0xae, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0xab, 0x51, 0xf7, 0x1e, 0xfd, 0x00, 0x00,
-	0x00,
+	0x16, 0xe0, 0xe3, 0x62, 0x0b, 0x4f, 0xcd, 0x4c, 0xcf, 0x28, 0x91, 0x60, 0x06, 0xf3, 0x79, 0xb8,
+	0x58, 0x1c, 0x53, 0x52, 0x8a, 0x24, 0x58, 0x80, 0x3c, 0x4e, 0xa5, 0x7c, 0x2e, 0x4e, 0x27, 0x98,
+	0xfd, 0x42, 0xf2, 0x5c, 0xac, 0x20, 0xf3, 0x8a, 0x81, 0x46, 0x31, 0x6b, 0x70, 0x1b, 0x09, 0xe8,
+	0x21, 0x39, 0x16, 0x6c, 0x91, 0x18, 0x17, 0x9f, 0x5b, 0x62, 0x69, 0x4e, 0x49, 0x48, 0x7e, 0x4e,
+	0x6a, 0x51, 0x62, 0x5e, 0x72, 0x2a, 0xd4, 0x4c, 0x5e, 0x2e, 0x56, 0xd7, 0x82, 0xfc, 0xe4, 0x0c,
+	0xb0, 0xa1, 0xbc, 0x42, 0x52, 0x5c, 0x42, 0x41, 0xa9, 0x89, 0x29, 0x68, 0x4a, 0x59, 0x41, 0x72,
+	0x4e, 0x3a, 0x17, 0x1e, 0xca, 0x31, 0xdc, 0x00, 0xe2, 0x07, 0x0f, 0xe5, 0x18, 0x1b, 0x1e, 0xc9,
+	0x31, 0xae, 0x00, 0xe2, 0x13, 0x40, 0x7c, 0x01, 0x88, 0x1f, 0x00, 0xf1, 0x8b, 0x47, 0x72, 0x0c,
+	0x1f, 0x80, 0xf4, 0x84, 0xc7, 0x72, 0x0c, 0x49, 0x6c, 0x60, 0x8f, 0x1a, 0x03, 0x00, 0xf3, 0x4a,
+	0x76, 0x1e, 0x37, 0x01, 0x00, 0x00,
 }
@@ -8,6 +8,12 @@
 // Blueprints together with the merge function form a lattice.
 package blueprints
 
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+)
+
 // This file contains manually coded methods on the blueprint structs created
 // from protobuf.
 
@@ -83,6 +89,8 @@ func subset(A, B []uint32) bool {
 // Merge implements the lattice join on the lattice of blueprints.
 // The configuration resulting from the merge will include all nodes, added
 // to one of the blueprints, and not removed yet.
+// Node addresses are kept from either blueprint. Since the id of a node is
+// derived from its address, addresses are not compared by Compare and Equals.
 func (bp *Blueprint) Merge(blpr *Blueprint) (mbp *Blueprint) {
 	if bp == nil {
 		return blpr
@@ -93,7 +101,7 @@ func (bp *Blueprint) Merge(blpr *Blueprint) (mbp *Blueprint) {
 	mbp = new(Blueprint)
 	mbp.Nodes = make([]*Node, len(bp.Nodes))
 	for i, n := range bp.Nodes {
-		mbp.Nodes[i] = &Node{Id: n.Id, Version: n.Version}
+		mbp.Nodes[i] = &Node{Id: n.Id, Version: n.Version, Weight: n.Weight, Addr: n.Addr}
 	}
 
 	for _, n := range blpr.Nodes {
@@ -102,32 +110,40 @@ func (bp *Blueprint) Merge(blpr *Blueprint) (mbp *Blueprint) {
 		for _, node := range mbp.Nodes {
 			if n.Id == node.Id {
 				found = true
-				if n.Version >= node.Version {
+				if node.Addr == "" {
+					node.Addr = n.Addr
+				}
+				if node.less(n) {
 					node.Version = n.Version
+					node.Weight = n.Weight
 				} else {
 					break for_blpr
 				}
 			}
 		}
 		if !found {
-			mbp.Nodes = append(mbp.Nodes, &Node{Id: n.Id, Version: n.Version})
+			mbp.Nodes = append(mbp.Nodes, &Node{Id: n.Id, Version: n.Version, Weight: n.Weight, Addr: n.Addr})
 		}
 	}
 
+	// The quorum parameters are taken together from the blueprint with the
+	// larger (Epoch, FaultTolerance, ReadFaultTolerance).
+	from := blpr
 	switch {
-	case bp.Epoch > blpr.Epoch:
-		mbp.Epoch = bp.Epoch
-		mbp.FaultTolerance = bp.FaultTolerance
-	case blpr.Epoch > blpr.Epoch:
-		mbp.Epoch = blpr.Epoch
-		mbp.FaultTolerance = blpr.FaultTolerance
-	case bp.FaultTolerance > blpr.FaultTolerance:
-		mbp.Epoch = bp.Epoch
-		mbp.FaultTolerance = bp.FaultTolerance
-	default:
-		mbp.Epoch = blpr.Epoch
-		mbp.FaultTolerance = blpr.FaultTolerance
+	case bp.Epoch != blpr.Epoch:
+		if bp.Epoch > blpr.Epoch {
+			from = bp
+		}
+	case bp.FaultTolerance != blpr.FaultTolerance:
+		if bp.FaultTolerance > blpr.FaultTolerance {
+			from = bp
+		}
+	case bp.ReadFaultTolerance > blpr.ReadFaultTolerance:
+		from = bp
 	}
+	mbp.Epoch = from.Epoch
+	mbp.FaultTolerance = from.FaultTolerance
+	mbp.ReadFaultTolerance = from.ReadFaultTolerance
 	return mbp
 }
 
@@ -155,6 +171,10 @@ func (a *Blueprint) Compare(b *Blueprint) int {
 		aleqb = false
 	case b.FaultTolerance > a.FaultTolerance:
 		bleqa = false
+	case a.ReadFaultTolerance > b.ReadFaultTolerance:
+		aleqb = false
+	case b.ReadFaultTolerance > a.ReadFaultTolerance:
+		bleqa = false
 	}
 
 	if len(a.Nodes) < len(b.Nodes) {
@@ -172,11 +192,11 @@ func (a *Blueprint) Compare(b *Blueprint) int {
 			for _, nb := range b.Nodes {
 				if na.Id == nb.Id {
 					found = true
-					if na.Version > nb.Version {
+					if nb.less(na) {
 						aleqb = false
 						break for_a
 					}
-					if na.Version < nb.Version {
+					if na.less(nb) {
 						bleqa = false
 					}
 					break for_b
@@ -197,7 +217,7 @@ func (a *Blueprint) Compare(b *Blueprint) int {
 			for _, na := range a.Nodes {
 				if nb.Id == na.Id {
 					found = true
-					if nb.Version > na.Version {
+					if na.less(nb) {
 						bleqa = false
 						break for_B
 					}
@@ -236,6 +256,9 @@ func (a *Blueprint) Equals(b *Blueprint) bool {
 	if a.FaultTolerance != b.FaultTolerance {
 		return false
 	}
+	if a.ReadFaultTolerance != b.ReadFaultTolerance {
+		return false
+	}
 
 	if len(a.Nodes) != len(b.Nodes) {
 		return false
@@ -245,7 +268,7 @@ for_a:
 	for _, na := range a.Nodes {
 		for _, nb := range b.Nodes {
 			if na.Id == nb.Id {
-				if na.Version != nb.Version {
+				if na.Version != nb.Version || na.VoteWeight() != nb.VoteWeight() {
 					return false
 				}
 				continue for_a
@@ -257,56 +280,73 @@ for_a:
 	return true
 }
 
-// Hash returns an integer that uniquely describs the configuration.
-// Additionally, if blueprints are comparable
-// (ordered as elements of the lattice), their hashes reflect this order.
-func (bp *Blueprint) Hash() int {
-	return bp.len()
-}
-
-// Len function name depricated. Should be replaced by Hash
-// returns an integer that uniquely describs the configuration.
-func (bp *Blueprint) Len() int {
-	return bp.len()
-}
-
-func (bp *Blueprint) len() int {
+// ID returns an identifier of the blueprint, which is used to identify configurations.
+// Different blueprints have different ids. If blueprints are comparable
+// (ordered as elements of the lattice), their ids reflect this order:
+// a < b implies bytes.Compare(a.ID(), b.ID()) < 0.
+//
+// The id is the big-endian encoding of the epoch, the fault tolerances, the
+// sum over all nodes of Version+1, followed by the ids, versions and vote weights
+// of all nodes, sorted by id. The nil blueprint has the empty id.
+func (bp *Blueprint) ID() []byte {
 	if bp == nil {
-		return 0
+		return nil
 	}
 
-	if bp.FaultTolerance > uint32(15) {
-		panic("Specified Fault tolerance larger than 15. Len not correct for such values.")
+	nodes := make([]*Node, len(bp.Nodes))
+	copy(nodes, bp.Nodes)
+	sort.Sort(byId(nodes))
+
+	sum := uint64(0)
+	for _, n := range nodes {
+		sum += uint64(n.Version) + 1
+		// +1 necessary to acchieve, that adding one id with version 0 results in a larger id.
 	}
 
-	sum := uint32(0)
-	for _, n := range bp.Nodes {
-		sum = sum + n.Version + 1
-		// +1 necessary to acchieve, that adding one id with version 0 results in increased length.
+	id := make([]byte, 20+12*len(nodes))
+	binary.BigEndian.PutUint32(id, bp.Epoch)
+	binary.BigEndian.PutUint32(id[4:], bp.FaultTolerance)
+	binary.BigEndian.PutUint32(id[8:], bp.ReadFaultTolerance)
+	binary.BigEndian.PutUint64(id[12:], sum)
+	for i, n := range nodes {
+		binary.BigEndian.PutUint32(id[20+12*i:], n.Id)
+		binary.BigEndian.PutUint32(id[24+12*i:], n.Version)
+		binary.BigEndian.PutUint32(id[28+12*i:], n.VoteWeight())
 	}
+	return id
+}
 
-	sum += bp.Epoch * 16
-	sum += bp.FaultTolerance
+// VoteWeight returns the weight of the nodes vote. Weight 0 counts as 1.
+func (n *Node) VoteWeight() uint32 {
+	if n.Weight == 0 {
+		return 1
+	}
+	return n.Weight
+}
 
-	return int(sum)
+// less orders the entries for the same node by version and weight.
+func (n *Node) less(m *Node) bool {
+	if n.Version != m.Version {
+		return n.Version < m.Version
+	}
+	return n.VoteWeight() < m.VoteWeight()
 }
 
+type byId []*Node
+
+func (p byId) Len() int           { return len(p) }
+func (p byId) Less(i, j int) bool { return p[i].Id < p[j].Id }
+func (p byId) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
 // LearnedCompare can be used to compare, if it is known that the blueprints are
 // comparable.
 func (bp *Blueprint) LearnedCompare(blpr *Blueprint) int {
-	if bp.len() < blpr.len() {
-		return 1
-	}
-	if bp.len() > blpr.len() {
-		return -1
-	}
-
-	return 0
+	return -bytes.Compare(bp.ID(), blpr.ID())
 }
 
 // LearnedEquals checks whether two comparable blueprints are equal.
 func (bp *Blueprint) LearnedEquals(blpr *Blueprint) bool {
-	return bp.len() == blpr.len()
+	return bytes.Equal(bp.ID(), blpr.ID())
 }
 
 // Ids returns the set of nodes (node ids) that are actually in the configuration.
@@ -345,6 +385,37 @@ func (bp *Blueprint) Add(id uint32) bool {
 	return true
 }
 
+// AddAt adds a node with the network address addr, like Add.
+// Clients learning the blueprint can connect to the node, even if it is not in their configuration file.
+// id must be the id of the address, see util.NodeID.
+func (bp *Blueprint) AddAt(id uint32, addr string) bool {
+	added := bp.Add(id)
+	for _, n := range bp.Nodes {
+		if n.Id == id && n.Addr == "" {
+			n.Addr = addr
+		}
+	}
+	return added
+}
+
+// SetWeight sets the vote weight of a node, that was added to the blueprint.
+// The version of the node is increased by two, such that the new blueprint is larger.
+// Returns true, if the weight was changed, false if the node is not in the
+// blueprint, already has this weight, or weight is 0.
+func (bp *Blueprint) SetWeight(id, weight uint32) bool {
+	for _, n := range bp.Nodes {
+		if n.Id == id {
+			if n.VoteWeight() == weight || weight == 0 {
+				return false
+			}
+			n.Version += 2
+			n.Weight = weight
+			return true
+		}
+	}
+	return false
+}
+
 // Rem removes a node from a blueprint. Has no effect if the node was never added to that blueprint.
 // Returns true, if node was removed, false otherwise
 func (bp *Blueprint) Rem(id uint32) bool {
@@ -361,50 +432,131 @@ func (bp *Blueprint) Rem(id uint32) bool {
 	return false
 }
 
-// Quorum returns the number of nodes necessary to form a quorum.
+// Quorum returns the number of nodes necessary to form a (write) quorum.
 // If bp.FaultTolerance is smaller than half of the nodes in the configuration,
 // Quorum will be larger than necessary.
+// With flexible quorums (ReadFaultTolerance > 0), Quorum may be smaller than a majority.
 func (bp *Blueprint) Quorum() int {
-	n := len(bp.Ids())
-	if q := n/2 + 1; q >= n-int(bp.FaultTolerance) {
-		return q
+	_, wq := bp.quorums(ones(len(bp.Ids())))
+	return wq
+}
+
+// ReadQuorum returns the number of nodes necessary to form a read quorum.
+// Every read quorum intersects every (write) quorum.
+func (bp *Blueprint) ReadQuorum() int {
+	rq, _ := bp.quorums(ones(len(bp.Ids())))
+	return rq
+}
+
+// Weights returns the vote weights of the nodes in the configuration.
+func (bp *Blueprint) Weights() map[uint32]int {
+	if bp == nil {
+		return nil
 	}
-	return n - int(bp.FaultTolerance)
+	ws := make(map[uint32]int, len(bp.Nodes))
+	for _, n := range bp.Nodes {
+		if n.Version%2 == 0 {
+			ws[n.Id] = int(n.VoteWeight())
+		}
+	}
+	return ws
 }
 
-/* The following was moved into the package qfunc
+// TotalWeight returns the sum of the vote weights of the nodes in the configuration.
+func (bp *Blueprint) TotalWeight() (w int) {
+	for _, nw := range bp.Weights() {
+		w += nw
+	}
+	return w
+}
 
-// ReadQuorum returns the size of a read quorum.
-// If bp.FaultTolerance is smaller than half of the nodes in the configuration,
-// The ReadQuorum size will equal FaultTolerance + 1.
-// Otherwise, a ReadQuorum includes half of the nodes (rounded up).
-func (bp *Blueprint) ReadQuorum() int {
-	return len(bp.Ids()) - bp.Quorum() + 1
+// WeightedQuorum returns the weight necessary to form a (write) quorum.
+// It is the weight of a majority, or larger, if the configuration should
+// stay available when the FaultTolerance heaviest nodes fail.
+// If all nodes have weight 1, WeightedQuorum equals Quorum.
+func (bp *Blueprint) WeightedQuorum() int {
+	_, wq := bp.quorums(bp.sortedWeights())
+	return wq
+}
+
+// WeightedReadQuorum returns the weight necessary to form a read quorum.
+// If all nodes have weight 1, WeightedReadQuorum equals ReadQuorum.
+func (bp *Blueprint) WeightedReadQuorum() int {
+	rq, _ := bp.quorums(bp.sortedWeights())
+	return rq
+}
+
+// WeightedReadWriteQuorum returns the weight necessary to form both a read and a write quorum.
+// Operations that read and write in the same round trip must contact this weight.
+func (bp *Blueprint) WeightedReadWriteQuorum() int {
+	rq, wq := bp.quorums(bp.sortedWeights())
+	return max(rq, wq)
+}
+
+// quorums returns the read and write quorum sizes, for nodes with the weights ws,
+// sorted in decreasing order.
+//
+// Without flexible quorums, a write quorum is a majority, or larger, such that
+// it tolerates FaultTolerance failures. A read quorum is just large enough to intersect every write quorum.
+// With flexible quorums, a write quorum tolerates FaultTolerance failures, and a read quorum
+// ReadFaultTolerance failures. If both is not possible, read quorums are enlarged to intersect every write quorum.
+func (bp *Blueprint) quorums(ws []int) (rq, wq int) {
+	total := 0
+	for _, w := range ws {
+		total += w
+	}
+	// alive returns the weight of the nodes left, when the f heaviest nodes fail.
+	alive := func(f uint32) int {
+		a := total
+		for i := 0; i < len(ws) && i < int(f); i++ {
+			a -= ws[i]
+		}
+		return a
+	}
+
+	if bp.ReadFaultTolerance == 0 {
+		wq = max(total/2+1, alive(bp.FaultTolerance))
+		return total - wq + 1, wq
+	}
+	wq = max(1, alive(bp.FaultTolerance))
+	rq = max(1, max(alive(bp.ReadFaultTolerance), total-wq+1))
+	return rq, wq
 }
 
-// WriteQuorum returns the size of a WriteQuorum.
-// WriteQuorum may be larger than ReadQuorums.
-func (bp *Blueprint) WriteQuorum() int {
-	return bp.Quorum()
+func (bp *Blueprint) sortedWeights() []int {
+	ws := make([]int, 0, len(bp.Nodes))
+	for _, w := range bp.Weights() {
+		ws = append(ws, w)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ws)))
+	return ws
 }
 
-// MaxQuorum returns the size of a Quorum
-// that is both a read and write quorum.
-func (bp *Blueprint) MaxQuorum() int {
-	if bp.Quorum() > bp.ReadQuorum() {
-		return bp.Quorum()
+func ones(n int) []int {
+	ws := make([]int, n)
+	for i := range ws {
+		ws[i] = 1
 	}
-	return bp.ReadQuorum()
-}*/
+	return ws
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 
 // Copy copies a blueprint.
 func (bp *Blueprint) Copy() *Blueprint {
 	b := new(Blueprint)
 	b.Epoch = bp.Epoch
 	b.FaultTolerance = bp.FaultTolerance
+	b.ReadFaultTolerance = bp.ReadFaultTolerance
 	b.Nodes = make([]*Node, len(bp.Nodes))
 	for i, n := range bp.Nodes {
-		b.Nodes[i] = &Node{n.Id, n.Version}
+		b.Nodes[i] = &Node{Id: n.Id, Version: n.Version, Weight: n.Weight, Addr: n.Addr}
 	}
 	return b
 }
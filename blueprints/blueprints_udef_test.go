@@ -1,6 +1,7 @@
 package blueprints
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -111,43 +112,43 @@ var four = uint32(4)
 var five = uint32(5)
 var six = uint32(6)
 
-var n00 = &Node{zero, zero}
-var n10 = &Node{one, zero}
-var n20 = &Node{two, zero}
-var n30 = &Node{tre, zero}
-var n40 = &Node{four, zero}
-var n50 = &Node{five, zero}
-var n60 = &Node{six, zero}
-
-var n11 = &Node{one, one}
-var n12 = &Node{one, two}
-var n22 = &Node{two, two}
-var n32 = &Node{tre, two}
-var n33 = &Node{tre, tre}
-
-var b1 = &Blueprint{[]*Node{n11}, one, one}
-var b2 = &Blueprint{[]*Node{n22}, two, one}
-var b12 = &Blueprint{[]*Node{n11, n22}, two, one}
-var b22 = &Blueprint{[]*Node{n11, n22}, two, two}
-var b23 = &Blueprint{[]*Node{n11, n22}, tre, two}
-
-var b12x = &Blueprint{[]*Node{n12, n22}, two, one}
-var b123 = &Blueprint{[]*Node{n12, n22, n32}, two, one}
-var bx = &Blueprint{[]*Node{n11, n33}, tre, two}
-var by = &Blueprint{[]*Node{n12, n32}, two, one}
+var n00 = &Node{Id: zero, Version: zero}
+var n10 = &Node{Id: one, Version: zero}
+var n20 = &Node{Id: two, Version: zero}
+var n30 = &Node{Id: tre, Version: zero}
+var n40 = &Node{Id: four, Version: zero}
+var n50 = &Node{Id: five, Version: zero}
+var n60 = &Node{Id: six, Version: zero}
+
+var n11 = &Node{Id: one, Version: one}
+var n12 = &Node{Id: one, Version: two}
+var n22 = &Node{Id: two, Version: two}
+var n32 = &Node{Id: tre, Version: two}
+var n33 = &Node{Id: tre, Version: tre}
+
+var b1 = &Blueprint{Nodes: []*Node{n11}, FaultTolerance: one, Epoch: one}
+var b2 = &Blueprint{Nodes: []*Node{n22}, FaultTolerance: two, Epoch: one}
+var b12 = &Blueprint{Nodes: []*Node{n11, n22}, FaultTolerance: two, Epoch: one}
+var b22 = &Blueprint{Nodes: []*Node{n11, n22}, FaultTolerance: two, Epoch: two}
+var b23 = &Blueprint{Nodes: []*Node{n11, n22}, FaultTolerance: tre, Epoch: two}
+
+var b12x = &Blueprint{Nodes: []*Node{n12, n22}, FaultTolerance: two, Epoch: one}
+var b123 = &Blueprint{Nodes: []*Node{n12, n22, n32}, FaultTolerance: two, Epoch: one}
+var bx = &Blueprint{Nodes: []*Node{n11, n33}, FaultTolerance: tre, Epoch: two}
+var by = &Blueprint{Nodes: []*Node{n12, n32}, FaultTolerance: two, Epoch: one}
 var b0 *Blueprint
 
-var q0 = &Blueprint{[]*Node{n00, n10, n20, n30, n40, n50, n60}, zero, zero}
-var q1 = &Blueprint{[]*Node{n00, n10, n20, n30, n40, n50, n60}, one, zero}
-var q2 = &Blueprint{[]*Node{n00, n10, n20, n30, n40, n50, n60}, two, zero}
-var q3 = &Blueprint{[]*Node{n00, n10, n20, n30, n40, n50, n60}, tre, zero}
-var q5 = &Blueprint{[]*Node{n00, n10, n20, n30, n40, n50, n60}, five, zero}
+var q0 = &Blueprint{Nodes: []*Node{n00, n10, n20, n30, n40, n50, n60}, FaultTolerance: zero, Epoch: zero}
+var q1 = &Blueprint{Nodes: []*Node{n00, n10, n20, n30, n40, n50, n60}, FaultTolerance: one, Epoch: zero}
+var q2 = &Blueprint{Nodes: []*Node{n00, n10, n20, n30, n40, n50, n60}, FaultTolerance: two, Epoch: zero}
+var q3 = &Blueprint{Nodes: []*Node{n00, n10, n20, n30, n40, n50, n60}, FaultTolerance: tre, Epoch: zero}
+var q5 = &Blueprint{Nodes: []*Node{n00, n10, n20, n30, n40, n50, n60}, FaultTolerance: five, Epoch: zero}
 
-var qx0 = &Blueprint{[]*Node{n00, n11, n20, n30, n40, n50, n60}, zero, zero}
-var qx1 = &Blueprint{[]*Node{n00, n11, n20, n30, n40, n50, n60}, one, zero}
-var qx2 = &Blueprint{[]*Node{n00, n11, n20, n30, n40, n50, n60}, two, zero}
-var qx3 = &Blueprint{[]*Node{n00, n11, n20, n30, n40, n50, n60}, tre, zero}
-var qx5 = &Blueprint{[]*Node{n00, n11, n20, n30, n40, n50, n60}, five, zero}
+var qx0 = &Blueprint{Nodes: []*Node{n00, n11, n20, n30, n40, n50, n60}, FaultTolerance: zero, Epoch: zero}
+var qx1 = &Blueprint{Nodes: []*Node{n00, n11, n20, n30, n40, n50, n60}, FaultTolerance: one, Epoch: zero}
+var qx2 = &Blueprint{Nodes: []*Node{n00, n11, n20, n30, n40, n50, n60}, FaultTolerance: two, Epoch: zero}
+var qx3 = &Blueprint{Nodes: []*Node{n00, n11, n20, n30, n40, n50, n60}, FaultTolerance: tre, Epoch: zero}
+var qx5 = &Blueprint{Nodes: []*Node{n00, n11, n20, n30, n40, n50, n60}, FaultTolerance: five, Epoch: zero}
 
 func TestCopy(t *testing.T) {
 	cop := b123.Copy()
@@ -253,30 +254,41 @@ func TestLearnedCompare(t *testing.T) {
 	}
 }
 
-// func TestLen(t *testing.T) {
-// 	if b2.Len() != 2+2+16 {
-// 		t.Error("Unexpected Length")
-// 	}
-// 	if b12.Len() != 1+2+2+16 {
-// 		t.Error("Unexpected Length")
-// 	}
-// 	if b12x.Len() != 2+2+2+16 {
-// 		t.Error("Unexpected Length")
-// 	}
-// 	if b123.Len() != 2+2+2+2+16 {
-// 		t.Error("Unexpected Length")
-// 	}
-// 	if bx.Len() != 1+3+3+16*2 {
-// 		t.Error("Unexpected Length")
-// 	}
-// 	if b0.Len() != 0 {
-// 		t.Error("Unexpected Length")
-// 	}
-// 	if by.Len() != 2+2+2+16 {
-// 		t.Error("Unexpected Length")
-// 	}
-//
-// }
+func TestID(t *testing.T) {
+	blps := []*Blueprint{b0, b1, b2, b12, b22, b23, b12x, b123, bx, by}
+	for _, a := range blps {
+		for _, b := range blps {
+			cmp := bytes.Compare(a.ID(), b.ID())
+			switch {
+			case a.Equals(b) && cmp != 0:
+				t.Errorf("Equal blueprints %v and %v have different ids", a, b)
+			case !a.Equals(b) && cmp == 0:
+				t.Errorf("Different blueprints %v and %v have the same id", a, b)
+			case !a.Equals(b) && a.Compare(b) == 1 && cmp != -1:
+				t.Errorf("Id of %v is not smaller than id of larger blueprint %v", a, b)
+			}
+		}
+	}
+
+	// Node order does not matter.
+	if !bytes.Equal(b12.ID(), (&Blueprint{Nodes: []*Node{n22, n11}, FaultTolerance: two, Epoch: one}).ID()) {
+		t.Error("Id depends on the order of nodes")
+	}
+
+	// Same number of nodes and versions, but incomparable.
+	c1 := &Blueprint{Nodes: []*Node{n10, n22}, FaultTolerance: two, Epoch: one}
+	c2 := &Blueprint{Nodes: []*Node{n11, {Id: two, Version: one}}, FaultTolerance: two, Epoch: one}
+	if bytes.Equal(c1.ID(), c2.ID()) {
+		t.Error("Incomparable blueprints have the same id")
+	}
+
+	// There is no limit on the fault tolerance.
+	f1 := &Blueprint{Nodes: []*Node{n10}, FaultTolerance: 100, Epoch: one}
+	f2 := &Blueprint{Nodes: []*Node{n10}, FaultTolerance: 16, Epoch: one}
+	if f2.LearnedCompare(f1) != 1 {
+		t.Error("LearnedCompare did not find smaller for large fault tolerance")
+	}
+}
 
 func TestMerge(t *testing.T) {
 	if !b1.Merge(b2).Equals(b12) {
@@ -408,3 +420,150 @@ func TestAddRem(t *testing.T) {
 		t.Error("Wrong result from adding & removing")
 	}
 }
+
+func TestWeights(t *testing.T) {
+	w := b12x.Copy()
+	if !w.SetWeight(two, 3) {
+		t.Fatal("SetWeight did not change the weight")
+	}
+	if w.SetWeight(two, 3) || w.SetWeight(tre, 3) || w.SetWeight(one, 0) {
+		t.Error("SetWeight changed the weight of an unknown node, or to the same weight")
+	}
+	if b12x.Compare(w) != 1 || w.Compare(b12x) != -1 {
+		t.Error("Changing the weight did not result in a larger blueprint")
+	}
+	if bytes.Compare(b12x.ID(), w.ID()) != -1 {
+		t.Error("Changing the weight did not result in a larger id")
+	}
+	if !b12x.Merge(w).Equals(w) || !w.Merge(b12x).Equals(w) {
+		t.Error("Merge did not keep the new weight")
+	}
+
+	// Same version, different weights. Merge takes the larger weight.
+	heavy := &Blueprint{Nodes: []*Node{n12, {Id: two, Version: two, Weight: 5}}, FaultTolerance: two, Epoch: one}
+	if b12x.Compare(heavy) != 1 || !b12x.Merge(heavy).Equals(heavy) {
+		t.Error("Merge or Compare ignored the weight")
+	}
+	if !b12x.Equals(&Blueprint{Nodes: []*Node{n12, {Id: two, Version: two, Weight: 1}}, FaultTolerance: two, Epoch: one}) {
+		t.Error("Weight 0 and 1 are not equal")
+	}
+
+	if ws := w.Weights(); len(ws) != 2 || ws[one] != 1 || ws[two] != 3 {
+		t.Errorf("Unexpected weights %v", ws)
+	}
+	if w.TotalWeight() != 4 {
+		t.Error("Wrong total weight")
+	}
+}
+
+func TestWeightedQuorum(t *testing.T) {
+	for _, b := range []*Blueprint{q0, q1, q2, q3, q5, qx0, qx1, qx2, qx3, qx5} {
+		if b.WeightedQuorum() != b.Quorum() {
+			t.Errorf("WeightedQuorum %d differs from Quorum %d for unit weights", b.WeightedQuorum(), b.Quorum())
+		}
+	}
+
+	// Weights 4,1,1,1,1: total 8.
+	b := &Blueprint{Nodes: []*Node{{Id: 0, Weight: 4}, n10, n20, n30, n40}, FaultTolerance: zero, Epoch: zero}
+	if b.WeightedQuorum() != 8 {
+		t.Error("Wrong weighted quorum")
+	}
+	b.FaultTolerance = one
+	if b.WeightedQuorum() != 5 {
+		t.Error("Wrong weighted quorum")
+	}
+	b.FaultTolerance = two
+	if b.WeightedQuorum() != 5 {
+		t.Error("Wrong weighted quorum")
+	}
+}
+
+func TestFlexibleQuorum(t *testing.T) {
+	// 7 nodes.
+	for _, b := range []*Blueprint{q0, q1, q2, q3, q5} {
+		if b.ReadQuorum()+b.Quorum() != 8 {
+			t.Errorf("Read quorum %d does not match quorum %d", b.ReadQuorum(), b.Quorum())
+		}
+	}
+
+	tests := []struct {
+		ft, rft uint32
+		rq, wq  int
+	}{
+		{ft: 5, rft: 1, rq: 6, wq: 2},
+		{ft: 1, rft: 5, rq: 2, wq: 6},
+		{ft: 2, rft: 2, rq: 5, wq: 5},
+		{ft: 4, rft: 4, rq: 5, wq: 3},
+		{ft: 7, rft: 1, rq: 7, wq: 1},
+	}
+	for _, test := range tests {
+		b := q0.Copy()
+		b.FaultTolerance, b.ReadFaultTolerance = test.ft, test.rft
+		if b.ReadQuorum() != test.rq || b.Quorum() != test.wq {
+			t.Errorf("ft %d, readft %d: got quorums (%d, %d), expected (%d, %d)",
+				test.ft, test.rft, b.ReadQuorum(), b.Quorum(), test.rq, test.wq)
+		}
+		if rwq := b.WeightedReadWriteQuorum(); rwq != max(test.rq, test.wq) {
+			t.Errorf("ft %d, readft %d: got read-write quorum %d, expected %d", test.ft, test.rft, rwq, max(test.rq, test.wq))
+		}
+		if b.ReadQuorum()+b.Quorum() <= 7 {
+			t.Errorf("ft %d, readft %d: read and write quorums do not intersect", test.ft, test.rft)
+		}
+	}
+}
+
+func TestMergeReadFaultTolerance(t *testing.T) {
+	fb := b12.Copy()
+	fb.ReadFaultTolerance = one
+	if b12.Compare(fb) != 1 || fb.Compare(b12) != -1 {
+		t.Error("Compare ignored the read fault tolerance")
+	}
+	if !b12.Merge(fb).Equals(fb) || !fb.Merge(b12).Equals(fb) {
+		t.Error("Merge did not keep the read fault tolerance")
+	}
+	if bytes.Compare(b12.ID(), fb.ID()) != -1 {
+		t.Error("Id did not grow with the read fault tolerance")
+	}
+
+	// The quorum parameters of the later epoch win.
+	m := fb.Merge(b22)
+	if m.Epoch != two || m.FaultTolerance != two || m.ReadFaultTolerance != 0 {
+		t.Errorf("Unexpected quorum parameters in merge %v", m)
+	}
+	if fb.Compare(m) != 1 || b22.Compare(m) != 1 {
+		t.Error("Merge is not an upper bound")
+	}
+}
+
+func TestAddAt(t *testing.T) {
+	b := b12.Copy()
+	if !b.AddAt(tre, "10.0.0.3:10000") {
+		t.Fatal("AddAt did not add new node")
+	}
+	if b.AddAt(tre, "10.0.0.3:10000") {
+		t.Error("AddAt added node twice")
+	}
+	if b12.Compare(b) != 1 {
+		t.Error("Adding a node did not result in a larger blueprint")
+	}
+
+	// Addresses are kept by Merge and Copy, and learned for known nodes.
+	m := b12.Merge(b)
+	for _, c := range []*Blueprint{m, b.Merge(b12), m.Copy()} {
+		found := false
+		for _, n := range c.Nodes {
+			if n.Id == tre {
+				found = n.Addr == "10.0.0.3:10000"
+			}
+		}
+		if !found {
+			t.Errorf("Lost address of node %d in %v", tre, c)
+		}
+	}
+
+	withAddr := b12.Copy()
+	withAddr.AddAt(two, "10.0.0.2:10000")
+	if !withAddr.Equals(b12) {
+		t.Error("Learning the address of a node changed the blueprint")
+	}
+}
@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func w(id, client int, val string, inv, ret int64) Op {
+	return Op{ID: id, Client: client, Kind: Write, Value: []byte(val), Invoke: inv, Return: ret}
+}
+
+func r(id, client int, kind Kind, val string, inv, ret int64) Op {
+	return Op{ID: id, Client: client, Kind: kind, Value: []byte(val), Invoke: inv, Return: ret}
+}
+
+func ids(ops []Op) (res []int) {
+	for _, op := range ops {
+		res = append(res, op.ID)
+	}
+	return res
+}
+
+var linTests = []struct {
+	name string
+	h    []Op
+	ok   bool
+	cex  []int // ids of the expected counterexample
+}{
+	{"empty", nil, true, nil},
+	{"initial", []Op{r(0, 1, Read, "", 1, 2)}, true, nil},
+	{"sequential", []Op{w(0, 1, "a", 1, 2), r(1, 2, Read, "a", 3, 4), w(2, 1, "b", 5, 6), r(3, 2, Read, "b", 7, 8)}, true, nil},
+	{"concurrent", []Op{w(0, 1, "a", 1, 10), r(1, 2, Read, "", 2, 3), r(2, 2, Read, "a", 4, 5), r(3, 3, Read, "a", 6, 12)}, true, nil},
+	{"pending write", []Op{w(0, 1, "a", 1, 0), r(1, 2, Read, "", 2, 3), r(2, 2, Read, "a", 4, 5)}, true, nil},
+	{"unknown value", []Op{w(0, 1, "a", 1, 2), r(1, 2, Read, "x", 3, 4)}, false, []int{1}},
+	{"stale read", []Op{
+		w(0, 1, "a", 1, 2),
+		r(1, 2, Read, "a", 3, 4),
+		w(2, 1, "b", 5, 6),
+		r(3, 3, Read, "b", 6, 7),
+		r(4, 2, Read, "a", 8, 9),
+		w(5, 1, "c", 10, 11),
+		r(6, 2, Read, "c", 12, 13),
+	}, false, []int{0, 2, 4}},
+	{"new-old inversion", []Op{
+		w(0, 1, "a", 1, 2),
+		w(1, 1, "b", 3, 20),
+		r(2, 2, Read, "b", 4, 5),
+		r(3, 3, Read, "a", 6, 7),
+		r(4, 2, Read, "b", 8, 9),
+	}, false, []int{0, 1, 2, 3}},
+}
+
+func TestCheckLinearizable(t *testing.T) {
+	for _, test := range linTests {
+		v := CheckLinearizable(test.h, "")
+		if (v == nil) != test.ok {
+			t.Errorf("%s: got violation %v, expected linearizable = %t", test.name, v, test.ok)
+			continue
+		}
+		if v != nil && !reflect.DeepEqual(ids(v.Ops), test.cex) {
+			t.Errorf("%s: got counterexample %v, expected ops %v", test.name, ids(v.Ops), test.cex)
+		}
+	}
+}
+
+func TestCheckRegular(t *testing.T) {
+	regTests := []struct {
+		name string
+		h    []Op
+		ok   bool
+		cex  []int
+	}{
+		{"concurrent old value", []Op{w(0, 1, "a", 1, 2), w(1, 1, "b", 3, 10), r(2, 2, RRead, "a", 4, 5)}, true, nil},
+		{"concurrent new value", []Op{w(0, 1, "a", 1, 2), w(1, 1, "b", 3, 10), r(2, 2, RRead, "b", 4, 5)}, true, nil},
+		{"new-old inversion", []Op{
+			w(0, 1, "a", 1, 2),
+			w(1, 1, "b", 3, 20),
+			r(2, 2, RRead, "b", 4, 5),
+			r(3, 3, RRead, "a", 6, 7),
+		}, true, nil},
+		{"overwritten value", []Op{w(0, 1, "a", 1, 2), w(1, 1, "b", 3, 4), w(2, 1, "c", 5, 6), r(3, 2, RRead, "a", 7, 8)}, false, []int{0, 1, 3}},
+		{"initial after write", []Op{w(0, 1, "a", 1, 2), r(1, 2, RRead, "", 3, 4)}, false, []int{0, 1}},
+		{"future value", []Op{r(0, 2, RRead, "a", 1, 2), w(1, 1, "a", 3, 4)}, false, []int{0}},
+	}
+
+	for _, test := range regTests {
+		v := CheckRegular(test.h, "")
+		if (v == nil) != test.ok {
+			t.Errorf("%s: got violation %v, expected regular = %t", test.name, v, test.ok)
+			continue
+		}
+		if v != nil && !reflect.DeepEqual(ids(v.Ops), test.cex) {
+			t.Errorf("%s: got counterexample %v, expected ops %v", test.name, ids(v.Ops), test.cex)
+		}
+	}
+}
+
+func TestCheckKeys(t *testing.T) {
+	h := []Op{
+		{ID: 0, Kind: Write, Key: "x", Value: []byte("a"), Invoke: 1, Return: 2},
+		{ID: 1, Kind: Reconf, Invoke: 2, Return: 3},
+		{ID: 2, Kind: Read, Key: "y", Value: []byte("a"), Invoke: 3, Return: 4},
+		{ID: 3, Kind: Read, Key: "x", Value: []byte("a"), Invoke: 3, Return: 4},
+	}
+	vs := Check(h)
+	if len(vs) != 1 || vs[0].Key != "y" {
+		t.Fatalf("expected a single violation on register y, got %v", vs)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var clock int64
+	rec := NewRecorder()
+	rec.Clock = func() int64 { clock++; return clock }
+
+	wr := rec.Invoke(1, Write, "", []byte("a"))
+	rd := rec.Invoke(2, Read, "", nil)
+	rec.Return(wr, nil, true)
+	rec.Return(rd, []byte("a"), true)
+	failed := rec.Invoke(2, Write, "", []byte("b"))
+	rec.Return(failed, nil, false)
+
+	h := rec.History()
+	want := []Op{w(0, 1, "a", 1, 3), r(1, 2, Read, "a", 2, 4), w(2, 2, "b", 5, 0)}
+	if !reflect.DeepEqual(h, want) {
+		t.Fatalf("recorded %v, expected %v", h, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHistory(&buf, h); err != nil {
+		t.Fatal(err)
+	}
+	h2, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(h, h2) {
+		t.Errorf("read history %v, expected %v", h2, h)
+	}
+	if vs := Check(h); len(vs) != 0 {
+		t.Errorf("unexpected violations: %v", vs)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Package checker records histories of register operations, and checks them
+for atomicity (linearizability) and regularity.
+
+Each register (key) is checked on its own. The checker assumes the register
+initially holds the empty value. Written values should be unique per register,
+otherwise a read can be matched to the wrong write, and violations may go
+unnoticed.
+*/
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of an operation.
+type Kind int
+
+const (
+	Write Kind = iota
+	Read       // Atomic read.
+	RRead      // Regular read.
+	Reconf
+)
+
+var kindNames = [...]string{"Write", "Read", "RRead", "Reconf"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// Op is an operation in a history.
+type Op struct {
+	ID     int
+	Client int
+	Kind   Kind
+	Key    string
+	Value  []byte // The value written or read.
+	Invoke int64
+	// Return is 0, if the operation did not return or failed.
+	// A write that did not return may or may not have taken effect.
+	Return int64
+}
+
+// Pending reports whether the operation did not return.
+func (op Op) Pending() bool {
+	return op.Return == 0
+}
+
+func (op Op) String() string {
+	ret := "pending"
+	if !op.Pending() {
+		ret = fmt.Sprint(op.Return)
+	}
+	switch op.Kind {
+	case Write, Read, RRead:
+		return fmt.Sprintf("#%d C%d %v(%q)=%x [%d, %s]", op.ID, op.Client, op.Kind, op.Key, op.Value, op.Invoke, ret)
+	default:
+		return fmt.Sprintf("#%d C%d %v [%d, %s]", op.ID, op.Client, op.Kind, op.Invoke, ret)
+	}
+}
+
+// Recorder records a history of operations. It is safe for concurrent use.
+type Recorder struct {
+	// Clock returns the current time. The default is the wall clock in nanoseconds.
+	// Histories recorded by different processes can only be combined, if they use the same clock.
+	Clock func() int64
+
+	mu  sync.Mutex
+	ops []Op
+}
+
+// NewRecorder returns an empty recorder using the wall clock.
+func NewRecorder() *Recorder {
+	return &Recorder{Clock: func() int64 { return time.Now().UnixNano() }}
+}
+
+// Invoke records the invocation of an operation, and returns its id.
+// For reads, val is ignored.
+func (r *Recorder) Invoke(client int, kind Kind, key string, val []byte) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	op := Op{
+		ID:     len(r.ops),
+		Client: client,
+		Kind:   kind,
+		Key:    key,
+		Invoke: r.Clock(),
+	}
+	if kind == Write {
+		op.Value = append([]byte(nil), val...)
+	}
+	r.ops = append(r.ops, op)
+	return op.ID
+}
+
+// Return records the response to the operation id. For reads, val is the value read.
+// If ok is false, the operation failed, and is treated as if it did not return.
+func (r *Recorder) Return(id int, val []byte, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !ok {
+		return
+	}
+	op := &r.ops[id]
+	op.Return = r.Clock()
+	if op.Kind == Read || op.Kind == RRead {
+		op.Value = append([]byte(nil), val...)
+	}
+}
+
+// History returns a copy of the operations recorded so far.
+func (r *Recorder) History() []Op {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Op(nil), r.ops...)
+}
+
+// WriteHistory writes h to w, one JSON object per operation.
+func WriteHistory(w io.Writer, h []Op) error {
+	enc := json.NewEncoder(w)
+	for _, op := range h {
+		if err := enc.Encode(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadHistory reads a history written by WriteHistory.
+func ReadHistory(r io.Reader) (h []Op, err error) {
+	dec := json.NewDecoder(r)
+	for {
+		var op Op
+		if err = dec.Decode(&op); err == io.EOF {
+			return h, nil
+		} else if err != nil {
+			return nil, err
+		}
+		h = append(h, op)
+	}
+}
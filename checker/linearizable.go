@@ -0,0 +1,288 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Violation reports that a history is not linearizable, or not regular.
+type Violation struct {
+	Key    string
+	Reason string
+	// Ops is a minimal counterexample: a part of the history, which already violates the property.
+	Ops []Op
+}
+
+func (v *Violation) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "register %q: %s", v.Key, v.Reason)
+	for _, op := range v.Ops {
+		fmt.Fprintf(&buf, "\n\t%v", op)
+	}
+	return buf.String()
+}
+
+// Check checks that the atomic reads and the writes in h are linearizable, and that the regular reads are regular.
+// It returns one violation for each register, that violates one of the properties.
+func Check(h []Op) (vs []*Violation) {
+	for _, key := range keys(h) {
+		if v := CheckLinearizable(h, key); v != nil {
+			vs = append(vs, v)
+		}
+		if v := CheckRegular(h, key); v != nil {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// CheckLinearizable checks, whether the writes and atomic reads of register key in h are linearizable.
+// It uses the algorithm by Wing and Gong, with the state cache introduced by Lowe.
+// If the history is not linearizable, it returns a violation with a minimal counterexample.
+func CheckLinearizable(h []Op, key string) *Violation {
+	var ops []Op
+	for _, op := range h {
+		if op.Key != key {
+			continue
+		}
+		switch {
+		case op.Kind == Write:
+			ops = append(ops, op)
+		case op.Kind == Read && !op.Pending():
+			ops = append(ops, op)
+		}
+	}
+
+	if linearizable(ops) {
+		return nil
+	}
+	return &Violation{
+		Key:    key,
+		Reason: "history is not linearizable",
+		Ops:    minimize(shortestPrefix(ops)),
+	}
+}
+
+// entry is a call or return event in the list used by linearizable.
+type entry struct {
+	op         int // index in ops
+	call       bool
+	time       int64
+	match      *entry // The return entry for a call, and vice versa.
+	prev, next *entry
+}
+
+// linearizable checks whether ops is linearizable.
+func linearizable(ops []Op) bool {
+	entries := make([]*entry, 0, 2*len(ops))
+	for i, op := range ops {
+		ret := op.Return
+		if op.Pending() {
+			ret = math.MaxInt64
+		}
+		c := &entry{op: i, call: true, time: op.Invoke}
+		r := &entry{op: i, time: ret, match: c}
+		c.match = r
+		entries = append(entries, c, r)
+	}
+	// Sort by time, calls before returns, such that operations with equal times are concurrent.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].time != entries[j].time {
+			return entries[i].time < entries[j].time
+		}
+		return entries[i].call && !entries[j].call
+	})
+
+	head := new(entry)
+	prev := head
+	for _, e := range entries {
+		prev.next = e
+		e.prev = prev
+		prev = e
+	}
+
+	type frame struct {
+		e     *entry
+		state string
+	}
+	var (
+		stack      []frame
+		state      string // The register holds the empty value initially.
+		linearized = make([]uint64, (len(ops)+63)/64)
+		cache      = make(map[string]bool)
+	)
+
+	cur := head.next
+	for head.next != nil {
+		if cur.call {
+			op := ops[cur.op]
+			newState := state
+			legal := true
+			switch op.Kind {
+			case Write:
+				newState = string(op.Value)
+			default:
+				legal = string(op.Value) == state
+			}
+			if legal {
+				setBit(linearized, cur.op)
+				ck := cacheKey(linearized, newState)
+				if !cache[ck] {
+					cache[ck] = true
+					stack = append(stack, frame{cur, state})
+					state = newState
+					lift(cur)
+					cur = head.next
+					continue
+				}
+				clearBit(linearized, cur.op)
+			}
+			cur = cur.next
+			continue
+		}
+
+		// A return entry: The operation was not linearized before it returned.
+		if len(stack) == 0 {
+			return false
+		}
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		state = f.state
+		clearBit(linearized, f.e.op)
+		unlift(f.e)
+		cur = f.e.next
+	}
+	return true
+}
+
+func lift(e *entry) {
+	e.prev.next = e.next
+	if e.next != nil {
+		e.next.prev = e.prev
+	}
+	m := e.match
+	m.prev.next = m.next
+	if m.next != nil {
+		m.next.prev = m.prev
+	}
+}
+
+func unlift(e *entry) {
+	m := e.match
+	m.prev.next = m
+	if m.next != nil {
+		m.next.prev = m
+	}
+	e.prev.next = e
+	if e.next != nil {
+		e.next.prev = e
+	}
+}
+
+func setBit(bs []uint64, i int)   { bs[i/64] |= 1 << uint(i%64) }
+func clearBit(bs []uint64, i int) { bs[i/64] &^= 1 << uint(i%64) }
+
+func cacheKey(bs []uint64, state string) string {
+	var buf bytes.Buffer
+	for _, w := range bs {
+		fmt.Fprintf(&buf, "%x,", w)
+	}
+	buf.WriteString(state)
+	return buf.String()
+}
+
+// shortestPrefix returns the shortest prefix of the non-linearizable ops, that is not linearizable.
+func shortestPrefix(ops []Op) []Op {
+	var times []int64
+	for _, op := range ops {
+		if !op.Pending() {
+			times = append(times, op.Return)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	i := sort.Search(len(times), func(i int) bool {
+		return !linearizable(truncate(ops, times[i]))
+	})
+	if i == len(times) {
+		return ops
+	}
+	return truncate(ops, times[i])
+}
+
+// truncate removes all operations invoked after time t, and treats operations returning after t as pending.
+// Since linearizability is prefix closed, ops truncated at the time of a failure are still not linearizable.
+func truncate(ops []Op, t int64) []Op {
+	var tops []Op
+	for _, op := range ops {
+		if op.Invoke > t {
+			continue
+		}
+		if op.Return > t {
+			if op.Kind != Write {
+				continue
+			}
+			op.Return = 0
+		}
+		tops = append(tops, op)
+	}
+	return tops
+}
+
+// minimize removes operations from the non-linearizable ops, as long as the remainder is not linearizable.
+// Only reads, and writes whose value is not read, are removed. Removing them from a linearizable history
+// gives a linearizable history, thus the result is a counterexample for ops.
+func minimize(ops []Op) []Op {
+	for size := len(ops) / 2; size >= 1; size /= 2 {
+		for changed := true; changed; {
+			changed = false
+			for i := 0; i < len(ops); i += size {
+				end := i + size
+				if end > len(ops) {
+					end = len(ops)
+				}
+				rest := append(append([]Op(nil), ops[:i]...), ops[end:]...)
+				if !removable(ops[i:end], rest) {
+					continue
+				}
+				if !linearizable(rest) {
+					ops = rest
+					changed = true
+					i -= size
+				}
+			}
+		}
+	}
+	return ops
+}
+
+// removable reports whether no operation in rest reads a value written in rem.
+func removable(rem, rest []Op) bool {
+	for _, w := range rem {
+		if w.Kind != Write {
+			continue
+		}
+		for _, r := range rest {
+			if r.Kind != Write && bytes.Equal(r.Value, w.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// keys returns the sorted keys of the registers in h.
+func keys(h []Op) []string {
+	seen := make(map[string]bool)
+	var ks []string
+	for _, op := range h {
+		if op.Kind == Reconf || seen[op.Key] {
+			continue
+		}
+		seen[op.Key] = true
+		ks = append(ks, op.Key)
+	}
+	sort.Strings(ks)
+	return ks
+}
@@ -0,0 +1,97 @@
+package checker
+
+import (
+	"bytes"
+	"sort"
+)
+
+// CheckRegular checks, whether the regular reads of register key in h are regular:
+// A read must return the value of a write, that was invoked before the read returned,
+// and that was not overwritten before the read was invoked. A write w is overwritten,
+// if another write is invoked after w returned, and returns before the read is invoked.
+// The empty initial value may only be read, if no write returned before the read was invoked.
+//
+// CheckRegular reports the first read that violates regularity, together with the writes showing the violation.
+func CheckRegular(h []Op, key string) *Violation {
+	var writes, reads []Op
+	for _, op := range h {
+		if op.Key != key {
+			continue
+		}
+		switch {
+		case op.Kind == Write:
+			writes = append(writes, op)
+		case op.Kind == RRead && !op.Pending():
+			reads = append(reads, op)
+		}
+	}
+	sort.Slice(reads, func(i, j int) bool { return reads[i].Return < reads[j].Return })
+
+	for _, r := range reads {
+		if ops := irregular(r, writes); ops != nil {
+			return &Violation{
+				Key:    key,
+				Reason: "regular read returned an old or unknown value",
+				Ops:    ops,
+			}
+		}
+	}
+	return nil
+}
+
+// irregular returns nil if the read r is regular. Otherwise, it returns r and the writes showing the violation.
+func irregular(r Op, writes []Op) []Op {
+	// overwriting returns a write that overwrites w before r is invoked, or nil.
+	overwriting := func(w *Op) *Op {
+		for i := range writes {
+			o := &writes[i]
+			if o.Pending() || o.Return >= r.Invoke {
+				continue
+			}
+			if w == nil || (!w.Pending() && w.Return < o.Invoke) {
+				return o
+			}
+		}
+		return nil
+	}
+
+	ops := []Op{r}
+	if len(r.Value) == 0 {
+		// The initial value.
+		o := overwriting(nil)
+		if o == nil {
+			return nil
+		}
+		return sortByInvoke(append(ops, *o))
+	}
+
+	for i := range writes {
+		w := &writes[i]
+		if !bytes.Equal(w.Value, r.Value) || w.Invoke >= r.Return {
+			continue
+		}
+		o := overwriting(w)
+		if o == nil {
+			return nil
+		}
+		ops = append(ops, *w, *o)
+	}
+	return sortByInvoke(dedup(ops))
+}
+
+func dedup(ops []Op) []Op {
+	seen := make(map[int]bool, len(ops))
+	res := ops[:0]
+	for _, op := range ops {
+		if !seen[op.ID] {
+			seen[op.ID] = true
+			res = append(res, op)
+		}
+	}
+	return res
+}
+
+func sortByInvoke(ops []Op) []Op {
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Invoke < ops[j].Invoke })
+	return ops
+}
@@ -0,0 +1,507 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/rsm"
+	smc "github.com/relab/smartmerge/smclient"
+	"github.com/relab/smartmerge/util"
+	"golang.org/x/net/context"
+)
+
+// cmdResult is printed as JSON by the subcommands.
+type cmdResult struct {
+	Command   string        `json:"command"`
+	Key       string        `json:"key"`
+	Value     *string       `json:"value,omitempty"`
+	Cnt       int           `json:"cnt"`
+	Blueprint *bp.Blueprint `json:"blueprint,omitempty"`
+	// Quorum sizes of the blueprint, only reported by status.
+	ReadQuorum  int    `json:"read_quorum,omitempty"`
+	WriteQuorum int    `json:"write_quorum,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// Errors are the last connection errors of each server, see PrintErrors.
+	Errors map[uint32]string `json:"errors,omitempty"`
+	// Servers is the state of each server, only reported by servers.
+	Servers []*serverView `json:"servers,omitempty"`
+	// Versions of the register, reported by versions, read-asof and watch.
+	Versions []*version `json:"versions,omitempty"`
+}
+
+// version is a version of a register, as reported by versions, read-asof and watch.
+type version struct {
+	Timestamp int32  `json:"timestamp"`
+	Writer    uint32 `json:"writer"`
+	Value     string `json:"value"`
+}
+
+// commands maps the name of each subcommand to its usage, the minimal and maximal
+// number of arguments, and its implementation.
+var commands = map[string]struct {
+	usage            string
+	minArgs, maxArgs int
+	run              func(c *cmdClient, args []string, res *cmdResult) error
+	noClient         bool // The command only uses the manager, it does not create a register client.
+}{
+	"read":                {"read", 0, 0, (*cmdClient).read, false},
+	"rread":               {"rread", 0, 0, (*cmdClient).rread, false},
+	"write":               {"write <value>", 1, 1, (*cmdClient).write, false},
+	"add":                 {"add <id | host:port>", 1, 1, (*cmdClient).add, false},
+	"remove":              {"remove <id>", 1, 1, (*cmdClient).remove, false},
+	"replace":             {"replace <id> <id | host:port>", 2, 2, (*cmdClient).replace, false},
+	"status":              {"status", 0, 0, (*cmdClient).status, false},
+	"set-fault-tolerance": {"set-fault-tolerance <ft> [<readft>]", 1, 2, (*cmdClient).setFaultTolerance, false},
+	"counter":             {"counter", 0, 0, (*cmdClient).counter, false},
+	"counter-add":         {"counter-add <n>", 1, 1, (*cmdClient).counterAdd, false},
+	"servers":             {"servers", 0, 0, (*cmdClient).servers, true},
+	"read-asof":           {"read-asof <timestamp>", 1, 1, (*cmdClient).readAsOf, false},
+	"versions":            {"versions <n>", 1, 1, (*cmdClient).versions, false},
+	"watch":               {"watch <n>", 1, 1, (*cmdClient).watch, false},
+}
+
+// cmdUsage prints the usage of the subcommands.
+func cmdUsage() {
+	fmt.Fprintf(os.Stderr, "\nCommands:\n")
+	for _, name := range []string{"read", "rread", "write", "add", "remove", "replace", "status", "set-fault-tolerance", "counter", "counter-add", "servers", "read-asof", "versions", "watch"} {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+type cmdClient struct {
+	ctx   context.Context
+	cl    RWRer
+	cp    conf.Provider
+	mgr   *pb.Manager
+	ids   []uint32
+	addrs []string
+}
+
+// cmdmain runs the subcommand args[0] with arguments args[1:], and prints the result as JSON.
+// It returns false, if the command failed.
+func cmdmain(args []string) bool {
+	res := &cmdResult{Command: args[0], Key: *key}
+	err := runCmd(args, res)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	if err := enc.Encode(res); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not print result:", err)
+		return false
+	}
+	return res.Error == ""
+}
+
+func runCmd(args []string, res *cmdResult) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	if n := len(args) - 1; n < cmd.minArgs || n > cmd.maxArgs {
+		return fmt.Errorf("usage: %s", cmd.usage)
+	}
+
+	addrs, ids := util.GetProcs(*confFile, false)
+	if len(ids) == 0 {
+		return fmt.Errorf("no servers in configuration file %s", *confFile)
+	}
+	if *initsize > len(ids) {
+		return errors.New("not enough servers to fulfill initsize")
+	}
+
+	initBlp := new(bp.Blueprint)
+	initBlp.Nodes = make([]*bp.Node, 0, len(ids))
+	for i, id := range ids {
+		if i >= *initsize {
+			break
+		}
+		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id, Addr: addrs[i]})
+	}
+	initBlp.FaultTolerance = uint32(*ft)
+	initBlp.ReadFaultTolerance = uint32(*readft)
+
+	cp, mgr, err := NewConfP(addrs, *cprov, *clientid)
+	if err != nil {
+		return err
+	}
+	defer mgr.Close()
+	defer func() {
+		errs := GetErrors(mgr)
+		if len(errs) == 0 {
+			return
+		}
+		res.Errors = make(map[uint32]string, len(errs))
+		for id, e := range errs {
+			res.Errors[id] = e.Error()
+		}
+	}()
+
+	ctx, cancel := opContext()
+	defer cancel()
+	c := &cmdClient{ctx: ctx, cp: cp, mgr: mgr, ids: ids, addrs: addrs}
+	if cmd.noClient {
+		return cmd.run(c, args[1:], res)
+	}
+
+	cl, err := NewClient(initBlp, *alg, *opt, *clientid, cp)
+	if err != nil {
+		return err
+	}
+	c.cl = cl
+	err = cmd.run(c, args[1:], res)
+	res.Blueprint = cl.GetCur()
+	return err
+}
+
+func (c *cmdClient) read(args []string, res *cmdResult) error {
+	val, cnt, err := c.cl.Read(c.ctx, c.cp, *key)
+	return res.setValue(val, cnt, err)
+}
+
+func (c *cmdClient) rread(args []string, res *cmdResult) error {
+	val, cnt, err := c.cl.RRead(c.ctx, c.cp, *key)
+	return res.setValue(val, cnt, err)
+}
+
+func (c *cmdClient) write(args []string, res *cmdResult) error {
+	var err error
+	res.Cnt, err = c.cl.Write(c.ctx, c.cp, *key, []byte(args[0]))
+	if err != nil {
+		return err
+	}
+	res.Value = &args[0]
+	return nil
+}
+
+func (res *cmdResult) setValue(val []byte, cnt int, err error) error {
+	res.Cnt = cnt
+	if err != nil {
+		return err
+	}
+	s := string(val)
+	res.Value = &s
+	return nil
+}
+
+// learnCur performs a regular read, to learn the current configuration.
+// Since the client starts with the initial configuration from the configuration file,
+// reconfigurations must be based on the current configuration.
+func (c *cmdClient) learnCur(res *cmdResult) (*bp.Blueprint, error) {
+	_, cnt, err := c.cl.RRead(c.ctx, c.cp, *key)
+	res.Cnt += cnt
+	if err != nil {
+		return nil, err
+	}
+	return c.cl.GetCur(), nil
+}
+
+func (c *cmdClient) reconf(target *bp.Blueprint, res *cmdResult) error {
+	cnt, err := c.cl.Reconf(c.ctx, c.cp, target)
+	res.Cnt += cnt
+	return err
+}
+
+func (c *cmdClient) status(args []string, res *cmdResult) error {
+	cur, err := c.learnCur(res)
+	if err != nil {
+		return err
+	}
+	res.ReadQuorum = cur.ReadQuorum()
+	res.WriteQuorum = cur.Quorum()
+	return nil
+}
+
+func (c *cmdClient) add(args []string, res *cmdResult) error {
+	id, addr, err := parseNode(args[0], c.ids, c.addrs)
+	if err != nil {
+		return err
+	}
+	cur, err := c.learnCur(res)
+	if err != nil {
+		return err
+	}
+	if !cur.AddAt(id, addr) {
+		return fmt.Errorf("node %d was already added", id)
+	}
+	return c.reconf(cur, res)
+}
+
+func (c *cmdClient) remove(args []string, res *cmdResult) error {
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	cur, err := c.learnCur(res)
+	if err != nil {
+		return err
+	}
+	if !cur.Rem(id) {
+		return fmt.Errorf("node %d is not part of the current configuration", id)
+	}
+	return c.reconf(cur, res)
+}
+
+func (c *cmdClient) replace(args []string, res *cmdResult) error {
+	old, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	id, addr, err := parseNode(args[1], c.ids, c.addrs)
+	if err != nil {
+		return err
+	}
+	cur, err := c.learnCur(res)
+	if err != nil {
+		return err
+	}
+	if !cur.Rem(old) {
+		return fmt.Errorf("node %d is not part of the current configuration", old)
+	}
+	if !cur.AddAt(id, addr) {
+		return fmt.Errorf("node %d was already added", id)
+	}
+	return c.reconf(cur, res)
+}
+
+// setFaultTolerance sets the fault tolerance, and optionally the read fault tolerance.
+// The epoch is increased, such that the new values are used, even if they are smaller.
+func (c *cmdClient) setFaultTolerance(args []string, res *cmdResult) error {
+	ft, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	var readft uint32
+	if len(args) > 1 {
+		if readft, err = parseID(args[1]); err != nil {
+			return err
+		}
+	}
+	cur, err := c.learnCur(res)
+	if err != nil {
+		return err
+	}
+	cur.Epoch++
+	cur.FaultTolerance = ft
+	cur.ReadFaultTolerance = readft
+	return c.reconf(cur, res)
+}
+
+// newCounter returns the replicated counter named by -key.
+func (c *cmdClient) newCounter() (*rsm.Counter, error) {
+	cl := c.cl
+	if rc, ok := cl.(*recordingRWRer); ok {
+		cl = rc.RWRer
+	}
+	a, ok := cl.(rsm.Agreer)
+	if !ok {
+		return nil, fmt.Errorf("algorithm %s with optimization %q does not support lattice agreement", *alg, *opt)
+	}
+	return rsm.NewCounter(a, c.cp, "counter/"+*key, uint32(*clientid)), nil
+}
+
+func (c *cmdClient) counter(args []string, res *cmdResult) error {
+	cntr, err := c.newCounter()
+	if err != nil {
+		return err
+	}
+	v, cnt, err := cntr.Value(c.ctx)
+	return res.setCounter(v, cnt, err)
+}
+
+func (c *cmdClient) counterAdd(args []string, res *cmdResult) error {
+	n, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("could not parse %q: %v", args[0], err)
+	}
+	cntr, err := c.newCounter()
+	if err != nil {
+		return err
+	}
+	v, cnt, err := cntr.Add(c.ctx, n)
+	return res.setCounter(v, cnt, err)
+}
+
+func (res *cmdResult) setCounter(v int64, cnt int, err error) error {
+	res.Cnt = cnt
+	if err != nil {
+		return err
+	}
+	s := strconv.FormatInt(v, 10)
+	res.Value = &s
+	return nil
+}
+
+// historian is implemented by the clients, that can read earlier versions of a register.
+type historian interface {
+	ReadAsOf(ctx context.Context, cp conf.Provider, key string, ts int32) (*pb.State, int, error)
+	Versions(ctx context.Context, cp conf.Provider, key string, n int) ([]*pb.State, int, error)
+}
+
+func (c *cmdClient) historian() (historian, error) {
+	cl := c.cl
+	if rc, ok := cl.(*recordingRWRer); ok {
+		cl = rc.RWRer
+	}
+	h, ok := cl.(historian)
+	if !ok {
+		return nil, fmt.Errorf("algorithm %s with optimization %q does not support reading earlier versions", *alg, *opt)
+	}
+	return h, nil
+}
+
+func (c *cmdClient) readAsOf(args []string, res *cmdResult) error {
+	ts, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("could not parse %q: %v", args[0], err)
+	}
+	h, err := c.historian()
+	if err != nil {
+		return err
+	}
+	st, cnt, err := h.ReadAsOf(c.ctx, c.cp, *key, int32(ts))
+	res.Cnt = cnt
+	if err != nil {
+		return err
+	}
+	res.Versions = []*version{{st.Timestamp, st.Writer, string(st.Value)}}
+	res.Value = &res.Versions[0].Value
+	return nil
+}
+
+func (c *cmdClient) versions(args []string, res *cmdResult) error {
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("could not parse %q: %v", args[0], err)
+	}
+	h, err := c.historian()
+	if err != nil {
+		return err
+	}
+	vs, cnt, err := h.Versions(c.ctx, c.cp, *key, n)
+	res.Cnt = cnt
+	if err != nil {
+		return err
+	}
+	res.Versions = make([]*version, len(vs))
+	for i, v := range vs {
+		res.Versions[i] = &version{v.Timestamp, v.Writer, string(v.Value)}
+	}
+	return nil
+}
+
+// watcher is implemented by the clients, that can watch a register.
+type watcher interface {
+	Watch(ctx context.Context, cp conf.Provider, sub conf.Subscriber, key string) (*smc.Watcher, error)
+}
+
+func (c *cmdClient) watch(args []string, res *cmdResult) error {
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("could not parse %q: %v", args[0], err)
+	}
+	cl := c.cl
+	if rc, ok := cl.(*recordingRWRer); ok {
+		cl = rc.RWRer
+	}
+	wc, ok := cl.(watcher)
+	if !ok {
+		return fmt.Errorf("algorithm %s with optimization %q does not support watching", *alg, *opt)
+	}
+	w, err := wc.Watch(c.ctx, c.cp, conf.NewSubscriber(c.mgr), *key)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	for len(res.Versions) < n {
+		st, _, err := w.Next()
+		if err != nil {
+			return err
+		}
+		if st != nil {
+			res.Versions = append(res.Versions, &version{st.Timestamp, st.Writer, string(st.Value)})
+		}
+	}
+	return nil
+}
+
+func parseID(s string) (uint32, error) {
+	x, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse %q: %v", s, err)
+	}
+	return uint32(x), nil
+}
+
+// serverView is the state of one server, as reported by the servers command.
+type serverView struct {
+	Id        uint32               `json:"id"`
+	Addr      string               `json:"addr"`
+	Error     string               `json:"error,omitempty"`
+	Uptime    string               `json:"uptime,omitempty"`
+	Cur       *bp.Blueprint        `json:"cur,omitempty"`
+	CurC      []byte               `json:"curc,omitempty"` // The id of Cur, as stored by the server.
+	LAState   *bp.Blueprint        `json:"lastate,omitempty"`
+	Next      []*bp.Blueprint      `json:"next,omitempty"`
+	Registers []*pb.RegisterStatus `json:"registers,omitempty"`
+	Paxos     []*pb.PaxosStatus    `json:"paxos,omitempty"`
+	// Lagging is set, if the server did not install the highest Cur reported by any server.
+	Lagging bool `json:"lagging,omitempty"`
+	// Unresolved is set, if the server holds Next blueprints, that were not installed yet.
+	Unresolved bool `json:"unresolved,omitempty"`
+}
+
+// servers queries the status of every server in the configuration file.
+// The blueprint of the result is the highest Cur reported by any server.
+func (c *cmdClient) servers(args []string, res *cmdResult) error {
+	res.Servers = make([]*serverView, len(c.ids))
+	var wg sync.WaitGroup
+	for i, id := range c.ids {
+		v := &serverView{Id: id, Addr: c.addrs[i]}
+		res.Servers[i] = v
+		node, found := c.mgr.Node(id)
+		if !found {
+			v.Error = pb.NodeNotFoundError(id).Error()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			st, err := node.SMandConsRegisterClient.Status(c.ctx, &pb.StatusRequest{})
+			if err != nil {
+				v.Error = err.Error()
+				return
+			}
+			v.Uptime = time.Duration(st.Uptime).String()
+			v.Cur, v.CurC, v.LAState, v.Next = st.Cur, st.CurC, st.LAState, st.Next
+			v.Registers, v.Paxos = st.Registers, st.Paxos
+		}()
+	}
+	wg.Wait()
+
+	var failed int
+	for _, v := range res.Servers {
+		if v.Error != "" {
+			failed++
+		}
+		if v.Cur != nil && (res.Blueprint == nil || res.Blueprint.LearnedCompare(v.Cur) == 1) {
+			res.Blueprint = v.Cur
+		}
+	}
+	for _, v := range res.Servers {
+		v.Lagging = v.Error == "" && (v.Cur == nil || (res.Blueprint != nil && !v.Cur.LearnedEquals(res.Blueprint)))
+		v.Unresolved = len(v.Next) > 0
+	}
+	if failed == len(res.Servers) {
+		return errors.New("no server replied")
+	}
+	return nil
+}
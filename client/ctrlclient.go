@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golang/glog"
+	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/controller"
+	"github.com/relab/smartmerge/util"
+)
+
+// ctrlmain runs a controller, that replaces failed servers of the configuration with spare servers.
+// The spare servers are the servers in the configuration file, that are not in the initial configuration.
+func ctrlmain() {
+	addrs, ids := util.GetProcs(*confFile, false)
+
+	//Build initial blueprint.
+	if *initsize > len(ids) && *initsize < 100 {
+		glog.Errorln("Not enough servers to fulfill initsize.")
+		return
+	}
+
+	initBlp := new(bp.Blueprint)
+	initBlp.Nodes = make([]*bp.Node, 0, len(ids))
+	var spares []uint32
+	for i, id := range ids {
+		if i >= *initsize {
+			spares = append(spares, id)
+			continue
+		}
+		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id, Addr: addrs[i]})
+	}
+	initBlp.FaultTolerance = uint32(*ft)
+	initBlp.ReadFaultTolerance = uint32(*readft)
+
+	cp, mgr, err := NewConfP(addrs, *cprov, *clientid)
+	if err != nil {
+		glog.Errorln("Error creating confProvider: ", err)
+		return
+	}
+	defer PrintErrors(mgr)
+
+	cl, err := NewClient(initBlp, *alg, *opt, *clientid, cp)
+	if err != nil {
+		glog.Errorln("Error creating client: ", err)
+		return
+	}
+	if *useleader {
+		if cl, err = createForwarder(cl, mgr, ids[len(ids)-1]); err != nil {
+			glog.Errorln("Error creating forwarder:", err)
+			return
+		}
+	}
+
+	ctrl := controller.New(cl, cp, controller.StatusProber(mgr), spares)
+	ctrl.Threshold = *suspectAfter
+	ctrl.MinInterval = *minReconf
+	ctrl.Interval = *probeEvery
+	glog.Infof("Starting controller for %v with spares %v.\n", initBlp.Ids(), spares)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(stop)
+		close(done)
+	}()
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, os.Kill, syscall.SIGTERM)
+	for signal := range signalChan {
+		if exit := handleSignal(signal); exit {
+			break
+		}
+	}
+	close(stop)
+	<-done
+	glog.Infoln("Controller stopped with configuration", cl.GetCur().Ids())
+}
@@ -17,18 +17,22 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/relab/smartmerge/auth"
 	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/checker"
 	conf "github.com/relab/smartmerge/confProvider"
 	cc "github.com/relab/smartmerge/consclient"
 	"github.com/relab/smartmerge/doreconf"
-	//dyna "github.com/relab/smartmerge/dynaclient"
+	dyna "github.com/relab/smartmerge/dynaclient"
 	"github.com/relab/smartmerge/elog"
 	e "github.com/relab/smartmerge/elog/event"
 	pb "github.com/relab/smartmerge/proto"
 	smc "github.com/relab/smartmerge/smclient"
-	//ssr "github.com/relab/smartmerge/ssrclient"
+	ssr "github.com/relab/smartmerge/ssrclient"
+	"github.com/relab/smartmerge/tlsconf"
 	"github.com/relab/smartmerge/util"
 	"github.com/relab/smartmerge/util/bgen"
+	"golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
 )
 
@@ -41,10 +45,10 @@ var (
 	httpprof   = flag.Bool("httpprof", false, "enable profiling via http server")
 
 	// Mode
-	mode   = flag.String("mode", "", "run mode: (user | bench | exp )")
+	mode   = flag.String("mode", "", "run mode: (user | bench | exp | ctrl )")
 	alg    = flag.String("alg", "", "algorithm to be used: (sm | dyna | ssr | cons )")
 	opt    = flag.String("opt", "", "which optimization to use: ( no | doreconf )")
-	cprov  = flag.String("cprov", "normal", "which configuration provider: (normal | thrifty | norecontact ) ")
+	cprov  = flag.String("cprov", "normal", "which configuration provider: (normal | thrifty | norecontact | fd ) ")
 	doelog = flag.Bool("elog", false, "log latencies in user or exp mode.")
 
 	//Config
@@ -53,6 +57,8 @@ var (
 	nclients  = flag.Int("nclients", 1, "the number of clients")
 	initsize  = flag.Int("initsize", 1, "the number of servers in the initial configuration")
 	useleader = flag.Bool("useleader", false, "let a leader handle reconfigurations.")
+	ft        = flag.Int("ft", 15, "the number of failures tolerated by write quorums in the initial configuration.")
+	readft    = flag.Int("readft", 0, "the number of failures tolerated by read quorums in the initial configuration. (0 uses majority write quorums.)")
 
 	//Read or Write Bench
 	contW  = flag.Bool("contW", false, "continuously write")
@@ -61,6 +67,12 @@ var (
 	writes = flag.Int("writes", 0, "number of writes to be performed.")
 	size   = flag.Int("size", 16, "number of bytes for value.")
 	regul  = flag.Bool("regular", false, "do only regular reads")
+	key    = flag.String("key", "", "the key of the register to read and write.")
+
+	timeout = flag.Duration("timeout", 0, "deadline for each operation in user mode and for commands. (0 means no deadline.)")
+	hedge   = flag.Duration("hedge", 0, "initial delay before hedging reads and writes with a call to the other servers of the configuration. (0 means no hedging.)")
+
+	historyFile = flag.String("history", "", "record all operations in this file, and check the history for atomicity.")
 
 	//Reconf Exp
 	rm   = flag.Bool("rm", false, "remove nclients servers concurrently.")
@@ -68,12 +80,21 @@ var (
 	repl = flag.Bool("repl", false, "replace nclient many servers concurrently")
 	cont = flag.Bool("cont", false, "continuously reconfigure")
 	logT = flag.Bool("logThroughput", false, "Log reads per second.")
+
+	//Controller
+	suspectAfter = flag.Duration("suspect", 5*time.Second, "in ctrl mode, replace a server suspected for this long.")
+	minReconf    = flag.Duration("minreconf", 30*time.Second, "in ctrl mode, the minimal time between two reconfigurations.")
+	probeEvery   = flag.Duration("probe", time.Second, "in ctrl mode, the time between probing the servers.")
+
+	tlsFiles  = tlsconf.Flags()
+	tokenFile = flag.String("tokenfile", "", "file with a bearer token, sent with every RPC. Needs TLS.")
 )
 
 func Usage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] [COMMAND [ARGS]]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\nOptions:\n")
 	flag.PrintDefaults()
+	cmdUsage()
 }
 
 func main() {
@@ -109,17 +130,39 @@ func main() {
 			log.Println(http.ListenAndServe("localhost:6060", nil))
 		}()
 	}
+	if *historyFile != "" {
+		recorder = checker.NewRecorder()
+	}
+
+	if flag.NArg() > 0 {
+		*mode = "cmd"
+	}
+
+	failed := false
 	switch *mode {
+	case "cmd":
+		failed = !cmdmain(flag.Args())
 	case "", "user":
 		usermain()
 	case "bench":
 		benchmain()
 	case "exp":
 		expmain()
+	case "ctrl":
+		ctrlmain()
 	default:
 		fmt.Fprintf(os.Stderr, "Unkown mode specified: %q\n", *mode)
 		flag.Usage()
 	}
+
+	if recorder != nil {
+		checkHistory()
+	}
+	if failed {
+		pprof.StopCPUProfile()
+		glog.Flush()
+		os.Exit(1)
+	}
 }
 
 func benchmain() {
@@ -146,10 +189,11 @@ func benchmain() {
 		if i >= *initsize {
 			break
 		}
-		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id})
+		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id, Addr: addrs[i]})
 	}
 	//FaultTolerance 15 ensures majority quorums are used, with up to 31 nodes.
-	initBlp.FaultTolerance = uint32(15)
+	initBlp.FaultTolerance = uint32(*ft)
+	initBlp.ReadFaultTolerance = uint32(*readft)
 
 	checkFlags(*alg, *cprov, *opt)
 
@@ -211,11 +255,21 @@ func benchmain() {
 }
 
 func NewConfP(addrs []string, cprov string, id int) (cp conf.Provider, mgr *pb.Manager, err error) {
-	mgr, err = pb.NewManager(addrs, pb.WithGrpcDialOptions(
-		grpc.WithBlock(),
-		grpc.WithTimeout(3000*time.Millisecond),
-		grpc.WithInsecure()),
-	)
+	sec, err := tlsFiles.DialOption()
+	if err != nil {
+		glog.Errorln("Setting up TLS returned error: ", err)
+		return
+	}
+	opts := []grpc.DialOption{grpc.WithBlock(), grpc.WithTimeout(3000 * time.Millisecond), sec}
+	if *tokenFile != "" {
+		tok, err := auth.TokenFile(*tokenFile)
+		if err != nil {
+			glog.Errorln("Reading token returned error: ", err)
+			return nil, nil, err
+		}
+		opts = append(opts, tok)
+	}
+	mgr, err = pb.NewManager(addrs, pb.WithGrpcDialOptions(opts...))
 	if err != nil {
 		glog.Errorln("Creating manager returned error: ", err)
 		return
@@ -227,6 +281,8 @@ func NewConfP(addrs []string, cprov string, id int) (cp conf.Provider, mgr *pb.M
 		break
 	case "thrifty":
 		cp = &conf.ThriftyConfP{Provider: cp}
+	case "fd":
+		cp = &conf.ThriftyConfP{Provider: conf.NewFDProvider(mgr, id)}
 	case "normal", "":
 		cp = &conf.NormalConfP{Provider: cp}
 	default:
@@ -246,10 +302,10 @@ func NewClient(initB *bp.Blueprint, alg string, opt string, id int, cp conf.Prov
 		default:
 			glog.Fatalf("optimization %v not supported.\n", opt)
 		}
-	// case "dyna":
-	// 	cl, err = dyna.New(initB, uint32(id), cp)
-	// case "ssr":
-	// 	cl, err = ssr.New(initB, uint32(id), cp)
+	case "dyna":
+		cl, err = dyna.New(initB, uint32(id), cp)
+	case "ssr":
+		cl, err = ssr.New(initB, uint32(id), cp)
 	case "cons":
 		switch opt {
 		case "", "no":
@@ -262,6 +318,19 @@ func NewClient(initB *bp.Blueprint, alg string, opt string, id int, cp conf.Prov
 	default:
 		glog.Fatalln("this algorithm is not supported.")
 	}
+	if err == nil && *hedge > 0 {
+		switch c := cl.(type) {
+		case *smc.SmClient:
+			c.Hedge = smc.NewHedging(*hedge)
+		case *cc.ConsClient:
+			c.Hedge = smc.NewHedging(*hedge)
+		default:
+			glog.Warningln("Hedging is only supported for algorithms sm and cons, without optimization.")
+		}
+	}
+	if err == nil && recorder != nil {
+		cl = newRecordingRWRer(cl, id)
+	}
 	return
 }
 
@@ -270,6 +339,7 @@ func contWrite(cl RWRer, cp conf.Provider, size int, stop chan struct{}, wg *syn
 	var (
 		value   = make([]byte, size)
 		cnt     int
+		err     error
 		reqsent time.Time
 	)
 
@@ -278,7 +348,10 @@ func contWrite(cl RWRer, cp conf.Provider, size int, stop chan struct{}, wg *syn
 loop:
 	for {
 		reqsent = time.Now()
-		cnt = cl.Write(cp, value)
+		cnt, err = cl.Write(context.Background(), cp, *key, value)
+		if err != nil {
+			glog.Errorln("Write returned error:", err)
+		}
 		elog.Log(e.NewTimedEventWithMetric(e.ClientWriteLatency, reqsent, uint64(cnt)))
 		if cnt > 100 {
 			break
@@ -316,10 +389,14 @@ loop:
 	for {
 		reqsent = time.Now()
 		go func() {
+			var err error
 			if reg {
-				_, c = cl.RRead(cp)
+				_, c, err = cl.RRead(context.Background(), cp, *key)
 			} else {
-				_, c = cl.Read(cp)
+				_, c, err = cl.Read(context.Background(), cp, *key)
+			}
+			if err != nil {
+				glog.Errorln("Read returned error:", err)
 			}
 			cchan <- c
 		}()
@@ -350,13 +427,17 @@ func doWrites(cl RWRer, cp conf.Provider, size int, writes int, wg *sync.WaitGro
 	var (
 		value   = make([]byte, size)
 		cnt     int
+		err     error
 		reqsent time.Time
 	)
 
 	bgen.GetBytes(value)
 	for i := 0; i < writes; i++ {
 		reqsent = time.Now()
-		cnt = cl.Write(cp, value)
+		cnt, err = cl.Write(context.Background(), cp, *key, value)
+		if err != nil {
+			glog.Errorln("Write returned error:", err)
+		}
 		elog.Log(e.NewTimedEventWithMetric(e.ClientWriteLatency, reqsent, uint64(cnt)))
 	}
 	glog.Infoln("finished writes")
@@ -368,15 +449,19 @@ func doWrites(cl RWRer, cp conf.Provider, size int, writes int, wg *sync.WaitGro
 func doReads(cl RWRer, cp conf.Provider, reads int, reg bool, wg *sync.WaitGroup) {
 	var (
 		cnt     int
+		err     error
 		reqsent time.Time
 	)
 
 	for i := 0; i < reads; i++ {
 		reqsent = time.Now()
 		if reg {
-			_, cnt = cl.RRead(cp)
+			_, cnt, err = cl.RRead(context.Background(), cp, *key)
 		} else {
-			_, cnt = cl.Read(cp)
+			_, cnt, err = cl.Read(context.Background(), cp, *key)
+		}
+		if err != nil {
+			glog.Errorln("Read returned error:", err)
 		}
 		elog.Log(e.NewTimedEventWithMetric(e.ClientReadLatency, reqsent, uint64(cnt)))
 	}
@@ -407,23 +492,32 @@ func handleSignal(signal os.Signal) bool {
 // RWRer is a ReaderWriterReconfigurer.
 // This is the interface the different algorithms need to implement.
 type RWRer interface {
-	// RRead performs a regular read. It returns the read value, and an integer,
+	// RRead performs a regular read of register key. It returns the read value, and an integer,
 	// indicating how many message round trips have been performed.
-	RRead(conf.Provider) ([]byte, int)
-	// Read performs an atomic read. It returns the read value, and an integer,
+	RRead(ctx context.Context, cp conf.Provider, key string) ([]byte, int, error)
+	// Read performs an atomic read of register key. It returns the read value, and an integer,
 	// indicating how many message round trips have been performed.
-	Read(conf.Provider) ([]byte, int)
-	// Write performs an atomic, or regular write. It returns an integer,
+	Read(ctx context.Context, cp conf.Provider, key string) ([]byte, int, error)
+	// Write performs an atomic, or regular write to register key. It returns an integer,
 	// indicating how many message round trips have been performed.
-	Write(cp conf.Provider, val []byte) int
+	Write(ctx context.Context, cp conf.Provider, key string, val []byte) (int, error)
 	// Reconf performs reconfiguration. It returns an integer,
 	// indicating how many message round trips have been performed.
-	Reconf(cp conf.Provider, prop *bp.Blueprint) (int, error)
+	Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (int, error)
 	// GetCur returns the last installed configuration a client knows of,
 	// this is purely local.
 	GetCur() *bp.Blueprint
 }
 
+// opContext returns the context for a single operation in user mode or of a command.
+// The operation is canceled after the duration given by the -timeout flag.
+func opContext() (context.Context, context.CancelFunc) {
+	if *timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), *timeout)
+}
+
 func GetErrors(mgr *pb.Manager) map[uint32]error {
 	nds := mgr.Nodes()
 	errs := make(map[uint32]error, len(nds))
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"errors"
+	"sync"
+	"time"
+
 	"golang.org/x/net/context"
 
 	"github.com/golang/glog"
@@ -10,24 +14,105 @@ import (
 	pb "github.com/relab/smartmerge/proto"
 )
 
+// fwdAttempts is the number of times a proposal is forwarded, before giving up.
+// After a leader failed, a new leader is elected within a lease duration.
+const fwdAttempts = 10
+
+// fwdRetryDelay is the time to wait, before forwarding to the next server after an error.
+const fwdRetryDelay = 300 * time.Millisecond
+
 // The FwdClient implements a client, that, instead of performing reconfigurations
 // forwards them to a leader.
 type FwdClient struct {
 	RWRer
-	leader *pb.Node
+	mgr *pb.Manager
+
+	mu     sync.Mutex
+	leader uint32 // The server proposals are forwarded to first.
 }
 
-// Reconf for the FwdClient simply forwards a reconfiguration request to the leader.
-func (fc *FwdClient) Reconf(cp conf.Provider, prop *bp.Blueprint) (int, error) {
+// Reconf for the FwdClient forwards a reconfiguration request to the leader.
+// If the server is not the leader, it redirects the request to the leader it knows.
+// If the server fails, the request is forwarded to the next server in the current configuration.
+func (fc *FwdClient) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (cnt int, err error) {
 	if glog.V(4) {
 		glog.Infoln("Sending reconfiguration proposal")
 	}
-	_, err := fc.leader.SMandConsRegisterClient.Fwd(context.Background(), &pb.Proposal{Prop: prop})
-	if err != nil {
-		glog.Errorln("Forward returned error", err)
+	for i := 0; i < fwdAttempts && ctx.Err() == nil; i++ {
+		id := fc.getLeader()
+		var ack *pb.Ack
+		ack, err = fc.fwd(ctx, id, prop)
+		cnt++
+
+		switch {
+		case err != nil:
+			glog.Errorf("Forward to %d returned error: %v\n", id, err)
+			fc.setLeader(id, nextID(fc.GetCur().Ids(), id))
+			select {
+			case <-ctx.Done():
+			case <-time.After(fwdRetryDelay):
+			}
+		case ack.Redirect:
+			if glog.V(4) {
+				glog.Infof("Proposal redirected from %d to %d\n", id, ack.Leader)
+			}
+			if _, found := fc.mgr.Node(ack.Leader); !found && ack.LeaderAddr != "" {
+				if err := fc.mgr.DialNode(ack.LeaderAddr); err != nil {
+					glog.Errorln("Could not connect to leader:", err)
+				}
+			}
+			fc.setLeader(id, ack.Leader)
+		default:
+			if glog.V(4) {
+				glog.Infoln("Proposal returned")
+			}
+			return cnt, nil
+		}
+	}
+	if err == nil {
+		err = errors.New("proposal redirected too often")
 	}
-	if glog.V(4) {
-		glog.Infoln("Proposal returned")
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return cnt, err
+}
+
+// fwd forwards the proposal to the server id.
+func (fc *FwdClient) fwd(ctx context.Context, id uint32, prop *bp.Blueprint) (*pb.Ack, error) {
+	node, found := fc.mgr.Node(id)
+	if !found {
+		return nil, pb.NodeNotFoundError(id)
+	}
+	return node.SMandConsRegisterClient.Fwd(ctx, &pb.Proposal{Prop: prop})
+}
+
+func (fc *FwdClient) getLeader() uint32 {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.leader
+}
+
+// setLeader replaces the leader old with id. It does nothing, if another
+// operation already replaced old.
+func (fc *FwdClient) setLeader(old, id uint32) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.leader == old {
+		fc.leader = id
+	}
+}
+
+// nextID returns the id following id in the list ids, wrapping around.
+// If id is not in the list, it returns the first id.
+func nextID(ids []uint32, id uint32) uint32 {
+	if len(ids) == 0 {
+		return id
+	}
+	for i, x := range ids {
+		if x == id {
+			return ids[(i+1)%len(ids)]
+		}
 	}
-	return 1, err
+	return ids[0]
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/checker"
+	conf "github.com/relab/smartmerge/confProvider"
+	"golang.org/x/net/context"
+)
+
+// recorder records the operations of all clients, if the -history flag is given.
+var recorder *checker.Recorder
+
+// recordingRWRer records the operations of a RWRer.
+type recordingRWRer struct {
+	RWRer
+	id  int
+	seq uint32
+}
+
+func newRecordingRWRer(cl RWRer, id int) *recordingRWRer {
+	return &recordingRWRer{RWRer: cl, id: id}
+}
+
+func (rc *recordingRWRer) RRead(ctx context.Context, cp conf.Provider, key string) ([]byte, int, error) {
+	op := recorder.Invoke(rc.id, checker.RRead, key, nil)
+	val, cnt, err := rc.RWRer.RRead(ctx, cp, key)
+	recorder.Return(op, val, err == nil)
+	return val, cnt, err
+}
+
+func (rc *recordingRWRer) Read(ctx context.Context, cp conf.Provider, key string) ([]byte, int, error) {
+	op := recorder.Invoke(rc.id, checker.Read, key, nil)
+	val, cnt, err := rc.RWRer.Read(ctx, cp, key)
+	recorder.Return(op, val, err == nil)
+	return val, cnt, err
+}
+
+// Write makes the written value unique, by overwriting its first 8 bytes
+// with the client id and a sequence number. Otherwise the history could not be checked.
+func (rc *recordingRWRer) Write(ctx context.Context, cp conf.Provider, key string, val []byte) (int, error) {
+	val = stamp(val, rc.id, atomic.AddUint32(&rc.seq, 1))
+	op := recorder.Invoke(rc.id, checker.Write, key, val)
+	cnt, err := rc.RWRer.Write(ctx, cp, key, val)
+	recorder.Return(op, nil, err == nil)
+	return cnt, err
+}
+
+func (rc *recordingRWRer) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (int, error) {
+	op := recorder.Invoke(rc.id, checker.Reconf, "", nil)
+	cnt, err := rc.RWRer.Reconf(ctx, cp, prop)
+	recorder.Return(op, nil, err == nil)
+	return cnt, err
+}
+
+// stamp returns a copy of val, with the id and seq written to the first 8 bytes.
+func stamp(val []byte, id int, seq uint32) []byte {
+	v := make([]byte, len(val), len(val)+8)
+	copy(v, val)
+	if len(v) < 8 {
+		v = v[:8]
+	}
+	binary.BigEndian.PutUint32(v, uint32(id))
+	binary.BigEndian.PutUint32(v[4:], seq)
+	return v
+}
+
+// checkHistory writes the recorded history to the file historyFile,
+// and checks it for atomicity and regularity.
+func checkHistory() {
+	h := recorder.History()
+	f, err := os.Create(*historyFile)
+	if err != nil {
+		glog.Errorln("Could not create history file:", err)
+	} else {
+		if err = checker.WriteHistory(f, h); err != nil {
+			glog.Errorln("Could not write history:", err)
+		}
+		f.Close()
+	}
+
+	vs := checker.Check(h)
+	if len(vs) == 0 {
+		fmt.Printf("History of %d operations: no violations found.\n", len(h))
+		return
+	}
+	for _, v := range vs {
+		fmt.Println("Violation:", v.Error())
+	}
+}
@@ -14,6 +14,7 @@ import (
 	e "github.com/relab/smartmerge/elog/event"
 	pb "github.com/relab/smartmerge/proto"
 	"github.com/relab/smartmerge/util"
+	"golang.org/x/net/context"
 )
 
 func expmain() {
@@ -33,9 +34,10 @@ func expmain() {
 		if i >= *initsize {
 			break
 		}
-		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id})
+		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id, Addr: addrs[i]})
 	}
-	initBlp.FaultTolerance = uint32(15)
+	initBlp.FaultTolerance = uint32(*ft)
+	initBlp.ReadFaultTolerance = uint32(*readft)
 
 	if *doelog {
 		elog.Enable()
@@ -130,7 +132,7 @@ func contremove(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i int
 			glog.Infoln("Could not remove %v\n.", ids[i])
 		} else {
 			reqsent := time.Now()
-			cnt, err := c.Reconf(cp, target)
+			cnt, err := c.Reconf(context.Background(), cp, target)
 			if err == nil || cnt == 0 {
 				elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 			} else {
@@ -160,7 +162,7 @@ func contadd(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i int, w
 			glog.V(4).Infoln("Could not add %v\n.", ids[i])
 		} else {
 			reqsent := time.Now()
-			cnt, err := c.Reconf(cp, target)
+			cnt, err := c.Reconf(context.Background(), cp, target)
 			if err == nil || cnt == 0 {
 				elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 			} else {
@@ -193,7 +195,7 @@ func contreplace(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i in
 		}
 
 		reqsent := time.Now()
-		cnt, err := c.Reconf(cp, target)
+		cnt, err := c.Reconf(context.Background(), cp, target)
 		if err == nil || cnt == 0 {
 			elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 		} else {
@@ -224,7 +226,7 @@ func replace(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i int, w
 
 	<-sc
 	reqsent := time.Now()
-	cnt, err := c.Reconf(cp, target)
+	cnt, err := c.Reconf(context.Background(), cp, target)
 	elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 
 	if err != nil {
@@ -247,7 +249,7 @@ func remove(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i int, wg
 
 	<-sc
 	reqsent := time.Now()
-	cnt, err := c.Reconf(cp, target)
+	cnt, err := c.Reconf(context.Background(), cp, target)
 	elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 
 	if err != nil {
@@ -273,7 +275,7 @@ func adds(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i int, wg *
 	<-sc
 
 	reqsent := time.Now()
-	cnt, err := c.Reconf(cp, target)
+	cnt, err := c.Reconf(context.Background(), cp, target)
 	elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 
 	if err != nil {
@@ -282,7 +284,11 @@ func adds(c RWRer, cp conf.Provider, ids []uint32, sc chan struct{}, i int, wg *
 	return
 }
 
+// createForwarder returns a client, that forwards reconfigurations to a leader,
+// starting with the server lid.
 func createForwarder(cl RWRer, mgr *pb.Manager, lid uint32) (RWRer, error) {
-	leader, _ := mgr.Node(lid)
-	return &FwdClient{cl, leader}, nil
+	if _, found := mgr.Node(lid); !found {
+		return nil, pb.NodeNotFoundError(lid)
+	}
+	return &FwdClient{RWRer: cl, mgr: mgr, leader: lid}, nil
 }
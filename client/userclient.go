@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	bp "github.com/relab/smartmerge/blueprints"
@@ -30,9 +31,10 @@ func usermain() {
 		if i >= *initsize {
 			break
 		}
-		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id})
+		initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id, Addr: addrs[i]})
 	}
-	initBlp.FaultTolerance = uint32(15)
+	initBlp.FaultTolerance = uint32(*ft)
+	initBlp.ReadFaultTolerance = uint32(*readft)
 
 	cp, mgr, err := NewConfP(addrs, *cprov, (*clientid))
 	if err != nil {
@@ -70,8 +72,13 @@ func usermain() {
 		switch op {
 		case 1:
 			reqsent := time.Now()
-			bytes, cnt := client.Read(cp)
+			ctx, cancel := opContext()
+			bytes, cnt, err := client.Read(ctx, cp, *key)
+			cancel()
 			elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
+			if err != nil {
+				fmt.Println("Read returned error: ", err)
+			}
 			state := string(bytes)
 			fmt.Println("Current value is: ", state)
 			fmt.Printf("Has %d bytes.\n", len(bytes))
@@ -81,19 +88,29 @@ func usermain() {
 			fmt.Print("Insert string to write: ")
 			fmt.Scanln(&str)
 			reqsent := time.Now()
-			cnt := client.Write(cp, []byte(str))
+			ctx, cancel := opContext()
+			cnt, err := client.Write(ctx, cp, *key, []byte(str))
+			cancel()
 			elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
+			if err != nil {
+				fmt.Println("Write returned error: ", err)
+			}
 			fmt.Printf("Did %d accesses.\n", cnt)
 		case 3:
 			reqsent := time.Now()
-			bytes, cnt := client.RRead(cp)
+			ctx, cancel := opContext()
+			bytes, cnt, err := client.RRead(ctx, cp, *key)
+			cancel()
 			elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
+			if err != nil {
+				fmt.Println("Read returned error: ", err)
+			}
 			state := string(bytes)
 			fmt.Println("Current value is: ", state)
 			fmt.Printf("Has %d bytes.\n", len(bytes))
 			fmt.Printf("Did %d accesses.\n", cnt)
 		case 4:
-			handleReconf(client, cp, ids)
+			handleReconf(client, cp, ids, addrs)
 		case 5:
 			var size, writes int
 			fmt.Println("Enter size:")
@@ -108,7 +125,7 @@ func usermain() {
 
 }
 
-func handleReconf(c RWRer, cp conf.Provider, ids []uint32) {
+func handleReconf(c RWRer, cp conf.Provider, ids []uint32, addrs []string) {
 	cur := c.GetCur()
 	fmt.Println("Current Blueprint is: ", cur.Nodes)
 	fmt.Println("Type 1 or 2 for add or remove?")
@@ -120,12 +137,17 @@ func handleReconf(c RWRer, cp conf.Provider, ids []uint32) {
 	switch adrem {
 	case 1:
 		fmt.Println("Available ids:")
-		for _, id := range ids {
-			fmt.Println(id)
+		for i, id := range ids {
+			fmt.Println(id, addrs[i])
 		}
-		fmt.Println("Type the id for the process to be added.")
-		var id uint32
-		_, err = fmt.Scanf("%d", &id)
+		fmt.Println("Type the id for the process to be added, or the address host:port of a new server.")
+		var in string
+		_, err = fmt.Scanln(&in)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		id, addr, err := parseNode(in, ids, addrs)
 		if err != nil {
 			fmt.Println(err)
 			return
@@ -133,14 +155,16 @@ func handleReconf(c RWRer, cp conf.Provider, ids []uint32) {
 
 		target := cur.Copy()
 
-		if !target.Add(id) {
+		if !target.AddAt(id, addr) {
 			fmt.Printf("Node wit id %d was already added.\n", id)
 			return
 		}
 
 		fmt.Println("Starting reconfiguration with target ", target.Nodes)
 		reqsent := time.Now()
-		cnt, err := c.Reconf(cp, target)
+		ctx, cancel := opContext()
+		cnt, err := c.Reconf(ctx, cp, target)
+		cancel()
 		elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 		if err != nil {
 			fmt.Println("Reconf returned error: ", err)
@@ -168,7 +192,9 @@ func handleReconf(c RWRer, cp conf.Provider, ids []uint32) {
 		}
 
 		reqsent := time.Now()
-		cnt, err := c.Reconf(cp, target)
+		ctx, cancel := opContext()
+		cnt, err := c.Reconf(ctx, cp, target)
+		cancel()
 		elog.Log(e.NewTimedEventWithMetric(e.ClientReconfLatency, reqsent, uint64(cnt)))
 
 		if err != nil {
@@ -184,6 +210,24 @@ func handleReconf(c RWRer, cp conf.Provider, ids []uint32) {
 
 }
 
+// parseNode returns the id and address of the server in, given either as id from the
+// configuration file, or as address.
+func parseNode(in string, ids []uint32, addrs []string) (id uint32, addr string, err error) {
+	if x, err := strconv.ParseUint(in, 10, 32); err == nil {
+		for i, cid := range ids {
+			if cid == uint32(x) {
+				return cid, addrs[i], nil
+			}
+		}
+		return 0, "", fmt.Errorf("id %d is not in the configuration file", x)
+	}
+	id, err = util.NodeID(in)
+	if err != nil {
+		return 0, "", fmt.Errorf("could not parse address %s: %v", in, err)
+	}
+	return id, in, nil
+}
+
 func PrintErrors(mgr *pb.Manager) {
 	founderrs := false
 	for _, n := range mgr.Nodes() {
@@ -0,0 +1,153 @@
+package confProvider
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// Configuration is the set of quorum calls the clients use.
+// It is implemented by *pb.WeightedConfiguration, and by the simulated configurations in package sim.
+type Configuration interface {
+	Read(ctx context.Context, args *pb.Read) (*pb.ReadReply_, error)
+	Write(ctx context.Context, args *pb.WriteS) (*pb.WriteReply, error)
+	WriteNext(ctx context.Context, args *pb.WriteN) (*pb.WriteNextReply, error)
+	SetCur(ctx context.Context, args *pb.NewCur) (*pb.SetCurReply, error)
+	LAProp(ctx context.Context, args *pb.LAProposal) (*pb.LAPropReply, error)
+	SetState(ctx context.Context, args *pb.NewState) (*pb.SetStateReply, error)
+	GetPromise(ctx context.Context, args *pb.Prepare) (*pb.GetPromiseReply, error)
+	Accept(ctx context.Context, args *pb.Propose) (*pb.AcceptReply, error)
+	LAPropValue(ctx context.Context, args *pb.LAValueProposal) (*pb.LAPropValueReply, error)
+	DWriteNext(ctx context.Context, args *pb.DWriteN) (*pb.DWriteNextReply, error)
+	DSetState(ctx context.Context, args *pb.DNewState) (*pb.DSetStateReply, error)
+	SpSnOneShot(ctx context.Context, args *pb.SpSnProp) (*pb.SpSnOneShotReply, error)
+	Elect(ctx context.Context, args *pb.Ballot) (*pb.ElectReply, error)
+}
+
+// Hedged returns a configuration, that invokes quorum calls on the nodes of cnf, and after delay also on the other
+// nodes of full, see pb.WeightedConfiguration.Hedged. Configurations of the failure detector stay monitored.
+// It returns nil, if the configurations can not be hedged, e.g. on a simulated network.
+func Hedged(cnf, full Configuration, delay time.Duration, report func(sent, won bool)) Configuration {
+	if fc, ok := cnf.(*FDConfiguration); ok {
+		ff, ok := full.(*FDConfiguration)
+		if !ok {
+			return nil
+		}
+		h := Hedged(fc.cnf, ff.cnf, delay, report)
+		if h == nil {
+			return nil
+		}
+		// Only the nodes contacted first are suspected, if the call does not return before the deadline.
+		return &FDConfiguration{cnf: h, ids: fc.ids, suspected: fc.suspected, fd: fc.fd, mon: fc.mon}
+	}
+	c, ok := cnf.(*pb.WeightedConfiguration)
+	f, fok := full.(*pb.WeightedConfiguration)
+	if !ok || !fok {
+		return nil
+	}
+	return c.Hedged(f, delay, report)
+}
+
+// Manager creates configurations from a set of node ids and a quorum specification.
+type Manager interface {
+	NewConfiguration(ids []uint32, qspec pb.WeightedQuorumSpec) (Configuration, error)
+	// Dial makes the node id with address addr available for new configurations.
+	// It does nothing, if the node is already known.
+	Dial(id uint32, addr string) error
+}
+
+// grpcManager adapts a gorums manager to the Manager interface.
+type grpcManager struct {
+	mgr     *pb.Manager
+	backoff *dialBackoff
+}
+
+func newGRPCManager(mgr *pb.Manager) grpcManager {
+	return grpcManager{mgr, &dialBackoff{nodes: make(map[uint32]*retry)}}
+}
+
+func (m grpcManager) NewConfiguration(ids []uint32, qspec pb.WeightedQuorumSpec) (Configuration, error) {
+	cnf, err := m.mgr.NewWeightedConfiguration(ids, qspec)
+	if err != nil {
+		// Avoid returning a non-nil interface holding a nil pointer.
+		return nil, err
+	}
+	return cnf, nil
+}
+
+// Dial dials node id, unless dialing it failed recently. Then it returns errBackoff, see DialBackoff.
+func (m grpcManager) Dial(id uint32, addr string) error {
+	if _, found := m.mgr.Node(id); found {
+		return nil
+	}
+	if err := m.backoff.check(id); err != nil {
+		return err
+	}
+	err := m.mgr.DialNode(addr)
+	if _, found := m.mgr.Node(id); found {
+		// The node may also have been added concurrently.
+		m.backoff.report(id, nil)
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("address %s does not belong to node %d", addr, id)
+	}
+	m.backoff.report(id, err)
+	return err
+}
+
+// DialBackoff is the time a node is not dialed again, after dialing it failed.
+// It doubles with every further failure, up to MaxDialBackoff.
+var DialBackoff = 100 * time.Millisecond
+var MaxDialBackoff = 30 * time.Second
+
+// errBackoff is returned by Dial, while waiting to dial a node again.
+var errBackoff = errors.New("waiting to dial again, after dialing failed")
+
+// dialBackoff remembers the nodes, that could not be dialed, such that
+// quorum calls do not dial them again, and log the error, every time.
+type dialBackoff struct {
+	mu    sync.Mutex
+	nodes map[uint32]*retry
+}
+
+type retry struct {
+	at   time.Time     // The time, after which the node is dialed again.
+	wait time.Duration // The time waited after the last failure.
+}
+
+// check returns errBackoff, if node id may not be dialed yet.
+func (b *dialBackoff) check(id uint32) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r, ok := b.nodes[id]; ok && time.Now().Before(r.at) {
+		return errBackoff
+	}
+	return nil
+}
+
+// report records the outcome err of dialing node id.
+func (b *dialBackoff) report(id uint32, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.nodes, id)
+		return
+	}
+	r, ok := b.nodes[id]
+	switch {
+	case !ok:
+		r = &retry{wait: DialBackoff}
+		b.nodes[id] = r
+	case r.wait < MaxDialBackoff/2:
+		r.wait *= 2
+	default:
+		r.wait = MaxDialBackoff
+	}
+	r.at = time.Now().Add(r.wait)
+}
@@ -0,0 +1,36 @@
+package confProvider
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDialBackoff(t *testing.T) {
+	b := &dialBackoff{nodes: make(map[uint32]*retry)}
+	if err := b.check(1); err != nil {
+		t.Errorf("node not dialed before is backing off: %v", err)
+	}
+	b.report(1, errors.New("connection refused"))
+	if err := b.check(1); err != errBackoff {
+		t.Errorf("failed node is dialed again immediately")
+	}
+	if err := b.check(2); err != nil {
+		t.Errorf("other node is backing off: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		b.report(1, errors.New("connection refused"))
+	}
+	if w := b.nodes[1].wait; w != MaxDialBackoff {
+		t.Errorf("backoff grew to %v, want %v", w, MaxDialBackoff)
+	}
+
+	b.nodes[1].at = time.Now()
+	if err := b.check(1); err != nil {
+		t.Errorf("node is not dialed again after the backoff: %v", err)
+	}
+	b.report(1, nil)
+	if _, ok := b.nodes[1]; ok {
+		t.Errorf("successful dial did not reset the backoff")
+	}
+}
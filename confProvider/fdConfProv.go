@@ -0,0 +1,408 @@
+package confProvider
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// NodeMonitor is implemented by managers, that keep the outcome of the last call to each node.
+// Gorums nodes do this, see pb.Node.LastErr and pb.Node.Latency.
+type NodeMonitor interface {
+	// LastErr returns the last error returned by a call to node id, or nil.
+	LastErr(id uint32) error
+	// Latency returns the latency of the last successful call to node id. It is not positive, if unknown.
+	Latency(id uint32) time.Duration
+}
+
+func (m grpcManager) LastErr(id uint32) error {
+	if n, found := m.mgr.Node(id); found {
+		return n.LastErr()
+	}
+	return nil
+}
+
+func (m grpcManager) Latency(id uint32) time.Duration {
+	if n, found := m.mgr.Node(id); found {
+		return n.Latency()
+	}
+	return -1
+}
+
+// NewFDProvider returns a provider like NewProvider, that uses a failure detector to choose quorums.
+// Quorums are chosen from the nodes not suspected, preferring nodes with low latency.
+func NewFDProvider(mgr *pb.Manager, id int) *ThriftyNorecConfP {
+	return &ThriftyNorecConfP{newGRPCManager(mgr), id, NewDetector()}
+}
+
+// NewFDProviderFor is like NewFDProvider, but creates configurations using any Manager.
+func NewFDProviderFor(mgr Manager, id int) *ThriftyNorecConfP {
+	return &ThriftyNorecConfP{mgr, id, NewDetector()}
+}
+
+// Detector returns the failure detector of the provider, or nil if it has none.
+func (cp *ThriftyNorecConfP) Detector() *Detector {
+	return cp.fd
+}
+
+// Suspected returns the nodes currently suspected by the providers failure detector.
+func (cp *ThriftyNorecConfP) Suspected() []uint32 {
+	if cp.fd == nil {
+		return nil
+	}
+	return cp.fd.Suspected()
+}
+
+// newConfiguration creates a configuration of the nodes ids.
+// If the provider has a failure detector, the outcomes of the configurations quorum calls are reported to it.
+func (cp *ThriftyNorecConfP) newConfiguration(ids []uint32, qs pb.WeightedQuorumSpec) (Configuration, error) {
+	cnf, err := cp.mgr.NewConfiguration(ids, qs)
+	if err != nil || cp.fd == nil {
+		return cnf, err
+	}
+	mon, _ := cp.mgr.(NodeMonitor)
+	return &FDConfiguration{
+		cnf:       cnf,
+		ids:       ids,
+		suspected: cp.fd.Suspected(),
+		fd:        cp.fd,
+		mon:       mon,
+	}, nil
+}
+
+// latencyWeight is the weight of a new sample in the latency estimate.
+const latencyWeight = 0.25
+
+// SuspicionTimeout is the time, after which a suspected node is tried again, when choosing quorums.
+// A node, that recovered, would otherwise never be contacted.
+var SuspicionTimeout = 10 * time.Second
+
+// Detector keeps estimates of the liveness and the latency of nodes.
+// A node is suspected, after a call to it failed or did not return before the deadline,
+// and is trusted again after it replied. Suspected nodes are avoided, until SuspicionTimeout
+// passed since the last failed call.
+type Detector struct {
+	mu    sync.Mutex
+	nodes map[uint32]*nodeState
+}
+
+type nodeState struct {
+	suspected bool
+	since     time.Time     // The time of the last failed call, while suspected.
+	latency   time.Duration // Moving average of the latency. 0 if unknown.
+	lastErr   error         // The last error reported by a NodeMonitor.
+}
+
+// NewDetector returns a failure detector, that does not suspect any node.
+func NewDetector() *Detector {
+	return &Detector{nodes: make(map[uint32]*nodeState)}
+}
+
+// state returns the state of node id. d.mu must be held.
+func (d *Detector) state(id uint32) *nodeState {
+	st, ok := d.nodes[id]
+	if !ok {
+		st = new(nodeState)
+		d.nodes[id] = st
+	}
+	return st
+}
+
+// Alive reports a reply from node id, that took lat.
+func (d *Detector) Alive(id uint32, lat time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.alive(id, lat)
+}
+
+func (d *Detector) alive(id uint32, lat time.Duration) {
+	st := d.state(id)
+	if st.suspected {
+		glog.V(3).Infof("Node %d is no longer suspected.\n", id)
+	}
+	st.suspected = false
+	switch {
+	case lat <= 0:
+	case st.latency == 0:
+		st.latency = lat
+	default:
+		st.latency += time.Duration(latencyWeight * float64(lat-st.latency))
+	}
+}
+
+// Suspect reports a failed call to node id.
+func (d *Detector) Suspect(id uint32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.suspect(id)
+}
+
+func (d *Detector) suspect(id uint32) {
+	st := d.state(id)
+	if !st.suspected {
+		glog.V(3).Infof("Suspecting node %d.\n", id)
+	}
+	st.suspected = true
+	st.since = time.Now()
+}
+
+// Suspected returns the suspected nodes, in increasing order.
+func (d *Detector) Suspected() []uint32 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var ids []uint32
+	for id, st := range d.nodes {
+		if st.suspected {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(byID(ids))
+	return ids
+}
+
+// Latency returns the latency estimated for node id, or 0 if unknown.
+func (d *Detector) Latency(id uint32) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if st, ok := d.nodes[id]; ok {
+		return st.latency
+	}
+	return 0
+}
+
+// rank orders ids by preference: Nodes not suspected come first, ordered by their latency.
+// Nodes suspected longer than SuspicionTimeout are ranked like nodes not suspected, such that they are tried.
+// Nodes without a latency estimate come before others, such that they are tried.
+// Ties are broken by the position in ids, counting from start.
+func (d *Detector) rank(ids []uint32, start int) []uint32 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ranked := make([]uint32, len(ids))
+	for i := range ids {
+		ranked[i] = ids[(start+i)%len(ids)]
+	}
+	now := time.Now()
+	avoid := func(st *nodeState) bool {
+		return st.suspected && now.Sub(st.since) < SuspicionTimeout
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := d.state(ranked[i]), d.state(ranked[j])
+		if avoid(a) != avoid(b) {
+			return avoid(b)
+		}
+		return a.latency < b.latency
+	})
+	return ranked
+}
+
+// observe updates the estimates after a quorum call to the nodes ids returned.
+// replied are the nodes that replied, including those that returned an error, and elapsed is the duration of the call.
+// If mon is not nil, it is used to distinguish replies from errors, and to find the latency of each node.
+func (d *Detector) observe(ctx context.Context, ids, replied []uint32, elapsed time.Duration, err error, mon NodeMonitor) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, id := range replied {
+		lat := elapsed
+		if mon != nil {
+			st := d.state(id)
+			if e := mon.LastErr(id); e != nil && e != st.lastErr {
+				st.lastErr = e
+				d.suspect(id)
+				continue
+			}
+			if l := mon.Latency(id); l > 0 {
+				lat = l
+			}
+		}
+		d.alive(id, lat)
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		// Nodes that did not reply before the deadline are suspected.
+		for _, id := range ids {
+			if !contains(replied, id) {
+				d.suspect(id)
+			}
+		}
+	}
+}
+
+func contains(ids []uint32, id uint32) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+type byID []uint32
+
+func (p byID) Len() int           { return len(p) }
+func (p byID) Less(i, j int) bool { return p[i] < p[j] }
+func (p byID) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// FDConfiguration is a configuration, that reports the outcome of its quorum calls to a failure detector.
+// It does not embed the configuration it wraps, such that it only implements Configuration, if every quorum call is monitored.
+type FDConfiguration struct {
+	cnf       Configuration
+	ids       []uint32
+	suspected []uint32
+	fd        *Detector
+	mon       NodeMonitor
+}
+
+var _ Configuration = (*FDConfiguration)(nil)
+
+// NodeIDs returns the ids of the nodes in the configuration.
+func (c *FDConfiguration) NodeIDs() []uint32 {
+	return c.ids
+}
+
+// Suspected returns the nodes, that were suspected when the configuration was created.
+func (c *FDConfiguration) Suspected() []uint32 {
+	return c.suspected
+}
+
+// monitor performs the quorum call call, which returns the nodes that replied,
+// and reports its outcome to the failure detector. All quorum calls of c go through monitor.
+func (c *FDConfiguration) monitor(ctx context.Context, call func() ([]uint32, error)) error {
+	start := time.Now()
+	ids, err := call()
+	c.fd.observe(ctx, c.ids, ids, time.Since(start), err, c.mon)
+	return err
+}
+
+func (c *FDConfiguration) Read(ctx context.Context, args *pb.Read) (r *pb.ReadReply_, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.Read(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) Write(ctx context.Context, args *pb.WriteS) (r *pb.WriteReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.Write(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) WriteNext(ctx context.Context, args *pb.WriteN) (r *pb.WriteNextReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.WriteNext(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) SetCur(ctx context.Context, args *pb.NewCur) (r *pb.SetCurReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.SetCur(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) LAProp(ctx context.Context, args *pb.LAProposal) (r *pb.LAPropReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.LAProp(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) SetState(ctx context.Context, args *pb.NewState) (r *pb.SetStateReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.SetState(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) GetPromise(ctx context.Context, args *pb.Prepare) (r *pb.GetPromiseReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.GetPromise(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) Accept(ctx context.Context, args *pb.Propose) (r *pb.AcceptReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.Accept(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) LAPropValue(ctx context.Context, args *pb.LAValueProposal) (r *pb.LAPropValueReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.LAPropValue(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) DWriteNext(ctx context.Context, args *pb.DWriteN) (r *pb.DWriteNextReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.DWriteNext(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) DSetState(ctx context.Context, args *pb.DNewState) (r *pb.DSetStateReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.DSetState(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) SpSnOneShot(ctx context.Context, args *pb.SpSnProp) (r *pb.SpSnOneShotReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.SpSnOneShot(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
+
+func (c *FDConfiguration) Elect(ctx context.Context, args *pb.Ballot) (r *pb.ElectReply, err error) {
+	err = c.monitor(ctx, func() ([]uint32, error) {
+		if r, err = c.cnf.Elect(ctx, args); r != nil {
+			return r.NodeIDs, err
+		}
+		return nil, err
+	})
+	return r, err
+}
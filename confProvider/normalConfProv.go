@@ -2,7 +2,6 @@ package confProvider
 
 import (
 	bp "github.com/relab/smartmerge/blueprints"
-	pb "github.com/relab/smartmerge/proto"
 )
 
 // This Config provider always return a full configuration.
@@ -10,15 +9,15 @@ type NormalConfP struct {
 	Provider
 }
 
-func (cp *NormalConfP) ReadC(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *NormalConfP) ReadC(blp *bp.Blueprint, rids []uint32) Configuration {
 	return cp.Provider.FullC(blp)
 }
 
-func (cp *NormalConfP) WriteC(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *NormalConfP) WriteC(blp *bp.Blueprint, rids []uint32) Configuration {
 	return cp.Provider.FullC(blp)
 }
 
-func (cp *NormalConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *NormalConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) Configuration {
 	return cp.Provider.FullC(blp)
 }
 
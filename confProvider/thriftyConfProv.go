@@ -2,7 +2,6 @@ package confProvider
 
 import (
 	bp "github.com/relab/smartmerge/blueprints"
-	pb "github.com/relab/smartmerge/proto"
 )
 
 // ThriftyConfP is a configuration provider that does not avoid recontacting servers.
@@ -11,14 +10,14 @@ type ThriftyConfP struct {
 	Provider
 }
 
-func (cp *ThriftyConfP) ReadC(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *ThriftyConfP) ReadC(blp *bp.Blueprint, rids []uint32) Configuration {
 	return cp.Provider.ReadC(blp, nil)
 }
 
-func (cp *ThriftyConfP) WriteC(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *ThriftyConfP) WriteC(blp *bp.Blueprint, rids []uint32) Configuration {
 	return cp.Provider.WriteC(blp, nil)
 }
 
-func (cp *ThriftyConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *ThriftyConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) Configuration {
 	return cp.Provider.WriteCNoS(blp, nil)
 }
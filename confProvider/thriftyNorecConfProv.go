@@ -13,44 +13,83 @@ var ConfTimeout = 1 * time.Second
 var TryTimeout = 500 * time.Millisecond
 
 type Provider interface {
-	FullC(*bp.Blueprint) *pb.Configuration
-	ReadC(*bp.Blueprint, []uint32) *pb.Configuration
-	WriteC(*bp.Blueprint, []uint32) *pb.Configuration
-	SingleC(*bp.Blueprint) *pb.Configuration
-	WriteCNoS(*bp.Blueprint, []uint32) *pb.Configuration
+	FullC(*bp.Blueprint) Configuration
+	ReadC(*bp.Blueprint, []uint32) Configuration
+	WriteC(*bp.Blueprint, []uint32) Configuration
+	SingleC(*bp.Blueprint) Configuration
+	WriteCNoS(*bp.Blueprint, []uint32) Configuration
 }
 
 type ThriftyNorecConfP struct {
-	mgr *pb.Manager
+	mgr Manager
 	id  int
+	fd  *Detector // If not nil, quorums are chosen from nodes not suspected.
 }
 
+// NewProvider returns a provider, that creates configurations using the gorums manager mgr.
 func NewProvider(mgr *pb.Manager, id int) *ThriftyNorecConfP {
-	return &ThriftyNorecConfP{mgr, id}
+	return &ThriftyNorecConfP{newGRPCManager(mgr), id, nil}
 }
 
-func (cp *ThriftyNorecConfP) chooseQ(ids []uint32, q int) (quorum []uint32) {
-	if q > len(ids) {
-		glog.Fatalf("Trying to choose %d nodes, out of %d\n", q, len(ids))
+// NewProviderFor is like NewProvider, but creates configurations using any Manager,
+// e.g. a simulated network.
+func NewProviderFor(mgr Manager, id int) *ThriftyNorecConfP {
+	return &ThriftyNorecConfP{mgr, id, nil}
+}
+
+// dial connects to the nodes in blp, that have an address and are not known to the manager yet.
+// This allows to use servers, that were added by a reconfiguration, but are not in the clients configuration file.
+func (cp *ThriftyNorecConfP) dial(blp *bp.Blueprint) {
+	dial(cp.mgr, blp)
+}
+
+func dial(mgr Manager, blp *bp.Blueprint) {
+	for _, n := range blp.GetNodes() {
+		if n.Addr == "" || n.Version%2 == 1 {
+			continue
+		}
+		if err := mgr.Dial(n.Id, n.Addr); err != nil && err != errBackoff {
+			glog.Errorf("could not connect to node %d at %s: %v\n", n.Id, n.Addr, err)
+		}
 	}
+}
 
-	quorum = make([]uint32, q)
+// chooseQ chooses nodes from ids with a total weight of at least q.
+// Different clients (ids) start choosing at different nodes.
+// With a failure detector, nodes not suspected and with low latency are chosen first.
+func (cp *ThriftyNorecConfP) chooseQ(ids []uint32, ws map[uint32]int, q int) (quorum []uint32) {
+	if q > weight(ws, ids) {
+		glog.Fatalf("Trying to choose weight %d, out of %d\n", q, weight(ws, ids))
+	}
+
+	quorum = make([]uint32, 0, len(ids))
 	start := cp.id % len(ids)
-	if start+q <= len(ids) {
-		copy(quorum, ids[start:])
-		return quorum
+	if cp.fd != nil {
+		ids, start = cp.fd.rank(ids, start), 0
+	}
+	for i, w := 0, 0; w < q; i++ {
+		id := ids[(start+i)%len(ids)]
+		quorum = append(quorum, id)
+		w += ws[id]
 	}
-	copy(quorum, ids[start:])
-	copy(quorum[len(ids)-start:], ids)
 	return quorum
 }
 
-func (cp *ThriftyNorecConfP) ReadC(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+// weight returns the sum of the weights of the nodes ids.
+func weight(ws map[uint32]int, ids []uint32) (w int) {
+	for _, id := range ids {
+		w += ws[id]
+	}
+	return w
+}
+
+func (cp *ThriftyNorecConfP) ReadC(blp *bp.Blueprint, rids []uint32) Configuration {
+	cp.dial(blp)
 	newcids, qs := cp.readC(blp, rids)
 	if newcids == nil {
 		return nil
 	}
-	cnf, err := cp.mgr.NewConfiguration(newcids, qs)
+	cnf, err := cp.newConfiguration(newcids, qs)
 	if err != nil {
 		glog.Fatalln("could not get read config")
 	}
@@ -61,17 +100,19 @@ func (cp *ThriftyNorecConfP) ReadC(blp *bp.Blueprint, rids []uint32) *pb.Configu
 // readC is an easily testable version of ReadC
 func (cp *ThriftyNorecConfP) readC(blp *bp.Blueprint, rids []uint32) (newcids []uint32, qs *qspec.SMQuorumSpec) {
 	cids := blp.Ids()
-	rq := qspec.ReadQuorum(blp.Quorum(), len(cids))
+	ws := blp.Weights()
+	rq := blp.WeightedReadQuorum()
 	newcids = bp.Difference(cids, rids) //Nodes in the configuration (cids), that have not yet replies (not in rids)
 
-	if len(cids)-len(newcids) >= rq {
+	// I already have replies with weight y.
+	y := weight(ws, cids) - weight(ws, newcids)
+	if y >= rq {
 		//We already have enough replies.
 		return nil, nil
 	}
 
-	// I already have y := len(cids) - len(newcids) many replies.
-	// I still need rq - y many.
-	newcids = cp.chooseQ(newcids, rq-(len(cids)-len(newcids)))
+	// I still need weight rq - y.
+	newcids = cp.chooseQ(newcids, ws, rq-y)
 
 	// With quorum size 1, a read quorum contains all processes.
 	qs = qspec.NewSMQSpec(1, len(newcids))
@@ -79,22 +120,25 @@ func (cp *ThriftyNorecConfP) readC(blp *bp.Blueprint, rids []uint32) (newcids []
 	return newcids, qs
 }
 
-func (cp *ThriftyNorecConfP) WriteC(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *ThriftyNorecConfP) WriteC(blp *bp.Blueprint, rids []uint32) Configuration {
+	cp.dial(blp)
 	cids := blp.Ids()
-	q := qspec.WriteQuorum(blp.Quorum(), len(cids))
+	ws := blp.Weights()
+	q := blp.WeightedReadWriteQuorum()
 	newcids := bp.Difference(cids, rids)
 
-	if len(cids)-len(newcids) >= q {
+	// I already have replies with weight y.
+	y := weight(ws, cids) - weight(ws, newcids)
+	if y >= q {
 		//We already have enough replies.
 		return nil
 	}
 
-	// I already have y := len(cids) - len(newcids) many replies.
-	// I still need q - y many.
-	newcids = cp.chooseQ(newcids, q-len(cids)+len(newcids))
+	// I still need weight q - y.
+	newcids = cp.chooseQ(newcids, ws, q-y)
 	qs := qspec.NewSMQSpec(len(newcids), len(newcids))
 
-	cnf, err := cp.mgr.NewConfiguration(newcids, qs)
+	cnf, err := cp.newConfiguration(newcids, qs)
 	if err != nil {
 		glog.Fatalln("could not get read config")
 	}
@@ -102,11 +146,12 @@ func (cp *ThriftyNorecConfP) WriteC(blp *bp.Blueprint, rids []uint32) *pb.Config
 	return cnf
 }
 
-func (cp *ThriftyNorecConfP) FullC(blp *bp.Blueprint) *pb.Configuration {
+func (cp *ThriftyNorecConfP) FullC(blp *bp.Blueprint) Configuration {
+	cp.dial(blp)
 	cids := blp.Ids()
 
-	qs := qspec.SMQSpecFromBP(blp)
-	cnf, err := cp.mgr.NewConfiguration(cids, qs)
+	qs := qspec.WeightedSMQSpecFromBP(blp)
+	cnf, err := cp.newConfiguration(cids, qs)
 	if err != nil {
 		glog.Fatalln("could not get config")
 	}
@@ -114,7 +159,8 @@ func (cp *ThriftyNorecConfP) FullC(blp *bp.Blueprint) *pb.Configuration {
 	return cnf
 }
 
-func (cp *ThriftyNorecConfP) SingleC(blp *bp.Blueprint) *pb.Configuration {
+func (cp *ThriftyNorecConfP) SingleC(blp *bp.Blueprint) Configuration {
+	cp.dial(blp)
 	cids := blp.Ids()
 	m := cids[0]
 	for _, id := range cids {
@@ -125,7 +171,7 @@ func (cp *ThriftyNorecConfP) SingleC(blp *bp.Blueprint) *pb.Configuration {
 	cids = []uint32{m}
 
 	qs := qspec.NewSMQSpec(1, 1)
-	cnf, err := cp.mgr.NewConfiguration(cids, qs)
+	cnf, err := cp.newConfiguration(cids, qs)
 	if err != nil {
 		glog.Fatalln("could not get config")
 	}
@@ -133,7 +179,8 @@ func (cp *ThriftyNorecConfP) SingleC(blp *bp.Blueprint) *pb.Configuration {
 	return cnf
 }
 
-func (cp *ThriftyNorecConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) *pb.Configuration {
+func (cp *ThriftyNorecConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) Configuration {
+	cp.dial(blp)
 	cids := blp.Ids()
 	m := cids[0]
 	for _, id := range cids {
@@ -142,21 +189,22 @@ func (cp *ThriftyNorecConfP) WriteCNoS(blp *bp.Blueprint, rids []uint32) *pb.Con
 		}
 	}
 
-	q := blp.Quorum()
+	ws := blp.Weights()
+	q := blp.WeightedReadWriteQuorum()
 	newcids := bp.Difference(cids, rids)
 
-	if len(cids)-len(newcids) >= q {
+	// I already have replies with weight y.
+	y := weight(ws, cids) - weight(ws, newcids)
+	if y >= q {
 		//We already have enough replies.
 		return nil
 	}
 
-	// I already have y := len(cids) - len(newcids) many replies.
-	// I still need q - y many.
-	y := len(cids) - len(newcids)
+	// I still need weight q - y.
 	newcids = bp.Difference(newcids, []uint32{m})
-	newcids = cp.chooseQ(newcids, q-y)
+	newcids = cp.chooseQ(newcids, ws, q-y)
 	qs := qspec.NewSMQSpec(len(newcids), len(newcids))
-	cnf, err := cp.mgr.NewConfiguration(newcids, qs)
+	cnf, err := cp.newConfiguration(newcids, qs)
 	if err != nil {
 		glog.Fatalln("could not get read config")
 	}
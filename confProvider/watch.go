@@ -0,0 +1,113 @@
+package confProvider
+
+import (
+	"golang.org/x/net/context"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// Subscriber opens Watch streams to the servers of a configuration.
+// It is implemented by the manager returned from NewSubscriber, and by the simulated network in package sim.
+type Subscriber interface {
+	// Watch calls the Watch RPC with req once at each node in the configurations blps, and merges the streams.
+	// The streams end, when ctx is done, or the WatchStream is closed.
+	Watch(ctx context.Context, blps []*bp.Blueprint, req *pb.WatchRequest) (WatchStream, error)
+}
+
+// WatchStream is the merged stream of the Watch RPC at several nodes.
+type WatchStream interface {
+	// Recv returns the next update, or the end of the stream of one node, in the order they arrive.
+	// It returns an error, if the context of the streams is done, or the WatchStream was closed.
+	Recv() (*WatchReply, error)
+	// Close ends the streams at all nodes.
+	Close()
+}
+
+// WatchReply is an update from the node Node, or, if Err is not nil, the end of its stream.
+type WatchReply struct {
+	Node   uint32
+	Update *pb.WatchUpdate
+	Err    error
+}
+
+// NewSubscriber returns a Subscriber, that opens streams using the gorums manager mgr.
+// Like the providers, it connects to new nodes in the configurations it is called with.
+func NewSubscriber(mgr *pb.Manager) Subscriber {
+	return newGRPCManager(mgr)
+}
+
+func (m grpcManager) Watch(ctx context.Context, blps []*bp.Blueprint, req *pb.WatchRequest) (WatchStream, error) {
+	for _, blp := range blps {
+		dial(m, blp)
+	}
+	ids := WatchIds(blps)
+	ctx, cancel := context.WithCancel(ctx)
+	s := &grpcWatchStream{ctx: ctx, cancel: cancel, c: make(chan *WatchReply, len(ids))}
+	for _, id := range ids {
+		go s.watch(m.mgr, id, req)
+	}
+	return s, nil
+}
+
+// WatchIds returns the ids of the nodes in the configurations blps, each once, in the order they first appear.
+func WatchIds(blps []*bp.Blueprint) []uint32 {
+	var ids []uint32
+	seen := make(map[uint32]bool)
+	for _, blp := range blps {
+		for _, id := range blp.Ids() {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// grpcWatchStream merges the streams of several nodes, each received by its own goroutine.
+type grpcWatchStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	c      chan *WatchReply
+}
+
+// watch receives the stream of the node id, until it ends.
+func (s *grpcWatchStream) watch(mgr *pb.Manager, id uint32, req *pb.WatchRequest) {
+	node, found := mgr.Node(id)
+	if !found {
+		s.deliver(&WatchReply{Node: id, Err: pb.NodeNotFoundError(id)})
+		return
+	}
+	stream, err := node.SMandConsRegisterClient.Watch(s.ctx, req)
+	for err == nil {
+		var u *pb.WatchUpdate
+		if u, err = stream.Recv(); err == nil && !s.deliver(&WatchReply{Node: id, Update: u}) {
+			return
+		}
+	}
+	s.deliver(&WatchReply{Node: id, Err: err})
+}
+
+// deliver passes r to Recv. It returns false, if the streams ended before.
+func (s *grpcWatchStream) deliver(r *WatchReply) bool {
+	select {
+	case s.c <- r:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func (s *grpcWatchStream) Recv() (*WatchReply, error) {
+	select {
+	case r := <-s.c:
+		return r, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *grpcWatchStream) Close() {
+	s.cancel()
+}
@@ -5,6 +5,8 @@
 package consclient
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/golang/glog"
 
 	bp "github.com/relab/smartmerge/blueprints"
@@ -27,13 +29,26 @@ func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*ConsClient, error
 	return &ConsClient{c}, nil
 }
 
-func (cc *ConsClient) Reconf(cp conf.Provider, prop *bp.Blueprint) (cnt int, err error) {
+// Reconf reconfigures to a configuration including the proposed blueprint prop.
+// If another proposal was decided, that does not include prop, an *smclient.AbortError is returned.
+func (cc *ConsClient) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (cnt int, err error) {
+	op := cc.begin()
+	defer cc.End(op.SmClient)
+
 	//Proposed blueprint is already in place, or outdated.
-	if prop.Compare(cc.Blueps[0]) == 1 {
+	if prop.Compare(op.Blueps[0]) == 1 {
 		glog.V(3).Infof("C%d: Proposal is already in place.", cc.Id)
 		return 0, nil
 	}
 
-	_, cnt, err = cc.Doreconf(cp, prop, 0, nil)
+	_, cnt, err = op.doreconf(ctx, cp, prop, 0, "", nil)
+	if err == nil && prop.Compare(op.Blueps[0]) != 1 {
+		err = &smc.AbortError{Prop: prop, Cur: op.GetCur()}
+	}
 	return
 }
+
+// begin returns a client for a single operation, see smclient.SmClient.Begin.
+func (cc *ConsClient) begin() *ConsClient {
+	return &ConsClient{cc.Begin()}
+}
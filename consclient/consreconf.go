@@ -1,7 +1,7 @@
 package consclient
 
 import (
-	"errors"
+	"fmt"
 	"time"
 
 	"golang.org/x/net/context"
@@ -10,17 +10,33 @@ import (
 
 	bp "github.com/relab/smartmerge/blueprints"
 	conf "github.com/relab/smartmerge/confProvider"
+	"github.com/relab/smartmerge/lattice"
 	pb "github.com/relab/smartmerge/proto"
 	smc "github.com/relab/smartmerge/smclient"
 )
 
-func (cc *ConsClient) Doreconf(cp conf.Provider, prop *bp.Blueprint, regular int, val []byte) (rst *pb.State, cnt int, err error) {
+// Doreconf is the consensus based version of smclient.Doreconf.
+// Reads and writes access the register key. The states of all registers are moved to new configurations.
+func (cc *ConsClient) Doreconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint, regular int, key string, val []byte) (rst *pb.State, cnt int, err error) {
+	op := cc.begin()
+	defer cc.End(op.SmClient)
+	return op.doreconf(ctx, cp, prop, regular, key, val)
+}
+
+func (cc *ConsClient) doreconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint, regular int, key string, val []byte) (rst *pb.State, cnt int, err error) {
 	if glog.V(6) {
 		glog.Infof("C%d: Starting reconfiguration\n", cc.Id)
 	}
 
 	doconsensus := true
 	cur := 0
+	var sts []*pb.State       // States of all registers.
+	var lavs []*pb.LAInstance // States of all lattice agreement instances.
+	var prs []*pb.State       // Ballots promised to conditional writes, for all registers.
+	var hs []*pb.State        // Earlier versions of all registers.
+	var cps []*pb.State       // Largest discarded versions of all registers.
+	wval := val               // The value written, val is reset once it is written.
+	var rejected bool         // Whether a server rejected the written state, see CondWrite.
 
 forconfiguration:
 	for i := 0; i < len(cc.Blueps); i++ {
@@ -36,11 +52,11 @@ forconfiguration:
 			if doconsensus {
 				//Need to agree on new proposal
 				var cs int
-				next, cs, cur, err = cc.getconsensus(cp, i, prop)
+				next, cs, cur, err = cc.getconsensus(ctx, cp, i, prop)
+				cnt += cs
 				if err != nil {
-					return nil, 0, err
+					return nil, cnt, err
 				}
-				cnt += cs
 			} else {
 				next = prop
 			}
@@ -51,11 +67,11 @@ forconfiguration:
 				// If atomic: Need to read before writing.
 				var st *pb.State
 				var c int
-				st, cur, c, err = cc.Doread(cp, cur, i, nil)
+				st, cur, c, err = cc.Doread(ctx, cp, cur, i, nil, key)
+				cnt += c
 				if err != nil {
-					return nil, 0, err
+					return nil, cnt, err
 				}
-				cnt += c
 				if rst.Compare(st) == 1 {
 					rst = st
 				}
@@ -77,8 +93,8 @@ forconfiguration:
 			writeN := new(pb.WriteNextReply)
 
 			for j := 0; cnf != nil; j++ {
-				writeN, err = cnf.WriteNext(context.Background(), &pb.WriteN{
-					CurC: uint32(cc.Blueps[i].Len()),
+				writeN, err = cnf.WriteNext(ctx, &pb.WriteN{
+					CurC: cc.Blueps[i].ID(),
 					Next: next,
 				})
 				cnt++
@@ -89,9 +105,9 @@ forconfiguration:
 					cnf = cp.FullC(cc.Blueps[i])
 				}
 
-				if err != nil && j == smc.Retry {
-					glog.Errorf("C%d: error %v from WriteN after %d retries: ", cc.Id, err, smc.Retry)
-					return nil, 0, err
+				if err != nil && (j == smc.Retry || ctx.Err() != nil) {
+					glog.Errorf("C%d: error %v from WriteN after %d retries: ", cc.Id, err, j)
+					return nil, cnt, smc.NewQuorumError(ctx, "WriteNext", cc.Blueps[i], err)
 				}
 
 				if err == nil {
@@ -105,23 +121,42 @@ forconfiguration:
 
 			cur = cc.HandleNewCur(cur, writeN.GetCur())
 
-			if rst.Compare(writeN.GetState()) == 1 {
-				rst = writeN.GetState()
+			sts = pb.MergeStates(sts, writeN.GetStates())
+			prs = pb.MergeStates(prs, writeN.GetPromises())
+			hs = pb.MergeVersions(hs, writeN.GetHistory())
+			cps = pb.MergeStates(cps, writeN.GetCompacted())
+			if lavs, err = lattice.MergeInstances(lavs, writeN.GetLAValues()); err != nil {
+				// Installing the new configuration without these states could lose agreed values.
+				glog.Errorf("C%d: could not merge lattice agreement states: %v\n", cc.Id, err)
+				return nil, cnt, fmt.Errorf("reconfiguration aborted: %v", err)
+			}
+			if st := pb.FindState(writeN.GetStates(), key); rst.Compare(st) == 1 {
+				rst = st
 			}
 
 		} else if i > cur || regular > 1 {
 			//Establish new cur, or write value in write, atomic read.
 
-			rst = cc.WriteValue(&val, rst)
+			rst = cc.WriteValue(key, &val, rst)
+			states, write := sts, rst
+			if regular <= 1 && rst != nil {
+				// Only moved to the new configuration, not written.
+				states, write = pb.MergeStates(sts, []*pb.State{rst}), nil
+			}
 
 			cnf := cp.WriteC(cc.Blueps[i], nil)
 
 			var setS *pb.SetStateReply
 
 			for j := 0; ; j++ {
-				setS, err = cnf.SetState(context.Background(), &pb.NewState{
-					CurC:  uint32(cc.Blueps[i].Len()),
-					State: rst,
+				setS, err = cnf.SetState(ctx, &pb.NewState{
+					CurC:      cc.Blueps[i].ID(),
+					States:    states,
+					LAValues:  lavs,
+					Promises:  prs,
+					History:   hs,
+					Compacted: cps,
+					Write:     write,
 				})
 				cnt++
 
@@ -131,9 +166,9 @@ forconfiguration:
 					cnf = cp.FullC(cc.Blueps[i])
 				}
 
-				if err != nil && j == smc.Retry {
-					glog.Errorf("C%d: error %v from SetState after %d retries: ", cc.Id, err, smc.Retry)
-					return nil, 0, err
+				if err != nil && (j == smc.Retry || ctx.Err() != nil) {
+					glog.Errorf("C%d: error %v from SetState after %d retries: ", cc.Id, err, j)
+					return nil, cnt, smc.NewQuorumError(ctx, "SetState", cc.Blueps[i], err)
 				}
 
 				if err == nil {
@@ -142,12 +177,13 @@ forconfiguration:
 			}
 
 			if i > 0 && glog.V(3) {
-				glog.Infof("C%d: Set state in configuration of size %d.\n", cc.Id, cc.Blueps[i].Len())
+				glog.Infof("C%d: Set state in configuration of size %d.\n", cc.Id, cc.Blueps[i].NSize())
 			} else if glog.V(6) {
 				glog.Infof("Set state returned.")
 			}
 
 			cur = cc.HandleOneCur(i, setS.GetCur())
+			rejected = rejected || setS.Rejected
 			cc.HandleNext(i, setS.GetNext())
 
 			if i < len(cc.Blueps)-1 {
@@ -159,14 +195,23 @@ forconfiguration:
 
 	cc.SetNewCur(cur)
 	if cnt > 2 {
-		cc.SetCur(cp, cc.Blueps[0])
+		cc.SetCur(ctx, cp, cc.Blueps[0])
 		cnt++
 	}
+	if rejected {
+		// A conditional write promised a larger ballot. Write, or write back the value read, with a ballot.
+		var c int
+		rst, _, c, err = cc.CondWrite(ctx, cp, key, wval, func(*pb.State) bool { return wval != nil })
+		cnt += c
+		if err != nil {
+			return nil, cnt, err
+		}
+	}
 
 	return rst, cnt, nil
 }
 
-func (cc *ConsClient) getconsensus(cp conf.Provider, i int, prop *bp.Blueprint) (next *bp.Blueprint, cnt, cur int, err error) {
+func (cc *ConsClient) getconsensus(ctx context.Context, cp conf.Provider, i int, prop *bp.Blueprint) (next *bp.Blueprint, cnt, cur int, err error) {
 	ms := 1 * time.Millisecond
 	rnd := cc.Id
 	// The 24 higher bits of rnd (uint32) are a counter, the lower 8 bits the client id. Should separate the two in the future, to simplify things
@@ -174,7 +219,7 @@ func (cc *ConsClient) getconsensus(cp conf.Provider, i int, prop *bp.Blueprint)
 prepare:
 	for {
 
-		var cnf *pb.Configuration
+		var cnf conf.Configuration
 		//Default leader need not do prepare phase.
 		if rnd != 0 {
 			//Send Prepare:
@@ -183,8 +228,8 @@ prepare:
 			var promise *pb.GetPromiseReply
 
 			for j := 0; ; j++ {
-				promise, err = cnf.GetPromise(context.Background(), &pb.Prepare{
-					CurC: uint32(cc.Blueps[i].Len()),
+				promise, err = cnf.GetPromise(ctx, &pb.Prepare{
+					CurC: cc.Blueps[i].ID(),
 					Rnd:  rnd})
 				if err != nil && j == 0 {
 					glog.Errorf("C%d: error from Optimized Prepare: %v\n", cc.Id, err)
@@ -193,9 +238,9 @@ prepare:
 				}
 				cnt++
 
-				if err != nil && j == smc.Retry {
-					glog.Errorf("C%d: error %v from Prepare after %d retries.\n", cc.Id, err, smc.Retry)
-					return nil, 0, 0, err
+				if err != nil && (j == smc.Retry || ctx.Err() != nil) {
+					glog.Errorf("C%d: error %v from Prepare after %d retries.\n", cc.Id, err, j)
+					return nil, cnt, cur, smc.NewQuorumError(ctx, "GetPromise", cc.Blueps[i], err)
 				}
 
 				if err == nil {
@@ -231,7 +276,7 @@ prepare:
 					next = prop.Merge(cc.Blueps[i]) // This could have side effects on prop. Is this a problem?
 					if len(prop.Ids()) == 0 {
 						glog.Errorf("Aborting Reconfiguration to avoid unacceptable configuration.")
-						return nil, cnt, cur, errors.New("Abort before proposing unacceptable configuration.")
+						return nil, cnt, cur, &smc.MinSizeError{Size: len(prop.Ids()), Min: 1}
 					}
 				}
 			case rrnd > rnd:
@@ -246,7 +291,11 @@ prepare:
 				} else {
 					rnd = rrnd - rrid + 256 + cc.Id
 				}
-				time.Sleep(ms)
+				select {
+				case <-time.After(ms):
+				case <-ctx.Done():
+					return nil, cnt, cur, smc.NewQuorumError(ctx, "GetPromise", cc.Blueps[i], ctx.Err())
+				}
 				ms = 2 * ms
 				continue prepare
 
@@ -260,8 +309,8 @@ prepare:
 		var learn *pb.AcceptReply
 
 		for j := 0; ; j++ {
-			learn, err = cnf.Accept(context.Background(), &pb.Propose{
-				CurC: uint32(cc.Blueps[i].Len()),
+			learn, err = cnf.Accept(ctx, &pb.Propose{
+				CurC: cc.Blueps[i].ID(),
 				Val:  &pb.CV{Rnd: rnd, Val: next},
 			})
 			cnt++
@@ -271,9 +320,9 @@ prepare:
 				cnf = cp.FullC(cc.Blueps[i])
 			}
 
-			if err != nil && j == smc.Retry {
-				glog.Errorf("C%d: error %v from Accept after %d retries: ", cc.Id, err, smc.Retry)
-				return nil, 0, cur, err
+			if err != nil && (j == smc.Retry || ctx.Err() != nil) {
+				glog.Errorf("C%d: error %v from Accept after %d retries: ", cc.Id, err, j)
+				return nil, cnt, cur, smc.NewQuorumError(ctx, "Accept", cc.Blueps[i], err)
 			}
 
 			if err == nil {
@@ -0,0 +1,235 @@
+/*
+Package controller implements automatic reconfiguration after failures.
+
+A Controller periodically probes the nodes of the current configuration and a pool of spare nodes.
+If a node of the configuration is suspected for longer than a threshold, the controller
+reconfigures, replacing the node with a spare node that is believed alive. All nodes suspected at
+that time are replaced by a single reconfiguration, and reconfigurations are rate limited.
+Nodes are only replaced, not removed: Without enough spare nodes, the remaining suspected nodes stay
+in the configuration, until new spare nodes are added.
+*/
+package controller
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+	smc "github.com/relab/smartmerge/smclient"
+)
+
+// Reconfigurer is a client, that can reconfigure. It is implemented by all register clients.
+type Reconfigurer interface {
+	Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (int, error)
+	GetCur() *bp.Blueprint
+}
+
+// Prober checks whether the node id is alive. It returns an error, if the node did not reply.
+// The controller probes different nodes concurrently.
+type Prober func(ctx context.Context, id uint32) error
+
+// Controller replaces suspected nodes of the configuration with spare nodes.
+type Controller struct {
+	cl    Reconfigurer
+	cp    conf.Provider
+	probe Prober
+
+	Threshold    time.Duration    // How long a node must be suspected, before it is replaced.
+	Interval     time.Duration    // The time between probes.
+	MinInterval  time.Duration    // The minimal time between two reconfigurations.
+	ProbeTimeout time.Duration    // The deadline for probing all nodes, in one step.
+	Clock        func() time.Time // Returns the current time. It can be replaced in tests.
+
+	mu         sync.Mutex
+	spares     []uint32             // Spare nodes, that can be added to the configuration.
+	suspected  map[uint32]time.Time // The time since each node is suspected.
+	lastReconf time.Time
+}
+
+// New returns a controller that reconfigures with the client cl, using the spare nodes spares.
+// Nodes are probed with probe, e.g. a StatusProber.
+func New(cl Reconfigurer, cp conf.Provider, probe Prober, spares []uint32) *Controller {
+	return &Controller{
+		cl:           cl,
+		cp:           cp,
+		probe:        probe,
+		Threshold:    5 * time.Second,
+		Interval:     time.Second,
+		MinInterval:  30 * time.Second,
+		ProbeTimeout: time.Second,
+		Clock:        time.Now,
+		spares:       append([]uint32(nil), spares...),
+		suspected:    make(map[uint32]time.Time),
+	}
+}
+
+// StatusProber returns a prober, that asks the node for its status, using the manager mgr.
+// The node must already be dialed. Status needs the operator role, if the servers use authorization.
+func StatusProber(mgr *pb.Manager) Prober {
+	return func(ctx context.Context, id uint32) error {
+		node, found := mgr.Node(id)
+		if !found {
+			return pb.NodeNotFoundError(id)
+		}
+		_, err := node.SMandConsRegisterClient.Status(ctx, &pb.StatusRequest{})
+		return err
+	}
+}
+
+// Spares returns the spare nodes, that were not added to the configuration yet.
+func (c *Controller) Spares() []uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]uint32(nil), c.spares...)
+}
+
+// Suspected returns the suspected nodes, and since when they are suspected.
+func (c *Controller) Suspected() map[uint32]time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := make(map[uint32]time.Time, len(c.suspected))
+	for id, t := range c.suspected {
+		s[id] = t
+	}
+	return s
+}
+
+// Run probes and reconfigures every Interval, until stop is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	for {
+		if _, err := c.Step(context.Background()); err != nil {
+			glog.Errorln("Controller: reconfiguration returned error:", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(c.Interval):
+		}
+	}
+}
+
+// Step probes all nodes of the current configuration and all spare nodes once.
+// If nodes of the configuration are suspected for longer than Threshold, and the last
+// reconfiguration is at least MinInterval ago, it replaces them by a single reconfiguration.
+// Step returns the proposed blueprint, or nil if it did not reconfigure.
+func (c *Controller) Step(ctx context.Context) (*bp.Blueprint, error) {
+	cur := c.cl.GetCur()
+	c.probeAll(ctx, bp.Union(cur.Ids(), c.Spares()))
+
+	c.mu.Lock()
+	now := c.Clock()
+	if !c.lastReconf.IsZero() && now.Sub(c.lastReconf) < c.MinInterval {
+		c.mu.Unlock()
+		return nil, nil
+	}
+	target, added := c.replace(cur, now)
+	if target == nil {
+		c.mu.Unlock()
+		return nil, nil
+	}
+	c.lastReconf = now
+	c.mu.Unlock()
+
+	glog.Infof("Controller: reconfiguring from %v to %v.\n", cur.Ids(), target.Ids())
+	if _, err := c.cl.Reconf(ctx, c.cp, target); err != nil {
+		unused := added
+		var ae *smc.AbortError
+		if errors.As(err, &ae) {
+			// Another configuration was installed. Only the spares it includes are used.
+			unused = bp.Difference(added, c.cl.GetCur().Ids())
+		}
+		// Try the unused spares again next time.
+		c.mu.Lock()
+		c.spares = bp.Union(c.spares, unused)
+		c.mu.Unlock()
+		return target, err
+	}
+	return target, nil
+}
+
+// probeAll probes the nodes ids in parallel, and updates the suspicions.
+// The probes share the deadline ProbeTimeout, such that failed nodes do not delay the step one after the other.
+func (c *Controller) probeAll(ctx context.Context, ids []uint32) {
+	ctx, cancel := context.WithTimeout(ctx, c.ProbeTimeout)
+	defer cancel()
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id uint32) {
+			defer wg.Done()
+			errs[i] = c.probe(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, id := range ids {
+		if errs[i] == nil {
+			delete(c.suspected, id)
+		} else if _, ok := c.suspected[id]; !ok {
+			glog.V(2).Infof("Controller: suspecting node %d: %v\n", id, errs[i])
+			c.suspected[id] = c.Clock()
+		}
+	}
+}
+
+// replace returns a blueprint, where the nodes of cur suspected for longer than Threshold are
+// replaced with spare nodes not suspected. With fewer spares than suspected nodes, only as many
+// nodes as there are spares are replaced, those with the smallest ids. It returns nil, if there is
+// nothing to replace, no spare node, or if the configuration would have less than smclient.MinSize nodes.
+// The spare nodes used are removed from the pool and returned. c.mu must be held.
+func (c *Controller) replace(cur *bp.Blueprint, now time.Time) (target *bp.Blueprint, added []uint32) {
+	var failed []uint32
+	for _, id := range cur.Ids() {
+		if since, ok := c.suspected[id]; ok && now.Sub(since) >= c.Threshold {
+			failed = append(failed, id)
+		}
+	}
+	if len(failed) == 0 {
+		return nil, nil
+	}
+	sort.Sort(byID(failed))
+
+	target = cur.Copy()
+	var spares []uint32
+	for _, s := range c.spares {
+		if _, suspected := c.suspected[s]; suspected || len(added) == len(failed) {
+			spares = append(spares, s)
+			continue
+		}
+		if target.Add(s) {
+			added = append(added, s)
+		}
+		// Spares already in the configuration are dropped from the pool.
+	}
+	if len(added) == 0 {
+		glog.Errorf("Controller: not replacing %v, since no spare node is available.\n", failed)
+		return nil, nil
+	}
+	for _, f := range failed[:len(added)] {
+		target.Rem(f)
+	}
+
+	if n := len(target.Ids()); n < smc.MinSize {
+		glog.Errorf("Controller: not replacing %v, since only %d nodes would be left.\n", failed, n)
+		return nil, nil
+	}
+	c.spares = spares
+	return target, added
+}
+
+type byID []uint32
+
+func (p byID) Len() int           { return len(p) }
+func (p byID) Less(i, j int) bool { return p[i] < p[j] }
+func (p byID) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
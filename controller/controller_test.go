@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	smc "github.com/relab/smartmerge/smclient"
+)
+
+// fixed is a Reconfigurer, that stays in its configuration.
+type fixed struct{ cur *bp.Blueprint }
+
+func (f fixed) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (int, error) {
+	return 0, nil
+}
+
+func (f fixed) GetCur() *bp.Blueprint { return f.cur }
+
+// aborting is a Reconfigurer, whose reconfigurations are aborted, since next is installed instead.
+type aborting struct{ cur, next *bp.Blueprint }
+
+func (a *aborting) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (int, error) {
+	a.cur = a.next
+	return 0, &smc.AbortError{Prop: prop, Cur: a.next}
+}
+
+func (a *aborting) GetCur() *bp.Blueprint { return a.cur }
+
+// failing returns a prober, where the nodes ids fail.
+func failing(ids ...uint32) Prober {
+	return func(ctx context.Context, id uint32) error {
+		for _, f := range ids {
+			if id == f {
+				return errors.New("failed")
+			}
+		}
+		return nil
+	}
+}
+
+// TestParallelProbes checks, that nodes, that do not reply, are probed in parallel,
+// and are suspected after ProbeTimeout.
+func TestParallelProbes(t *testing.T) {
+	cur := &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}}}
+	c := New(fixed{cur}, nil, func(ctx context.Context, id uint32) error {
+		if id == 1 {
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, []uint32{5, 6})
+	c.ProbeTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	if _, err := c.Step(context.Background()); err != nil {
+		t.Fatal("Step returned error:", err)
+	}
+	if d := time.Since(start); d > 3*c.ProbeTimeout {
+		t.Errorf("probing 5 failed nodes took %v, with ProbeTimeout %v", d, c.ProbeTimeout)
+	}
+	if s := c.Suspected(); len(s) != 5 {
+		t.Errorf("got suspected %v, want 2 to 6", s)
+	}
+}
+
+// TestFewSpares checks, that with fewer spares than failed nodes, only as many nodes are replaced.
+func TestFewSpares(t *testing.T) {
+	cur := &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}}}
+	c := New(fixed{cur}, nil, failing(2, 3), []uint32{5})
+	c.Threshold = 0
+	target, err := c.Step(context.Background())
+	if err != nil {
+		t.Fatal("Step returned error:", err)
+	}
+	if target == nil || !reflect.DeepEqual(target.Ids(), []uint32{1, 3, 4, 5}) {
+		t.Errorf("got target %v, want 2 replaced by 5", target)
+	}
+	if target, _ = c.Step(context.Background()); target != nil {
+		t.Errorf("reconfigured to %v without spare nodes", target.Ids())
+	}
+}
+
+// TestAbortReturnsSpares checks, that spares not included in the configuration installed instead
+// of the proposal are returned to the pool.
+func TestAbortReturnsSpares(t *testing.T) {
+	cur := &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}}}
+	next := &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 3}, {Id: 4}, {Id: 5}}}
+	c := New(&aborting{cur: cur, next: next}, nil, failing(2, 3), []uint32{5, 6})
+	c.Threshold = 0
+	target, err := c.Step(context.Background())
+	var ae *smc.AbortError
+	if !errors.As(err, &ae) {
+		t.Fatalf("Step returned error %v, want abort", err)
+	}
+	if target == nil || !reflect.DeepEqual(target.Ids(), []uint32{1, 4, 5, 6}) {
+		t.Errorf("got target %v, want 2 and 3 replaced", target)
+	}
+	if sp := c.Spares(); !reflect.DeepEqual(sp, []uint32{6}) {
+		t.Errorf("got spares %v, want 6", sp)
+	}
+}
@@ -1,9 +1,13 @@
 // Package doreconf implmements a client that helps to perform reconfigurations,
 // when new, not yet installed configurations are found during Read or Write operations.
 // The client will have no advantage from using a norecontact configuration provider.
+// Its reads and writes store states with SetState, which rejects them if a larger ballot was promised to a
+// conditional write. They are then completed with a ballot, see smclient.CondWrite.
 package doreconf
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/golang/glog"
 	bp "github.com/relab/smartmerge/blueprints"
 	conf "github.com/relab/smartmerge/confProvider"
@@ -13,8 +17,8 @@ import (
 )
 
 type Reconfer interface {
-	Doreconf(conf.Provider, *bp.Blueprint, int, []byte) (*pb.State, int, error)
-	Reconf(conf.Provider, *bp.Blueprint) (int, error)
+	Doreconf(context.Context, conf.Provider, *bp.Blueprint, int, string, []byte) (*pb.State, int, error)
+	Reconf(context.Context, conf.Provider, *bp.Blueprint) (int, error)
 	GetCur() *bp.Blueprint
 }
 
@@ -44,18 +48,16 @@ func NewCons(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*DoreconfClien
 	return &DoreconfClient{rec}, nil
 }
 
-//Atomic read
-func (drc *DoreconfClient) Read(cp conf.Provider) (val []byte, cnt int) {
+//Atomic read of the register key.
+func (drc *DoreconfClient) Read(ctx context.Context, cp conf.Provider, key string) (val []byte, cnt int, err error) {
 	if glog.V(5) {
 		glog.Infoln("starting Read")
 	}
 	var st *pb.State
-	var err error
 
-	st, cnt, err = drc.Doreconf(cp, nil, 2, nil)
+	st, cnt, err = drc.Doreconf(ctx, cp, nil, 2, key, nil)
 	if err != nil {
-		glog.Errorln("Error during Read", err)
-		return nil, 0
+		return nil, cnt, err
 	}
 
 	if glog.V(3) {
@@ -64,25 +66,21 @@ func (drc *DoreconfClient) Read(cp conf.Provider) (val []byte, cnt int) {
 		}
 	}
 	if st == nil {
-		glog.Errorln("read returned nil state")
-		return nil, cnt
+		return nil, cnt, nil
 	}
-	return st.Value, cnt
+	return st.Value, cnt, nil
 }
 
-//Regular read
-func (drc *DoreconfClient) RRead(cp conf.Provider) (val []byte, cnt int) {
+//Regular read of the register key.
+func (drc *DoreconfClient) RRead(ctx context.Context, cp conf.Provider, key string) (val []byte, cnt int, err error) {
 	if glog.V(5) {
 		glog.Infoln("starting regular Read")
 	}
 	var st *pb.State
-	var err error
-
-	st, cnt, err = drc.Doreconf(cp, nil, 1, nil)
 
+	st, cnt, err = drc.Doreconf(ctx, cp, nil, 1, key, nil)
 	if err != nil {
-		glog.Errorln("Error during RRead")
-		return nil, 0
+		return nil, cnt, err
 	}
 	if glog.V(3) {
 		if cnt > 1 {
@@ -90,28 +88,25 @@ func (drc *DoreconfClient) RRead(cp conf.Provider) (val []byte, cnt int) {
 		}
 	}
 	if st == nil {
-		glog.Errorln("read returned nil state")
-		return nil, cnt
+		return nil, cnt, nil
 	}
-	return st.Value, cnt
+	return st.Value, cnt, nil
 }
 
-func (drc *DoreconfClient) Write(cp conf.Provider, val []byte) (cnt int) {
+// Write writes val to the register key.
+func (drc *DoreconfClient) Write(ctx context.Context, cp conf.Provider, key string, val []byte) (cnt int, err error) {
 	if glog.V(5) {
 		glog.Infoln("starting Write")
 	}
-	var err error
-
-	_, cnt, err = drc.Doreconf(cp, nil, 2, val)
 
+	_, cnt, err = drc.Doreconf(ctx, cp, nil, 2, key, val)
 	if err != nil {
-		glog.Errorln("Error during Write")
-		return 0
+		return cnt, err
 	}
 	if glog.V(3) {
 		if cnt > 2 {
 			glog.Infof("Write used %d accesses\n", cnt)
 		}
 	}
-	return cnt
+	return cnt, nil
 }
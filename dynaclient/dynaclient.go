@@ -0,0 +1,286 @@
+/*
+Package dynaclient implements the client side of the DynaStore algorithm.
+
+DynaStore does not install configurations at the servers. Instead, every configuration, called a view,
+stores the successor views proposed by the clients. An operation traverses the graph of views,
+starting from the last view known to the client, until it reaches a view without successors,
+that includes all views it found on the way. On every view it leaves, the client records the successors
+and reads the states of all registers, which are then written to the last view.
+
+The way successors are proposed can be replaced, see NewWithNext.
+This is used by package ssrclient, which implements SpSnStore.
+*/
+package dynaclient
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+	smc "github.com/relab/smartmerge/smclient"
+)
+
+// NextFunc returns the successors, the client proposes for the view s,
+// if no successors are recorded yet. desired includes s and all views, the client knows of.
+// It returns the number of quorum calls.
+type NextFunc func(ctx context.Context, cp conf.Provider, s, desired *bp.Blueprint) (next []*bp.Blueprint, cnt int, err error)
+
+// DynaClient is a DynaStore client. It can be used by concurrent goroutines.
+type DynaClient struct {
+	Id uint32
+
+	next NextFunc
+
+	mu  sync.Mutex // Protects cur and ts.
+	cur *bp.Blueprint
+	ts  int32 // The largest timestamp written by this client.
+}
+
+// The operations performed by traverse.
+const (
+	reconf = iota
+	regularRead
+	atomicRead
+	write
+)
+
+// allKeys selects all registers.
+var allKeys = &pb.Keys{Range: true}
+
+// New returns a DynaStore client, starting in the view initBlp.
+func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*DynaClient, error) {
+	return NewWithNext(initBlp, id, propose), nil
+}
+
+// NewWithNext returns a client, starting in the view initBlp, that proposes successors with next.
+func NewWithNext(initBlp *bp.Blueprint, id uint32, next NextFunc) *DynaClient {
+	glog.Infof("New Client with Id: %d\n", id)
+	return &DynaClient{Id: id, next: next, cur: initBlp}
+}
+
+// propose proposes the desired view as the single successor of s.
+func propose(ctx context.Context, cp conf.Provider, s, desired *bp.Blueprint) ([]*bp.Blueprint, int, error) {
+	return []*bp.Blueprint{desired}, 0, nil
+}
+
+// Read performs an atomic read of the register key.
+func (dc *DynaClient) Read(ctx context.Context, cp conf.Provider, key string) (val []byte, cnt int, err error) {
+	st, cnt, err := dc.traverse(ctx, cp, nil, atomicRead, key, nil)
+	if err != nil || st == nil {
+		return nil, cnt, err
+	}
+	return st.Value, cnt, nil
+}
+
+// RRead performs a regular read of the register key. It does not write back the read value.
+func (dc *DynaClient) RRead(ctx context.Context, cp conf.Provider, key string) (val []byte, cnt int, err error) {
+	st, cnt, err := dc.traverse(ctx, cp, nil, regularRead, key, nil)
+	if err != nil || st == nil {
+		return nil, cnt, err
+	}
+	return st.Value, cnt, nil
+}
+
+// Write writes val to the register key.
+func (dc *DynaClient) Write(ctx context.Context, cp conf.Provider, key string, val []byte) (cnt int, err error) {
+	_, cnt, err = dc.traverse(ctx, cp, nil, write, key, val)
+	return cnt, err
+}
+
+// Reconf moves to a view including the proposed blueprint prop.
+func (dc *DynaClient) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (cnt int, err error) {
+	cur := dc.GetCur()
+	//Proposed blueprint is already in place, or outdated.
+	if prop.Compare(cur) == 1 {
+		glog.V(3).Infof("C%d: Proposal is already in place.", dc.Id)
+		return 0, nil
+	}
+	if size := len(cur.Merge(prop).Ids()); size < smc.MinSize {
+		glog.Errorf("Aborting Reconfiguration to avoid unacceptable configuration.")
+		return 0, &smc.MinSizeError{Size: size, Min: smc.MinSize}
+	}
+
+	_, cnt, err = dc.traverse(ctx, cp, prop, reconf, "", nil)
+	return cnt, err
+}
+
+// GetCur returns a copy of the last view the client reached.
+func (dc *DynaClient) GetCur() *bp.Blueprint {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return dc.cur.Copy()
+}
+
+// setCur sets the current view to s, if it includes the current view.
+func (dc *DynaClient) setCur(s *bp.Blueprint) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.cur.Compare(s) == 1 {
+		dc.cur = s
+	}
+}
+
+// timestamp returns a timestamp larger than ts, and larger than all timestamps used by the client before.
+func (dc *DynaClient) timestamp(ts int32) int32 {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.ts > ts {
+		ts = dc.ts
+	}
+	dc.ts = ts + 1
+	return dc.ts
+}
+
+// traverse moves from the current view to a view without successors, that includes prop
+// and all views found on the way. The views are visited in the order of their ids.
+// It performs the operation op on the register key, and returns the read or written state.
+func (dc *DynaClient) traverse(ctx context.Context, cp conf.Provider, prop *bp.Blueprint, op int, key string, val []byte) (rst *pb.State, cnt int, err error) {
+	cur := dc.GetCur()
+	desired := cur.Merge(prop)
+	front := []*bp.Blueprint{cur}
+	keys := &pb.Keys{Key: key}
+	var sts []*pb.State // States of all registers read so far.
+	var wst *pb.State   // The state written by a write operation.
+
+	for len(front) > 0 {
+		s := front[0]
+		front = front[1:]
+
+		var rep *pb.DWriteNextReply
+		c, err := Call(ctx, cp, s, "DWriteNext", func(cnf conf.Configuration) (err error) {
+			rep, err = cnf.DWriteNext(ctx, &pb.DWriteN{CurC: s.ID(), Keys: keys})
+			return err
+		})
+		cnt += c
+		if err != nil {
+			return nil, cnt, err
+		}
+		sts = pb.MergeStates(sts, rep.GetStates())
+
+		next := rep.GetNext()
+		if len(next) == 0 && !s.LearnedEquals(desired) {
+			next, c, err = dc.next(ctx, cp, s, desired)
+			cnt += c
+			if err != nil {
+				return nil, cnt, err
+			}
+		}
+
+		if len(next) > 0 {
+			// Leave s: Record all successors we will visit, and read all registers.
+			for {
+				c, err := Call(ctx, cp, s, "DWriteNext", func(cnf conf.Configuration) (err error) {
+					rep, err = cnf.DWriteNext(ctx, &pb.DWriteN{CurC: s.ID(), Next: next, Keys: allKeys})
+					return err
+				})
+				cnt += c
+				if err != nil {
+					return nil, cnt, err
+				}
+				sts = pb.MergeStates(sts, rep.GetStates())
+				if len(rep.GetNext()) == len(next) {
+					break
+				}
+				// Other clients proposed successors, that may not be recorded at a quorum yet.
+				next = rep.GetNext()
+			}
+
+			for _, x := range next {
+				if x.Compare(s) == 1 {
+					continue
+				}
+				w := s.Merge(x)
+				desired = desired.Merge(w)
+				front = insert(front, w)
+			}
+			if glog.V(4) {
+				glog.Infof("C%d: leaving view with %d successors.\n", dc.Id, len(next))
+			}
+			continue
+		}
+
+		// s is the desired view, and has no successors.
+		rst = pb.FindState(sts, key)
+		switch op {
+		case regularRead:
+			dc.setCur(s)
+			return rst, cnt, nil
+		case write:
+			if wst == nil {
+				var ts int32
+				if rst != nil {
+					ts = rst.Timestamp
+				}
+				wst = &pb.State{Value: val, Timestamp: dc.timestamp(ts), Writer: dc.Id, Key: key}
+			}
+			sts = pb.MergeStates(sts, []*pb.State{wst})
+			rst = wst
+		}
+
+		var srep *pb.DSetStateReply
+		c, err = Call(ctx, cp, s, "DSetState", func(cnf conf.Configuration) (err error) {
+			srep, err = cnf.DSetState(ctx, &pb.DNewState{CurC: s.ID(), States: sts})
+			return err
+		})
+		cnt += c
+		if err != nil {
+			return nil, cnt, err
+		}
+		if len(srep.GetNext()) > 0 {
+			// A successor was recorded concurrently. Continue from s.
+			front = insert(front, s)
+			continue
+		}
+
+		dc.setCur(s)
+		return rst, cnt, nil
+	}
+	return nil, cnt, errors.New("traversal found no view without successors")
+}
+
+// Call performs a quorum call on the view s. f is called with the configuration,
+// and is tried again with the full configuration, if it returns an error.
+// It returns the number of quorum calls.
+func Call(ctx context.Context, cp conf.Provider, s *bp.Blueprint, method string, f func(cnf conf.Configuration) error) (cnt int, err error) {
+	cnf := cp.WriteC(s, nil)
+	for j := 0; ; j++ {
+		err = f(cnf)
+		cnt++
+
+		if err == nil {
+			return cnt, nil
+		}
+
+		if j == 0 {
+			glog.Errorf("error from Optimized%s: %v\n", method, err)
+			// Try again with full configuration.
+			cnf = cp.FullC(s)
+		}
+
+		if j == smc.Retry || ctx.Err() != nil {
+			glog.Errorf("error %v from %s after %d retries.\n", err, method, j)
+			return cnt, smc.NewQuorumError(ctx, method, s, err)
+		}
+	}
+}
+
+// insert inserts the view w into the list views, which is sorted by id, if it is not present.
+func insert(views []*bp.Blueprint, w *bp.Blueprint) []*bp.Blueprint {
+	i := 0
+	for i < len(views) && views[i].LearnedCompare(w) == 1 {
+		i++
+	}
+	if i < len(views) && views[i].LearnedEquals(w) {
+		return views
+	}
+	views = append(views, nil)
+	copy(views[i+1:], views[i:])
+	views[i] = w
+	return views
+}
@@ -0,0 +1,183 @@
+/*
+Package lattice implements lattice agreement for the elements of any join-semilattice.
+
+In lattice agreement, every proposer proposes a value, and learns a value, such that
+all learned values are comparable, every learned value includes the proposers own value,
+and is the merge of proposed values only.
+SmartMerge uses lattice agreement to agree on blueprints. Other lattices, e.g.
+grow-only sets, max-registers or version vectors, can be used after registering them.
+
+The package provides the rules for acceptors (servers) and proposers (clients).
+The quorum calls are implemented by the regserver and smclient packages.
+*/
+package lattice
+
+import (
+	"fmt"
+	"sync"
+
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// Value is an element of a join-semilattice.
+type Value interface {
+	// Type returns the name the lattice type was registered with.
+	Type() string
+	// Merge returns the least upper bound of the value and v. It must not modify either of them.
+	// v is of the same type as the value.
+	Merge(v Value) Value
+	// Compare compares the value a with b, which is of the same type:
+	// a.Compare(b) = 1 <=> a <= b
+	// a.Compare(b) = -1 <=> b < a
+	// a.Compare(b) = 0 <=> !(b <= a) && !(a <= b)
+	Compare(v Value) int
+	// Marshal returns the encoding of the value,
+	// that is decoded by the function the type was registered with.
+	Marshal() ([]byte, error)
+}
+
+// Merge returns the least upper bound of a and b.
+// A nil Value is smaller than all values.
+func Merge(a, b Value) Value {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return a.Merge(b)
+}
+
+// Compare compares a and b like Value.Compare.
+// A nil Value is smaller than all values.
+func Compare(a, b Value) int {
+	if a == nil {
+		return 1
+	}
+	if b == nil {
+		return -1
+	}
+	return a.Compare(b)
+}
+
+// Equal reports whether a <= b and b <= a.
+func Equal(a, b Value) bool {
+	return Compare(a, b) == 1 && Compare(b, a) == 1
+}
+
+// Accept is the rule of an acceptor, with state state, receiving the proposal prop.
+// If state <= prop, the proposal is accepted and becomes the new state.
+// Otherwise, the new state is the merge of state and prop,
+// and the proposer has to propose it again.
+func Accept(state, prop Value) (newState Value, accepted bool) {
+	if Compare(state, prop) == 1 {
+		return prop, true
+	}
+	return Merge(state, prop), false
+}
+
+// Propose is the rule of a proposer. It proposes prop using propose,
+// which sends a proposal to a quorum of acceptors, and returns the merge of the states
+// of all acceptors that did not accept it, or nil, if all accepted.
+// Propose merges the returned states into the proposal, until it is accepted,
+// and returns the accepted, i.e. learned value.
+func Propose(prop Value, propose func(Value) (Value, error)) (Value, error) {
+	for {
+		st, err := propose(prop)
+		if err != nil {
+			return nil, err
+		}
+		if Compare(st, prop) == 1 {
+			return prop, nil
+		}
+		prop = Merge(prop, st)
+	}
+}
+
+var (
+	mu    sync.RWMutex
+	types = make(map[string]func(data []byte) (Value, error))
+)
+
+// Register makes the lattice type name available to Unmarshal.
+// unmarshal must decode the values returned by Value.Marshal.
+// Register panics, if name is registered twice.
+func Register(name string, unmarshal func(data []byte) (Value, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := types[name]; dup {
+		panic("lattice: Register called twice for type " + name)
+	}
+	types[name] = unmarshal
+}
+
+// Marshal encodes v as a LAValue message. A nil Value is encoded as nil.
+func Marshal(v Value) (*pb.LAValue, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := v.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LAValue{Type: v.Type(), Value: data}, nil
+}
+
+// Unmarshal decodes a LAValue message, using the function registered for its type.
+// A nil message is decoded as a nil Value.
+func Unmarshal(m *pb.LAValue) (Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	mu.RLock()
+	unmarshal, ok := types[m.Type]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("lattice: unknown type %q", m.Type)
+	}
+	return unmarshal(m.Value)
+}
+
+// MergeInstances merges two lists of instance states, sorted by instance.
+// The states of instances contained in both lists are merged.
+// The returned list is sorted, a and b are not modified.
+func MergeInstances(a, b []*pb.LAInstance) ([]*pb.LAInstance, error) {
+	m := make([]*pb.LAInstance, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].Instance < b[j].Instance:
+			m = append(m, a[i])
+			i++
+		case b[j].Instance < a[i].Instance:
+			m = append(m, b[j])
+			j++
+		default:
+			st, err := mergeStates(a[i].State, b[j].State)
+			if err != nil {
+				return nil, fmt.Errorf("instance %q: %v", a[i].Instance, err)
+			}
+			m = append(m, &pb.LAInstance{Instance: a[i].Instance, State: st})
+			i++
+			j++
+		}
+	}
+	m = append(m, a[i:]...)
+	return append(m, b[j:]...), nil
+}
+
+// mergeStates returns the encoding of the merge of the encoded values a and b.
+func mergeStates(a, b *pb.LAValue) (*pb.LAValue, error) {
+	va, err := Unmarshal(a)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+	if va != nil && vb != nil && va.Type() != vb.Type() {
+		return nil, fmt.Errorf("lattice: cannot merge %s with %s", va.Type(), vb.Type())
+	}
+	return Marshal(Merge(va, vb))
+}
@@ -0,0 +1,141 @@
+package lattice
+
+import (
+	"reflect"
+	"testing"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+var b1 = &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}}, FaultTolerance: 1, Epoch: 1}
+var b12 = &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 2}}, FaultTolerance: 1, Epoch: 1}
+var b13 = &bp.Blueprint{Nodes: []*bp.Node{{Id: 1}, {Id: 3}}, FaultTolerance: 1, Epoch: 1}
+
+var compareTests = []struct {
+	a, b Value
+	cmp  int
+}{
+	{NewSet("a"), NewSet("a", "b"), 1},
+	{NewSet("a", "b"), NewSet("a"), -1},
+	{NewSet("a"), NewSet("b"), 0},
+	{NewSet("b", "a", "b"), NewSet("a", "b"), 1},
+	{Max(3), Max(4), 1},
+	{Max(4), Max(3), -1},
+	{VersionVector{1: 2}, VersionVector{1: 2, 2: 1}, 1},
+	{VersionVector{1: 2, 2: 0}, VersionVector{1: 2}, 1},
+	{VersionVector{1: 3}, VersionVector{1: 2, 2: 1}, 0},
+	{Blueprint{Blueprint: b1}, Blueprint{Blueprint: b12}, 1},
+	{Blueprint{Blueprint: b12}, Blueprint{Blueprint: b13}, 0},
+	{nil, Max(0), 1},
+	{Max(0), nil, -1},
+}
+
+func TestCompare(t *testing.T) {
+	for _, test := range compareTests {
+		if cmp := Compare(test.a, test.b); cmp != test.cmp {
+			t.Errorf("Compare(%v, %v) = %d, expected %d", test.a, test.b, cmp, test.cmp)
+		}
+		m := Merge(test.a, test.b)
+		if Compare(test.a, m) != 1 || Compare(test.b, m) != 1 {
+			t.Errorf("Merge(%v, %v) = %v is not an upper bound", test.a, test.b, m)
+		}
+		if test.cmp == 1 && !Equal(m, test.b) {
+			t.Errorf("Merge(%v, %v) = %v, expected %v", test.a, test.b, m, test.b)
+		}
+	}
+}
+
+func TestMergeSet(t *testing.T) {
+	m := NewSet("a", "c").Merge(NewSet("b", "c", "d"))
+	if exp := NewSet("a", "b", "c", "d"); !reflect.DeepEqual(m, exp) {
+		t.Errorf("got %v, expected %v", m, exp)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, v := range []Value{NewSet("a", "bc"), NewSet(), Max(1 << 40), VersionVector{1: 2, 7: 300}, Blueprint{Blueprint: b12}} {
+		m, err := Marshal(v)
+		if err != nil {
+			t.Fatalf("could not marshal %v: %v", v, err)
+		}
+		u, err := Unmarshal(m)
+		if err != nil {
+			t.Fatalf("could not unmarshal %v: %v", v, err)
+		}
+		if u.Type() != v.Type() || !Equal(u, v) {
+			t.Errorf("unmarshaled %v, expected %v", u, v)
+		}
+	}
+
+	if m, err := Marshal(nil); m != nil || err != nil {
+		t.Errorf("Marshal(nil) = %v, %v, expected nil", m, err)
+	}
+	m, _ := Marshal(Max(1))
+	m.Type = "unknown"
+	if _, err := Unmarshal(m); err == nil {
+		t.Error("unmarshaled a value of unknown type")
+	}
+	m.Type = "set"
+	m.Value = []byte{5, 'a'}
+	if _, err := Unmarshal(m); err == nil {
+		t.Error("unmarshaled a corrupt set")
+	}
+}
+
+func TestAccept(t *testing.T) {
+	st, ok := Accept(nil, Max(2))
+	if !ok || st != Max(2) {
+		t.Errorf("initial state did not accept, new state %v", st)
+	}
+	st, ok = Accept(NewSet("a"), NewSet("b"))
+	if ok || !Equal(st, NewSet("a", "b")) {
+		t.Errorf("accepted incomparable proposal, new state %v", st)
+	}
+}
+
+// TestPropose runs Propose against three acceptors, where one acceptor has a state
+// that must be learned before the proposal is accepted.
+func TestPropose(t *testing.T) {
+	acceptors := []Value{nil, NewSet("x"), nil}
+	calls := 0
+	dec, err := Propose(NewSet("a"), func(prop Value) (Value, error) {
+		calls++
+		var st Value
+		for i := range acceptors {
+			var ok bool
+			if acceptors[i], ok = Accept(acceptors[i], prop); !ok {
+				st = Merge(st, acceptors[i])
+			}
+		}
+		return st, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(dec, NewSet("a", "x")) || calls != 2 {
+		t.Errorf("learned %v after %d proposals, expected [a x] after 2", dec, calls)
+	}
+}
+
+func TestMergeInstances(t *testing.T) {
+	sa, _ := Marshal(NewSet("a"))
+	sb, _ := Marshal(NewSet("b"))
+	sab, _ := Marshal(NewSet("a", "b"))
+	m1, _ := Marshal(Max(1))
+	x := []*pb.LAInstance{{Instance: "m", State: m1}, {Instance: "s", State: sa}}
+	y := []*pb.LAInstance{{Instance: "s", State: sb}, {Instance: "t", State: sb}}
+	m, err := MergeInstances(x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []*pb.LAInstance{{Instance: "m", State: m1}, {Instance: "s", State: sab}, {Instance: "t", State: sb}}
+	if !reflect.DeepEqual(m, exp) {
+		t.Errorf("got %v, expected %v", m, exp)
+	}
+
+	y[0].State = m1
+	if _, err = MergeInstances(x, y); err == nil {
+		t.Error("merged instance states of different types")
+	}
+}
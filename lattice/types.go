@@ -0,0 +1,254 @@
+package lattice
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	bp "github.com/relab/smartmerge/blueprints"
+)
+
+func init() {
+	Register("blueprint", unmarshalBlueprint)
+	Register("set", unmarshalSet)
+	Register("max", unmarshalMax)
+	Register("versionvector", unmarshalVersionVector)
+}
+
+var errCorrupt = errors.New("lattice: corrupt value")
+
+// Blueprint is the lattice of blueprints, used to agree on configurations.
+type Blueprint struct {
+	*bp.Blueprint
+}
+
+func (b Blueprint) Type() string { return "blueprint" }
+
+func (b Blueprint) Merge(v Value) Value {
+	return Blueprint{b.Blueprint.Merge(v.(Blueprint).Blueprint)}
+}
+
+func (b Blueprint) Compare(v Value) int {
+	return b.Blueprint.Compare(v.(Blueprint).Blueprint)
+}
+
+func (b Blueprint) Marshal() ([]byte, error) {
+	if b.Blueprint == nil {
+		return nil, nil
+	}
+	return b.Blueprint.Marshal()
+}
+
+func unmarshalBlueprint(data []byte) (Value, error) {
+	if len(data) == 0 {
+		return Blueprint{}, nil
+	}
+	blp := new(bp.Blueprint)
+	if err := blp.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return Blueprint{blp}, nil
+}
+
+// Set is a grow-only set of strings, ordered by inclusion.
+// The elements are sorted, use NewSet to create a Set.
+type Set []string
+
+// NewSet returns the set of elems.
+func NewSet(elems ...string) Set {
+	s := append(Set(nil), elems...)
+	sort.Strings(s)
+	return s.dedup()
+}
+
+func (s Set) dedup() Set {
+	res := s[:0]
+	for _, e := range s {
+		if len(res) == 0 || e != res[len(res)-1] {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Contains reports whether e is an element of s.
+func (s Set) Contains(e string) bool {
+	i := sort.SearchStrings(s, e)
+	return i < len(s) && s[i] == e
+}
+
+func (s Set) Type() string { return "set" }
+
+func (s Set) Merge(v Value) Value {
+	o := v.(Set)
+	m := make(Set, 0, len(s)+len(o))
+	i, j := 0, 0
+	for i < len(s) && j < len(o) {
+		switch {
+		case s[i] < o[j]:
+			m = append(m, s[i])
+			i++
+		case o[j] < s[i]:
+			m = append(m, o[j])
+			j++
+		default:
+			m = append(m, s[i])
+			i++
+			j++
+		}
+	}
+	m = append(m, s[i:]...)
+	return append(m, o[j:]...)
+}
+
+func (s Set) Compare(v Value) int {
+	o := v.(Set)
+	return compare(s.subset(o), o.subset(s))
+}
+
+func (s Set) subset(o Set) bool {
+	if len(s) > len(o) {
+		return false
+	}
+	for _, e := range s {
+		if !o.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Set) Marshal() ([]byte, error) {
+	var data []byte
+	for _, e := range s {
+		data = appendUvarint(data, uint64(len(e)))
+		data = append(data, e...)
+	}
+	return data, nil
+}
+
+func unmarshalSet(data []byte) (Value, error) {
+	var s Set
+	for len(data) > 0 {
+		l, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < l {
+			return nil, errCorrupt
+		}
+		s = append(s, string(data[n:n+int(l)]))
+		data = data[n+int(l):]
+	}
+	return s, nil
+}
+
+// Max is a max-register. Merge returns the larger value.
+type Max uint64
+
+func (m Max) Type() string { return "max" }
+
+func (m Max) Merge(v Value) Value {
+	if o := v.(Max); o > m {
+		return o
+	}
+	return m
+}
+
+func (m Max) Compare(v Value) int {
+	o := v.(Max)
+	return compare(m <= o, o <= m)
+}
+
+func (m Max) Marshal() ([]byte, error) {
+	return appendUvarint(nil, uint64(m)), nil
+}
+
+func unmarshalMax(data []byte) (Value, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 || n != len(data) {
+		return nil, errCorrupt
+	}
+	return Max(x), nil
+}
+
+// VersionVector maps node ids to counters. Merge takes the maximum of each counter.
+// A missing entry equals zero.
+type VersionVector map[uint32]uint64
+
+func (vv VersionVector) Type() string { return "versionvector" }
+
+func (vv VersionVector) Merge(v Value) Value {
+	o := v.(VersionVector)
+	m := make(VersionVector, len(vv))
+	for id, c := range vv {
+		m[id] = c
+	}
+	for id, c := range o {
+		if c > m[id] {
+			m[id] = c
+		}
+	}
+	return m
+}
+
+func (vv VersionVector) Compare(v Value) int {
+	o := v.(VersionVector)
+	return compare(vv.leq(o), o.leq(vv))
+}
+
+func (vv VersionVector) leq(o VersionVector) bool {
+	for id, c := range vv {
+		if c > o[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// Marshal encodes the non-zero entries, sorted by id.
+func (vv VersionVector) Marshal() ([]byte, error) {
+	ids := make([]uint32, 0, len(vv))
+	for id, c := range vv {
+		if c > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var data []byte
+	for _, id := range ids {
+		data = appendUvarint(data, uint64(id))
+		data = appendUvarint(data, vv[id])
+	}
+	return data, nil
+}
+
+func unmarshalVersionVector(data []byte) (Value, error) {
+	vv := make(VersionVector)
+	for len(data) > 0 {
+		id, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errCorrupt
+		}
+		c, m := binary.Uvarint(data[n:])
+		if m <= 0 {
+			return nil, errCorrupt
+		}
+		vv[uint32(id)] = c
+		data = data[n+m:]
+	}
+	return vv, nil
+}
+
+// compare returns the result of Compare, given whether a <= b and b <= a.
+func compare(aleqb, bleqa bool) int {
+	switch {
+	case aleqb:
+		return 1
+	case bleqa:
+		return -1
+	}
+	return 0
+}
+
+func appendUvarint(data []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(data, buf[:binary.PutUvarint(buf[:], x)]...)
+}
@@ -0,0 +1,119 @@
+package leader
+
+import (
+	"math/rand"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// LeaseDuration is the duration of the leader lease requested by candidates.
+// A leader renews its lease every third of this duration.
+var LeaseDuration = 2 * time.Second
+
+// Campaign asks the servers of the current configuration for a leader lease.
+// A leader renews its lease with the same ballot. Otherwise a new ballot is used,
+// with a round larger than all rounds known. Campaign reports whether the lease was granted.
+//
+// Leases only decide which server handles forwarded proposals.
+// Reconfigurations stay safe with several leaders, since they are decided by consensus.
+func (l *Leader) Campaign(ctx context.Context) (bool, error) {
+	cur := l.GetCur()
+	if !contains(cur.Ids(), l.Id) {
+		glog.V(3).Infof("L%d: not part of the current configuration.", l.Id)
+		return false, nil
+	}
+
+	l.mu.Lock()
+	b := l.ballot
+	if b == nil || !time.Now().Before(l.leaseEnd) {
+		rnd := uint32(1)
+		if l.known != nil {
+			rnd = l.known.Rnd + 1
+		}
+		b = &pb.Ballot{Rnd: rnd, Id: l.Id, Addr: l.Addr, Lease: int64(LeaseDuration)}
+	}
+	l.mu.Unlock()
+
+	start := time.Now()
+	rep, err := l.cp.FullC(cur).Elect(ctx, b)
+	if err != nil {
+		glog.Errorf("L%d: Elect returned error: %v\n", l.Id, err)
+		l.mu.Lock()
+		l.leaseEnd = time.Time{}
+		l.mu.Unlock()
+		return false, err
+	}
+
+	if rep.GetCur() != nil {
+		// The configuration changed. Campaign there next time.
+		op := l.Begin()
+		op.SetNewCur(op.HandleOneCur(0, rep.GetCur()))
+		l.End(op)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if h := rep.GetHolder(); h != nil && (l.known == nil || h.Rnd > l.known.Rnd) {
+		l.known = h
+	}
+	if !rep.Granted {
+		l.leaseEnd = time.Time{}
+		return false, nil
+	}
+	if l.ballot != b {
+		glog.Infof("L%d: elected with round %d.\n", l.Id, b.Rnd)
+	}
+	l.ballot = b
+	l.known = b
+	l.leaseEnd = start.Add(LeaseDuration)
+	return true, nil
+}
+
+// IsLeader reports whether the leader holds a valid lease.
+func (l *Leader) IsLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Now().Before(l.leaseEnd)
+}
+
+// Known returns the ballot with the largest round the leader knows of, or nil.
+// This may be the ballot of a leader, whose lease ended.
+func (l *Leader) Known() *pb.Ballot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.known
+}
+
+// elect campaigns until the leader is stopped. An elected leader renews its lease in time.
+// Others try again after a random delay, to avoid splitting the votes.
+func (l *Leader) elect() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), LeaseDuration/3)
+		elected, _ := l.Campaign(ctx)
+		cancel()
+
+		wait := LeaseDuration / 3
+		if !elected {
+			wait += time.Duration(rand.Int63n(int64(LeaseDuration)))
+		}
+		select {
+		case <-l.stopC:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func contains(ids []uint32, id uint32) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
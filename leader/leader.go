@@ -1,18 +1,30 @@
 package leader
 
 import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
 	"github.com/golang/glog"
 	bp "github.com/relab/smartmerge/blueprints"
 	conf "github.com/relab/smartmerge/confProvider"
 	cs "github.com/relab/smartmerge/consclient"
+	pb "github.com/relab/smartmerge/proto"
 )
 
 type Leader struct {
 	*cs.ConsClient
+	Addr     string // The address clients forward proposals to, announced with the ballot.
 	propC    chan *bp.Blueprint
 	getdoneC chan chan struct{}
 	stopC    chan bool
 	cp       conf.Provider
+
+	mu       sync.Mutex // Protects the election state below.
+	ballot   *pb.Ballot // The ballot of the last campaign.
+	known    *pb.Ballot // The ballot with the largest round known.
+	leaseEnd time.Time  // The end of the lease, if elected.
 }
 
 func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*Leader, error) {
@@ -35,12 +47,15 @@ func (l *Leader) Propose(prop *bp.Blueprint) {
 	<-doneC
 }
 
+// Stop stops handling proposals and campaigning.
 func (l *Leader) Stop() {
-	l.stopC <- true
+	close(l.stopC)
 }
 
+// Run starts handling proposals, and campaigning for leadership in the current configuration.
 func (l *Leader) Run() {
 	go l.run()
+	go l.elect()
 }
 
 func (l *Leader) run() {
@@ -67,7 +82,7 @@ run_for:
 		}
 
 		//Should we add a check, whether the proposal is actually holding anything new?
-		_, err := l.Reconf(l.cp, prop)
+		_, err := l.Reconf(context.Background(), l.cp, prop)
 		if err != nil {
 			glog.Errorln("Reconf returned error:", err)
 		}
@@ -2,37 +2,46 @@ package main
 
 import (
 	"flag"
-	//"strconv"
+	"fmt"
 	"os"
 	"os/signal"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/relab/smartmerge/auth"
+	bp "github.com/relab/smartmerge/blueprints"
 	conf "github.com/relab/smartmerge/confProvider"
 	"github.com/relab/smartmerge/leader"
 	pb "github.com/relab/smartmerge/proto"
-	qf "github.com/relab/smartmerge/qfuncs"
 	"github.com/relab/smartmerge/regserver"
+	"github.com/relab/smartmerge/tlsconf"
 	"github.com/relab/smartmerge/util"
 	grpc "google.golang.org/grpc"
 )
 
 var (
 	port     = flag.Int("port", 10000, "this servers address ip:port.")
+	addr     = flag.String("addr", "", "this servers address, as listed in the config file. (Default is the entry with the given port.)")
 	gcoff    = flag.Bool("gcoff", false, "turn garbage collection off.")
 	alg      = flag.String("alg", "", "algorithm to use (sm | dyna | ssr | cons )")
 	allCores = flag.Bool("all-cores", false, "use all available logical CPUs")
 
 	noabort = flag.Bool("no-abort", false, "do not send aborting new-cur information.")
 
-	cprov = flag.String("cprov", "normal", "which configuration provider: (normal | thrifty | norecontact ) ")
+	cprov = flag.String("cprov", "normal", "which configuration provider: (normal | thrifty | norecontact | fd ) ")
 	//Config
 	confFile = flag.String("conf", "config", "the config file, a list of host:port addresses.")
-	clientid = flag.Int("id", 0, "the client id")
 	initsize = flag.Int("initsize", 1, "the number of servers in the initial configuration")
+	lease    = flag.Duration("lease", leader.LeaseDuration, "the duration of a leader lease.")
+
+	// The leader uses the certificate of the server also as client certificate.
+	tlsFiles   = tlsconf.Flags()
+	authPolicy = flag.String("authpolicy", "", "file with the roles of clients, see package auth. (Empty allows all RPCs to every client.)")
+	tokenFile  = flag.String("tokenfile", "", "file with a bearer token, sent with every RPC. Needs TLS.")
 )
 
 func main() {
@@ -57,56 +66,78 @@ func main() {
 		return
 	}
 
-	var err error
-	var rs *regserver.ConsServer
+	// Find this server in the config file. The leader uses the id of the server.
+	self := -1
+	for i, a := range addrs {
+		if a == *addr || (*addr == "" && strings.HasSuffix(a, fmt.Sprintf(":%d", *port))) {
+			self = i
+			break
+		}
+	}
+	if self < 0 {
+		glog.Errorln("This server is not listed in the config file.")
+		return
+	}
+
+	opts, err := tlsFiles.ServerOptions()
+	if err != nil {
+		glog.Fatalln("Setting up TLS returned error", err)
+	}
+	regserver.ServerOptions = opts
+	if *authPolicy != "" {
+		pol, err := auth.LoadPolicy(*authPolicy)
+		if err != nil {
+			glog.Fatalln("Loading authorization policy returned error", err)
+		}
+		regserver.ServerOptions = append(regserver.ServerOptions, pol.ServerOptions()...)
+	}
+
 	glog.Infoln("Starting Server with port: ", *port)
+	var rs *regserver.RegServer
 	switch *alg {
-	case "", "sm":
-		_, err = regserver.StartAdv(*port, *noabort)
-	case "dyna":
-		_, err = regserver.StartDyna(*port)
-	case "ssr":
-		_, err = regserver.StartSSR(*port)
-	case "cons":
-		rs, err = regserver.StartCons(*port, *noabort)
+	case "", "sm", "dyna", "ssr", "cons":
+		// The RegServer implements the RPCs of all algorithms.
+		rs, err = regserver.StartAt(fmt.Sprintf(":%d", *port), nil, nil, *noabort)
+	default:
+		glog.Fatalf("Unknown algorithm %q, expected sm, dyna, ssr or cons.\n", *alg)
 	}
-
 	if err != nil {
 		glog.Fatalln("Starting server returned error", err)
 	} else {
 
 		time.Sleep(1 * time.Second) //Better than a long timeout here is a long timeout for trying to connect.
 
-		initBlp := new(pb.Blueprint)
-		initBlp.Nodes = make([]*pb.Node, 0, len(ids))
+		initBlp := new(bp.Blueprint)
+		initBlp.Nodes = make([]*bp.Node, 0, len(ids))
 		for i, id := range ids {
 			if i >= *initsize {
 				break
 			}
-			initBlp.Nodes = append(initBlp.Nodes, &pb.Node{Id: id})
+			initBlp.Nodes = append(initBlp.Nodes, &bp.Node{Id: id})
 		}
 		initBlp.FaultTolerance = uint32(15)
 
 		glog.Infof("starting configProvider and manager at time %v\n", time.Now())
-		cp, mgr, err := NewConfP(addrs, *cprov, (*clientid))
+		cp, mgr, err := NewConfP(addrs, *cprov, int(ids[self]))
 		if err != nil {
 			glog.Errorln("Error creating confProvider: ", err)
 			return
 		}
 
 		defer LogErrors(mgr)
-		glog.Infoln("starting client with id", (*clientid))
-		l, err := leader.New(initBlp, uint32(*clientid), cp)
+		glog.Infoln("starting leader with id", ids[self])
+		leader.LeaseDuration = *lease
+		l, err := leader.New(initBlp, ids[self], cp)
 		if err != nil {
 			glog.Errorln("Error creating leader: ", err)
 			return
 		}
+		l.Addr = addrs[self]
 
 		glog.Infoln("starting to run")
+		rs.AddLeader(l)
 		l.Run()
 		defer l.Stop()
-
-		rs.AddLeader(l)
 	}
 
 	signalChan := make(chan os.Signal, 1)
@@ -138,27 +169,21 @@ func handleSignal(signal os.Signal) bool {
 }
 
 func NewConfP(addrs []string, cprov string, id int) (cp conf.Provider, mgr *pb.Manager, err error) {
-	mgr, err = pb.NewManager(addrs, pb.WithGrpcDialOptions(
-		grpc.WithBlock(),
-		grpc.WithTimeout(6000*time.Millisecond),
-		grpc.WithInsecure()),
-		pb.WithAReadSQuorumFunc(qf.AReadSQF),
-		pb.WithAWriteSQuorumFunc(qf.AWriteSQF),
-		pb.WithAWriteNQuorumFunc(qf.AWriteNQF),
-		pb.WithSetCurQuorumFunc(qf.SetCurQF),
-		pb.WithLAPropQuorumFunc(qf.LAPropQF),
-		pb.WithSetStateQuorumFunc(qf.SetStateQF),
-		pb.WithGetPromiseQuorumFunc(qf.GetPromiseQF),
-		pb.WithAcceptQuorumFunc(qf.AcceptQF),
-		pb.WithDWriteNQuorumFunc(qf.DWriteNQF),
-		pb.WithDSetStateQuorumFunc(qf.DSetStateQF),
-		pb.WithDWriteNSetQuorumFunc(qf.DWriteNSetQF),
-		pb.WithDSetCurQuorumFunc(qf.DSetCurQF),
-		pb.WithGetOneNQuorumFunc(qf.GetOneNQF),
-		pb.WithSpSnOneQuorumFunc(qf.SpSnOneQF),
-		pb.WithSCommitQuorumFunc(qf.SCommitQF),
-		pb.WithSSetStateQuorumFunc(qf.SSetStateQF),
-	)
+	sec, err := tlsFiles.DialOption()
+	if err != nil {
+		glog.Errorln("Setting up TLS returned error: ", err)
+		return
+	}
+	opts := []grpc.DialOption{grpc.WithBlock(), grpc.WithTimeout(6000 * time.Millisecond), sec}
+	if *tokenFile != "" {
+		tok, err := auth.TokenFile(*tokenFile)
+		if err != nil {
+			glog.Errorln("Reading token returned error: ", err)
+			return nil, nil, err
+		}
+		opts = append(opts, tok)
+	}
+	mgr, err = pb.NewManager(addrs, pb.WithGrpcDialOptions(opts...))
 	if err != nil {
 		glog.Errorln("Creating manager returned error: ", err)
 		return
@@ -169,9 +194,11 @@ func NewConfP(addrs []string, cprov string, id int) (cp conf.Provider, mgr *pb.M
 	case "norecontact":
 		break
 	case "thrifty":
-		cp = &conf.ThriftyConfP{cp}
+		cp = &conf.ThriftyConfP{Provider: cp}
+	case "fd":
+		cp = &conf.ThriftyConfP{Provider: conf.NewFDProvider(mgr, id)}
 	case "normal", "":
-		cp = &conf.NormalConfP{cp}
+		cp = &conf.NormalConfP{Provider: cp}
 	default:
 		glog.Fatalf("confprovider %v is not supported.\n", cprov)
 	}
@@ -179,9 +206,13 @@ func NewConfP(addrs []string, cprov string, id int) (cp conf.Provider, mgr *pb.M
 }
 
 func LogErrors(mgr *pb.Manager) {
-	errs := mgr.GetErrors()
 	founderrs := false
-	for id, e := range errs {
+	for _, n := range mgr.Nodes() {
+		e := n.LastErr()
+		if e == nil {
+			continue
+		}
+		id := n.ID()
 		if !founderrs {
 			glog.Errorln("Printing connection errors.")
 		}
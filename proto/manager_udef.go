@@ -0,0 +1,33 @@
+package proto
+
+import "fmt"
+
+// DialNode dials the node address addr, and adds it to the Manager's pool of nodes,
+// if a connection was established. The AddNode method generated by gorums is not implemented.
+func (m *Manager) DialNode(addr string) error {
+	node, err := m.createNode(addr)
+	if err != nil {
+		return err
+	}
+
+	if !m.opts.noConnect {
+		if err = node.connect(m.opts.grpcDialOpts...); err != nil {
+			if m.eventLog != nil {
+				m.eventLog.Errorf("connect failed, error connecting to node %s, error: %v", node.addr, err)
+			}
+			return fmt.Errorf("connect node %s error: %v", node.addr, err)
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if _, found := m.lookup[node.id]; found {
+		if node.conn != nil {
+			node.close()
+		}
+		return fmt.Errorf("create node %s error: node already exists", addr)
+	}
+	m.lookup[node.id] = node
+	m.nodes = append(m.nodes, node)
+	return nil
+}
@@ -31,3 +31,89 @@ func (s *State) Compare(st *State) int {
 
 	return 0
 }
+
+// MergeStates merges two lists of register states, sorted by key.
+// For each key, the state with the largest timestamp is kept.
+// The result is sorted by key. The input lists are not modified.
+func MergeStates(a, b []*State) []*State {
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	res := make([]*State, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].Key < b[j].Key:
+			res = append(res, a[i])
+			i++
+		case a[i].Key > b[j].Key:
+			res = append(res, b[j])
+			j++
+		default:
+			if a[i].Compare(b[j]) == 1 {
+				res = append(res, b[j])
+			} else {
+				res = append(res, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	res = append(res, a[i:]...)
+	return append(res, b[j:]...)
+}
+
+// MergeVersions merges two lists of register versions, sorted by key and then by (Timestamp, Writer).
+// Versions contained in both lists are kept once. The input lists are not modified.
+func MergeVersions(a, b []*State) []*State {
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	res := make([]*State, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].Key < b[j].Key || (a[i].Key == b[j].Key && a[i].Compare(b[j]) == 1):
+			res = append(res, a[i])
+			i++
+		case a[i].Key > b[j].Key || a[i].Compare(b[j]) == -1:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	res = append(res, a[i:]...)
+	return append(res, b[j:]...)
+}
+
+// FindState returns the state of the register key in the list sts, sorted by key.
+// It returns nil, if the register is not included.
+func FindState(sts []*State, key string) *State {
+	for _, st := range sts {
+		if st.Key == key {
+			return st
+		}
+	}
+	return nil
+}
+
+// Contains reports whether the register key is selected by ks.
+// A nil Keys only selects the register with the empty key.
+func (ks *Keys) Contains(key string) bool {
+	if ks == nil {
+		return key == ""
+	}
+	if !ks.Range {
+		return key == ks.Key
+	}
+	return key >= ks.Key && (ks.End == "" || key < ks.End)
+}
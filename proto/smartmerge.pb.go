@@ -30,6 +30,25 @@
 		Learn
 		Proposal
 		Ack
+		Keys
+		LAValue
+		LAValueProposal
+		LAValueReply
+		LAInstance
+		DWriteN
+		DReadReply
+		DNewState
+		DNewStateReply
+		SpSnProp
+		SpSnReply
+		Ballot
+		Lease
+		StatusRequest
+		RegisterStatus
+		PaxosStatus
+		ServerStatus
+		WatchRequest
+		WatchUpdate
 */
 package proto
 
@@ -86,17 +105,18 @@ type State struct {
 	Value     []byte `protobuf:"bytes,1,opt,name=Value,proto3" json:"Value,omitempty"`
 	Timestamp int32  `protobuf:"varint,2,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
 	Writer    uint32 `protobuf:"varint,3,opt,name=Writer,proto3" json:"Writer,omitempty"`
+	Key       string `protobuf:"bytes,4,opt,name=Key,proto3" json:"Key,omitempty"`
 }
 
 func (m *State) Reset()                    { *m = State{} }
 func (*State) ProtoMessage()               {}
 func (*State) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{0} }
 
-// This message hold the hash value of the current configuration,
-// and the configuration used for this quorum call
+// This message holds the id (Blueprint.ID) of the current configuration,
+// and of the configuration used for this quorum call
 type Conf struct {
-	This uint32 `protobuf:"varint,1,opt,name=This,proto3" json:"This,omitempty"`
-	Cur  uint32 `protobuf:"varint,2,opt,name=Cur,proto3" json:"Cur,omitempty"`
+	This []byte `protobuf:"bytes,1,opt,name=This,proto3" json:"This,omitempty"`
+	Cur  []byte `protobuf:"bytes,2,opt,name=Cur,proto3" json:"Cur,omitempty"`
 }
 
 func (m *Conf) Reset()                    { *m = Conf{} }
@@ -107,6 +127,8 @@ type ConfReply struct {
 	Cur   *blueprints.Blueprint   `protobuf:"bytes,1,opt,name=Cur" json:"Cur,omitempty"`
 	Abort bool                    `protobuf:"varint,2,opt,name=Abort,proto3" json:"Abort,omitempty"`
 	Next  []*blueprints.Blueprint `protobuf:"bytes,3,rep,name=Next" json:"Next,omitempty"`
+	// Set by Write, if the state was not stored, since the server promised a larger ballot for the register.
+	Rejected bool `protobuf:"varint,4,opt,name=Rejected,proto3" json:"Rejected,omitempty"`
 }
 
 func (m *ConfReply) Reset()                    { *m = ConfReply{} }
@@ -129,7 +151,7 @@ func (m *ConfReply) GetNext() []*blueprints.Blueprint {
 
 type NewCur struct {
 	Cur  *blueprints.Blueprint `protobuf:"bytes,1,opt,name=Cur" json:"Cur,omitempty"`
-	CurC uint32                `protobuf:"varint,2,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	CurC []byte                `protobuf:"bytes,2,opt,name=CurC,proto3" json:"CurC,omitempty"`
 }
 
 func (m *NewCur) Reset()                    { *m = NewCur{} }
@@ -153,6 +175,16 @@ func (*NewCurReply) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartM
 
 type Read struct {
 	Conf *Conf `protobuf:"bytes,1,opt,name=Conf" json:"Conf,omitempty"`
+	// If empty, the register with the empty key is read.
+	Keys *Keys `protobuf:"bytes,2,opt,name=Keys" json:"Keys,omitempty"`
+	// If set, the server promises to reject writes of the register Ballot.Key with a smaller (Timestamp, Writer),
+	// unless it already promised or stored a larger or equal one. Used by conditional writes.
+	Ballot *State `protobuf:"bytes,3,opt,name=Ballot" json:"Ballot,omitempty"`
+	// If larger than 0, State is the latest version of the register with a smaller or equal timestamp,
+	// instead of the current state. Timestamps of written states start at 1.
+	AsOf int32 `protobuf:"varint,4,opt,name=AsOf,proto3" json:"AsOf,omitempty"`
+	// The number of recent versions of the register to return in Versions.
+	Versions uint32 `protobuf:"varint,5,opt,name=Versions,proto3" json:"Versions,omitempty"`
 }
 
 func (m *Read) Reset()                    { *m = Read{} }
@@ -166,9 +198,32 @@ func (m *Read) GetConf() *Conf {
 	return nil
 }
 
+func (m *Read) GetKeys() *Keys {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *Read) GetBallot() *State {
+	if m != nil {
+		return m.Ballot
+	}
+	return nil
+}
+
 type ReadReply struct {
+	// The state of a single register.
 	State *State     `protobuf:"bytes,1,opt,name=State" json:"State,omitempty"`
 	Cur   *ConfReply `protobuf:"bytes,2,opt,name=Cur" json:"Cur,omitempty"`
+	// The states of a range of registers, sorted by key.
+	States []*State `protobuf:"bytes,3,rep,name=States" json:"States,omitempty"`
+	// The largest ballot promised for the register, if a Ballot was sent.
+	Promise *State `protobuf:"bytes,4,opt,name=Promise" json:"Promise,omitempty"`
+	// Recent versions of the register, including the current state, sorted from oldest to newest.
+	Versions []*State `protobuf:"bytes,5,rep,name=Versions" json:"Versions,omitempty"`
+	// Set with AsOf, if the server discarded versions that may be the requested one.
+	Compacted bool `protobuf:"varint,6,opt,name=Compacted,proto3" json:"Compacted,omitempty"`
 }
 
 func (m *ReadReply) Reset()                    { *m = ReadReply{} }
@@ -189,6 +244,27 @@ func (m *ReadReply) GetCur() *ConfReply {
 	return nil
 }
 
+func (m *ReadReply) GetStates() []*State {
+	if m != nil {
+		return m.States
+	}
+	return nil
+}
+
+func (m *ReadReply) GetPromise() *State {
+	if m != nil {
+		return m.Promise
+	}
+	return nil
+}
+
+func (m *ReadReply) GetVersions() []*State {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
 type WriteS struct {
 	State *State `protobuf:"bytes,1,opt,name=State" json:"State,omitempty"`
 	Conf  *Conf  `protobuf:"bytes,2,opt,name=Conf" json:"Conf,omitempty"`
@@ -213,8 +289,10 @@ func (m *WriteS) GetConf() *Conf {
 }
 
 type WriteN struct {
-	CurC uint32                `protobuf:"varint,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	CurC []byte                `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
 	Next *blueprints.Blueprint `protobuf:"bytes,2,opt,name=Next" json:"Next,omitempty"`
+	// The registers to return. If empty, all registers are returned.
+	Keys *Keys `protobuf:"bytes,3,opt,name=Keys" json:"Keys,omitempty"`
 }
 
 func (m *WriteN) Reset()                    { *m = WriteN{} }
@@ -228,10 +306,25 @@ func (m *WriteN) GetNext() *blueprints.Blueprint {
 	return nil
 }
 
+func (m *WriteN) GetKeys() *Keys {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
 type WriteNReply struct {
 	Cur     *ConfReply            `protobuf:"bytes,1,opt,name=Cur" json:"Cur,omitempty"`
-	State   *State                `protobuf:"bytes,2,opt,name=State" json:"State,omitempty"`
+	States  []*State              `protobuf:"bytes,2,rep,name=States" json:"States,omitempty"`
 	LAState *blueprints.Blueprint `protobuf:"bytes,3,opt,name=LAState" json:"LAState,omitempty"`
+	// The states of all lattice agreement instances, sorted by instance.
+	LAValues []*LAInstance `protobuf:"bytes,4,rep,name=LAValues" json:"LAValues,omitempty"`
+	// The ballots promised for the registers, sorted by key.
+	Promises []*State `protobuf:"bytes,5,rep,name=Promises" json:"Promises,omitempty"`
+	// The earlier versions retained for the registers, sorted by key, and from oldest to newest.
+	History []*State `protobuf:"bytes,6,rep,name=History" json:"History,omitempty"`
+	// The largest version discarded from the history of the registers, sorted by key.
+	Compacted []*State `protobuf:"bytes,7,rep,name=Compacted" json:"Compacted,omitempty"`
 }
 
 func (m *WriteNReply) Reset()                    { *m = WriteNReply{} }
@@ -245,9 +338,9 @@ func (m *WriteNReply) GetCur() *ConfReply {
 	return nil
 }
 
-func (m *WriteNReply) GetState() *State {
+func (m *WriteNReply) GetStates() []*State {
 	if m != nil {
-		return m.State
+		return m.States
 	}
 	return nil
 }
@@ -259,6 +352,34 @@ func (m *WriteNReply) GetLAState() *blueprints.Blueprint {
 	return nil
 }
 
+func (m *WriteNReply) GetLAValues() []*LAInstance {
+	if m != nil {
+		return m.LAValues
+	}
+	return nil
+}
+
+func (m *WriteNReply) GetPromises() []*State {
+	if m != nil {
+		return m.Promises
+	}
+	return nil
+}
+
+func (m *WriteNReply) GetHistory() []*State {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *WriteNReply) GetCompacted() []*State {
+	if m != nil {
+		return m.Compacted
+	}
+	return nil
+}
+
 type LAProposal struct {
 	Conf *Conf                 `protobuf:"bytes,1,opt,name=Conf" json:"Conf,omitempty"`
 	Prop *blueprints.Blueprint `protobuf:"bytes,2,opt,name=Prop" json:"Prop,omitempty"`
@@ -306,18 +427,25 @@ func (m *LAReply) GetLAState() *blueprints.Blueprint {
 }
 
 type NewState struct {
-	CurC    uint32                `protobuf:"varint,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
-	State   *State                `protobuf:"bytes,2,opt,name=State" json:"State,omitempty"`
-	LAState *blueprints.Blueprint `protobuf:"bytes,3,opt,name=LAState" json:"LAState,omitempty"`
+	CurC      []byte                `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	States    []*State              `protobuf:"bytes,2,rep,name=States" json:"States,omitempty"`
+	LAState   *blueprints.Blueprint `protobuf:"bytes,3,opt,name=LAState" json:"LAState,omitempty"`
+	LAValues  []*LAInstance         `protobuf:"bytes,4,rep,name=LAValues" json:"LAValues,omitempty"`
+	Promises  []*State              `protobuf:"bytes,5,rep,name=Promises" json:"Promises,omitempty"`
+	History   []*State              `protobuf:"bytes,6,rep,name=History" json:"History,omitempty"`
+	Compacted []*State              `protobuf:"bytes,7,rep,name=Compacted" json:"Compacted,omitempty"`
+	// A state written by the operation. Unlike States, it is not stored,
+	// if the server promised a larger ballot for the register.
+	Write *State `protobuf:"bytes,8,opt,name=Write" json:"Write,omitempty"`
 }
 
 func (m *NewState) Reset()                    { *m = NewState{} }
 func (*NewState) ProtoMessage()               {}
 func (*NewState) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{12} }
 
-func (m *NewState) GetState() *State {
+func (m *NewState) GetStates() []*State {
 	if m != nil {
-		return m.State
+		return m.States
 	}
 	return nil
 }
@@ -329,9 +457,46 @@ func (m *NewState) GetLAState() *blueprints.Blueprint {
 	return nil
 }
 
+func (m *NewState) GetLAValues() []*LAInstance {
+	if m != nil {
+		return m.LAValues
+	}
+	return nil
+}
+
+func (m *NewState) GetPromises() []*State {
+	if m != nil {
+		return m.Promises
+	}
+	return nil
+}
+
+func (m *NewState) GetHistory() []*State {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *NewState) GetCompacted() []*State {
+	if m != nil {
+		return m.Compacted
+	}
+	return nil
+}
+
+func (m *NewState) GetWrite() *State {
+	if m != nil {
+		return m.Write
+	}
+	return nil
+}
+
 type NewStateReply struct {
 	Cur  *blueprints.Blueprint   `protobuf:"bytes,1,opt,name=Cur" json:"Cur,omitempty"`
 	Next []*blueprints.Blueprint `protobuf:"bytes,2,rep,name=Next" json:"Next,omitempty"`
+	// Set, if Write was not stored, since the server promised a larger ballot for the register.
+	Rejected bool `protobuf:"varint,3,opt,name=Rejected,proto3" json:"Rejected,omitempty"`
 }
 
 func (m *NewStateReply) Reset()                    { *m = NewStateReply{} }
@@ -369,7 +534,7 @@ func (m *CV) GetVal() *blueprints.Blueprint {
 }
 
 type Prepare struct {
-	CurC uint32 `protobuf:"varint,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	CurC []byte `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
 	Rnd  uint32 `protobuf:"varint,2,opt,name=Rnd,proto3" json:"Rnd,omitempty"`
 }
 
@@ -410,7 +575,7 @@ func (m *Promise) GetDec() *blueprints.Blueprint {
 }
 
 type Propose struct {
-	CurC uint32 `protobuf:"varint,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	CurC []byte `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
 	Val  *CV    `protobuf:"bytes,2,opt,name=Val" json:"Val,omitempty"`
 }
 
@@ -465,174 +630,431 @@ func (m *Proposal) GetProp() *blueprints.Blueprint {
 }
 
 type Ack struct {
+	// Redirect is set, if the proposal was not handled, since another server is the leader.
+	Redirect   bool   `protobuf:"varint,1,opt,name=Redirect,proto3" json:"Redirect,omitempty"`
+	Leader     uint32 `protobuf:"varint,2,opt,name=Leader,proto3" json:"Leader,omitempty"`
+	LeaderAddr string `protobuf:"bytes,3,opt,name=LeaderAddr,proto3" json:"LeaderAddr,omitempty"`
 }
 
 func (m *Ack) Reset()                    { *m = Ack{} }
 func (*Ack) ProtoMessage()               {}
 func (*Ack) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{20} }
 
-func init() {
-	proto1.RegisterType((*State)(nil), "proto.State")
-	proto1.RegisterType((*Conf)(nil), "proto.Conf")
-	proto1.RegisterType((*ConfReply)(nil), "proto.ConfReply")
-	proto1.RegisterType((*NewCur)(nil), "proto.NewCur")
-	proto1.RegisterType((*NewCurReply)(nil), "proto.NewCurReply")
-	proto1.RegisterType((*Read)(nil), "proto.Read")
-	proto1.RegisterType((*ReadReply)(nil), "proto.ReadReply")
-	proto1.RegisterType((*WriteS)(nil), "proto.WriteS")
-	proto1.RegisterType((*WriteN)(nil), "proto.WriteN")
-	proto1.RegisterType((*WriteNReply)(nil), "proto.WriteNReply")
-	proto1.RegisterType((*LAProposal)(nil), "proto.LAProposal")
-	proto1.RegisterType((*LAReply)(nil), "proto.LAReply")
-	proto1.RegisterType((*NewState)(nil), "proto.NewState")
-	proto1.RegisterType((*NewStateReply)(nil), "proto.NewStateReply")
-	proto1.RegisterType((*CV)(nil), "proto.CV")
-	proto1.RegisterType((*Prepare)(nil), "proto.Prepare")
-	proto1.RegisterType((*Promise)(nil), "proto.Promise")
-	proto1.RegisterType((*Propose)(nil), "proto.Propose")
-	proto1.RegisterType((*Learn)(nil), "proto.Learn")
-	proto1.RegisterType((*Proposal)(nil), "proto.Proposal")
-	proto1.RegisterType((*Ack)(nil), "proto.Ack")
+// Keys selects either the single register Key, or if Range is set,
+// all registers with keys in [Key, End). An empty End is unbounded.
+type Keys struct {
+	Key   string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	End   string `protobuf:"bytes,2,opt,name=End,proto3" json:"End,omitempty"`
+	Range bool   `protobuf:"varint,3,opt,name=Range,proto3" json:"Range,omitempty"`
 }
-func (this *State) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that == nil && this != nil")
-	}
 
-	that1, ok := that.(*State)
-	if !ok {
-		that2, ok := that.(State)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *State")
-		}
-	}
-	if that1 == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that is type *State but is nil && this != nil")
-	} else if this == nil {
-		return fmt.Errorf("that is type *State but is not nil && this == nil")
-	}
-	if !bytes.Equal(this.Value, that1.Value) {
-		return fmt.Errorf("Value this(%v) Not Equal that(%v)", this.Value, that1.Value)
-	}
-	if this.Timestamp != that1.Timestamp {
-		return fmt.Errorf("Timestamp this(%v) Not Equal that(%v)", this.Timestamp, that1.Timestamp)
-	}
-	if this.Writer != that1.Writer {
-		return fmt.Errorf("Writer this(%v) Not Equal that(%v)", this.Writer, that1.Writer)
+func (m *Keys) Reset()                    { *m = Keys{} }
+func (*Keys) ProtoMessage()               {}
+func (*Keys) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{21} }
+
+// LAValue is an element of a lattice type registered with the lattice package.
+type LAValue struct {
+	Type  string `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+}
+
+func (m *LAValue) Reset()                    { *m = LAValue{} }
+func (*LAValue) ProtoMessage()               {}
+func (*LAValue) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{22} }
+
+type LAValueProposal struct {
+	Conf *Conf `protobuf:"bytes,1,opt,name=Conf" json:"Conf,omitempty"`
+	// The lattice agreement instance.
+	Instance string   `protobuf:"bytes,2,opt,name=Instance,proto3" json:"Instance,omitempty"`
+	Prop     *LAValue `protobuf:"bytes,3,opt,name=Prop" json:"Prop,omitempty"`
+}
+
+func (m *LAValueProposal) Reset()                    { *m = LAValueProposal{} }
+func (*LAValueProposal) ProtoMessage()               {}
+func (*LAValueProposal) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{23} }
+
+func (m *LAValueProposal) GetConf() *Conf {
+	if m != nil {
+		return m.Conf
 	}
 	return nil
 }
-func (this *State) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
-	}
 
-	that1, ok := that.(*State)
-	if !ok {
-		that2, ok := that.(State)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
-	} else if this == nil {
-		return false
-	}
-	if !bytes.Equal(this.Value, that1.Value) {
-		return false
+func (m *LAValueProposal) GetProp() *LAValue {
+	if m != nil {
+		return m.Prop
 	}
-	if this.Timestamp != that1.Timestamp {
-		return false
+	return nil
+}
+
+type LAValueReply struct {
+	Cur *ConfReply `protobuf:"bytes,1,opt,name=Cur" json:"Cur,omitempty"`
+	// The state of the instance, if the proposal was not accepted.
+	State *LAValue `protobuf:"bytes,2,opt,name=State" json:"State,omitempty"`
+}
+
+func (m *LAValueReply) Reset()                    { *m = LAValueReply{} }
+func (*LAValueReply) ProtoMessage()               {}
+func (*LAValueReply) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{24} }
+
+func (m *LAValueReply) GetCur() *ConfReply {
+	if m != nil {
+		return m.Cur
 	}
-	if this.Writer != that1.Writer {
-		return false
+	return nil
+}
+
+func (m *LAValueReply) GetState() *LAValue {
+	if m != nil {
+		return m.State
 	}
-	return true
+	return nil
 }
-func (this *Conf) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that == nil && this != nil")
+
+// LAInstance is the state of a lattice agreement instance.
+type LAInstance struct {
+	Instance string   `protobuf:"bytes,1,opt,name=Instance,proto3" json:"Instance,omitempty"`
+	State    *LAValue `protobuf:"bytes,2,opt,name=State" json:"State,omitempty"`
+}
+
+func (m *LAInstance) Reset()                    { *m = LAInstance{} }
+func (*LAInstance) ProtoMessage()               {}
+func (*LAInstance) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{25} }
+
+func (m *LAInstance) GetState() *LAValue {
+	if m != nil {
+		return m.State
 	}
+	return nil
+}
 
-	that1, ok := that.(*Conf)
-	if !ok {
-		that2, ok := that.(Conf)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *Conf")
-		}
+// DWriteN adds Next to the successors of the view CurC.
+type DWriteN struct {
+	CurC []byte                  `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	Next []*blueprints.Blueprint `protobuf:"bytes,2,rep,name=Next" json:"Next,omitempty"`
+	// The registers to return.
+	Keys *Keys `protobuf:"bytes,3,opt,name=Keys" json:"Keys,omitempty"`
+}
+
+func (m *DWriteN) Reset()                    { *m = DWriteN{} }
+func (*DWriteN) ProtoMessage()               {}
+func (*DWriteN) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{26} }
+
+func (m *DWriteN) GetNext() []*blueprints.Blueprint {
+	if m != nil {
+		return m.Next
 	}
-	if that1 == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that is type *Conf but is nil && this != nil")
-	} else if this == nil {
-		return fmt.Errorf("that is type *Conf but is not nil && this == nil")
+	return nil
+}
+
+func (m *DWriteN) GetKeys() *Keys {
+	if m != nil {
+		return m.Keys
 	}
-	if this.This != that1.This {
-		return fmt.Errorf("This this(%v) Not Equal that(%v)", this.This, that1.This)
+	return nil
+}
+
+type DReadReply struct {
+	States []*State `protobuf:"bytes,1,rep,name=States" json:"States,omitempty"`
+	// All successors of the view.
+	Next []*blueprints.Blueprint `protobuf:"bytes,2,rep,name=Next" json:"Next,omitempty"`
+}
+
+func (m *DReadReply) Reset()                    { *m = DReadReply{} }
+func (*DReadReply) ProtoMessage()               {}
+func (*DReadReply) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{27} }
+
+func (m *DReadReply) GetStates() []*State {
+	if m != nil {
+		return m.States
 	}
-	if this.Cur != that1.Cur {
-		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	return nil
+}
+
+func (m *DReadReply) GetNext() []*blueprints.Blueprint {
+	if m != nil {
+		return m.Next
 	}
 	return nil
 }
-func (this *Conf) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
+
+type DNewState struct {
+	CurC   []byte   `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	States []*State `protobuf:"bytes,2,rep,name=States" json:"States,omitempty"`
+}
+
+func (m *DNewState) Reset()                    { *m = DNewState{} }
+func (*DNewState) ProtoMessage()               {}
+func (*DNewState) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{28} }
+
+func (m *DNewState) GetStates() []*State {
+	if m != nil {
+		return m.States
 	}
+	return nil
+}
 
-	that1, ok := that.(*Conf)
-	if !ok {
-		that2, ok := that.(Conf)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+type DNewStateReply struct {
+	// All successors of the view.
+	Next []*blueprints.Blueprint `protobuf:"bytes,1,rep,name=Next" json:"Next,omitempty"`
+}
+
+func (m *DNewStateReply) Reset()                    { *m = DNewStateReply{} }
+func (*DNewStateReply) ProtoMessage()               {}
+func (*DNewStateReply) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{29} }
+
+func (m *DNewStateReply) GetNext() []*blueprints.Blueprint {
+	if m != nil {
+		return m.Next
 	}
-	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
-	} else if this == nil {
-		return false
+	return nil
+}
+
+type SpSnProp struct {
+	// The view of the speculating snapshot.
+	CurC  []byte                `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	Level uint32                `protobuf:"varint,2,opt,name=Level,proto3" json:"Level,omitempty"`
+	Prop  *blueprints.Blueprint `protobuf:"bytes,3,opt,name=Prop" json:"Prop,omitempty"`
+}
+
+func (m *SpSnProp) Reset()                    { *m = SpSnProp{} }
+func (*SpSnProp) ProtoMessage()               {}
+func (*SpSnProp) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{30} }
+
+func (m *SpSnProp) GetProp() *blueprints.Blueprint {
+	if m != nil {
+		return m.Prop
 	}
-	if this.This != that1.This {
-		return false
+	return nil
+}
+
+type SpSnReply struct {
+	// The distinct values proposed at the level.
+	Vals []*blueprints.Blueprint `protobuf:"bytes,1,rep,name=Vals" json:"Vals,omitempty"`
+}
+
+func (m *SpSnReply) Reset()                    { *m = SpSnReply{} }
+func (*SpSnReply) ProtoMessage()               {}
+func (*SpSnReply) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{31} }
+
+func (m *SpSnReply) GetVals() []*blueprints.Blueprint {
+	if m != nil {
+		return m.Vals
 	}
-	if this.Cur != that1.Cur {
-		return false
+	return nil
+}
+
+type Ballot struct {
+	Rnd uint32 `protobuf:"varint,1,opt,name=Rnd,proto3" json:"Rnd,omitempty"`
+	// The id and address of the candidate.
+	Id   uint32 `protobuf:"varint,2,opt,name=Id,proto3" json:"Id,omitempty"`
+	Addr string `protobuf:"bytes,3,opt,name=Addr,proto3" json:"Addr,omitempty"`
+	// The requested lease duration in nanoseconds.
+	Lease int64 `protobuf:"varint,4,opt,name=Lease,proto3" json:"Lease,omitempty"`
+}
+
+func (m *Ballot) Reset()                    { *m = Ballot{} }
+func (*Ballot) ProtoMessage()               {}
+func (*Ballot) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{32} }
+
+type Lease struct {
+	Granted bool `protobuf:"varint,1,opt,name=Granted,proto3" json:"Granted,omitempty"`
+	// The ballot holding the lease, or the largest ballot known.
+	Holder *Ballot `protobuf:"bytes,2,opt,name=Holder" json:"Holder,omitempty"`
+	// The current configuration, if the candidate is not part of it.
+	Cur *blueprints.Blueprint `protobuf:"bytes,3,opt,name=Cur" json:"Cur,omitempty"`
+}
+
+func (m *Lease) Reset()                    { *m = Lease{} }
+func (*Lease) ProtoMessage()               {}
+func (*Lease) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{33} }
+
+func (m *Lease) GetHolder() *Ballot {
+	if m != nil {
+		return m.Holder
 	}
-	return true
+	return nil
 }
-func (this *ConfReply) VerboseEqual(that interface{}) error {
+
+func (m *Lease) GetCur() *blueprints.Blueprint {
+	if m != nil {
+		return m.Cur
+	}
+	return nil
+}
+
+type StatusRequest struct {
+}
+
+func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
+func (*StatusRequest) ProtoMessage()               {}
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{34} }
+
+type RegisterStatus struct {
+	Key       string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	Timestamp int32  `protobuf:"varint,2,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Writer    uint32 `protobuf:"varint,3,opt,name=Writer,proto3" json:"Writer,omitempty"`
+}
+
+func (m *RegisterStatus) Reset()                    { *m = RegisterStatus{} }
+func (*RegisterStatus) ProtoMessage()               {}
+func (*RegisterStatus) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{35} }
+
+type PaxosStatus struct {
+	// The configuration, in which the consensus instance runs.
+	CurC []byte `protobuf:"bytes,1,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	Rnd  uint32 `protobuf:"varint,2,opt,name=Rnd,proto3" json:"Rnd,omitempty"`
+	Val  *CV    `protobuf:"bytes,3,opt,name=Val" json:"Val,omitempty"`
+}
+
+func (m *PaxosStatus) Reset()                    { *m = PaxosStatus{} }
+func (*PaxosStatus) ProtoMessage()               {}
+func (*PaxosStatus) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{36} }
+
+func (m *PaxosStatus) GetVal() *CV {
+	if m != nil {
+		return m.Val
+	}
+	return nil
+}
+
+type ServerStatus struct {
+	Cur       *blueprints.Blueprint   `protobuf:"bytes,1,opt,name=Cur" json:"Cur,omitempty"`
+	CurC      []byte                  `protobuf:"bytes,2,opt,name=CurC,proto3" json:"CurC,omitempty"`
+	LAState   *blueprints.Blueprint   `protobuf:"bytes,3,opt,name=LAState" json:"LAState,omitempty"`
+	Next      []*blueprints.Blueprint `protobuf:"bytes,4,rep,name=Next" json:"Next,omitempty"`
+	Registers []*RegisterStatus       `protobuf:"bytes,5,rep,name=Registers" json:"Registers,omitempty"`
+	// The consensus instances, that are not decided yet.
+	Paxos []*PaxosStatus `protobuf:"bytes,6,rep,name=Paxos" json:"Paxos,omitempty"`
+	// The time since the server started, in nanoseconds.
+	Uptime int64 `protobuf:"varint,7,opt,name=Uptime,proto3" json:"Uptime,omitempty"`
+}
+
+func (m *ServerStatus) Reset()                    { *m = ServerStatus{} }
+func (*ServerStatus) ProtoMessage()               {}
+func (*ServerStatus) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{37} }
+
+func (m *ServerStatus) GetCur() *blueprints.Blueprint {
+	if m != nil {
+		return m.Cur
+	}
+	return nil
+}
+
+func (m *ServerStatus) GetLAState() *blueprints.Blueprint {
+	if m != nil {
+		return m.LAState
+	}
+	return nil
+}
+
+func (m *ServerStatus) GetNext() []*blueprints.Blueprint {
+	if m != nil {
+		return m.Next
+	}
+	return nil
+}
+
+func (m *ServerStatus) GetRegisters() []*RegisterStatus {
+	if m != nil {
+		return m.Registers
+	}
+	return nil
+}
+
+func (m *ServerStatus) GetPaxos() []*PaxosStatus {
+	if m != nil {
+		return m.Paxos
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	// The register to watch.
+	Key string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+}
+
+func (m *WatchRequest) Reset()                    { *m = WatchRequest{} }
+func (*WatchRequest) ProtoMessage()               {}
+func (*WatchRequest) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{38} }
+
+type WatchUpdate struct {
+	// The state of the register. Set in the first update, and whenever the state advances.
+	State *State `protobuf:"bytes,1,opt,name=State" json:"State,omitempty"`
+	// The current configuration. Set in the first update, and whenever it, or Next, changes.
+	Cur *blueprints.Blueprint `protobuf:"bytes,2,opt,name=Cur" json:"Cur,omitempty"`
+	// The configurations proposed by reconfigurations, that are newer than Cur. Set together with Cur.
+	Next []*blueprints.Blueprint `protobuf:"bytes,3,rep,name=Next" json:"Next,omitempty"`
+}
+
+func (m *WatchUpdate) Reset()                    { *m = WatchUpdate{} }
+func (*WatchUpdate) ProtoMessage()               {}
+func (*WatchUpdate) Descriptor() ([]byte, []int) { return fileDescriptorDcSmartMerge, []int{39} }
+
+func (m *WatchUpdate) GetState() *State {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *WatchUpdate) GetCur() *blueprints.Blueprint {
+	if m != nil {
+		return m.Cur
+	}
+	return nil
+}
+
+func (m *WatchUpdate) GetNext() []*blueprints.Blueprint {
+	if m != nil {
+		return m.Next
+	}
+	return nil
+}
+
+func init() {
+	proto1.RegisterType((*State)(nil), "proto.State")
+	proto1.RegisterType((*Conf)(nil), "proto.Conf")
+	proto1.RegisterType((*ConfReply)(nil), "proto.ConfReply")
+	proto1.RegisterType((*NewCur)(nil), "proto.NewCur")
+	proto1.RegisterType((*NewCurReply)(nil), "proto.NewCurReply")
+	proto1.RegisterType((*Read)(nil), "proto.Read")
+	proto1.RegisterType((*ReadReply)(nil), "proto.ReadReply")
+	proto1.RegisterType((*WriteS)(nil), "proto.WriteS")
+	proto1.RegisterType((*WriteN)(nil), "proto.WriteN")
+	proto1.RegisterType((*WriteNReply)(nil), "proto.WriteNReply")
+	proto1.RegisterType((*LAProposal)(nil), "proto.LAProposal")
+	proto1.RegisterType((*LAReply)(nil), "proto.LAReply")
+	proto1.RegisterType((*NewState)(nil), "proto.NewState")
+	proto1.RegisterType((*NewStateReply)(nil), "proto.NewStateReply")
+	proto1.RegisterType((*CV)(nil), "proto.CV")
+	proto1.RegisterType((*Prepare)(nil), "proto.Prepare")
+	proto1.RegisterType((*Promise)(nil), "proto.Promise")
+	proto1.RegisterType((*Propose)(nil), "proto.Propose")
+	proto1.RegisterType((*Learn)(nil), "proto.Learn")
+	proto1.RegisterType((*Proposal)(nil), "proto.Proposal")
+	proto1.RegisterType((*Ack)(nil), "proto.Ack")
+	proto1.RegisterType((*Keys)(nil), "proto.Keys")
+	proto1.RegisterType((*LAValue)(nil), "proto.LAValue")
+	proto1.RegisterType((*LAValueProposal)(nil), "proto.LAValueProposal")
+	proto1.RegisterType((*LAValueReply)(nil), "proto.LAValueReply")
+	proto1.RegisterType((*LAInstance)(nil), "proto.LAInstance")
+	proto1.RegisterType((*DWriteN)(nil), "proto.DWriteN")
+	proto1.RegisterType((*DReadReply)(nil), "proto.DReadReply")
+	proto1.RegisterType((*DNewState)(nil), "proto.DNewState")
+	proto1.RegisterType((*DNewStateReply)(nil), "proto.DNewStateReply")
+	proto1.RegisterType((*SpSnProp)(nil), "proto.SpSnProp")
+	proto1.RegisterType((*SpSnReply)(nil), "proto.SpSnReply")
+	proto1.RegisterType((*Ballot)(nil), "proto.Ballot")
+	proto1.RegisterType((*Lease)(nil), "proto.Lease")
+	proto1.RegisterType((*StatusRequest)(nil), "proto.StatusRequest")
+	proto1.RegisterType((*RegisterStatus)(nil), "proto.RegisterStatus")
+	proto1.RegisterType((*PaxosStatus)(nil), "proto.PaxosStatus")
+	proto1.RegisterType((*ServerStatus)(nil), "proto.ServerStatus")
+	proto1.RegisterType((*WatchRequest)(nil), "proto.WatchRequest")
+	proto1.RegisterType((*WatchUpdate)(nil), "proto.WatchUpdate")
+}
+func (this *State) VerboseEqual(that interface{}) error {
 	if that == nil {
 		if this == nil {
 			return nil
@@ -640,40 +1062,38 @@ func (this *ConfReply) VerboseEqual(that interface{}) error {
 		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	that1, ok := that.(*ConfReply)
+	that1, ok := that.(*State)
 	if !ok {
-		that2, ok := that.(ConfReply)
+		that2, ok := that.(State)
 		if ok {
 			that1 = &that2
 		} else {
-			return fmt.Errorf("that is not of type *ConfReply")
+			return fmt.Errorf("that is not of type *State")
 		}
 	}
 	if that1 == nil {
 		if this == nil {
 			return nil
 		}
-		return fmt.Errorf("that is type *ConfReply but is nil && this != nil")
+		return fmt.Errorf("that is type *State but is nil && this != nil")
 	} else if this == nil {
-		return fmt.Errorf("that is type *ConfReply but is not nil && this == nil")
+		return fmt.Errorf("that is type *State but is not nil && this == nil")
 	}
-	if !this.Cur.Equal(that1.Cur) {
-		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	if !bytes.Equal(this.Value, that1.Value) {
+		return fmt.Errorf("Value this(%v) Not Equal that(%v)", this.Value, that1.Value)
 	}
-	if this.Abort != that1.Abort {
-		return fmt.Errorf("Abort this(%v) Not Equal that(%v)", this.Abort, that1.Abort)
+	if this.Timestamp != that1.Timestamp {
+		return fmt.Errorf("Timestamp this(%v) Not Equal that(%v)", this.Timestamp, that1.Timestamp)
 	}
-	if len(this.Next) != len(that1.Next) {
-		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	if this.Writer != that1.Writer {
+		return fmt.Errorf("Writer this(%v) Not Equal that(%v)", this.Writer, that1.Writer)
 	}
-	for i := range this.Next {
-		if !this.Next[i].Equal(that1.Next[i]) {
-			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
-		}
+	if this.Key != that1.Key {
+		return fmt.Errorf("Key this(%v) Not Equal that(%v)", this.Key, that1.Key)
 	}
 	return nil
 }
-func (this *ConfReply) Equal(that interface{}) bool {
+func (this *State) Equal(that interface{}) bool {
 	if that == nil {
 		if this == nil {
 			return true
@@ -681,9 +1101,9 @@ func (this *ConfReply) Equal(that interface{}) bool {
 		return false
 	}
 
-	that1, ok := that.(*ConfReply)
+	that1, ok := that.(*State)
 	if !ok {
-		that2, ok := that.(ConfReply)
+		that2, ok := that.(State)
 		if ok {
 			that1 = &that2
 		} else {
@@ -698,23 +1118,21 @@ func (this *ConfReply) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if !this.Cur.Equal(that1.Cur) {
+	if !bytes.Equal(this.Value, that1.Value) {
 		return false
 	}
-	if this.Abort != that1.Abort {
+	if this.Timestamp != that1.Timestamp {
 		return false
 	}
-	if len(this.Next) != len(that1.Next) {
+	if this.Writer != that1.Writer {
 		return false
 	}
-	for i := range this.Next {
-		if !this.Next[i].Equal(that1.Next[i]) {
-			return false
-		}
+	if this.Key != that1.Key {
+		return false
 	}
 	return true
 }
-func (this *NewCur) VerboseEqual(that interface{}) error {
+func (this *Conf) VerboseEqual(that interface{}) error {
 	if that == nil {
 		if this == nil {
 			return nil
@@ -722,32 +1140,32 @@ func (this *NewCur) VerboseEqual(that interface{}) error {
 		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	that1, ok := that.(*NewCur)
+	that1, ok := that.(*Conf)
 	if !ok {
-		that2, ok := that.(NewCur)
+		that2, ok := that.(Conf)
 		if ok {
 			that1 = &that2
 		} else {
-			return fmt.Errorf("that is not of type *NewCur")
+			return fmt.Errorf("that is not of type *Conf")
 		}
 	}
 	if that1 == nil {
 		if this == nil {
 			return nil
 		}
-		return fmt.Errorf("that is type *NewCur but is nil && this != nil")
+		return fmt.Errorf("that is type *Conf but is nil && this != nil")
 	} else if this == nil {
-		return fmt.Errorf("that is type *NewCur but is not nil && this == nil")
+		return fmt.Errorf("that is type *Conf but is not nil && this == nil")
 	}
-	if !this.Cur.Equal(that1.Cur) {
-		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	if !bytes.Equal(this.This, that1.This) {
+		return fmt.Errorf("This this(%v) Not Equal that(%v)", this.This, that1.This)
 	}
-	if this.CurC != that1.CurC {
-		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
+	if !bytes.Equal(this.Cur, that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
 	}
 	return nil
 }
-func (this *NewCur) Equal(that interface{}) bool {
+func (this *Conf) Equal(that interface{}) bool {
 	if that == nil {
 		if this == nil {
 			return true
@@ -755,9 +1173,9 @@ func (this *NewCur) Equal(that interface{}) bool {
 		return false
 	}
 
-	that1, ok := that.(*NewCur)
+	that1, ok := that.(*Conf)
 	if !ok {
-		that2, ok := that.(NewCur)
+		that2, ok := that.(Conf)
 		if ok {
 			that1 = &that2
 		} else {
@@ -772,15 +1190,15 @@ func (this *NewCur) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if !this.Cur.Equal(that1.Cur) {
+	if !bytes.Equal(this.This, that1.This) {
 		return false
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.Cur, that1.Cur) {
 		return false
 	}
 	return true
 }
-func (this *NewCurReply) VerboseEqual(that interface{}) error {
+func (this *ConfReply) VerboseEqual(that interface{}) error {
 	if that == nil {
 		if this == nil {
 			return nil
@@ -788,29 +1206,43 @@ func (this *NewCurReply) VerboseEqual(that interface{}) error {
 		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	that1, ok := that.(*NewCurReply)
+	that1, ok := that.(*ConfReply)
 	if !ok {
-		that2, ok := that.(NewCurReply)
+		that2, ok := that.(ConfReply)
 		if ok {
 			that1 = &that2
 		} else {
-			return fmt.Errorf("that is not of type *NewCurReply")
+			return fmt.Errorf("that is not of type *ConfReply")
 		}
 	}
 	if that1 == nil {
 		if this == nil {
 			return nil
 		}
-		return fmt.Errorf("that is type *NewCurReply but is nil && this != nil")
+		return fmt.Errorf("that is type *ConfReply but is nil && this != nil")
 	} else if this == nil {
-		return fmt.Errorf("that is type *NewCurReply but is not nil && this == nil")
+		return fmt.Errorf("that is type *ConfReply but is not nil && this == nil")
 	}
-	if this.New != that1.New {
-		return fmt.Errorf("New this(%v) Not Equal that(%v)", this.New, that1.New)
+	if !this.Cur.Equal(that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	}
+	if this.Abort != that1.Abort {
+		return fmt.Errorf("Abort this(%v) Not Equal that(%v)", this.Abort, that1.Abort)
+	}
+	if len(this.Next) != len(that1.Next) {
+		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
+		}
+	}
+	if this.Rejected != that1.Rejected {
+		return fmt.Errorf("Rejected this(%v) Not Equal that(%v)", this.Rejected, that1.Rejected)
 	}
 	return nil
 }
-func (this *NewCurReply) Equal(that interface{}) bool {
+func (this *ConfReply) Equal(that interface{}) bool {
 	if that == nil {
 		if this == nil {
 			return true
@@ -818,9 +1250,149 @@ func (this *NewCurReply) Equal(that interface{}) bool {
 		return false
 	}
 
-	that1, ok := that.(*NewCurReply)
+	that1, ok := that.(*ConfReply)
 	if !ok {
-		that2, ok := that.(NewCurReply)
+		that2, ok := that.(ConfReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return false
+	}
+	if this.Abort != that1.Abort {
+		return false
+	}
+	if len(this.Next) != len(that1.Next) {
+		return false
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return false
+		}
+	}
+	if this.Rejected != that1.Rejected {
+		return false
+	}
+	return true
+}
+func (this *NewCur) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*NewCur)
+	if !ok {
+		that2, ok := that.(NewCur)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *NewCur")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *NewCur but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *NewCur but is not nil && this == nil")
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
+	}
+	return nil
+}
+func (this *NewCur) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
+
+	that1, ok := that.(*NewCur)
+	if !ok {
+		that2, ok := that.(NewCur)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return false
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return false
+	}
+	return true
+}
+func (this *NewCurReply) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*NewCurReply)
+	if !ok {
+		that2, ok := that.(NewCurReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *NewCurReply")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *NewCurReply but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *NewCurReply but is not nil && this == nil")
+	}
+	if this.New != that1.New {
+		return fmt.Errorf("New this(%v) Not Equal that(%v)", this.New, that1.New)
+	}
+	return nil
+}
+func (this *NewCurReply) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
+
+	that1, ok := that.(*NewCurReply)
+	if !ok {
+		that2, ok := that.(NewCurReply)
 		if ok {
 			that1 = &that2
 		} else {
@@ -868,6 +1440,18 @@ func (this *Read) VerboseEqual(that interface{}) error {
 	if !this.Conf.Equal(that1.Conf) {
 		return fmt.Errorf("Conf this(%v) Not Equal that(%v)", this.Conf, that1.Conf)
 	}
+	if !this.Keys.Equal(that1.Keys) {
+		return fmt.Errorf("Keys this(%v) Not Equal that(%v)", this.Keys, that1.Keys)
+	}
+	if !this.Ballot.Equal(that1.Ballot) {
+		return fmt.Errorf("Ballot this(%v) Not Equal that(%v)", this.Ballot, that1.Ballot)
+	}
+	if this.AsOf != that1.AsOf {
+		return fmt.Errorf("AsOf this(%v) Not Equal that(%v)", this.AsOf, that1.AsOf)
+	}
+	if this.Versions != that1.Versions {
+		return fmt.Errorf("Versions this(%v) Not Equal that(%v)", this.Versions, that1.Versions)
+	}
 	return nil
 }
 func (this *Read) Equal(that interface{}) bool {
@@ -898,6 +1482,18 @@ func (this *Read) Equal(that interface{}) bool {
 	if !this.Conf.Equal(that1.Conf) {
 		return false
 	}
+	if !this.Keys.Equal(that1.Keys) {
+		return false
+	}
+	if !this.Ballot.Equal(that1.Ballot) {
+		return false
+	}
+	if this.AsOf != that1.AsOf {
+		return false
+	}
+	if this.Versions != that1.Versions {
+		return false
+	}
 	return true
 }
 func (this *ReadReply) VerboseEqual(that interface{}) error {
@@ -931,6 +1527,28 @@ func (this *ReadReply) VerboseEqual(that interface{}) error {
 	if !this.Cur.Equal(that1.Cur) {
 		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
 	}
+	if len(this.States) != len(that1.States) {
+		return fmt.Errorf("States this(%v) Not Equal that(%v)", len(this.States), len(that1.States))
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return fmt.Errorf("States this[%v](%v) Not Equal that[%v](%v)", i, this.States[i], i, that1.States[i])
+		}
+	}
+	if !this.Promise.Equal(that1.Promise) {
+		return fmt.Errorf("Promise this(%v) Not Equal that(%v)", this.Promise, that1.Promise)
+	}
+	if len(this.Versions) != len(that1.Versions) {
+		return fmt.Errorf("Versions this(%v) Not Equal that(%v)", len(this.Versions), len(that1.Versions))
+	}
+	for i := range this.Versions {
+		if !this.Versions[i].Equal(that1.Versions[i]) {
+			return fmt.Errorf("Versions this[%v](%v) Not Equal that[%v](%v)", i, this.Versions[i], i, that1.Versions[i])
+		}
+	}
+	if this.Compacted != that1.Compacted {
+		return fmt.Errorf("Compacted this(%v) Not Equal that(%v)", this.Compacted, that1.Compacted)
+	}
 	return nil
 }
 func (this *ReadReply) Equal(that interface{}) bool {
@@ -964,6 +1582,28 @@ func (this *ReadReply) Equal(that interface{}) bool {
 	if !this.Cur.Equal(that1.Cur) {
 		return false
 	}
+	if len(this.States) != len(that1.States) {
+		return false
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return false
+		}
+	}
+	if !this.Promise.Equal(that1.Promise) {
+		return false
+	}
+	if len(this.Versions) != len(that1.Versions) {
+		return false
+	}
+	for i := range this.Versions {
+		if !this.Versions[i].Equal(that1.Versions[i]) {
+			return false
+		}
+	}
+	if this.Compacted != that1.Compacted {
+		return false
+	}
 	return true
 }
 func (this *WriteS) VerboseEqual(that interface{}) error {
@@ -1057,12 +1697,15 @@ func (this *WriteN) VerboseEqual(that interface{}) error {
 	} else if this == nil {
 		return fmt.Errorf("that is type *WriteN but is not nil && this == nil")
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
 	}
 	if !this.Next.Equal(that1.Next) {
 		return fmt.Errorf("Next this(%v) Not Equal that(%v)", this.Next, that1.Next)
 	}
+	if !this.Keys.Equal(that1.Keys) {
+		return fmt.Errorf("Keys this(%v) Not Equal that(%v)", this.Keys, that1.Keys)
+	}
 	return nil
 }
 func (this *WriteN) Equal(that interface{}) bool {
@@ -1090,12 +1733,15 @@ func (this *WriteN) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return false
 	}
 	if !this.Next.Equal(that1.Next) {
 		return false
 	}
+	if !this.Keys.Equal(that1.Keys) {
+		return false
+	}
 	return true
 }
 func (this *WriteNReply) VerboseEqual(that interface{}) error {
@@ -1126,12 +1772,49 @@ func (this *WriteNReply) VerboseEqual(that interface{}) error {
 	if !this.Cur.Equal(that1.Cur) {
 		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
 	}
-	if !this.State.Equal(that1.State) {
-		return fmt.Errorf("State this(%v) Not Equal that(%v)", this.State, that1.State)
+	if len(this.States) != len(that1.States) {
+		return fmt.Errorf("States this(%v) Not Equal that(%v)", len(this.States), len(that1.States))
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return fmt.Errorf("States this[%v](%v) Not Equal that[%v](%v)", i, this.States[i], i, that1.States[i])
+		}
 	}
 	if !this.LAState.Equal(that1.LAState) {
 		return fmt.Errorf("LAState this(%v) Not Equal that(%v)", this.LAState, that1.LAState)
 	}
+	if len(this.LAValues) != len(that1.LAValues) {
+		return fmt.Errorf("LAValues this(%v) Not Equal that(%v)", len(this.LAValues), len(that1.LAValues))
+	}
+	for i := range this.LAValues {
+		if !this.LAValues[i].Equal(that1.LAValues[i]) {
+			return fmt.Errorf("LAValues this[%v](%v) Not Equal that[%v](%v)", i, this.LAValues[i], i, that1.LAValues[i])
+		}
+	}
+	if len(this.Promises) != len(that1.Promises) {
+		return fmt.Errorf("Promises this(%v) Not Equal that(%v)", len(this.Promises), len(that1.Promises))
+	}
+	for i := range this.Promises {
+		if !this.Promises[i].Equal(that1.Promises[i]) {
+			return fmt.Errorf("Promises this[%v](%v) Not Equal that[%v](%v)", i, this.Promises[i], i, that1.Promises[i])
+		}
+	}
+	if len(this.History) != len(that1.History) {
+		return fmt.Errorf("History this(%v) Not Equal that(%v)", len(this.History), len(that1.History))
+	}
+	for i := range this.History {
+		if !this.History[i].Equal(that1.History[i]) {
+			return fmt.Errorf("History this[%v](%v) Not Equal that[%v](%v)", i, this.History[i], i, that1.History[i])
+		}
+	}
+	if len(this.Compacted) != len(that1.Compacted) {
+		return fmt.Errorf("Compacted this(%v) Not Equal that(%v)", len(this.Compacted), len(that1.Compacted))
+	}
+	for i := range this.Compacted {
+		if !this.Compacted[i].Equal(that1.Compacted[i]) {
+			return fmt.Errorf("Compacted this[%v](%v) Not Equal that[%v](%v)", i, this.Compacted[i], i, that1.Compacted[i])
+		}
+	}
 	return nil
 }
 func (this *WriteNReply) Equal(that interface{}) bool {
@@ -1162,12 +1845,49 @@ func (this *WriteNReply) Equal(that interface{}) bool {
 	if !this.Cur.Equal(that1.Cur) {
 		return false
 	}
-	if !this.State.Equal(that1.State) {
+	if len(this.States) != len(that1.States) {
 		return false
 	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return false
+		}
+	}
 	if !this.LAState.Equal(that1.LAState) {
 		return false
 	}
+	if len(this.LAValues) != len(that1.LAValues) {
+		return false
+	}
+	for i := range this.LAValues {
+		if !this.LAValues[i].Equal(that1.LAValues[i]) {
+			return false
+		}
+	}
+	if len(this.Promises) != len(that1.Promises) {
+		return false
+	}
+	for i := range this.Promises {
+		if !this.Promises[i].Equal(that1.Promises[i]) {
+			return false
+		}
+	}
+	if len(this.History) != len(that1.History) {
+		return false
+	}
+	for i := range this.History {
+		if !this.History[i].Equal(that1.History[i]) {
+			return false
+		}
+	}
+	if len(this.Compacted) != len(that1.Compacted) {
+		return false
+	}
+	for i := range this.Compacted {
+		if !this.Compacted[i].Equal(that1.Compacted[i]) {
+			return false
+		}
+	}
 	return true
 }
 func (this *LAProposal) VerboseEqual(that interface{}) error {
@@ -1327,15 +2047,55 @@ func (this *NewState) VerboseEqual(that interface{}) error {
 	} else if this == nil {
 		return fmt.Errorf("that is type *NewState but is not nil && this == nil")
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
 	}
-	if !this.State.Equal(that1.State) {
-		return fmt.Errorf("State this(%v) Not Equal that(%v)", this.State, that1.State)
+	if len(this.States) != len(that1.States) {
+		return fmt.Errorf("States this(%v) Not Equal that(%v)", len(this.States), len(that1.States))
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return fmt.Errorf("States this[%v](%v) Not Equal that[%v](%v)", i, this.States[i], i, that1.States[i])
+		}
 	}
 	if !this.LAState.Equal(that1.LAState) {
 		return fmt.Errorf("LAState this(%v) Not Equal that(%v)", this.LAState, that1.LAState)
 	}
+	if len(this.LAValues) != len(that1.LAValues) {
+		return fmt.Errorf("LAValues this(%v) Not Equal that(%v)", len(this.LAValues), len(that1.LAValues))
+	}
+	for i := range this.LAValues {
+		if !this.LAValues[i].Equal(that1.LAValues[i]) {
+			return fmt.Errorf("LAValues this[%v](%v) Not Equal that[%v](%v)", i, this.LAValues[i], i, that1.LAValues[i])
+		}
+	}
+	if len(this.Promises) != len(that1.Promises) {
+		return fmt.Errorf("Promises this(%v) Not Equal that(%v)", len(this.Promises), len(that1.Promises))
+	}
+	for i := range this.Promises {
+		if !this.Promises[i].Equal(that1.Promises[i]) {
+			return fmt.Errorf("Promises this[%v](%v) Not Equal that[%v](%v)", i, this.Promises[i], i, that1.Promises[i])
+		}
+	}
+	if len(this.History) != len(that1.History) {
+		return fmt.Errorf("History this(%v) Not Equal that(%v)", len(this.History), len(that1.History))
+	}
+	for i := range this.History {
+		if !this.History[i].Equal(that1.History[i]) {
+			return fmt.Errorf("History this[%v](%v) Not Equal that[%v](%v)", i, this.History[i], i, that1.History[i])
+		}
+	}
+	if len(this.Compacted) != len(that1.Compacted) {
+		return fmt.Errorf("Compacted this(%v) Not Equal that(%v)", len(this.Compacted), len(that1.Compacted))
+	}
+	for i := range this.Compacted {
+		if !this.Compacted[i].Equal(that1.Compacted[i]) {
+			return fmt.Errorf("Compacted this[%v](%v) Not Equal that[%v](%v)", i, this.Compacted[i], i, that1.Compacted[i])
+		}
+	}
+	if !this.Write.Equal(that1.Write) {
+		return fmt.Errorf("Write this(%v) Not Equal that(%v)", this.Write, that1.Write)
+	}
 	return nil
 }
 func (this *NewState) Equal(that interface{}) bool {
@@ -1363,32 +2123,72 @@ func (this *NewState) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return false
 	}
-	if !this.State.Equal(that1.State) {
+	if len(this.States) != len(that1.States) {
 		return false
 	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return false
+		}
+	}
 	if !this.LAState.Equal(that1.LAState) {
 		return false
 	}
-	return true
-}
-func (this *NewStateReply) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
+	if len(this.LAValues) != len(that1.LAValues) {
+		return false
+	}
+	for i := range this.LAValues {
+		if !this.LAValues[i].Equal(that1.LAValues[i]) {
+			return false
 		}
-		return fmt.Errorf("that == nil && this != nil")
 	}
-
-	that1, ok := that.(*NewStateReply)
-	if !ok {
-		that2, ok := that.(NewStateReply)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *NewStateReply")
+	if len(this.Promises) != len(that1.Promises) {
+		return false
+	}
+	for i := range this.Promises {
+		if !this.Promises[i].Equal(that1.Promises[i]) {
+			return false
+		}
+	}
+	if len(this.History) != len(that1.History) {
+		return false
+	}
+	for i := range this.History {
+		if !this.History[i].Equal(that1.History[i]) {
+			return false
+		}
+	}
+	if len(this.Compacted) != len(that1.Compacted) {
+		return false
+	}
+	for i := range this.Compacted {
+		if !this.Compacted[i].Equal(that1.Compacted[i]) {
+			return false
+		}
+	}
+	if !this.Write.Equal(that1.Write) {
+		return false
+	}
+	return true
+}
+func (this *NewStateReply) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*NewStateReply)
+	if !ok {
+		that2, ok := that.(NewStateReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *NewStateReply")
 		}
 	}
 	if that1 == nil {
@@ -1410,6 +2210,9 @@ func (this *NewStateReply) VerboseEqual(that interface{}) error {
 			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
 		}
 	}
+	if this.Rejected != that1.Rejected {
+		return fmt.Errorf("Rejected this(%v) Not Equal that(%v)", this.Rejected, that1.Rejected)
+	}
 	return nil
 }
 func (this *NewStateReply) Equal(that interface{}) bool {
@@ -1448,6 +2251,9 @@ func (this *NewStateReply) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.Rejected != that1.Rejected {
+		return false
+	}
 	return true
 }
 func (this *CV) VerboseEqual(that interface{}) error {
@@ -1541,7 +2347,7 @@ func (this *Prepare) VerboseEqual(that interface{}) error {
 	} else if this == nil {
 		return fmt.Errorf("that is type *Prepare but is not nil && this == nil")
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
 	}
 	if this.Rnd != that1.Rnd {
@@ -1574,7 +2380,7 @@ func (this *Prepare) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return false
 	}
 	if this.Rnd != that1.Rnd {
@@ -1685,7 +2491,7 @@ func (this *Propose) VerboseEqual(that interface{}) error {
 	} else if this == nil {
 		return fmt.Errorf("that is type *Propose but is not nil && this == nil")
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
 	}
 	if !this.Val.Equal(that1.Val) {
@@ -1718,7 +2524,7 @@ func (this *Propose) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CurC != that1.CurC {
+	if !bytes.Equal(this.CurC, that1.CurC) {
 		return false
 	}
 	if !this.Val.Equal(that1.Val) {
@@ -1883,6 +2689,15 @@ func (this *Ack) VerboseEqual(that interface{}) error {
 	} else if this == nil {
 		return fmt.Errorf("that is type *Ack but is not nil && this == nil")
 	}
+	if this.Redirect != that1.Redirect {
+		return fmt.Errorf("Redirect this(%v) Not Equal that(%v)", this.Redirect, that1.Redirect)
+	}
+	if this.Leader != that1.Leader {
+		return fmt.Errorf("Leader this(%v) Not Equal that(%v)", this.Leader, that1.Leader)
+	}
+	if this.LeaderAddr != that1.LeaderAddr {
+		return fmt.Errorf("LeaderAddr this(%v) Not Equal that(%v)", this.LeaderAddr, that1.LeaderAddr)
+	}
 	return nil
 }
 func (this *Ack) Equal(that interface{}) bool {
@@ -1910,3261 +2725,10121 @@ func (this *Ack) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
+	if this.Redirect != that1.Redirect {
+		return false
+	}
+	if this.Leader != that1.Leader {
+		return false
+	}
+	if this.LeaderAddr != that1.LeaderAddr {
+		return false
+	}
 	return true
 }
+func (this *Keys) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-//  Reference Gorums specific imports to suppress errors if they are not otherwise used.
-var _ = codes.OK
-
-/* 'gorums' plugin for protoc-gen-go - generated from: config_qc_tmpl */
-
-// AcceptReply encapsulates the reply from a Accept quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type AcceptReply struct {
-	NodeIDs []uint32
-	*Learn
+	that1, ok := that.(*Keys)
+	if !ok {
+		that2, ok := that.(Keys)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Keys")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Keys but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Keys but is not nil && this == nil")
+	}
+	if this.Key != that1.Key {
+		return fmt.Errorf("Key this(%v) Not Equal that(%v)", this.Key, that1.Key)
+	}
+	if this.End != that1.End {
+		return fmt.Errorf("End this(%v) Not Equal that(%v)", this.End, that1.End)
+	}
+	if this.Range != that1.Range {
+		return fmt.Errorf("Range this(%v) Not Equal that(%v)", this.Range, that1.Range)
+	}
+	return nil
 }
+func (this *Keys) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-func (r AcceptReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.Learn)
+	that1, ok := that.(*Keys)
+	if !ok {
+		that2, ok := that.(Keys)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Key != that1.Key {
+		return false
+	}
+	if this.End != that1.End {
+		return false
+	}
+	if this.Range != that1.Range {
+		return false
+	}
+	return true
 }
+func (this *LAValue) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-// Accept invokes a Accept quorum call on configuration c
-// and returns the result as a AcceptReply.
-func (c *Configuration) Accept(ctx context.Context, args *Propose) (*AcceptReply, error) {
-	return c.mgr.accept(ctx, c, args)
+	that1, ok := that.(*LAValue)
+	if !ok {
+		that2, ok := that.(LAValue)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *LAValue")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *LAValue but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *LAValue but is not nil && this == nil")
+	}
+	if this.Type != that1.Type {
+		return fmt.Errorf("Type this(%v) Not Equal that(%v)", this.Type, that1.Type)
+	}
+	if !bytes.Equal(this.Value, that1.Value) {
+		return fmt.Errorf("Value this(%v) Not Equal that(%v)", this.Value, that1.Value)
+	}
+	return nil
 }
+func (this *LAValue) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-// GetPromiseReply encapsulates the reply from a GetPromise quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type GetPromiseReply struct {
-	NodeIDs []uint32
-	*Promise
+	that1, ok := that.(*LAValue)
+	if !ok {
+		that2, ok := that.(LAValue)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if !bytes.Equal(this.Value, that1.Value) {
+		return false
+	}
+	return true
 }
+func (this *LAValueProposal) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (r GetPromiseReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.Promise)
+	that1, ok := that.(*LAValueProposal)
+	if !ok {
+		that2, ok := that.(LAValueProposal)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *LAValueProposal")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *LAValueProposal but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *LAValueProposal but is not nil && this == nil")
+	}
+	if !this.Conf.Equal(that1.Conf) {
+		return fmt.Errorf("Conf this(%v) Not Equal that(%v)", this.Conf, that1.Conf)
+	}
+	if this.Instance != that1.Instance {
+		return fmt.Errorf("Instance this(%v) Not Equal that(%v)", this.Instance, that1.Instance)
+	}
+	if !this.Prop.Equal(that1.Prop) {
+		return fmt.Errorf("Prop this(%v) Not Equal that(%v)", this.Prop, that1.Prop)
+	}
+	return nil
 }
+func (this *LAValueProposal) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-// GetPromise invokes a GetPromise quorum call on configuration c
-// and returns the result as a GetPromiseReply.
-func (c *Configuration) GetPromise(ctx context.Context, args *Prepare) (*GetPromiseReply, error) {
-	return c.mgr.getPromise(ctx, c, args)
+	that1, ok := that.(*LAValueProposal)
+	if !ok {
+		that2, ok := that.(LAValueProposal)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !this.Conf.Equal(that1.Conf) {
+		return false
+	}
+	if this.Instance != that1.Instance {
+		return false
+	}
+	if !this.Prop.Equal(that1.Prop) {
+		return false
+	}
+	return true
 }
+func (this *LAValueReply) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-// LAPropReply encapsulates the reply from a LAProp quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type LAPropReply struct {
-	NodeIDs []uint32
-	*LAReply
-}
-
-func (r LAPropReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.LAReply)
-}
-
-// LAProp invokes a LAProp quorum call on configuration c
-// and returns the result as a LAPropReply.
-func (c *Configuration) LAProp(ctx context.Context, args *LAProposal) (*LAPropReply, error) {
-	return c.mgr.lAProp(ctx, c, args)
-}
-
-// ReadReply_ encapsulates the reply from a Read quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type ReadReply_ struct {
-	NodeIDs []uint32
-	*ReadReply
+	that1, ok := that.(*LAValueReply)
+	if !ok {
+		that2, ok := that.(LAValueReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *LAValueReply")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *LAValueReply but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *LAValueReply but is not nil && this == nil")
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	}
+	if !this.State.Equal(that1.State) {
+		return fmt.Errorf("State this(%v) Not Equal that(%v)", this.State, that1.State)
+	}
+	return nil
 }
+func (this *LAValueReply) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-func (r ReadReply_) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.ReadReply)
+	that1, ok := that.(*LAValueReply)
+	if !ok {
+		that2, ok := that.(LAValueReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return false
+	}
+	if !this.State.Equal(that1.State) {
+		return false
+	}
+	return true
 }
+func (this *LAInstance) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-// Read invokes a Read quorum call on configuration c
-// and returns the result as a ReadReply_.
-func (c *Configuration) Read(ctx context.Context, args *Conf) (*ReadReply_, error) {
-	return c.mgr.read(ctx, c, args)
+	that1, ok := that.(*LAInstance)
+	if !ok {
+		that2, ok := that.(LAInstance)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *LAInstance")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *LAInstance but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *LAInstance but is not nil && this == nil")
+	}
+	if this.Instance != that1.Instance {
+		return fmt.Errorf("Instance this(%v) Not Equal that(%v)", this.Instance, that1.Instance)
+	}
+	if !this.State.Equal(that1.State) {
+		return fmt.Errorf("State this(%v) Not Equal that(%v)", this.State, that1.State)
+	}
+	return nil
 }
+func (this *LAInstance) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-// SetCurReply encapsulates the reply from a SetCur quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type SetCurReply struct {
-	NodeIDs []uint32
-	*NewCurReply
+	that1, ok := that.(*LAInstance)
+	if !ok {
+		that2, ok := that.(LAInstance)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Instance != that1.Instance {
+		return false
+	}
+	if !this.State.Equal(that1.State) {
+		return false
+	}
+	return true
 }
+func (this *DWriteN) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (r SetCurReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.NewCurReply)
+	that1, ok := that.(*DWriteN)
+	if !ok {
+		that2, ok := that.(DWriteN)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DWriteN")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DWriteN but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DWriteN but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
+	}
+	if len(this.Next) != len(that1.Next) {
+		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
+		}
+	}
+	if !this.Keys.Equal(that1.Keys) {
+		return fmt.Errorf("Keys this(%v) Not Equal that(%v)", this.Keys, that1.Keys)
+	}
+	return nil
 }
+func (this *DWriteN) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-// SetCur invokes a SetCur quorum call on configuration c
-// and returns the result as a SetCurReply.
-func (c *Configuration) SetCur(ctx context.Context, args *NewCur) (*SetCurReply, error) {
-	return c.mgr.setCur(ctx, c, args)
+	that1, ok := that.(*DWriteN)
+	if !ok {
+		that2, ok := that.(DWriteN)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return false
+	}
+	if len(this.Next) != len(that1.Next) {
+		return false
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return false
+		}
+	}
+	if !this.Keys.Equal(that1.Keys) {
+		return false
+	}
+	return true
 }
+func (this *DReadReply) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-// SetStateReply encapsulates the reply from a SetState quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type SetStateReply struct {
-	NodeIDs []uint32
-	*NewStateReply
+	that1, ok := that.(*DReadReply)
+	if !ok {
+		that2, ok := that.(DReadReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DReadReply")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DReadReply but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DReadReply but is not nil && this == nil")
+	}
+	if len(this.States) != len(that1.States) {
+		return fmt.Errorf("States this(%v) Not Equal that(%v)", len(this.States), len(that1.States))
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return fmt.Errorf("States this[%v](%v) Not Equal that[%v](%v)", i, this.States[i], i, that1.States[i])
+		}
+	}
+	if len(this.Next) != len(that1.Next) {
+		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
+		}
+	}
+	return nil
 }
+func (this *DReadReply) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-func (r SetStateReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.NewStateReply)
+	that1, ok := that.(*DReadReply)
+	if !ok {
+		that2, ok := that.(DReadReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if len(this.States) != len(that1.States) {
+		return false
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return false
+		}
+	}
+	if len(this.Next) != len(that1.Next) {
+		return false
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return false
+		}
+	}
+	return true
 }
+func (this *DNewState) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-// SetState invokes a SetState quorum call on configuration c
-// and returns the result as a SetStateReply.
-func (c *Configuration) SetState(ctx context.Context, args *NewState) (*SetStateReply, error) {
-	return c.mgr.setState(ctx, c, args)
-}
-
-// WriteReply encapsulates the reply from a Write quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type WriteReply struct {
-	NodeIDs []uint32
-	*ConfReply
-}
-
-func (r WriteReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.ConfReply)
-}
-
-// Write invokes a Write quorum call on configuration c
-// and returns the result as a WriteReply.
-func (c *Configuration) Write(ctx context.Context, args *WriteS) (*WriteReply, error) {
-	return c.mgr.write(ctx, c, args)
-}
-
-// WriteNextReply encapsulates the reply from a WriteNext quorum call.
-// It contains the id of each node of the quorum that replied and a single reply.
-type WriteNextReply struct {
-	NodeIDs []uint32
-	*WriteNReply
-}
-
-func (r WriteNextReply) String() string {
-	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.WriteNReply)
-}
-
-// WriteNext invokes a WriteNext quorum call on configuration c
-// and returns the result as a WriteNextReply.
-func (c *Configuration) WriteNext(ctx context.Context, args *WriteN) (*WriteNextReply, error) {
-	return c.mgr.writeNext(ctx, c, args)
-}
-
-/* 'gorums' plugin for protoc-gen-go - generated from: mgr_qc_tmpl */
-
-type acceptReply struct {
-	nid   uint32
-	reply *Learn
-	err   error
-}
-
-func (m *Manager) accept(ctx context.Context, c *Configuration, args *Propose) (r *AcceptReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Accept")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+	that1, ok := that.(*DNewState)
+	if !ok {
+		that2, ok := that.(DNewState)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DNewState")
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.Learn,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
 	}
-
-	replyChan := make(chan acceptReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DNewState but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DNewState but is not nil && this == nil")
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCAccept(ctx, n, args, replyChan)
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
 	}
-
-	var (
-		replyValues = make([]*Learn, 0, c.n)
-		reply       = &AcceptReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.Learn, quorum = c.qspec.AcceptQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
-		}
-
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+	if len(this.States) != len(that1.States) {
+		return fmt.Errorf("States this(%v) Not Equal that(%v)", len(this.States), len(that1.States))
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return fmt.Errorf("States this[%v](%v) Not Equal that[%v](%v)", i, this.States[i], i, that1.States[i])
 		}
 	}
+	return nil
 }
-
-func callGRPCAccept(ctx context.Context, node *Node, args *Propose, replyChan chan<- acceptReply) {
-	reply := new(Learn)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/Accept",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+func (this *DNewState) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
 	}
-	replyChan <- acceptReply{node.id, reply, err}
-}
-
-type getPromiseReply struct {
-	nid   uint32
-	reply *Promise
-	err   error
-}
 
-func (m *Manager) getPromise(ctx context.Context, c *Configuration, args *Prepare) (r *GetPromiseReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "GetPromise")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+	that1, ok := that.(*DNewState)
+	if !ok {
+		that2, ok := that.(DNewState)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.Promise,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
 	}
-
-	replyChan := make(chan getPromiseReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCGetPromise(ctx, n, args, replyChan)
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return false
 	}
-
-	var (
-		replyValues = make([]*Promise, 0, c.n)
-		reply       = &GetPromiseReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.Promise, quorum = c.qspec.GetPromiseQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+	if len(this.States) != len(that1.States) {
+		return false
+	}
+	for i := range this.States {
+		if !this.States[i].Equal(that1.States[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *DNewStateReply) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
 		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+	that1, ok := that.(*DNewStateReply)
+	if !ok {
+		that2, ok := that.(DNewStateReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DNewStateReply")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DNewStateReply but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DNewStateReply but is not nil && this == nil")
+	}
+	if len(this.Next) != len(that1.Next) {
+		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
 		}
 	}
+	return nil
 }
+func (this *DNewStateReply) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-func callGRPCGetPromise(ctx context.Context, node *Node, args *Prepare, replyChan chan<- getPromiseReply) {
-	reply := new(Promise)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/GetPromise",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+	that1, ok := that.(*DNewStateReply)
+	if !ok {
+		that2, ok := that.(DNewStateReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	replyChan <- getPromiseReply{node.id, reply, err}
-}
-
-type lAPropReply struct {
-	nid   uint32
-	reply *LAReply
-	err   error
-}
-
-func (m *Manager) lAProp(ctx context.Context, c *Configuration, args *LAProposal) (r *LAPropReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "LAProp")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+	if that1 == nil {
+		if this == nil {
+			return true
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.LAReply,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
+		return false
+	} else if this == nil {
+		return false
 	}
-
-	replyChan := make(chan lAPropReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	if len(this.Next) != len(that1.Next) {
+		return false
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCLAProp(ctx, n, args, replyChan)
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return false
+		}
 	}
-
-	var (
-		replyValues = make([]*LAReply, 0, c.n)
-		reply       = &LAPropReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.LAReply, quorum = c.qspec.LAPropQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+	return true
+}
+func (this *SpSnProp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
 		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+	that1, ok := that.(*SpSnProp)
+	if !ok {
+		that2, ok := that.(SpSnProp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SpSnProp")
 		}
 	}
-}
-
-func callGRPCLAProp(ctx context.Context, node *Node, args *LAProposal, replyChan chan<- lAPropReply) {
-	reply := new(LAReply)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/LAProp",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SpSnProp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SpSnProp but is not nil && this == nil")
 	}
-	replyChan <- lAPropReply{node.id, reply, err}
-}
-
-type readReply struct {
-	nid   uint32
-	reply *ReadReply
-	err   error
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
+	}
+	if this.Level != that1.Level {
+		return fmt.Errorf("Level this(%v) Not Equal that(%v)", this.Level, that1.Level)
+	}
+	if !this.Prop.Equal(that1.Prop) {
+		return fmt.Errorf("Prop this(%v) Not Equal that(%v)", this.Prop, that1.Prop)
+	}
+	return nil
 }
-
-func (m *Manager) read(ctx context.Context, c *Configuration, args *Conf) (r *ReadReply_, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Read")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+func (this *SpSnProp) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.ReadReply,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
+		return false
 	}
 
-	replyChan := make(chan readReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	that1, ok := that.(*SpSnProp)
+	if !ok {
+		that2, ok := that.(SpSnProp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCRead(ctx, n, args, replyChan)
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
 	}
-
-	var (
-		replyValues = make([]*ReadReply, 0, c.n)
-		reply       = &ReadReply_{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.ReadReply, quorum = c.qspec.ReadQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return false
+	}
+	if this.Level != that1.Level {
+		return false
+	}
+	if !this.Prop.Equal(that1.Prop) {
+		return false
+	}
+	return true
+}
+func (this *SpSnReply) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
 		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+	that1, ok := that.(*SpSnReply)
+	if !ok {
+		that2, ok := that.(SpSnReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SpSnReply")
 		}
 	}
-}
-
-func callGRPCRead(ctx context.Context, node *Node, args *Conf, replyChan chan<- readReply) {
-	reply := new(ReadReply)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/Read",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SpSnReply but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SpSnReply but is not nil && this == nil")
 	}
-	replyChan <- readReply{node.id, reply, err}
+	if len(this.Vals) != len(that1.Vals) {
+		return fmt.Errorf("Vals this(%v) Not Equal that(%v)", len(this.Vals), len(that1.Vals))
+	}
+	for i := range this.Vals {
+		if !this.Vals[i].Equal(that1.Vals[i]) {
+			return fmt.Errorf("Vals this[%v](%v) Not Equal that[%v](%v)", i, this.Vals[i], i, that1.Vals[i])
+		}
+	}
+	return nil
 }
-
-type setCurReply struct {
-	nid   uint32
-	reply *NewCurReply
-	err   error
-}
-
-func (m *Manager) setCur(ctx context.Context, c *Configuration, args *NewCur) (r *SetCurReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "SetCur")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+func (this *SpSnReply) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.NewCurReply,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
-	}
-
-	replyChan := make(chan setCurReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+		return false
 	}
 
-	for _, n := range c.nodes {
-		go callGRPCSetCur(ctx, n, args, replyChan)
+	that1, ok := that.(*SpSnReply)
+	if !ok {
+		that2, ok := that.(SpSnReply)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-
-	var (
-		replyValues = make([]*NewCurReply, 0, c.n)
-		reply       = &SetCurReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.NewCurReply, quorum = c.qspec.SetCurQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+	if that1 == nil {
+		if this == nil {
+			return true
 		}
-
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if len(this.Vals) != len(that1.Vals) {
+		return false
+	}
+	for i := range this.Vals {
+		if !this.Vals[i].Equal(that1.Vals[i]) {
+			return false
 		}
 	}
+	return true
 }
-
-func callGRPCSetCur(ctx context.Context, node *Node, args *NewCur, replyChan chan<- setCurReply) {
-	reply := new(NewCurReply)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/SetCur",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+func (this *Ballot) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	replyChan <- setCurReply{node.id, reply, err}
-}
-
-type setStateReply struct {
-	nid   uint32
-	reply *NewStateReply
-	err   error
-}
-
-func (m *Manager) setState(ctx context.Context, c *Configuration, args *NewState) (r *SetStateReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "SetState")
-		defer ti.tr.Finish()
 
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+	that1, ok := that.(*Ballot)
+	if !ok {
+		that2, ok := that.(Ballot)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Ballot")
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.NewStateReply,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
 	}
-
-	replyChan := make(chan setStateReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Ballot but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Ballot but is not nil && this == nil")
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCSetState(ctx, n, args, replyChan)
+	if this.Rnd != that1.Rnd {
+		return fmt.Errorf("Rnd this(%v) Not Equal that(%v)", this.Rnd, that1.Rnd)
 	}
-
-	var (
-		replyValues = make([]*NewStateReply, 0, c.n)
-		reply       = &SetStateReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.NewStateReply, quorum = c.qspec.SetStateQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
-		}
-
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
-		}
+	if this.Id != that1.Id {
+		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
 	}
-}
-
-func callGRPCSetState(ctx context.Context, node *Node, args *NewState, replyChan chan<- setStateReply) {
-	reply := new(NewStateReply)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/SetState",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+	if this.Addr != that1.Addr {
+		return fmt.Errorf("Addr this(%v) Not Equal that(%v)", this.Addr, that1.Addr)
 	}
-	replyChan <- setStateReply{node.id, reply, err}
-}
-
-type writeReply struct {
-	nid   uint32
-	reply *ConfReply
-	err   error
+	if this.Lease != that1.Lease {
+		return fmt.Errorf("Lease this(%v) Not Equal that(%v)", this.Lease, that1.Lease)
+	}
+	return nil
 }
-
-func (m *Manager) write(ctx context.Context, c *Configuration, args *WriteS) (r *WriteReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Write")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+func (this *Ballot) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.ConfReply,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
+		return false
 	}
 
-	replyChan := make(chan writeReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	that1, ok := that.(*Ballot)
+	if !ok {
+		that2, ok := that.(Ballot)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCWrite(ctx, n, args, replyChan)
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
 	}
-
-	var (
-		replyValues = make([]*ConfReply, 0, c.n)
-		reply       = &WriteReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.ConfReply, quorum = c.qspec.WriteQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+	if this.Rnd != that1.Rnd {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.Addr != that1.Addr {
+		return false
+	}
+	if this.Lease != that1.Lease {
+		return false
+	}
+	return true
+}
+func (this *Lease) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
 		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+	that1, ok := that.(*Lease)
+	if !ok {
+		that2, ok := that.(Lease)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Lease")
 		}
 	}
-}
-
-func callGRPCWrite(ctx context.Context, node *Node, args *WriteS, replyChan chan<- writeReply) {
-	reply := new(ConfReply)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/Write",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Lease but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Lease but is not nil && this == nil")
 	}
-	replyChan <- writeReply{node.id, reply, err}
+	if this.Granted != that1.Granted {
+		return fmt.Errorf("Granted this(%v) Not Equal that(%v)", this.Granted, that1.Granted)
+	}
+	if !this.Holder.Equal(that1.Holder) {
+		return fmt.Errorf("Holder this(%v) Not Equal that(%v)", this.Holder, that1.Holder)
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	}
+	return nil
 }
+func (this *Lease) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-type writeNextReply struct {
-	nid   uint32
-	reply *WriteNReply
-	err   error
+	that1, ok := that.(*Lease)
+	if !ok {
+		that2, ok := that.(Lease)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Granted != that1.Granted {
+		return false
+	}
+	if !this.Holder.Equal(that1.Holder) {
+		return false
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return false
+	}
+	return true
 }
-
-func (m *Manager) writeNext(ctx context.Context, c *Configuration, args *WriteN) (r *WriteNextReply, err error) {
-	var ti traceInfo
-	if m.opts.trace {
-		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "WriteNext")
-		defer ti.tr.Finish()
-
-		ti.firstLine.cid = c.id
-		if deadline, ok := ctx.Deadline(); ok {
-			ti.firstLine.deadline = deadline.Sub(time.Now())
+func (this *StatusRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
 		}
-		ti.tr.LazyLog(&ti.firstLine, false)
-
-		defer func() {
-			ti.tr.LazyLog(&qcresult{
-				ids:   r.NodeIDs,
-				reply: r.WriteNReply,
-				err:   err,
-			}, false)
-			if err != nil {
-				ti.tr.SetError()
-			}
-		}()
+		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	replyChan := make(chan writeNextReply, c.n)
-
-	if m.opts.trace {
-		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	that1, ok := that.(*StatusRequest)
+	if !ok {
+		that2, ok := that.(StatusRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StatusRequest")
+		}
 	}
-
-	for _, n := range c.nodes {
-		go callGRPCWriteNext(ctx, n, args, replyChan)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StatusRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StatusRequest but is not nil && this == nil")
 	}
-
-	var (
-		replyValues = make([]*WriteNReply, 0, c.n)
-		reply       = &WriteNextReply{NodeIDs: make([]uint32, 0, c.n)}
-		errCount    int
-		quorum      bool
-	)
-
-	for {
-		select {
-		case r := <-replyChan:
-			reply.NodeIDs = append(reply.NodeIDs, r.nid)
-			if r.err != nil {
-				errCount++
-				break
-			}
-			if m.opts.trace {
-				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
-			}
-			replyValues = append(replyValues, r.reply)
-			if reply.WriteNReply, quorum = c.qspec.WriteNextQF(replyValues); quorum {
-				return reply, nil
-			}
-		case <-ctx.Done():
-			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+	return nil
+}
+func (this *StatusRequest) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
 		}
+		return false
+	}
 
-		if errCount+len(replyValues) == c.n {
-			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+	that1, ok := that.(*StatusRequest)
+	if !ok {
+		that2, ok := that.(StatusRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
 		}
+		return false
+	} else if this == nil {
+		return false
 	}
+	return true
 }
+func (this *RegisterStatus) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func callGRPCWriteNext(ctx context.Context, node *Node, args *WriteN, replyChan chan<- writeNextReply) {
-	reply := new(WriteNReply)
-	start := time.Now()
-	err := grpc.Invoke(
-		ctx,
-		"/proto.SMandConsRegister/WriteNext",
-		args,
-		reply,
-		node.conn,
-	)
-	switch grpc.Code(err) { // nil -> codes.OK
-	case codes.OK, codes.Canceled:
-		node.setLatency(time.Since(start))
-	default:
-		node.setLastErr(err)
+	that1, ok := that.(*RegisterStatus)
+	if !ok {
+		that2, ok := that.(RegisterStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *RegisterStatus")
+		}
 	}
-	replyChan <- writeNextReply{node.id, reply, err}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *RegisterStatus but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *RegisterStatus but is not nil && this == nil")
+	}
+	if this.Key != that1.Key {
+		return fmt.Errorf("Key this(%v) Not Equal that(%v)", this.Key, that1.Key)
+	}
+	if this.Timestamp != that1.Timestamp {
+		return fmt.Errorf("Timestamp this(%v) Not Equal that(%v)", this.Timestamp, that1.Timestamp)
+	}
+	if this.Writer != that1.Writer {
+		return fmt.Errorf("Writer this(%v) Not Equal that(%v)", this.Writer, that1.Writer)
+	}
+	return nil
 }
+func (this *RegisterStatus) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-/* 'gorums' plugin for protoc-gen-go - generated from: node_tmpl */
-
-// Node encapsulates the state of a node on which a remote procedure call
-// can be made.
-type Node struct {
-	// Only assigned at creation.
-	id   uint32
-	self bool
-	addr string
-	conn *grpc.ClientConn
-
-	SMandConsRegisterClient SMandConsRegisterClient
-
-	sync.Mutex
-	lastErr error
-	latency time.Duration
+	that1, ok := that.(*RegisterStatus)
+	if !ok {
+		that2, ok := that.(RegisterStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Key != that1.Key {
+		return false
+	}
+	if this.Timestamp != that1.Timestamp {
+		return false
+	}
+	if this.Writer != that1.Writer {
+		return false
+	}
+	return true
 }
-
-func (n *Node) connect(opts ...grpc.DialOption) error {
-	var err error
-	n.conn, err = grpc.Dial(n.addr, opts...)
-	if err != nil {
-		return fmt.Errorf("dialing node failed: %v", err)
+func (this *PaxosStatus) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	n.SMandConsRegisterClient = NewSMandConsRegisterClient(n.conn)
-
+	that1, ok := that.(*PaxosStatus)
+	if !ok {
+		that2, ok := that.(PaxosStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *PaxosStatus")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *PaxosStatus but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *PaxosStatus but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
+	}
+	if this.Rnd != that1.Rnd {
+		return fmt.Errorf("Rnd this(%v) Not Equal that(%v)", this.Rnd, that1.Rnd)
+	}
+	if !this.Val.Equal(that1.Val) {
+		return fmt.Errorf("Val this(%v) Not Equal that(%v)", this.Val, that1.Val)
+	}
 	return nil
 }
+func (this *PaxosStatus) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-func (n *Node) close() error {
-	// TODO: Log error, mainly care about the connection error below.
-	// We should log this error, but we currently don't have access to the
-	// logger in the manager.
+	that1, ok := that.(*PaxosStatus)
+	if !ok {
+		that2, ok := that.(PaxosStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return false
+	}
+	if this.Rnd != that1.Rnd {
+		return false
+	}
+	if !this.Val.Equal(that1.Val) {
+		return false
+	}
+	return true
+}
+func (this *ServerStatus) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-	if err := n.conn.Close(); err != nil {
-		return fmt.Errorf("conn close error: %v", err)
+	that1, ok := that.(*ServerStatus)
+	if !ok {
+		that2, ok := that.(ServerStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *ServerStatus")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *ServerStatus but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *ServerStatus but is not nil && this == nil")
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return fmt.Errorf("CurC this(%v) Not Equal that(%v)", this.CurC, that1.CurC)
+	}
+	if !this.LAState.Equal(that1.LAState) {
+		return fmt.Errorf("LAState this(%v) Not Equal that(%v)", this.LAState, that1.LAState)
+	}
+	if len(this.Next) != len(that1.Next) {
+		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
+		}
+	}
+	if len(this.Registers) != len(that1.Registers) {
+		return fmt.Errorf("Registers this(%v) Not Equal that(%v)", len(this.Registers), len(that1.Registers))
+	}
+	for i := range this.Registers {
+		if !this.Registers[i].Equal(that1.Registers[i]) {
+			return fmt.Errorf("Registers this[%v](%v) Not Equal that[%v](%v)", i, this.Registers[i], i, that1.Registers[i])
+		}
+	}
+	if len(this.Paxos) != len(that1.Paxos) {
+		return fmt.Errorf("Paxos this(%v) Not Equal that(%v)", len(this.Paxos), len(that1.Paxos))
+	}
+	for i := range this.Paxos {
+		if !this.Paxos[i].Equal(that1.Paxos[i]) {
+			return fmt.Errorf("Paxos this[%v](%v) Not Equal that[%v](%v)", i, this.Paxos[i], i, that1.Paxos[i])
+		}
+	}
+	if this.Uptime != that1.Uptime {
+		return fmt.Errorf("Uptime this(%v) Not Equal that(%v)", this.Uptime, that1.Uptime)
 	}
 	return nil
 }
+func (this *ServerStatus) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-/* 'gorums' plugin for protoc-gen-go - generated from: qspec_tmpl */
-
-// QuorumSpec is the interface that wraps every quorum function.
-type QuorumSpec interface {
-	// AcceptQF is the quorum function for the Accept
-	// quorum call method.
-	AcceptQF(replies []*Learn) (*Learn, bool)
-
-	// GetPromiseQF is the quorum function for the GetPromise
-	// quorum call method.
-	GetPromiseQF(replies []*Promise) (*Promise, bool)
-
-	// LAPropQF is the quorum function for the LAProp
-	// quorum call method.
-	LAPropQF(replies []*LAReply) (*LAReply, bool)
-
-	// ReadQF is the quorum function for the Read
-	// quorum call method.
-	ReadQF(replies []*ReadReply) (*ReadReply, bool)
-
-	// SetCurQF is the quorum function for the SetCur
-	// quorum call method.
-	SetCurQF(replies []*NewCurReply) (*NewCurReply, bool)
-
-	// SetStateQF is the quorum function for the SetState
-	// quorum call method.
-	SetStateQF(replies []*NewStateReply) (*NewStateReply, bool)
-
-	// WriteQF is the quorum function for the Write
-	// quorum call method.
-	WriteQF(replies []*ConfReply) (*ConfReply, bool)
-
-	// WriteNextQF is the quorum function for the WriteNext
-	// quorum call method.
-	WriteNextQF(replies []*WriteNReply) (*WriteNReply, bool)
+	that1, ok := that.(*ServerStatus)
+	if !ok {
+		that2, ok := that.(ServerStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return false
+	}
+	if !bytes.Equal(this.CurC, that1.CurC) {
+		return false
+	}
+	if !this.LAState.Equal(that1.LAState) {
+		return false
+	}
+	if len(this.Next) != len(that1.Next) {
+		return false
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return false
+		}
+	}
+	if len(this.Registers) != len(that1.Registers) {
+		return false
+	}
+	for i := range this.Registers {
+		if !this.Registers[i].Equal(that1.Registers[i]) {
+			return false
+		}
+	}
+	if len(this.Paxos) != len(that1.Paxos) {
+		return false
+	}
+	for i := range this.Paxos {
+		if !this.Paxos[i].Equal(that1.Paxos[i]) {
+			return false
+		}
+	}
+	if this.Uptime != that1.Uptime {
+		return false
+	}
+	return true
 }
+func (this *WatchRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-/* Static resources */
-
-/* config.go */
+	that1, ok := that.(*WatchRequest)
+	if !ok {
+		that2, ok := that.(WatchRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *WatchRequest")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *WatchRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *WatchRequest but is not nil && this == nil")
+	}
+	if this.Key != that1.Key {
+		return fmt.Errorf("Key this(%v) Not Equal that(%v)", this.Key, that1.Key)
+	}
+	return nil
+}
+func (this *WatchRequest) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-// A Configuration represents a static set of nodes on which quorum remote
-// procedure calls may be invoked.
-type Configuration struct {
-	id    uint32
-	nodes []*Node
-	n     int
-	mgr   *Manager
-	qspec QuorumSpec
+	that1, ok := that.(*WatchRequest)
+	if !ok {
+		that2, ok := that.(WatchRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Key != that1.Key {
+		return false
+	}
+	return true
 }
+func (this *WatchUpdate) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-// ID reports the identifier for the configuration.
-func (c *Configuration) ID() uint32 {
-	return c.id
+	that1, ok := that.(*WatchUpdate)
+	if !ok {
+		that2, ok := that.(WatchUpdate)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *WatchUpdate")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *WatchUpdate but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *WatchUpdate but is not nil && this == nil")
+	}
+	if !this.State.Equal(that1.State) {
+		return fmt.Errorf("State this(%v) Not Equal that(%v)", this.State, that1.State)
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return fmt.Errorf("Cur this(%v) Not Equal that(%v)", this.Cur, that1.Cur)
+	}
+	if len(this.Next) != len(that1.Next) {
+		return fmt.Errorf("Next this(%v) Not Equal that(%v)", len(this.Next), len(that1.Next))
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return fmt.Errorf("Next this[%v](%v) Not Equal that[%v](%v)", i, this.Next[i], i, that1.Next[i])
+		}
+	}
+	return nil
 }
+func (this *WatchUpdate) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
 
-// NodeIDs returns a slice containing the local ids of all the nodes in the
-// configuration. IDs are returned in the same order as they were provided in
-// the creation of the Configuration.
-func (c *Configuration) NodeIDs() []uint32 {
-	ids := make([]uint32, len(c.nodes))
-	for i, node := range c.nodes {
-		ids[i] = node.ID()
+	that1, ok := that.(*WatchUpdate)
+	if !ok {
+		that2, ok := that.(WatchUpdate)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return ids
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if !this.State.Equal(that1.State) {
+		return false
+	}
+	if !this.Cur.Equal(that1.Cur) {
+		return false
+	}
+	if len(this.Next) != len(that1.Next) {
+		return false
+	}
+	for i := range this.Next {
+		if !this.Next[i].Equal(that1.Next[i]) {
+			return false
+		}
+	}
+	return true
 }
 
-// Nodes returns a slice of each available node. IDs are returned in the same
-// order as they were provided in the creation of the Configuration.
-func (c *Configuration) Nodes() []*Node {
-	return c.nodes
+//  Reference Gorums specific imports to suppress errors if they are not otherwise used.
+var _ = codes.OK
+
+/* 'gorums' plugin for protoc-gen-go - generated from: config_qc_tmpl */
+
+// AcceptReply encapsulates the reply from a Accept quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type AcceptReply struct {
+	NodeIDs []uint32
+	*Learn
 }
 
-// Size returns the number of nodes in the configuration.
-func (c *Configuration) Size() int {
-	return c.n
+func (r AcceptReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.Learn)
 }
 
-func (c *Configuration) String() string {
-	return fmt.Sprintf("configuration %d", c.id)
+// Accept invokes a Accept quorum call on configuration c
+// and returns the result as a AcceptReply.
+func (c *Configuration) Accept(ctx context.Context, args *Propose) (*AcceptReply, error) {
+	return c.mgr.accept(ctx, c, args)
 }
 
-func (c *Configuration) tstring() string {
-	return fmt.Sprintf("config-%d", c.id)
+// DSetStateReply encapsulates the reply from a DSetState quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type DSetStateReply struct {
+	NodeIDs []uint32
+	*DNewStateReply
 }
 
-// Equal returns a boolean reporting whether a and b represents the same
-// configuration.
-func Equal(a, b *Configuration) bool { return a.id == b.id }
+func (r DSetStateReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.DNewStateReply)
+}
 
-// NewTestConfiguration returns a new configuration with quorum size q and
-// node size n. No other fields are set. Configurations returned from this
-// constructor should only be used when testing quorum functions.
-func NewTestConfiguration(q, n int) *Configuration {
-	return &Configuration{
-		nodes: make([]*Node, n),
-	}
+// DSetState invokes a DSetState quorum call on configuration c
+// and returns the result as a DSetStateReply.
+func (c *Configuration) DSetState(ctx context.Context, args *DNewState) (*DSetStateReply, error) {
+	return c.mgr.dSetState(ctx, c, args)
 }
 
-/* errors.go */
+// DWriteNextReply encapsulates the reply from a DWriteNext quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type DWriteNextReply struct {
+	NodeIDs []uint32
+	*DReadReply
+}
 
-// A NodeNotFoundError reports that a specified node could not be found.
-type NodeNotFoundError uint32
+func (r DWriteNextReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.DReadReply)
+}
 
-func (e NodeNotFoundError) Error() string {
-	return fmt.Sprintf("node not found: %d", e)
+// DWriteNext invokes a DWriteNext quorum call on configuration c
+// and returns the result as a DWriteNextReply.
+func (c *Configuration) DWriteNext(ctx context.Context, args *DWriteN) (*DWriteNextReply, error) {
+	return c.mgr.dWriteNext(ctx, c, args)
 }
 
-// A ConfigNotFoundError reports that a specified configuration could not be
-// found.
-type ConfigNotFoundError uint32
+// ElectReply encapsulates the reply from a Elect quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type ElectReply struct {
+	NodeIDs []uint32
+	*Lease
+}
 
-func (e ConfigNotFoundError) Error() string {
-	return fmt.Sprintf("configuration not found: %d", e)
+func (r ElectReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.Lease)
 }
 
-// An IllegalConfigError reports that a specified configuration could not be
-// created.
-type IllegalConfigError string
+// Elect invokes a Elect quorum call on configuration c
+// and returns the result as a ElectReply.
+func (c *Configuration) Elect(ctx context.Context, args *Ballot) (*ElectReply, error) {
+	return c.mgr.elect(ctx, c, args)
+}
 
-func (e IllegalConfigError) Error() string {
-	return "illegal configuration: " + string(e)
+// GetPromiseReply encapsulates the reply from a GetPromise quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type GetPromiseReply struct {
+	NodeIDs []uint32
+	*Promise
 }
 
-// ManagerCreationError returns an error reporting that a Manager could not be
-// created due to err.
-func ManagerCreationError(err error) error {
-	return fmt.Errorf("could not create manager: %s", err.Error())
+func (r GetPromiseReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.Promise)
 }
 
-// A QuorumCallError is used to report that a quorum call failed.
-type QuorumCallError struct {
-	Reason               string
-	ErrCount, ReplyCount int
+// GetPromise invokes a GetPromise quorum call on configuration c
+// and returns the result as a GetPromiseReply.
+func (c *Configuration) GetPromise(ctx context.Context, args *Prepare) (*GetPromiseReply, error) {
+	return c.mgr.getPromise(ctx, c, args)
 }
 
-func (e QuorumCallError) Error() string {
-	return fmt.Sprintf(
-		"quorum call error: %s (errors: %d, replies: %d)",
-		e.Reason, e.ErrCount, e.ReplyCount,
-	)
+// LAPropReply encapsulates the reply from a LAProp quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type LAPropReply struct {
+	NodeIDs []uint32
+	*LAReply
 }
 
-/* level.go */
+func (r LAPropReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.LAReply)
+}
 
-// LevelNotSet is the zero value level used to indicate that no level (and
-// thereby no reply) has been set for a correctable quorum call.
-const LevelNotSet = -1
+// LAProp invokes a LAProp quorum call on configuration c
+// and returns the result as a LAPropReply.
+func (c *Configuration) LAProp(ctx context.Context, args *LAProposal) (*LAPropReply, error) {
+	return c.mgr.lAProp(ctx, c, args)
+}
 
-/* mgr.go */
+// LAPropValueReply encapsulates the reply from a LAPropValue quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type LAPropValueReply struct {
+	NodeIDs []uint32
+	*LAValueReply
+}
 
-// Manager manages a pool of node configurations on which quorum remote
-// procedure calls can be made.
-type Manager struct {
-	sync.Mutex
-	nodes    []*Node
-	lookup   map[uint32]*Node
-	configs  map[uint32]*Configuration
-	eventLog trace.EventLog
+func (r LAPropValueReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.LAValueReply)
+}
 
-	closeOnce sync.Once
-	logger    *log.Logger
-	opts      managerOptions
+// LAPropValue invokes a LAPropValue quorum call on configuration c
+// and returns the result as a LAPropValueReply.
+func (c *Configuration) LAPropValue(ctx context.Context, args *LAValueProposal) (*LAPropValueReply, error) {
+	return c.mgr.lAPropValue(ctx, c, args)
 }
 
-// NewManager attempts to connect to the given set of node addresses and if
-// successful returns a new Manager containing connections to those nodes.
-func NewManager(nodeAddrs []string, opts ...ManagerOption) (*Manager, error) {
-	if len(nodeAddrs) == 0 {
-		return nil, fmt.Errorf("could not create manager: no nodes provided")
-	}
+// ReadReply_ encapsulates the reply from a Read quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type ReadReply_ struct {
+	NodeIDs []uint32
+	*ReadReply
+}
 
-	m := &Manager{
-		lookup:  make(map[uint32]*Node),
-		configs: make(map[uint32]*Configuration),
-	}
+func (r ReadReply_) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.ReadReply)
+}
 
-	for _, opt := range opts {
-		opt(&m.opts)
-	}
+// Read invokes a Read quorum call on configuration c
+// and returns the result as a ReadReply_.
+func (c *Configuration) Read(ctx context.Context, args *Read) (*ReadReply_, error) {
+	return c.mgr.read(ctx, c, args)
+}
 
-	for _, naddr := range nodeAddrs {
-		node, err2 := m.createNode(naddr)
-		if err2 != nil {
-			return nil, ManagerCreationError(err2)
-		}
-		m.lookup[node.id] = node
-		m.nodes = append(m.nodes, node)
-	}
-
-	if m.opts.trace {
-		title := strings.Join(nodeAddrs, ",")
-		m.eventLog = trace.NewEventLog("gorums.Manager", title)
-	}
-
-	err := m.connectAll()
-	if err != nil {
-		return nil, ManagerCreationError(err)
-	}
-
-	if m.opts.logger != nil {
-		m.logger = m.opts.logger
-	}
-
-	if m.eventLog != nil {
-		m.eventLog.Printf("ready")
-	}
-
-	return m, nil
+// SetCurReply encapsulates the reply from a SetCur quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type SetCurReply struct {
+	NodeIDs []uint32
+	*NewCurReply
 }
 
-func (m *Manager) createNode(addr string) (*Node, error) {
-	m.Lock()
-	defer m.Unlock()
-
-	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
-	if err != nil {
-		return nil, fmt.Errorf("create node %s error: %v", addr, err)
-	}
-
-	h := fnv.New32a()
-	_, _ = h.Write([]byte(tcpAddr.String()))
-	id := h.Sum32()
-
-	if _, found := m.lookup[id]; found {
-		return nil, fmt.Errorf("create node %s error: node already exists", addr)
-	}
-
-	node := &Node{
-		id:      id,
-		addr:    tcpAddr.String(),
-		latency: -1 * time.Second,
-	}
-
-	return node, nil
+func (r SetCurReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.NewCurReply)
 }
 
-func (m *Manager) connectAll() error {
-	if m.opts.noConnect {
-		return nil
-	}
+// SetCur invokes a SetCur quorum call on configuration c
+// and returns the result as a SetCurReply.
+func (c *Configuration) SetCur(ctx context.Context, args *NewCur) (*SetCurReply, error) {
+	return c.mgr.setCur(ctx, c, args)
+}
 
-	if m.eventLog != nil {
-		m.eventLog.Printf("connecting")
-	}
+// SetStateReply encapsulates the reply from a SetState quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type SetStateReply struct {
+	NodeIDs []uint32
+	*NewStateReply
+}
 
-	for _, node := range m.nodes {
-		err := node.connect(m.opts.grpcDialOpts...)
-		if err != nil {
-			if m.eventLog != nil {
-				m.eventLog.Errorf("connect failed, error connecting to node %s, error: %v", node.addr, err)
-			}
-			return fmt.Errorf("connect node %s error: %v", node.addr, err)
-		}
-	}
-	return nil
+func (r SetStateReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.NewStateReply)
 }
 
-func (m *Manager) closeNodeConns() {
-	for _, node := range m.nodes {
-		err := node.close()
-		if err == nil {
-			continue
-		}
-		if m.logger != nil {
-			m.logger.Printf("node %d: error closing: %v", node.id, err)
-		}
-	}
+// SetState invokes a SetState quorum call on configuration c
+// and returns the result as a SetStateReply.
+func (c *Configuration) SetState(ctx context.Context, args *NewState) (*SetStateReply, error) {
+	return c.mgr.setState(ctx, c, args)
 }
 
-// Close closes all node connections and any client streams.
-func (m *Manager) Close() {
-	m.closeOnce.Do(func() {
-		if m.eventLog != nil {
-			m.eventLog.Printf("closing")
-		}
-		m.closeNodeConns()
-	})
+// SpSnOneShotReply encapsulates the reply from a SpSnOneShot quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type SpSnOneShotReply struct {
+	NodeIDs []uint32
+	*SpSnReply
 }
 
-// NodeIDs returns the identifier of each available node. IDs are returned in
-// the same order as they were provided in the creation of the Manager.
-func (m *Manager) NodeIDs() []uint32 {
-	m.Lock()
-	defer m.Unlock()
-	ids := make([]uint32, 0, len(m.nodes))
-	for _, node := range m.nodes {
-		ids = append(ids, node.ID())
-	}
-	return ids
+func (r SpSnOneShotReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.SpSnReply)
 }
 
-// Node returns the node with the given identifier if present.
-func (m *Manager) Node(id uint32) (node *Node, found bool) {
-	m.Lock()
-	defer m.Unlock()
-	node, found = m.lookup[id]
-	return node, found
+// SpSnOneShot invokes a SpSnOneShot quorum call on configuration c
+// and returns the result as a SpSnOneShotReply.
+func (c *Configuration) SpSnOneShot(ctx context.Context, args *SpSnProp) (*SpSnOneShotReply, error) {
+	return c.mgr.spSnOneShot(ctx, c, args)
 }
 
-// Nodes returns a slice of each available node. IDs are returned in the same
-// order as they were provided in the creation of the Manager.
-func (m *Manager) Nodes() []*Node {
-	m.Lock()
-	defer m.Unlock()
-	return m.nodes
+// WriteReply encapsulates the reply from a Write quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type WriteReply struct {
+	NodeIDs []uint32
+	*ConfReply
 }
 
-// ConfigurationIDs returns the identifier of each available
-// configuration.
-func (m *Manager) ConfigurationIDs() []uint32 {
-	m.Lock()
-	defer m.Unlock()
-	ids := make([]uint32, 0, len(m.configs))
-	for id := range m.configs {
-		ids = append(ids, id)
-	}
-	return ids
+func (r WriteReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.ConfReply)
 }
 
-// Configuration returns the configuration with the given global
-// identifier if present.
-func (m *Manager) Configuration(id uint32) (config *Configuration, found bool) {
-	m.Lock()
-	defer m.Unlock()
-	config, found = m.configs[id]
-	return config, found
+// Write invokes a Write quorum call on configuration c
+// and returns the result as a WriteReply.
+func (c *Configuration) Write(ctx context.Context, args *WriteS) (*WriteReply, error) {
+	return c.mgr.write(ctx, c, args)
 }
 
-// Configurations returns a slice of each available configuration.
-func (m *Manager) Configurations() []*Configuration {
-	m.Lock()
-	defer m.Unlock()
-	configs := make([]*Configuration, 0, len(m.configs))
-	for _, conf := range m.configs {
-		configs = append(configs, conf)
-	}
-	return configs
+// WriteNextReply encapsulates the reply from a WriteNext quorum call.
+// It contains the id of each node of the quorum that replied and a single reply.
+type WriteNextReply struct {
+	NodeIDs []uint32
+	*WriteNReply
 }
 
-// Size returns the number of nodes and configurations in the Manager.
-func (m *Manager) Size() (nodes, configs int) {
-	m.Lock()
-	defer m.Unlock()
-	return len(m.nodes), len(m.configs)
+func (r WriteNextReply) String() string {
+	return fmt.Sprintf("node ids: %v | answer: %v", r.NodeIDs, r.WriteNReply)
 }
 
-// AddNode attempts to dial to the provide node address. The node is
-// added to the Manager's pool of nodes if a connection was established.
-func (m *Manager) AddNode(addr string) error {
-	panic("not implemented")
+// WriteNext invokes a WriteNext quorum call on configuration c
+// and returns the result as a WriteNextReply.
+func (c *Configuration) WriteNext(ctx context.Context, args *WriteN) (*WriteNextReply, error) {
+	return c.mgr.writeNext(ctx, c, args)
 }
 
-// NewConfiguration returns a new configuration given quorum specification and
-// a timeout.
-func (m *Manager) NewConfiguration(ids []uint32, qspec QuorumSpec) (*Configuration, error) {
-	m.Lock()
-	defer m.Unlock()
+/* 'gorums' plugin for protoc-gen-go - generated from: mgr_qc_tmpl */
 
-	if len(ids) == 0 {
-		return nil, IllegalConfigError("need at least one node")
-	}
+type acceptReply struct {
+	nid   uint32
+	reply *Learn
+	err   error
+}
 
-	var cnodes []*Node
-	for _, nid := range ids {
-		node, found := m.lookup[nid]
-		if !found {
-			return nil, NodeNotFoundError(nid)
+func (m *Manager) accept(ctx context.Context, c *Configuration, args *Propose) (r *AcceptReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Accept")
+		defer ti.tr.Finish()
+
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
 		}
-		cnodes = append(cnodes, node)
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.Learn,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
 
-	// Node ids are sorted ensure a globally consistent configuration id.
-	sort.Sort(idSlice(ids))
+	replyChan := make(chan acceptReply, c.n)
 
-	h := fnv.New32a()
-	for _, id := range ids {
-		binary.Write(h, binary.LittleEndian, id)
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	cid := h.Sum32()
 
-	conf, found := m.configs[cid]
-	if found {
-		return conf, nil
+	for _, n := range c.nodes {
+		go callGRPCAccept(ctx, n, args, replyChan)
 	}
 
-	c := &Configuration{
-		id:    cid,
-		nodes: cnodes,
-		n:     len(cnodes),
-		mgr:   m,
-		qspec: qspec,
-	}
-	m.configs[cid] = c
+	var (
+		replyValues = make([]*Learn, 0, c.n)
+		reply       = &AcceptReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
 
-	return c, nil
-}
-
-type idSlice []uint32
-
-func (p idSlice) Len() int           { return len(p) }
-func (p idSlice) Less(i, j int) bool { return p[i] < p[j] }
-func (p idSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-
-/* node_func.go */
-
-// ID returns the ID of m.
-func (n *Node) ID() uint32 {
-	return n.id
-}
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.Learn, quorum = c.qspec.AcceptQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
 
-// Address returns network address of m.
-func (n *Node) Address() string {
-	return n.addr
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
+	}
 }
 
-func (n *Node) String() string {
-	n.Lock()
-	defer n.Unlock()
-	return fmt.Sprintf(
-		"node %d | addr: %s | latency: %v",
-		n.id, n.addr, n.latency,
+func callGRPCAccept(ctx context.Context, node *Node, args *Propose, replyChan chan<- acceptReply) {
+	reply := new(Learn)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/Accept",
+		args,
+		reply,
+		node.conn,
 	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
+	}
+	replyChan <- acceptReply{node.id, reply, err}
 }
 
-func (n *Node) setLastErr(err error) {
-	n.Lock()
-	defer n.Unlock()
-	n.lastErr = err
-}
-
-// LastErr returns the last error encountered (if any) when invoking a remote
-// procedure call on this node.
-func (n *Node) LastErr() error {
-	n.Lock()
-	defer n.Unlock()
-	return n.lastErr
+type dSetStateReply struct {
+	nid   uint32
+	reply *DNewStateReply
+	err   error
 }
 
-func (n *Node) setLatency(lat time.Duration) {
-	n.Lock()
-	defer n.Unlock()
-	n.latency = lat
-}
+func (m *Manager) dSetState(ctx context.Context, c *Configuration, args *DNewState) (r *DSetStateReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "DSetState")
+		defer ti.tr.Finish()
 
-// Latency returns the latency of the last successful remote procedure call
-// made to this node.
-func (n *Node) Latency() time.Duration {
-	n.Lock()
-	defer n.Unlock()
-	return n.latency
-}
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
 
-type lessFunc func(n1, n2 *Node) bool
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.DNewStateReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
+	}
 
-// MultiSorter implements the Sort interface, sorting the nodes within.
-type MultiSorter struct {
-	nodes []*Node
-	less  []lessFunc
-}
+	replyChan := make(chan dSetStateReply, c.n)
 
-// Sort sorts the argument slice according to the less functions passed to
-// OrderedBy.
-func (ms *MultiSorter) Sort(nodes []*Node) {
-	ms.nodes = nodes
-	sort.Sort(ms)
-}
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
+	}
 
-// OrderedBy returns a Sorter that sorts using the less functions, in order.
-// Call its Sort method to sort the data.
-func OrderedBy(less ...lessFunc) *MultiSorter {
-	return &MultiSorter{
-		less: less,
+	for _, n := range c.nodes {
+		go callGRPCDSetState(ctx, n, args, replyChan)
 	}
-}
 
-// Len is part of sort.Interface.
-func (ms *MultiSorter) Len() int {
-	return len(ms.nodes)
-}
+	var (
+		replyValues = make([]*DNewStateReply, 0, c.n)
+		reply       = &DSetStateReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
 
-// Swap is part of sort.Interface.
-func (ms *MultiSorter) Swap(i, j int) {
-	ms.nodes[i], ms.nodes[j] = ms.nodes[j], ms.nodes[i]
-}
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.DNewStateReply, quorum = c.qspec.DSetStateQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
 
-// Less is part of sort.Interface. It is implemented by looping along the
-// less functions until it finds a comparison that is either Less or
-// !Less. Note that it can call the less functions twice per call. We
-// could change the functions to return -1, 0, 1 and reduce the
-// number of calls for greater efficiency: an exercise for the reader.
-func (ms *MultiSorter) Less(i, j int) bool {
-	p, q := ms.nodes[i], ms.nodes[j]
-	// Try all but the last comparison.
-	var k int
-	for k = 0; k < len(ms.less)-1; k++ {
-		less := ms.less[k]
-		switch {
-		case less(p, q):
-			// p < q, so we have a decision.
-			return true
-		case less(q, p):
-			// p > q, so we have a decision.
-			return false
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
 		}
-		// p == q; try the next comparison.
 	}
-	// All comparisons to here said "equal", so just return whatever
-	// the final comparison reports.
-	return ms.less[k](p, q)
-}
-
-// ID sorts nodes by their identifier in increasing order.
-var ID = func(n1, n2 *Node) bool {
-	return n1.id < n2.id
 }
 
-// Latency sorts nodes by latency in increasing order. Latencies less then
-// zero (sentinel value) are considered greater than any positive latency.
-var Latency = func(n1, n2 *Node) bool {
-	if n1.latency < 0 {
-		return false
+func callGRPCDSetState(ctx context.Context, node *Node, args *DNewState, replyChan chan<- dSetStateReply) {
+	reply := new(DNewStateReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/DSetState",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return n1.latency < n2.latency
-
+	replyChan <- dSetStateReply{node.id, reply, err}
 }
 
-// Error sorts nodes by their LastErr() status in increasing order. A
-// node with LastErr() != nil is larger than a node with LastErr() == nil.
-var Error = func(n1, n2 *Node) bool {
-	if n1.lastErr != nil && n2.lastErr == nil {
-		return false
-	}
-	return true
+type dWriteNextReply struct {
+	nid   uint32
+	reply *DReadReply
+	err   error
 }
 
-/* opts.go */
-
-type managerOptions struct {
-	grpcDialOpts []grpc.DialOption
-	logger       *log.Logger
-	noConnect    bool
-	trace        bool
-}
+func (m *Manager) dWriteNext(ctx context.Context, c *Configuration, args *DWriteN) (r *DWriteNextReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "DWriteNext")
+		defer ti.tr.Finish()
 
-// ManagerOption provides a way to set different options on a new Manager.
-type ManagerOption func(*managerOptions)
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
 
-// WithGrpcDialOptions returns a ManagerOption which sets any gRPC dial options
-// the Manager should use when initially connecting to each node in its
-// pool.
-func WithGrpcDialOptions(opts ...grpc.DialOption) ManagerOption {
-	return func(o *managerOptions) {
-		o.grpcDialOpts = opts
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.DReadReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-}
 
-// WithLogger returns a ManagerOption which sets an optional error logger for
-// the Manager.
-func WithLogger(logger *log.Logger) ManagerOption {
-	return func(o *managerOptions) {
-		o.logger = logger
+	replyChan := make(chan dWriteNextReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-}
 
-// WithNoConnect returns a ManagerOption which instructs the Manager not to
-// connect to any of its nodes. Mainly used for testing purposes.
-func WithNoConnect() ManagerOption {
-	return func(o *managerOptions) {
-		o.noConnect = true
-	}
-}
-
-// WithTracing controls whether to trace qourum calls for this Manager instance
-// using the golang.org/x/net/trace package. Tracing is currently only supported
-// for regular quorum calls.
-func WithTracing() ManagerOption {
-	return func(o *managerOptions) {
-		o.trace = true
+	for _, n := range c.nodes {
+		go callGRPCDWriteNext(ctx, n, args, replyChan)
 	}
-}
 
-/* trace.go */
-
-type traceInfo struct {
-	tr        trace.Trace
-	firstLine firstLine
-}
+	var (
+		replyValues = make([]*DReadReply, 0, c.n)
+		reply       = &DWriteNextReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
 
-type firstLine struct {
-	deadline time.Duration
-	cid      uint32
-}
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.DReadReply, quorum = c.qspec.DWriteNextQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
 
-func (f *firstLine) String() string {
-	var line bytes.Buffer
-	io.WriteString(&line, "QC: to config")
-	fmt.Fprintf(&line, "%v deadline:", f.cid)
-	if f.deadline != 0 {
-		fmt.Fprint(&line, f.deadline)
-	} else {
-		io.WriteString(&line, "none")
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
 	}
-	return line.String()
-}
-
-type payload struct {
-	sent bool
-	id   uint32
-	msg  interface{}
 }
 
-func (p payload) String() string {
-	if p.sent {
-		return fmt.Sprintf("sent: %v", p.msg)
+func callGRPCDWriteNext(ctx context.Context, node *Node, args *DWriteN, replyChan chan<- dWriteNextReply) {
+	reply := new(DReadReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/DWriteNext",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return fmt.Sprintf("recv from %d: %v", p.id, p.msg)
+	replyChan <- dWriteNextReply{node.id, reply, err}
 }
 
-type qcresult struct {
-	ids   []uint32
-	reply interface{}
+type electReply struct {
+	nid   uint32
+	reply *Lease
 	err   error
 }
 
-func (q qcresult) String() string {
-	var out bytes.Buffer
-	io.WriteString(&out, "recv QC reply: ")
-	fmt.Fprintf(&out, "ids: %v, ", q.ids)
-	fmt.Fprintf(&out, "reply: %v ", q.reply)
-	if q.err != nil {
-		fmt.Fprintf(&out, ", error: %v", q.err)
+func (m *Manager) elect(ctx context.Context, c *Configuration, args *Ballot) (r *ElectReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Elect")
+		defer ti.tr.Finish()
+
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.Lease,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return out.String()
-}
 
-/* util.go */
+	replyChan := make(chan electReply, c.n)
 
-func appendIfNotPresent(set []uint32, x uint32) []uint32 {
-	for _, y := range set {
-		if y == x {
-			return set
-		}
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return append(set, x)
-}
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+	for _, n := range c.nodes {
+		go callGRPCElect(ctx, n, args, replyChan)
+	}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+	var (
+		replyValues = make([]*Lease, 0, c.n)
+		reply       = &ElectReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
 
-// Client API for SMandConsRegister service
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.Lease, quorum = c.qspec.ElectQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
 
-type SMandConsRegisterClient interface {
-	// Read a register value
-	Read(ctx context.Context, in *Conf, opts ...grpc.CallOption) (*ReadReply, error)
-	// Write a value to the register
-	Write(ctx context.Context, in *WriteS, opts ...grpc.CallOption) (*ConfReply, error)
-	// Inform the servers about a new proposed configuration/blueprint
-	WriteNext(ctx context.Context, in *WriteN, opts ...grpc.CallOption) (*WriteNReply, error)
-	// Inform the servers that a new configuration has been installed
-	SetCur(ctx context.Context, in *NewCur, opts ...grpc.CallOption) (*NewCurReply, error)
-	// Propose a value to lattice agreement
-	// Only used in smartmerge algorithm
-	LAProp(ctx context.Context, in *LAProposal, opts ...grpc.CallOption) (*LAReply, error)
-	// Set register and lattice agreement state in new configuration
-	SetState(ctx context.Context, in *NewState, opts ...grpc.CallOption) (*NewStateReply, error)
-	// Consensus: Paxos first phase
-	// Only used in the consensus based algorithm (RAMBO)
-	GetPromise(ctx context.Context, in *Prepare, opts ...grpc.CallOption) (*Promise, error)
-	// Consensus: Paxos second phase
-	// Only used in the consensus based algorithm (RAMBO)
-	Accept(ctx context.Context, in *Propose, opts ...grpc.CallOption) (*Learn, error)
-	// Fwd is used to forward a reconfiguration-proposal to a leader.
-	// Only used in the consensus based algorithm (RAMBO)
-	Fwd(ctx context.Context, in *Proposal, opts ...grpc.CallOption) (*Ack, error)
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
+	}
 }
 
-type sMandConsRegisterClient struct {
-	cc *grpc.ClientConn
+func callGRPCElect(ctx context.Context, node *Node, args *Ballot, replyChan chan<- electReply) {
+	reply := new(Lease)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/Elect",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
+	}
+	replyChan <- electReply{node.id, reply, err}
 }
 
-func NewSMandConsRegisterClient(cc *grpc.ClientConn) SMandConsRegisterClient {
-	return &sMandConsRegisterClient{cc}
+type getPromiseReply struct {
+	nid   uint32
+	reply *Promise
+	err   error
 }
 
-func (c *sMandConsRegisterClient) Read(ctx context.Context, in *Conf, opts ...grpc.CallOption) (*ReadReply, error) {
-	out := new(ReadReply)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Read", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
+func (m *Manager) getPromise(ctx context.Context, c *Configuration, args *Prepare) (r *GetPromiseReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "GetPromise")
+		defer ti.tr.Finish()
 
-func (c *sMandConsRegisterClient) Write(ctx context.Context, in *WriteS, opts ...grpc.CallOption) (*ConfReply, error) {
-	out := new(ConfReply)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Write", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.Promise,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return out, nil
-}
 
-func (c *sMandConsRegisterClient) WriteNext(ctx context.Context, in *WriteN, opts ...grpc.CallOption) (*WriteNReply, error) {
-	out := new(WriteNReply)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/WriteNext", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	replyChan := make(chan getPromiseReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return out, nil
-}
 
-func (c *sMandConsRegisterClient) SetCur(ctx context.Context, in *NewCur, opts ...grpc.CallOption) (*NewCurReply, error) {
-	out := new(NewCurReply)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/SetCur", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	for _, n := range c.nodes {
+		go callGRPCGetPromise(ctx, n, args, replyChan)
 	}
-	return out, nil
-}
 
-func (c *sMandConsRegisterClient) LAProp(ctx context.Context, in *LAProposal, opts ...grpc.CallOption) (*LAReply, error) {
-	out := new(LAReply)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/LAProp", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
+	var (
+		replyValues = make([]*Promise, 0, c.n)
+		reply       = &GetPromiseReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
 
-func (c *sMandConsRegisterClient) SetState(ctx context.Context, in *NewState, opts ...grpc.CallOption) (*NewStateReply, error) {
-	out := new(NewStateReply)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/SetState", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.Promise, quorum = c.qspec.GetPromiseQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
 
-func (c *sMandConsRegisterClient) GetPromise(ctx context.Context, in *Prepare, opts ...grpc.CallOption) (*Promise, error) {
-	out := new(Promise)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/GetPromise", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
 	}
-	return out, nil
 }
 
-func (c *sMandConsRegisterClient) Accept(ctx context.Context, in *Propose, opts ...grpc.CallOption) (*Learn, error) {
-	out := new(Learn)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Accept", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func callGRPCGetPromise(ctx context.Context, node *Node, args *Prepare, replyChan chan<- getPromiseReply) {
+	reply := new(Promise)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/GetPromise",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return out, nil
+	replyChan <- getPromiseReply{node.id, reply, err}
 }
 
-func (c *sMandConsRegisterClient) Fwd(ctx context.Context, in *Proposal, opts ...grpc.CallOption) (*Ack, error) {
-	out := new(Ack)
-	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Fwd", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type lAPropReply struct {
+	nid   uint32
+	reply *LAReply
+	err   error
 }
 
-// Server API for SMandConsRegister service
-
-type SMandConsRegisterServer interface {
-	// Read a register value
-	Read(context.Context, *Conf) (*ReadReply, error)
-	// Write a value to the register
-	Write(context.Context, *WriteS) (*ConfReply, error)
-	// Inform the servers about a new proposed configuration/blueprint
-	WriteNext(context.Context, *WriteN) (*WriteNReply, error)
-	// Inform the servers that a new configuration has been installed
-	SetCur(context.Context, *NewCur) (*NewCurReply, error)
-	// Propose a value to lattice agreement
-	// Only used in smartmerge algorithm
-	LAProp(context.Context, *LAProposal) (*LAReply, error)
-	// Set register and lattice agreement state in new configuration
-	SetState(context.Context, *NewState) (*NewStateReply, error)
-	// Consensus: Paxos first phase
-	// Only used in the consensus based algorithm (RAMBO)
-	GetPromise(context.Context, *Prepare) (*Promise, error)
-	// Consensus: Paxos second phase
-	// Only used in the consensus based algorithm (RAMBO)
-	Accept(context.Context, *Propose) (*Learn, error)
-	// Fwd is used to forward a reconfiguration-proposal to a leader.
-	// Only used in the consensus based algorithm (RAMBO)
-	Fwd(context.Context, *Proposal) (*Ack, error)
-}
+func (m *Manager) lAProp(ctx context.Context, c *Configuration, args *LAProposal) (r *LAPropReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "LAProp")
+		defer ti.tr.Finish()
 
-func RegisterSMandConsRegisterServer(s *grpc.Server, srv SMandConsRegisterServer) {
-	s.RegisterService(&_SMandConsRegister_serviceDesc, srv)
-}
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
 
-func _SMandConsRegister_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Conf)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).Read(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/Read",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).Read(ctx, req.(*Conf))
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.LAReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return interceptor(ctx, in, info, handler)
-}
 
-func _SMandConsRegister_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WriteS)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).Write(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/Write",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).Write(ctx, req.(*WriteS))
-	}
-	return interceptor(ctx, in, info, handler)
-}
+	replyChan := make(chan lAPropReply, c.n)
 
-func _SMandConsRegister_WriteNext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WriteN)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).WriteNext(ctx, in)
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/WriteNext",
+
+	for _, n := range c.nodes {
+		go callGRPCLAProp(ctx, n, args, replyChan)
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).WriteNext(ctx, req.(*WriteN))
+
+	var (
+		replyValues = make([]*LAReply, 0, c.n)
+		reply       = &LAPropReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.LAReply, quorum = c.qspec.LAPropQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
+
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
 	}
-	return interceptor(ctx, in, info, handler)
 }
 
-func _SMandConsRegister_SetCur_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewCur)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).SetCur(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/SetCur",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).SetCur(ctx, req.(*NewCur))
+func callGRPCLAProp(ctx context.Context, node *Node, args *LAProposal, replyChan chan<- lAPropReply) {
+	reply := new(LAReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/LAProp",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return interceptor(ctx, in, info, handler)
+	replyChan <- lAPropReply{node.id, reply, err}
 }
 
-func _SMandConsRegister_LAProp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LAProposal)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).LAProp(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/LAProp",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).LAProp(ctx, req.(*LAProposal))
-	}
-	return interceptor(ctx, in, info, handler)
+type lAPropValueReply struct {
+	nid   uint32
+	reply *LAValueReply
+	err   error
 }
 
-func _SMandConsRegister_SetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewState)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).SetState(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/SetState",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).SetState(ctx, req.(*NewState))
-	}
-	return interceptor(ctx, in, info, handler)
-}
+func (m *Manager) lAPropValue(ctx context.Context, c *Configuration, args *LAValueProposal) (r *LAPropValueReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "LAPropValue")
+		defer ti.tr.Finish()
 
-func _SMandConsRegister_GetPromise_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Prepare)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).GetPromise(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/GetPromise",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).GetPromise(ctx, req.(*Prepare))
-	}
-	return interceptor(ctx, in, info, handler)
-}
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
 
-func _SMandConsRegister_Accept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Propose)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).Accept(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/Accept",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).Accept(ctx, req.(*Propose))
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.LAValueReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return interceptor(ctx, in, info, handler)
-}
 
-func _SMandConsRegister_Fwd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Proposal)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SMandConsRegisterServer).Fwd(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.SMandConsRegister/Fwd",
+	replyChan := make(chan lAPropValueReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMandConsRegisterServer).Fwd(ctx, req.(*Proposal))
+
+	for _, n := range c.nodes {
+		go callGRPCLAPropValue(ctx, n, args, replyChan)
 	}
-	return interceptor(ctx, in, info, handler)
-}
 
-var _SMandConsRegister_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.SMandConsRegister",
-	HandlerType: (*SMandConsRegisterServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Read",
-			Handler:    _SMandConsRegister_Read_Handler,
-		},
-		{
-			MethodName: "Write",
-			Handler:    _SMandConsRegister_Write_Handler,
-		},
-		{
-			MethodName: "WriteNext",
-			Handler:    _SMandConsRegister_WriteNext_Handler,
-		},
-		{
-			MethodName: "SetCur",
-			Handler:    _SMandConsRegister_SetCur_Handler,
-		},
-		{
-			MethodName: "LAProp",
-			Handler:    _SMandConsRegister_LAProp_Handler,
-		},
-		{
-			MethodName: "SetState",
-			Handler:    _SMandConsRegister_SetState_Handler,
-		},
-		{
-			MethodName: "GetPromise",
-			Handler:    _SMandConsRegister_GetPromise_Handler,
-		},
-		{
-			MethodName: "Accept",
-			Handler:    _SMandConsRegister_Accept_Handler,
-		},
-		{
-			MethodName: "Fwd",
-			Handler:    _SMandConsRegister_Fwd_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "dc-smartmerge.proto",
-}
+	var (
+		replyValues = make([]*LAValueReply, 0, c.n)
+		reply       = &LAPropValueReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
 
-func (m *State) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.LAValueReply, quorum = c.qspec.LAPropValueQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
+		}
+
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
 	}
-	return dAtA[:n], nil
 }
 
-func (m *State) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Value) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Value)))
-		i += copy(dAtA[i:], m.Value)
-	}
-	if m.Timestamp != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Timestamp))
-	}
-	if m.Writer != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Writer))
+func callGRPCLAPropValue(ctx context.Context, node *Node, args *LAValueProposal, replyChan chan<- lAPropValueReply) {
+	reply := new(LAValueReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/LAPropValue",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return i, nil
+	replyChan <- lAPropValueReply{node.id, reply, err}
 }
 
-func (m *Conf) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type readReply struct {
+	nid   uint32
+	reply *ReadReply
+	err   error
 }
 
-func (m *Conf) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.This != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.This))
+func (m *Manager) read(ctx context.Context, c *Configuration, args *Read) (r *ReadReply_, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Read")
+		defer ti.tr.Finish()
+
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
+		}
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.ReadReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	if m.Cur != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur))
+
+	replyChan := make(chan readReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return i, nil
-}
 
-func (m *ConfReply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	for _, n := range c.nodes {
+		go callGRPCRead(ctx, n, args, replyChan)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *ConfReply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n1, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
-	}
-	if m.Abort {
-		dAtA[i] = 0x10
-		i++
-		if m.Abort {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i++
-	}
-	if len(m.Next) > 0 {
-		for _, msg := range m.Next {
-			dAtA[i] = 0x1a
-			i++
-			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+	var (
+		replyValues = make([]*ReadReply, 0, c.n)
+		reply       = &ReadReply_{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
 			}
-			i += n
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.ReadReply, quorum = c.qspec.ReadQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
 		}
-	}
-	return i, nil
-}
 
-func (m *NewCur) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
 	}
-	return dAtA[:n], nil
 }
 
-func (m *NewCur) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n2, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n2
-	}
-	if m.CurC != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.CurC))
+func callGRPCRead(ctx context.Context, node *Node, args *Read, replyChan chan<- readReply) {
+	reply := new(ReadReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/Read",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return i, nil
+	replyChan <- readReply{node.id, reply, err}
 }
 
-func (m *NewCurReply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type setCurReply struct {
+	nid   uint32
+	reply *NewCurReply
+	err   error
 }
 
-func (m *NewCurReply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.New {
-		dAtA[i] = 0x8
-		i++
-		if m.New {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+func (m *Manager) setCur(ctx context.Context, c *Configuration, args *NewCur) (r *SetCurReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "SetCur")
+		defer ti.tr.Finish()
+
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
 		}
-		i++
-	}
-	return i, nil
-}
+		ti.tr.LazyLog(&ti.firstLine, false)
 
-func (m *Read) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.NewCurReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return dAtA[:n], nil
-}
 
-func (m *Read) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Conf != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
-		n3, err := m.Conf.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n3
+	replyChan := make(chan setCurReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return i, nil
-}
 
-func (m *ReadReply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	for _, n := range c.nodes {
+		go callGRPCSetCur(ctx, n, args, replyChan)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *ReadReply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.State != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
-		n4, err := m.State.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	var (
+		replyValues = make([]*NewCurReply, 0, c.n)
+		reply       = &SetCurReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.NewCurReply, quorum = c.qspec.SetCurQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
 		}
-		i += n4
-	}
-	if m.Cur != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n5, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
 		}
-		i += n5
 	}
-	return i, nil
 }
 
-func (m *WriteS) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func callGRPCSetCur(ctx context.Context, node *Node, args *NewCur, replyChan chan<- setCurReply) {
+	reply := new(NewCurReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/SetCur",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return dAtA[:n], nil
+	replyChan <- setCurReply{node.id, reply, err}
 }
 
-func (m *WriteS) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.State != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
-		n6, err := m.State.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n6
-	}
-	if m.Conf != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
-		n7, err := m.Conf.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n7
-	}
-	return i, nil
+type setStateReply struct {
+	nid   uint32
+	reply *NewStateReply
+	err   error
 }
 
-func (m *WriteN) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
+func (m *Manager) setState(ctx context.Context, c *Configuration, args *NewState) (r *SetStateReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "SetState")
+		defer ti.tr.Finish()
 
-func (m *WriteN) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.CurC))
-	}
-	if m.Next != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Next.Size()))
-		n8, err := m.Next.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
 		}
-		i += n8
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.NewStateReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return i, nil
-}
 
-func (m *WriteNReply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	replyChan := make(chan setStateReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *WriteNReply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n9, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n9
+	for _, n := range c.nodes {
+		go callGRPCSetState(ctx, n, args, replyChan)
 	}
-	if m.State != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
-		n10, err := m.State.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+
+	var (
+		replyValues = make([]*NewStateReply, 0, c.n)
+		reply       = &SetStateReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.NewStateReply, quorum = c.qspec.SetStateQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
 		}
-		i += n10
-	}
-	if m.LAState != nil {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
-		n11, err := m.LAState.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
 		}
-		i += n11
 	}
-	return i, nil
 }
 
-func (m *LAProposal) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func callGRPCSetState(ctx context.Context, node *Node, args *NewState, replyChan chan<- setStateReply) {
+	reply := new(NewStateReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/SetState",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return dAtA[:n], nil
+	replyChan <- setStateReply{node.id, reply, err}
 }
 
-func (m *LAProposal) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Conf != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
-		n12, err := m.Conf.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n12
-	}
-	if m.Prop != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Prop.Size()))
-		n13, err := m.Prop.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n13
-	}
-	return i, nil
+type spSnOneShotReply struct {
+	nid   uint32
+	reply *SpSnReply
+	err   error
 }
 
-func (m *LAReply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
+func (m *Manager) spSnOneShot(ctx context.Context, c *Configuration, args *SpSnProp) (r *SpSnOneShotReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "SpSnOneShot")
+		defer ti.tr.Finish()
 
-func (m *LAReply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n14, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
 		}
-		i += n14
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.SpSnReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	if m.LAState != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
-		n15, err := m.LAState.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n15
+
+	replyChan := make(chan spSnOneShotReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return i, nil
-}
 
-func (m *NewState) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	for _, n := range c.nodes {
+		go callGRPCSpSnOneShot(ctx, n, args, replyChan)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *NewState) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.CurC))
-	}
-	if m.State != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
-		n16, err := m.State.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	var (
+		replyValues = make([]*SpSnReply, 0, c.n)
+		reply       = &SpSnOneShotReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.SpSnReply, quorum = c.qspec.SpSnOneShotQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
 		}
-		i += n16
-	}
-	if m.LAState != nil {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
-		n17, err := m.LAState.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
 		}
-		i += n17
 	}
-	return i, nil
 }
 
-func (m *NewStateReply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func callGRPCSpSnOneShot(ctx context.Context, node *Node, args *SpSnProp, replyChan chan<- spSnOneShotReply) {
+	reply := new(SpSnReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/SpSnOneShot",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return dAtA[:n], nil
+	replyChan <- spSnOneShotReply{node.id, reply, err}
 }
 
-func (m *NewStateReply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n18, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+type writeReply struct {
+	nid   uint32
+	reply *ConfReply
+	err   error
+}
+
+func (m *Manager) write(ctx context.Context, c *Configuration, args *WriteS) (r *WriteReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "Write")
+		defer ti.tr.Finish()
+
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
 		}
-		i += n18
-	}
-	if len(m.Next) > 0 {
-		for _, msg := range m.Next {
-			dAtA[i] = 0x12
-			i++
-			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
+		ti.tr.LazyLog(&ti.firstLine, false)
+
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.ConfReply,
+				err:   err,
+			}, false)
 			if err != nil {
-				return 0, err
+				ti.tr.SetError()
 			}
-			i += n
-		}
+		}()
 	}
-	return i, nil
-}
 
-func (m *CV) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	replyChan := make(chan writeReply, c.n)
+
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *CV) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Rnd != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+	for _, n := range c.nodes {
+		go callGRPCWrite(ctx, n, args, replyChan)
 	}
-	if m.Val != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
-		n19, err := m.Val.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+
+	var (
+		replyValues = make([]*ConfReply, 0, c.n)
+		reply       = &WriteReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.ConfReply, quorum = c.qspec.WriteQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
 		}
-		i += n19
-	}
-	return i, nil
-}
 
-func (m *Prepare) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
+		}
 	}
-	return dAtA[:n], nil
 }
 
-func (m *Prepare) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.CurC))
-	}
-	if m.Rnd != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+func callGRPCWrite(ctx context.Context, node *Node, args *WriteS, replyChan chan<- writeReply) {
+	reply := new(ConfReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/Write",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return i, nil
+	replyChan <- writeReply{node.id, reply, err}
 }
 
-func (m *Promise) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type writeNextReply struct {
+	nid   uint32
+	reply *WriteNReply
+	err   error
 }
 
-func (m *Promise) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n20, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+func (m *Manager) writeNext(ctx context.Context, c *Configuration, args *WriteN) (r *WriteNextReply, err error) {
+	var ti traceInfo
+	if m.opts.trace {
+		ti.tr = trace.New("gorums."+c.tstring()+".Sent", "WriteNext")
+		defer ti.tr.Finish()
+
+		ti.firstLine.cid = c.id
+		if deadline, ok := ctx.Deadline(); ok {
+			ti.firstLine.deadline = deadline.Sub(time.Now())
 		}
-		i += n20
-	}
-	if m.Rnd != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
-	}
-	if m.Val != nil {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
-		n21, err := m.Val.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n21
-	}
-	if m.Dec != nil {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Dec.Size()))
-		n22, err := m.Dec.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n22
-	}
-	return i, nil
-}
+		ti.tr.LazyLog(&ti.firstLine, false)
 
-func (m *Propose) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+		defer func() {
+			ti.tr.LazyLog(&qcresult{
+				ids:   r.NodeIDs,
+				reply: r.WriteNReply,
+				err:   err,
+			}, false)
+			if err != nil {
+				ti.tr.SetError()
+			}
+		}()
 	}
-	return dAtA[:n], nil
-}
 
-func (m *Propose) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.CurC))
-	}
-	if m.Val != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
-		n23, err := m.Val.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n23
-	}
-	return i, nil
-}
+	replyChan := make(chan writeNextReply, c.n)
 
-func (m *Learn) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if m.opts.trace {
+		ti.tr.LazyLog(&payload{sent: true, msg: args}, false)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *Learn) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Cur != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
-		n24, err := m.Cur.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n24
+	for _, n := range c.nodes {
+		go callGRPCWriteNext(ctx, n, args, replyChan)
 	}
-	if m.Dec != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Dec.Size()))
-		n25, err := m.Dec.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+
+	var (
+		replyValues = make([]*WriteNReply, 0, c.n)
+		reply       = &WriteNextReply{NodeIDs: make([]uint32, 0, c.n)}
+		errCount    int
+		quorum      bool
+	)
+
+	for {
+		select {
+		case r := <-replyChan:
+			reply.NodeIDs = append(reply.NodeIDs, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			if m.opts.trace {
+				ti.tr.LazyLog(&payload{sent: false, id: r.nid, msg: r.reply}, false)
+			}
+			replyValues = append(replyValues, r.reply)
+			if reply.WriteNReply, quorum = c.qspec.WriteNextQF(replyValues); quorum {
+				return reply, nil
+			}
+		case <-ctx.Done():
+			return reply, QuorumCallError{ctx.Err().Error(), errCount, len(replyValues)}
 		}
-		i += n25
-	}
-	if m.Learned {
-		dAtA[i] = 0x18
-		i++
-		if m.Learned {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+
+		if errCount+len(replyValues) == c.n {
+			return reply, QuorumCallError{"incomplete call", errCount, len(replyValues)}
 		}
-		i++
 	}
-	return i, nil
 }
 
-func (m *Proposal) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func callGRPCWriteNext(ctx context.Context, node *Node, args *WriteN, replyChan chan<- writeNextReply) {
+	reply := new(WriteNReply)
+	start := time.Now()
+	err := grpc.Invoke(
+		ctx,
+		"/proto.SMandConsRegister/WriteNext",
+		args,
+		reply,
+		node.conn,
+	)
+	switch grpc.Code(err) { // nil -> codes.OK
+	case codes.OK, codes.Canceled:
+		node.setLatency(time.Since(start))
+	default:
+		node.setLastErr(err)
 	}
-	return dAtA[:n], nil
+	replyChan <- writeNextReply{node.id, reply, err}
 }
 
-func (m *Proposal) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Prop != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Prop.Size()))
-		n26, err := m.Prop.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n26
-	}
-	return i, nil
+/* 'gorums' plugin for protoc-gen-go - generated from: node_tmpl */
+
+// Node encapsulates the state of a node on which a remote procedure call
+// can be made.
+type Node struct {
+	// Only assigned at creation.
+	id   uint32
+	self bool
+	addr string
+	conn *grpc.ClientConn
+
+	SMandConsRegisterClient SMandConsRegisterClient
+
+	sync.Mutex
+	lastErr error
+	latency time.Duration
 }
 
-func (m *Ack) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+func (n *Node) connect(opts ...grpc.DialOption) error {
+	var err error
+	n.conn, err = grpc.Dial(n.addr, opts...)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("dialing node failed: %v", err)
 	}
-	return dAtA[:n], nil
-}
 
-func (m *Ack) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	return i, nil
-}
+	n.SMandConsRegisterClient = NewSMandConsRegisterClient(n.conn)
 
-func encodeFixed64DcSmartMerge(dAtA []byte, offset int, v uint64) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	dAtA[offset+4] = uint8(v >> 32)
-	dAtA[offset+5] = uint8(v >> 40)
-	dAtA[offset+6] = uint8(v >> 48)
-	dAtA[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32DcSmartMerge(dAtA []byte, offset int, v uint32) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintDcSmartMerge(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
-}
-func (m *State) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.Timestamp != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.Timestamp))
-	}
-	if m.Writer != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.Writer))
-	}
-	return n
+	return nil
 }
 
-func (m *Conf) Size() (n int) {
-	var l int
-	_ = l
-	if m.This != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.This))
-	}
-	if m.Cur != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.Cur))
+func (n *Node) close() error {
+	// TODO: Log error, mainly care about the connection error below.
+	// We should log this error, but we currently don't have access to the
+	// logger in the manager.
+
+	if err := n.conn.Close(); err != nil {
+		return fmt.Errorf("conn close error: %v", err)
 	}
-	return n
+	return nil
 }
 
-func (m *ConfReply) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.Abort {
-		n += 2
-	}
-	if len(m.Next) > 0 {
-		for _, e := range m.Next {
-			l = e.Size()
-			n += 1 + l + sovDcSmartMerge(uint64(l))
-		}
-	}
-	return n
-}
+/* 'gorums' plugin for protoc-gen-go - generated from: qspec_tmpl */
 
-func (m *NewCur) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.CurC != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.CurC))
-	}
-	return n
-}
+// QuorumSpec is the interface that wraps every quorum function.
+type QuorumSpec interface {
+	// AcceptQF is the quorum function for the Accept
+	// quorum call method.
+	AcceptQF(replies []*Learn) (*Learn, bool)
 
-func (m *NewCurReply) Size() (n int) {
-	var l int
-	_ = l
-	if m.New {
-		n += 2
-	}
-	return n
-}
+	// DSetStateQF is the quorum function for the DSetState
+	// quorum call method.
+	DSetStateQF(replies []*DNewStateReply) (*DNewStateReply, bool)
 
-func (m *Read) Size() (n int) {
-	var l int
-	_ = l
-	if m.Conf != nil {
-		l = m.Conf.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
-}
+	// DWriteNextQF is the quorum function for the DWriteNext
+	// quorum call method.
+	DWriteNextQF(replies []*DReadReply) (*DReadReply, bool)
 
-func (m *ReadReply) Size() (n int) {
-	var l int
-	_ = l
-	if m.State != nil {
-		l = m.State.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
+	// ElectQF is the quorum function for the Elect
+	// quorum call method.
+	ElectQF(replies []*Lease) (*Lease, bool)
+
+	// GetPromiseQF is the quorum function for the GetPromise
+	// quorum call method.
+	GetPromiseQF(replies []*Promise) (*Promise, bool)
+
+	// LAPropQF is the quorum function for the LAProp
+	// quorum call method.
+	LAPropQF(replies []*LAReply) (*LAReply, bool)
+
+	// LAPropValueQF is the quorum function for the LAPropValue
+	// quorum call method.
+	LAPropValueQF(replies []*LAValueReply) (*LAValueReply, bool)
+
+	// ReadQF is the quorum function for the Read
+	// quorum call method.
+	ReadQF(replies []*ReadReply) (*ReadReply, bool)
+
+	// SetCurQF is the quorum function for the SetCur
+	// quorum call method.
+	SetCurQF(replies []*NewCurReply) (*NewCurReply, bool)
+
+	// SetStateQF is the quorum function for the SetState
+	// quorum call method.
+	SetStateQF(replies []*NewStateReply) (*NewStateReply, bool)
+
+	// SpSnOneShotQF is the quorum function for the SpSnOneShot
+	// quorum call method.
+	SpSnOneShotQF(replies []*SpSnReply) (*SpSnReply, bool)
+
+	// WriteQF is the quorum function for the Write
+	// quorum call method.
+	WriteQF(replies []*ConfReply) (*ConfReply, bool)
+
+	// WriteNextQF is the quorum function for the WriteNext
+	// quorum call method.
+	WriteNextQF(replies []*WriteNReply) (*WriteNReply, bool)
 }
 
-func (m *WriteS) Size() (n int) {
-	var l int
-	_ = l
-	if m.State != nil {
-		l = m.State.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.Conf != nil {
-		l = m.Conf.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
+/* Static resources */
+
+/* config.go */
+
+// A Configuration represents a static set of nodes on which quorum remote
+// procedure calls may be invoked.
+type Configuration struct {
+	id    uint32
+	nodes []*Node
+	n     int
+	mgr   *Manager
+	qspec QuorumSpec
 }
 
-func (m *WriteN) Size() (n int) {
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.CurC))
-	}
-	if m.Next != nil {
-		l = m.Next.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
+// ID reports the identifier for the configuration.
+func (c *Configuration) ID() uint32 {
+	return c.id
 }
 
-func (m *WriteNReply) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.State != nil {
-		l = m.State.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.LAState != nil {
-		l = m.LAState.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+// NodeIDs returns a slice containing the local ids of all the nodes in the
+// configuration. IDs are returned in the same order as they were provided in
+// the creation of the Configuration.
+func (c *Configuration) NodeIDs() []uint32 {
+	ids := make([]uint32, len(c.nodes))
+	for i, node := range c.nodes {
+		ids[i] = node.ID()
 	}
-	return n
+	return ids
 }
 
-func (m *LAProposal) Size() (n int) {
-	var l int
-	_ = l
-	if m.Conf != nil {
-		l = m.Conf.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.Prop != nil {
-		l = m.Prop.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
+// Nodes returns a slice of each available node. IDs are returned in the same
+// order as they were provided in the creation of the Configuration.
+func (c *Configuration) Nodes() []*Node {
+	return c.nodes
 }
 
-func (m *LAReply) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.LAState != nil {
-		l = m.LAState.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
+// Size returns the number of nodes in the configuration.
+func (c *Configuration) Size() int {
+	return c.n
 }
 
-func (m *NewState) Size() (n int) {
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.CurC))
-	}
-	if m.State != nil {
-		l = m.State.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if m.LAState != nil {
-		l = m.LAState.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	return n
+func (c *Configuration) String() string {
+	return fmt.Sprintf("configuration %d", c.id)
 }
 
-func (m *NewStateReply) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
-	}
-	if len(m.Next) > 0 {
-		for _, e := range m.Next {
-			l = e.Size()
-			n += 1 + l + sovDcSmartMerge(uint64(l))
-		}
-	}
-	return n
+func (c *Configuration) tstring() string {
+	return fmt.Sprintf("config-%d", c.id)
 }
 
-func (m *CV) Size() (n int) {
-	var l int
-	_ = l
-	if m.Rnd != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
-	}
-	if m.Val != nil {
-		l = m.Val.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+// Equal returns a boolean reporting whether a and b represents the same
+// configuration.
+func Equal(a, b *Configuration) bool { return a.id == b.id }
+
+// NewTestConfiguration returns a new configuration with quorum size q and
+// node size n. No other fields are set. Configurations returned from this
+// constructor should only be used when testing quorum functions.
+func NewTestConfiguration(q, n int) *Configuration {
+	return &Configuration{
+		nodes: make([]*Node, n),
 	}
-	return n
 }
 
-func (m *Prepare) Size() (n int) {
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.CurC))
-	}
-	if m.Rnd != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
-	}
-	return n
+/* errors.go */
+
+// A NodeNotFoundError reports that a specified node could not be found.
+type NodeNotFoundError uint32
+
+func (e NodeNotFoundError) Error() string {
+	return fmt.Sprintf("node not found: %d", e)
 }
 
-func (m *Promise) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+// A ConfigNotFoundError reports that a specified configuration could not be
+// found.
+type ConfigNotFoundError uint32
+
+func (e ConfigNotFoundError) Error() string {
+	return fmt.Sprintf("configuration not found: %d", e)
+}
+
+// An IllegalConfigError reports that a specified configuration could not be
+// created.
+type IllegalConfigError string
+
+func (e IllegalConfigError) Error() string {
+	return "illegal configuration: " + string(e)
+}
+
+// ManagerCreationError returns an error reporting that a Manager could not be
+// created due to err.
+func ManagerCreationError(err error) error {
+	return fmt.Errorf("could not create manager: %s", err.Error())
+}
+
+// A QuorumCallError is used to report that a quorum call failed.
+type QuorumCallError struct {
+	Reason               string
+	ErrCount, ReplyCount int
+}
+
+func (e QuorumCallError) Error() string {
+	return fmt.Sprintf(
+		"quorum call error: %s (errors: %d, replies: %d)",
+		e.Reason, e.ErrCount, e.ReplyCount,
+	)
+}
+
+/* level.go */
+
+// LevelNotSet is the zero value level used to indicate that no level (and
+// thereby no reply) has been set for a correctable quorum call.
+const LevelNotSet = -1
+
+/* mgr.go */
+
+// Manager manages a pool of node configurations on which quorum remote
+// procedure calls can be made.
+type Manager struct {
+	sync.Mutex
+	nodes    []*Node
+	lookup   map[uint32]*Node
+	configs  map[uint32]*Configuration
+	eventLog trace.EventLog
+
+	closeOnce sync.Once
+	logger    *log.Logger
+	opts      managerOptions
+}
+
+// NewManager attempts to connect to the given set of node addresses and if
+// successful returns a new Manager containing connections to those nodes.
+func NewManager(nodeAddrs []string, opts ...ManagerOption) (*Manager, error) {
+	if len(nodeAddrs) == 0 {
+		return nil, fmt.Errorf("could not create manager: no nodes provided")
 	}
-	if m.Rnd != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
+
+	m := &Manager{
+		lookup:  make(map[uint32]*Node),
+		configs: make(map[uint32]*Configuration),
 	}
-	if m.Val != nil {
-		l = m.Val.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+
+	for _, opt := range opts {
+		opt(&m.opts)
 	}
-	if m.Dec != nil {
-		l = m.Dec.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+
+	for _, naddr := range nodeAddrs {
+		node, err2 := m.createNode(naddr)
+		if err2 != nil {
+			return nil, ManagerCreationError(err2)
+		}
+		m.lookup[node.id] = node
+		m.nodes = append(m.nodes, node)
 	}
-	return n
-}
 
-func (m *Propose) Size() (n int) {
-	var l int
-	_ = l
-	if m.CurC != 0 {
-		n += 1 + sovDcSmartMerge(uint64(m.CurC))
+	if m.opts.trace {
+		title := strings.Join(nodeAddrs, ",")
+		m.eventLog = trace.NewEventLog("gorums.Manager", title)
 	}
-	if m.Val != nil {
-		l = m.Val.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+
+	err := m.connectAll()
+	if err != nil {
+		return nil, ManagerCreationError(err)
 	}
-	return n
+
+	if m.opts.logger != nil {
+		m.logger = m.opts.logger
+	}
+
+	if m.eventLog != nil {
+		m.eventLog.Printf("ready")
+	}
+
+	return m, nil
 }
 
-func (m *Learn) Size() (n int) {
-	var l int
-	_ = l
-	if m.Cur != nil {
-		l = m.Cur.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+func (m *Manager) createNode(addr string) (*Node, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("create node %s error: %v", addr, err)
 	}
-	if m.Dec != nil {
-		l = m.Dec.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tcpAddr.String()))
+	id := h.Sum32()
+
+	if _, found := m.lookup[id]; found {
+		return nil, fmt.Errorf("create node %s error: node already exists", addr)
 	}
-	if m.Learned {
-		n += 2
+
+	node := &Node{
+		id:      id,
+		addr:    tcpAddr.String(),
+		latency: -1 * time.Second,
 	}
-	return n
+
+	return node, nil
 }
 
-func (m *Proposal) Size() (n int) {
-	var l int
-	_ = l
-	if m.Prop != nil {
-		l = m.Prop.Size()
-		n += 1 + l + sovDcSmartMerge(uint64(l))
+func (m *Manager) connectAll() error {
+	if m.opts.noConnect {
+		return nil
 	}
-	return n
-}
 
-func (m *Ack) Size() (n int) {
-	var l int
-	_ = l
-	return n
-}
+	if m.eventLog != nil {
+		m.eventLog.Printf("connecting")
+	}
 
-func sovDcSmartMerge(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+	for _, node := range m.nodes {
+		err := node.connect(m.opts.grpcDialOpts...)
+		if err != nil {
+			if m.eventLog != nil {
+				m.eventLog.Errorf("connect failed, error connecting to node %s, error: %v", node.addr, err)
+			}
+			return fmt.Errorf("connect node %s error: %v", node.addr, err)
 		}
 	}
-	return n
-}
-func sozDcSmartMerge(x uint64) (n int) {
-	return sovDcSmartMerge(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *State) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&State{`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
-		`Writer:` + fmt.Sprintf("%v", this.Writer) + `,`,
-		`}`,
-	}, "")
-	return s
+	return nil
 }
-func (this *Conf) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *Manager) closeNodeConns() {
+	for _, node := range m.nodes {
+		err := node.close()
+		if err == nil {
+			continue
+		}
+		if m.logger != nil {
+			m.logger.Printf("node %d: error closing: %v", node.id, err)
+		}
 	}
-	s := strings.Join([]string{`&Conf{`,
-		`This:` + fmt.Sprintf("%v", this.This) + `,`,
-		`Cur:` + fmt.Sprintf("%v", this.Cur) + `,`,
-		`}`,
-	}, "")
-	return s
 }
-func (this *ConfReply) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&ConfReply{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`Abort:` + fmt.Sprintf("%v", this.Abort) + `,`,
-		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// Close closes all node connections and any client streams.
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		if m.eventLog != nil {
+			m.eventLog.Printf("closing")
+		}
+		m.closeNodeConns()
+	})
 }
-func (this *NewCur) String() string {
-	if this == nil {
-		return "nil"
+
+// NodeIDs returns the identifier of each available node. IDs are returned in
+// the same order as they were provided in the creation of the Manager.
+func (m *Manager) NodeIDs() []uint32 {
+	m.Lock()
+	defer m.Unlock()
+	ids := make([]uint32, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		ids = append(ids, node.ID())
 	}
-	s := strings.Join([]string{`&NewCur{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
-		`}`,
-	}, "")
-	return s
+	return ids
 }
-func (this *NewCurReply) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&NewCurReply{`,
-		`New:` + fmt.Sprintf("%v", this.New) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// Node returns the node with the given identifier if present.
+func (m *Manager) Node(id uint32) (node *Node, found bool) {
+	m.Lock()
+	defer m.Unlock()
+	node, found = m.lookup[id]
+	return node, found
 }
-func (this *Read) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Read{`,
-		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// Nodes returns a slice of each available node. IDs are returned in the same
+// order as they were provided in the creation of the Manager.
+func (m *Manager) Nodes() []*Node {
+	m.Lock()
+	defer m.Unlock()
+	return m.nodes
 }
-func (this *ReadReply) String() string {
-	if this == nil {
-		return "nil"
+
+// ConfigurationIDs returns the identifier of each available
+// configuration.
+func (m *Manager) ConfigurationIDs() []uint32 {
+	m.Lock()
+	defer m.Unlock()
+	ids := make([]uint32, 0, len(m.configs))
+	for id := range m.configs {
+		ids = append(ids, id)
 	}
-	s := strings.Join([]string{`&ReadReply{`,
-		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return ids
 }
-func (this *WriteS) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&WriteS{`,
-		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
-		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// Configuration returns the configuration with the given global
+// identifier if present.
+func (m *Manager) Configuration(id uint32) (config *Configuration, found bool) {
+	m.Lock()
+	defer m.Unlock()
+	config, found = m.configs[id]
+	return config, found
 }
-func (this *WriteN) String() string {
-	if this == nil {
-		return "nil"
+
+// Configurations returns a slice of each available configuration.
+func (m *Manager) Configurations() []*Configuration {
+	m.Lock()
+	defer m.Unlock()
+	configs := make([]*Configuration, 0, len(m.configs))
+	for _, conf := range m.configs {
+		configs = append(configs, conf)
 	}
-	s := strings.Join([]string{`&WriteN{`,
-		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
-		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return configs
 }
-func (this *WriteNReply) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&WriteNReply{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
-		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
-		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// Size returns the number of nodes and configurations in the Manager.
+func (m *Manager) Size() (nodes, configs int) {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.nodes), len(m.configs)
 }
-func (this *LAProposal) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&LAProposal{`,
-		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
-		`Prop:` + strings.Replace(fmt.Sprintf("%v", this.Prop), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// AddNode attempts to dial to the provide node address. The node is
+// added to the Manager's pool of nodes if a connection was established.
+func (m *Manager) AddNode(addr string) error {
+	panic("not implemented")
 }
-func (this *LAReply) String() string {
-	if this == nil {
-		return "nil"
+
+// NewConfiguration returns a new configuration given quorum specification and
+// a timeout.
+func (m *Manager) NewConfiguration(ids []uint32, qspec QuorumSpec) (*Configuration, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(ids) == 0 {
+		return nil, IllegalConfigError("need at least one node")
 	}
-	s := strings.Join([]string{`&LAReply{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
-		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *NewState) String() string {
-	if this == nil {
-		return "nil"
+
+	var cnodes []*Node
+	for _, nid := range ids {
+		node, found := m.lookup[nid]
+		if !found {
+			return nil, NodeNotFoundError(nid)
+		}
+		cnodes = append(cnodes, node)
 	}
-	s := strings.Join([]string{`&NewState{`,
-		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
-		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
-		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *NewStateReply) String() string {
-	if this == nil {
-		return "nil"
+
+	// Node ids are sorted ensure a globally consistent configuration id.
+	sort.Sort(idSlice(ids))
+
+	h := fnv.New32a()
+	for _, id := range ids {
+		binary.Write(h, binary.LittleEndian, id)
 	}
-	s := strings.Join([]string{`&NewStateReply{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *CV) String() string {
-	if this == nil {
-		return "nil"
+	cid := h.Sum32()
+
+	conf, found := m.configs[cid]
+	if found {
+		return conf, nil
 	}
-	s := strings.Join([]string{`&CV{`,
-		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
-		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Prepare) String() string {
-	if this == nil {
-		return "nil"
+
+	c := &Configuration{
+		id:    cid,
+		nodes: cnodes,
+		n:     len(cnodes),
+		mgr:   m,
+		qspec: qspec,
 	}
-	s := strings.Join([]string{`&Prepare{`,
-		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
-		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
-		`}`,
-	}, "")
-	return s
+	m.configs[cid] = c
+
+	return c, nil
 }
-func (this *Promise) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Promise{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
-		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "CV", "CV", 1) + `,`,
-		`Dec:` + strings.Replace(fmt.Sprintf("%v", this.Dec), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+type idSlice []uint32
+
+func (p idSlice) Len() int           { return len(p) }
+func (p idSlice) Less(i, j int) bool { return p[i] < p[j] }
+func (p idSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+/* node_func.go */
+
+// ID returns the ID of m.
+func (n *Node) ID() uint32 {
+	return n.id
 }
-func (this *Propose) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Propose{`,
-		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
-		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "CV", "CV", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+// Address returns network address of m.
+func (n *Node) Address() string {
+	return n.addr
 }
-func (this *Learn) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Learn{`,
-		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`Dec:` + strings.Replace(fmt.Sprintf("%v", this.Dec), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`Learned:` + fmt.Sprintf("%v", this.Learned) + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (n *Node) String() string {
+	n.Lock()
+	defer n.Unlock()
+	return fmt.Sprintf(
+		"node %d | addr: %s | latency: %v",
+		n.id, n.addr, n.latency,
+	)
 }
-func (this *Proposal) String() string {
-	if this == nil {
-		return "nil"
+
+func (n *Node) setLastErr(err error) {
+	n.Lock()
+	defer n.Unlock()
+	n.lastErr = err
+}
+
+// LastErr returns the last error encountered (if any) when invoking a remote
+// procedure call on this node.
+func (n *Node) LastErr() error {
+	n.Lock()
+	defer n.Unlock()
+	return n.lastErr
+}
+
+func (n *Node) setLatency(lat time.Duration) {
+	n.Lock()
+	defer n.Unlock()
+	n.latency = lat
+}
+
+// Latency returns the latency of the last successful remote procedure call
+// made to this node.
+func (n *Node) Latency() time.Duration {
+	n.Lock()
+	defer n.Unlock()
+	return n.latency
+}
+
+type lessFunc func(n1, n2 *Node) bool
+
+// MultiSorter implements the Sort interface, sorting the nodes within.
+type MultiSorter struct {
+	nodes []*Node
+	less  []lessFunc
+}
+
+// Sort sorts the argument slice according to the less functions passed to
+// OrderedBy.
+func (ms *MultiSorter) Sort(nodes []*Node) {
+	ms.nodes = nodes
+	sort.Sort(ms)
+}
+
+// OrderedBy returns a Sorter that sorts using the less functions, in order.
+// Call its Sort method to sort the data.
+func OrderedBy(less ...lessFunc) *MultiSorter {
+	return &MultiSorter{
+		less: less,
 	}
-	s := strings.Join([]string{`&Proposal{`,
-		`Prop:` + strings.Replace(fmt.Sprintf("%v", this.Prop), "Blueprint", "blueprints.Blueprint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
 }
-func (this *Ack) String() string {
-	if this == nil {
-		return "nil"
+
+// Len is part of sort.Interface.
+func (ms *MultiSorter) Len() int {
+	return len(ms.nodes)
+}
+
+// Swap is part of sort.Interface.
+func (ms *MultiSorter) Swap(i, j int) {
+	ms.nodes[i], ms.nodes[j] = ms.nodes[j], ms.nodes[i]
+}
+
+// Less is part of sort.Interface. It is implemented by looping along the
+// less functions until it finds a comparison that is either Less or
+// !Less. Note that it can call the less functions twice per call. We
+// could change the functions to return -1, 0, 1 and reduce the
+// number of calls for greater efficiency: an exercise for the reader.
+func (ms *MultiSorter) Less(i, j int) bool {
+	p, q := ms.nodes[i], ms.nodes[j]
+	// Try all but the last comparison.
+	var k int
+	for k = 0; k < len(ms.less)-1; k++ {
+		less := ms.less[k]
+		switch {
+		case less(p, q):
+			// p < q, so we have a decision.
+			return true
+		case less(q, p):
+			// p > q, so we have a decision.
+			return false
+		}
+		// p == q; try the next comparison.
 	}
-	s := strings.Join([]string{`&Ack{`,
-		`}`,
-	}, "")
-	return s
+	// All comparisons to here said "equal", so just return whatever
+	// the final comparison reports.
+	return ms.less[k](p, q)
 }
-func valueToStringDcSmartMerge(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+// ID sorts nodes by their identifier in increasing order.
+var ID = func(n1, n2 *Node) bool {
+	return n1.id < n2.id
+}
+
+// Latency sorts nodes by latency in increasing order. Latencies less then
+// zero (sentinel value) are considered greater than any positive latency.
+var Latency = func(n1, n2 *Node) bool {
+	if n1.latency < 0 {
+		return false
+	}
+	return n1.latency < n2.latency
+
+}
+
+// Error sorts nodes by their LastErr() status in increasing order. A
+// node with LastErr() != nil is larger than a node with LastErr() == nil.
+var Error = func(n1, n2 *Node) bool {
+	if n1.lastErr != nil && n2.lastErr == nil {
+		return false
+	}
+	return true
+}
+
+/* opts.go */
+
+type managerOptions struct {
+	grpcDialOpts []grpc.DialOption
+	logger       *log.Logger
+	noConnect    bool
+	trace        bool
+}
+
+// ManagerOption provides a way to set different options on a new Manager.
+type ManagerOption func(*managerOptions)
+
+// WithGrpcDialOptions returns a ManagerOption which sets any gRPC dial options
+// the Manager should use when initially connecting to each node in its
+// pool.
+func WithGrpcDialOptions(opts ...grpc.DialOption) ManagerOption {
+	return func(o *managerOptions) {
+		o.grpcDialOpts = opts
+	}
+}
+
+// WithLogger returns a ManagerOption which sets an optional error logger for
+// the Manager.
+func WithLogger(logger *log.Logger) ManagerOption {
+	return func(o *managerOptions) {
+		o.logger = logger
+	}
+}
+
+// WithNoConnect returns a ManagerOption which instructs the Manager not to
+// connect to any of its nodes. Mainly used for testing purposes.
+func WithNoConnect() ManagerOption {
+	return func(o *managerOptions) {
+		o.noConnect = true
+	}
+}
+
+// WithTracing controls whether to trace qourum calls for this Manager instance
+// using the golang.org/x/net/trace package. Tracing is currently only supported
+// for regular quorum calls.
+func WithTracing() ManagerOption {
+	return func(o *managerOptions) {
+		o.trace = true
+	}
+}
+
+/* trace.go */
+
+type traceInfo struct {
+	tr        trace.Trace
+	firstLine firstLine
+}
+
+type firstLine struct {
+	deadline time.Duration
+	cid      uint32
+}
+
+func (f *firstLine) String() string {
+	var line bytes.Buffer
+	io.WriteString(&line, "QC: to config")
+	fmt.Fprintf(&line, "%v deadline:", f.cid)
+	if f.deadline != 0 {
+		fmt.Fprint(&line, f.deadline)
+	} else {
+		io.WriteString(&line, "none")
+	}
+	return line.String()
+}
+
+type payload struct {
+	sent bool
+	id   uint32
+	msg  interface{}
+}
+
+func (p payload) String() string {
+	if p.sent {
+		return fmt.Sprintf("sent: %v", p.msg)
+	}
+	return fmt.Sprintf("recv from %d: %v", p.id, p.msg)
+}
+
+type qcresult struct {
+	ids   []uint32
+	reply interface{}
+	err   error
+}
+
+func (q qcresult) String() string {
+	var out bytes.Buffer
+	io.WriteString(&out, "recv QC reply: ")
+	fmt.Fprintf(&out, "ids: %v, ", q.ids)
+	fmt.Fprintf(&out, "reply: %v ", q.reply)
+	if q.err != nil {
+		fmt.Fprintf(&out, ", error: %v", q.err)
+	}
+	return out.String()
+}
+
+/* util.go */
+
+func appendIfNotPresent(set []uint32, x uint32) []uint32 {
+	for _, y := range set {
+		if y == x {
+			return set
+		}
+	}
+	return append(set, x)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for SMandConsRegister service
+
+type SMandConsRegisterClient interface {
+	// Read a register value, or the values of a range of registers
+	Read(ctx context.Context, in *Read, opts ...grpc.CallOption) (*ReadReply, error)
+	// Write a value to the register
+	Write(ctx context.Context, in *WriteS, opts ...grpc.CallOption) (*ConfReply, error)
+	// Inform the servers about a new proposed configuration/blueprint
+	WriteNext(ctx context.Context, in *WriteN, opts ...grpc.CallOption) (*WriteNReply, error)
+	// Inform the servers that a new configuration has been installed
+	SetCur(ctx context.Context, in *NewCur, opts ...grpc.CallOption) (*NewCurReply, error)
+	// Propose a value to lattice agreement
+	// Only used in smartmerge algorithm
+	LAProp(ctx context.Context, in *LAProposal, opts ...grpc.CallOption) (*LAReply, error)
+	// Set register and lattice agreement state in new configuration
+	SetState(ctx context.Context, in *NewState, opts ...grpc.CallOption) (*NewStateReply, error)
+	// Consensus: Paxos first phase
+	// Only used in the consensus based algorithm (RAMBO)
+	GetPromise(ctx context.Context, in *Prepare, opts ...grpc.CallOption) (*Promise, error)
+	// Consensus: Paxos second phase
+	// Only used in the consensus based algorithm (RAMBO)
+	Accept(ctx context.Context, in *Propose, opts ...grpc.CallOption) (*Learn, error)
+	// Fwd is used to forward a reconfiguration-proposal to a leader.
+	// Only used in the consensus based algorithm (RAMBO)
+	Fwd(ctx context.Context, in *Proposal, opts ...grpc.CallOption) (*Ack, error)
+	// Propose a value to the lattice agreement instance given in the proposal.
+	// The value can be of any type registered with the lattice package.
+	LAPropValue(ctx context.Context, in *LAValueProposal, opts ...grpc.CallOption) (*LAValueReply, error)
+	// Record successors of a view, and read the registers stored in it.
+	// Only used in DynaStore and SpSnStore.
+	DWriteNext(ctx context.Context, in *DWriteN, opts ...grpc.CallOption) (*DReadReply, error)
+	// Write register states in a view, and read its successors.
+	// Only used in DynaStore and SpSnStore.
+	DSetState(ctx context.Context, in *DNewState, opts ...grpc.CallOption) (*DNewStateReply, error)
+	// Propose a view at one level of the speculating snapshot of a view.
+	// Only used in SpSnStore.
+	SpSnOneShot(ctx context.Context, in *SpSnProp, opts ...grpc.CallOption) (*SpSnReply, error)
+	// Elect asks for a leader lease for the ballot.
+	// Only used with a leader for the consensus based algorithm.
+	Elect(ctx context.Context, in *Ballot, opts ...grpc.CallOption) (*Lease, error)
+	// Status returns the state of a single server, for monitoring.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*ServerStatus, error)
+	// Watch streams the changes of a register, and of the current configuration, of a single server.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (SMandConsRegister_WatchClient, error)
+}
+
+type sMandConsRegisterClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSMandConsRegisterClient(cc *grpc.ClientConn) SMandConsRegisterClient {
+	return &sMandConsRegisterClient{cc}
+}
+
+func (c *sMandConsRegisterClient) Read(ctx context.Context, in *Read, opts ...grpc.CallOption) (*ReadReply, error) {
+	out := new(ReadReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Read", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) Write(ctx context.Context, in *WriteS, opts ...grpc.CallOption) (*ConfReply, error) {
+	out := new(ConfReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Write", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) WriteNext(ctx context.Context, in *WriteN, opts ...grpc.CallOption) (*WriteNReply, error) {
+	out := new(WriteNReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/WriteNext", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) SetCur(ctx context.Context, in *NewCur, opts ...grpc.CallOption) (*NewCurReply, error) {
+	out := new(NewCurReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/SetCur", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) LAProp(ctx context.Context, in *LAProposal, opts ...grpc.CallOption) (*LAReply, error) {
+	out := new(LAReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/LAProp", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) SetState(ctx context.Context, in *NewState, opts ...grpc.CallOption) (*NewStateReply, error) {
+	out := new(NewStateReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/SetState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) GetPromise(ctx context.Context, in *Prepare, opts ...grpc.CallOption) (*Promise, error) {
+	out := new(Promise)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/GetPromise", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) Accept(ctx context.Context, in *Propose, opts ...grpc.CallOption) (*Learn, error) {
+	out := new(Learn)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Accept", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) Fwd(ctx context.Context, in *Proposal, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Fwd", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) LAPropValue(ctx context.Context, in *LAValueProposal, opts ...grpc.CallOption) (*LAValueReply, error) {
+	out := new(LAValueReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/LAPropValue", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) DWriteNext(ctx context.Context, in *DWriteN, opts ...grpc.CallOption) (*DReadReply, error) {
+	out := new(DReadReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/DWriteNext", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) DSetState(ctx context.Context, in *DNewState, opts ...grpc.CallOption) (*DNewStateReply, error) {
+	out := new(DNewStateReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/DSetState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) SpSnOneShot(ctx context.Context, in *SpSnProp, opts ...grpc.CallOption) (*SpSnReply, error) {
+	out := new(SpSnReply)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/SpSnOneShot", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) Elect(ctx context.Context, in *Ballot, opts ...grpc.CallOption) (*Lease, error) {
+	out := new(Lease)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Elect", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*ServerStatus, error) {
+	out := new(ServerStatus)
+	err := grpc.Invoke(ctx, "/proto.SMandConsRegister/Status", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMandConsRegisterClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (SMandConsRegister_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_SMandConsRegister_serviceDesc.Streams[0], c.cc, "/proto.SMandConsRegister/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sMandConsRegisterWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SMandConsRegister_WatchClient interface {
+	Recv() (*WatchUpdate, error)
+	grpc.ClientStream
+}
+
+type sMandConsRegisterWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *sMandConsRegisterWatchClient) Recv() (*WatchUpdate, error) {
+	m := new(WatchUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for SMandConsRegister service
+
+type SMandConsRegisterServer interface {
+	// Read a register value, or the values of a range of registers
+	Read(context.Context, *Read) (*ReadReply, error)
+	// Write a value to the register
+	Write(context.Context, *WriteS) (*ConfReply, error)
+	// Inform the servers about a new proposed configuration/blueprint
+	WriteNext(context.Context, *WriteN) (*WriteNReply, error)
+	// Inform the servers that a new configuration has been installed
+	SetCur(context.Context, *NewCur) (*NewCurReply, error)
+	// Propose a value to lattice agreement
+	// Only used in smartmerge algorithm
+	LAProp(context.Context, *LAProposal) (*LAReply, error)
+	// Set register and lattice agreement state in new configuration
+	SetState(context.Context, *NewState) (*NewStateReply, error)
+	// Consensus: Paxos first phase
+	// Only used in the consensus based algorithm (RAMBO)
+	GetPromise(context.Context, *Prepare) (*Promise, error)
+	// Consensus: Paxos second phase
+	// Only used in the consensus based algorithm (RAMBO)
+	Accept(context.Context, *Propose) (*Learn, error)
+	// Fwd is used to forward a reconfiguration-proposal to a leader.
+	// Only used in the consensus based algorithm (RAMBO)
+	Fwd(context.Context, *Proposal) (*Ack, error)
+	// Propose a value to the lattice agreement instance given in the proposal.
+	// The value can be of any type registered with the lattice package.
+	LAPropValue(context.Context, *LAValueProposal) (*LAValueReply, error)
+	// Record successors of a view, and read the registers stored in it.
+	// Only used in DynaStore and SpSnStore.
+	DWriteNext(context.Context, *DWriteN) (*DReadReply, error)
+	// Write register states in a view, and read its successors.
+	// Only used in DynaStore and SpSnStore.
+	DSetState(context.Context, *DNewState) (*DNewStateReply, error)
+	// Propose a view at one level of the speculating snapshot of a view.
+	// Only used in SpSnStore.
+	SpSnOneShot(context.Context, *SpSnProp) (*SpSnReply, error)
+	// Elect asks for a leader lease for the ballot.
+	// Only used with a leader for the consensus based algorithm.
+	Elect(context.Context, *Ballot) (*Lease, error)
+	// Status returns the state of a single server, for monitoring.
+	Status(context.Context, *StatusRequest) (*ServerStatus, error)
+	// Watch streams the changes of a register, and of the current configuration, of a single server.
+	Watch(*WatchRequest, SMandConsRegister_WatchServer) error
+}
+
+func RegisterSMandConsRegisterServer(s *grpc.Server, srv SMandConsRegisterServer) {
+	s.RegisterService(&_SMandConsRegister_serviceDesc, srv)
+}
+
+func _SMandConsRegister_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Read)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).Read(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/Read",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).Read(ctx, req.(*Read))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteS)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/Write",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).Write(ctx, req.(*WriteS))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_WriteNext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteN)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).WriteNext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/WriteNext",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).WriteNext(ctx, req.(*WriteN))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_SetCur_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewCur)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).SetCur(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/SetCur",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).SetCur(ctx, req.(*NewCur))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_LAProp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LAProposal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).LAProp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/LAProp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).LAProp(ctx, req.(*LAProposal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_SetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).SetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/SetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).SetState(ctx, req.(*NewState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_GetPromise_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Prepare)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).GetPromise(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/GetPromise",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).GetPromise(ctx, req.(*Prepare))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_Accept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Propose)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).Accept(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/Accept",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).Accept(ctx, req.(*Propose))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_Fwd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Proposal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).Fwd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/Fwd",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).Fwd(ctx, req.(*Proposal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_LAPropValue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LAValueProposal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).LAPropValue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/LAPropValue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).LAPropValue(ctx, req.(*LAValueProposal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_DWriteNext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DWriteN)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).DWriteNext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/DWriteNext",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).DWriteNext(ctx, req.(*DWriteN))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_DSetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DNewState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).DSetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/DSetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).DSetState(ctx, req.(*DNewState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_SpSnOneShot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpSnProp)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).SpSnOneShot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/SpSnOneShot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).SpSnOneShot(ctx, req.(*SpSnProp))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_Elect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ballot)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).Elect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/Elect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).Elect(ctx, req.(*Ballot))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMandConsRegisterServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SMandConsRegister/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMandConsRegisterServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMandConsRegister_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SMandConsRegisterServer).Watch(m, &sMandConsRegisterWatchServer{stream})
+}
+
+type SMandConsRegister_WatchServer interface {
+	Send(*WatchUpdate) error
+	grpc.ServerStream
+}
+
+type sMandConsRegisterWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *sMandConsRegisterWatchServer) Send(m *WatchUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _SMandConsRegister_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.SMandConsRegister",
+	HandlerType: (*SMandConsRegisterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Read",
+			Handler:    _SMandConsRegister_Read_Handler,
+		},
+		{
+			MethodName: "Write",
+			Handler:    _SMandConsRegister_Write_Handler,
+		},
+		{
+			MethodName: "WriteNext",
+			Handler:    _SMandConsRegister_WriteNext_Handler,
+		},
+		{
+			MethodName: "SetCur",
+			Handler:    _SMandConsRegister_SetCur_Handler,
+		},
+		{
+			MethodName: "LAProp",
+			Handler:    _SMandConsRegister_LAProp_Handler,
+		},
+		{
+			MethodName: "SetState",
+			Handler:    _SMandConsRegister_SetState_Handler,
+		},
+		{
+			MethodName: "GetPromise",
+			Handler:    _SMandConsRegister_GetPromise_Handler,
+		},
+		{
+			MethodName: "Accept",
+			Handler:    _SMandConsRegister_Accept_Handler,
+		},
+		{
+			MethodName: "Fwd",
+			Handler:    _SMandConsRegister_Fwd_Handler,
+		},
+		{
+			MethodName: "LAPropValue",
+			Handler:    _SMandConsRegister_LAPropValue_Handler,
+		},
+		{
+			MethodName: "DWriteNext",
+			Handler:    _SMandConsRegister_DWriteNext_Handler,
+		},
+		{
+			MethodName: "DSetState",
+			Handler:    _SMandConsRegister_DSetState_Handler,
+		},
+		{
+			MethodName: "SpSnOneShot",
+			Handler:    _SMandConsRegister_SpSnOneShot_Handler,
+		},
+		{
+			MethodName: "Elect",
+			Handler:    _SMandConsRegister_Elect_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _SMandConsRegister_Status_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _SMandConsRegister_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dc-smartmerge.proto",
+}
+
+func (m *State) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *State) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Timestamp))
+	}
+	if m.Writer != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Writer))
+	}
+	if len(m.Key) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	return i, nil
+}
+
+func (m *Conf) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Conf) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.This) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.This)))
+		i += copy(dAtA[i:], m.This)
+	}
+	if len(m.Cur) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Cur)))
+		i += copy(dAtA[i:], m.Cur)
+	}
+	return i, nil
+}
+
+func (m *ConfReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConfReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n1, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Abort {
+		dAtA[i] = 0x10
+		i++
+		if m.Abort {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Rejected {
+		dAtA[i] = 0x20
+		i++
+		if m.Rejected {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *NewCur) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NewCur) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n2, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	return i, nil
+}
+
+func (m *NewCurReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NewCurReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.New {
+		dAtA[i] = 0x8
+		i++
+		if m.New {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *Read) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Read) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Conf != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
+		n3, err := m.Conf.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Keys != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Keys.Size()))
+		n4, err := m.Keys.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.Ballot != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Ballot.Size()))
+		n5, err := m.Ballot.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.AsOf != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.AsOf))
+	}
+	if m.Versions != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Versions))
+	}
+	return i, nil
+}
+
+func (m *ReadReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.State != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
+		n6, err := m.State.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if m.Cur != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n7, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if len(m.States) > 0 {
+		for _, msg := range m.States {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Promise != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Promise.Size()))
+		n8, err := m.Promise.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if len(m.Versions) > 0 {
+		for _, msg := range m.Versions {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Compacted {
+		dAtA[i] = 0x30
+		i++
+		if m.Compacted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *WriteS) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteS) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.State != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
+		n9, err := m.State.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if m.Conf != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
+		n10, err := m.Conf.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+
+func (m *WriteN) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteN) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if m.Next != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Next.Size()))
+		n11, err := m.Next.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.Keys != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Keys.Size()))
+		n12, err := m.Keys.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	return i, nil
+}
+
+func (m *WriteNReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteNReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n13, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if len(m.States) > 0 {
+		for _, msg := range m.States {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.LAState != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
+		n14, err := m.LAState.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if len(m.LAValues) > 0 {
+		for _, msg := range m.LAValues {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Promises) > 0 {
+		for _, msg := range m.Promises {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.History) > 0 {
+		for _, msg := range m.History {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Compacted) > 0 {
+		for _, msg := range m.Compacted {
+			dAtA[i] = 0x3a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *LAProposal) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LAProposal) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Conf != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
+		n15, err := m.Conf.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	if m.Prop != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Prop.Size()))
+		n16, err := m.Prop.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	return i, nil
+}
+
+func (m *LAReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LAReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n17, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	if m.LAState != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
+		n18, err := m.LAState.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n18
+	}
+	return i, nil
+}
+
+func (m *NewState) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NewState) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if len(m.States) > 0 {
+		for _, msg := range m.States {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.LAState != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
+		n19, err := m.LAState.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	if len(m.LAValues) > 0 {
+		for _, msg := range m.LAValues {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Promises) > 0 {
+		for _, msg := range m.Promises {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.History) > 0 {
+		for _, msg := range m.History {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Compacted) > 0 {
+		for _, msg := range m.Compacted {
+			dAtA[i] = 0x3a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Write != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Write.Size()))
+		n20, err := m.Write.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	return i, nil
+}
+
+func (m *NewStateReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NewStateReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n21, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Rejected {
+		dAtA[i] = 0x18
+		i++
+		if m.Rejected {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *CV) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CV) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Rnd != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+	}
+	if m.Val != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
+		n22, err := m.Val.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
+	return i, nil
+}
+
+func (m *Prepare) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Prepare) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if m.Rnd != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+	}
+	return i, nil
+}
+
+func (m *Promise) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Promise) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n23, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	if m.Rnd != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+	}
+	if m.Val != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
+		n24, err := m.Val.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
+	if m.Dec != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Dec.Size()))
+		n25, err := m.Dec.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n25
+	}
+	return i, nil
+}
+
+func (m *Propose) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Propose) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if m.Val != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
+		n26, err := m.Val.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
+	return i, nil
+}
+
+func (m *Learn) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Learn) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n27, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	if m.Dec != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Dec.Size()))
+		n28, err := m.Dec.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n28
+	}
+	if m.Learned {
+		dAtA[i] = 0x18
+		i++
+		if m.Learned {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *Proposal) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Proposal) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Prop != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Prop.Size()))
+		n29, err := m.Prop.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	return i, nil
+}
+
+func (m *Ack) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ack) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Redirect {
+		dAtA[i] = 0x8
+		i++
+		if m.Redirect {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Leader != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Leader))
+	}
+	if len(m.LeaderAddr) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.LeaderAddr)))
+		i += copy(dAtA[i:], m.LeaderAddr)
+	}
+	return i, nil
+}
+
+func (m *Keys) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Keys) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if len(m.End) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.End)))
+		i += copy(dAtA[i:], m.End)
+	}
+	if m.Range {
+		dAtA[i] = 0x18
+		i++
+		if m.Range {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *LAValue) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LAValue) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Type) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Type)))
+		i += copy(dAtA[i:], m.Type)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
+	return i, nil
+}
+
+func (m *LAValueProposal) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LAValueProposal) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Conf != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Conf.Size()))
+		n30, err := m.Conf.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n30
+	}
+	if len(m.Instance) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Instance)))
+		i += copy(dAtA[i:], m.Instance)
+	}
+	if m.Prop != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Prop.Size()))
+		n31, err := m.Prop.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n31
+	}
+	return i, nil
+}
+
+func (m *LAValueReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LAValueReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n32, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n32
+	}
+	if m.State != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
+		n33, err := m.State.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n33
+	}
+	return i, nil
+}
+
+func (m *LAInstance) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LAInstance) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Instance) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Instance)))
+		i += copy(dAtA[i:], m.Instance)
+	}
+	if m.State != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
+		n34, err := m.State.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n34
+	}
+	return i, nil
+}
+
+func (m *DWriteN) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DWriteN) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Keys != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Keys.Size()))
+		n35, err := m.Keys.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n35
+	}
+	return i, nil
+}
+
+func (m *DReadReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DReadReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.States) > 0 {
+		for _, msg := range m.States {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *DNewState) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DNewState) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if len(m.States) > 0 {
+		for _, msg := range m.States {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *DNewStateReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DNewStateReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *SpSnProp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpSnProp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if m.Level != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Level))
+	}
+	if m.Prop != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Prop.Size()))
+		n36, err := m.Prop.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n36
+	}
+	return i, nil
+}
+
+func (m *SpSnReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpSnReply) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Vals) > 0 {
+		for _, msg := range m.Vals {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Ballot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ballot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Rnd != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+	}
+	if m.Id != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Id))
+	}
+	if len(m.Addr) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Addr)))
+		i += copy(dAtA[i:], m.Addr)
+	}
+	if m.Lease != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Lease))
+	}
+	return i, nil
+}
+
+func (m *Lease) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Lease) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Granted {
+		dAtA[i] = 0x8
+		i++
+		if m.Granted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Holder != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Holder.Size()))
+		n37, err := m.Holder.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n37
+	}
+	if m.Cur != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n38, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n38
+	}
+	return i, nil
+}
+
+func (m *StatusRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatusRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *RegisterStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RegisterStatus) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Timestamp))
+	}
+	if m.Writer != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Writer))
+	}
+	return i, nil
+}
+
+func (m *PaxosStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PaxosStatus) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if m.Rnd != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Rnd))
+	}
+	if m.Val != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Val.Size()))
+		n39, err := m.Val.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n39
+	}
+	return i, nil
+}
+
+func (m *ServerStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ServerStatus) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cur != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n40, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n40
+	}
+	if len(m.CurC) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.CurC)))
+		i += copy(dAtA[i:], m.CurC)
+	}
+	if m.LAState != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.LAState.Size()))
+		n41, err := m.LAState.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n41
+	}
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Registers) > 0 {
+		for _, msg := range m.Registers {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Paxos) > 0 {
+		for _, msg := range m.Paxos {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Uptime != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Uptime))
+	}
+	return i, nil
+}
+
+func (m *WatchRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WatchRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	return i, nil
+}
+
+func (m *WatchUpdate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WatchUpdate) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.State != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.State.Size()))
+		n42, err := m.State.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n42
+	}
+	if m.Cur != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDcSmartMerge(dAtA, i, uint64(m.Cur.Size()))
+		n43, err := m.Cur.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n43
+	}
+	if len(m.Next) > 0 {
+		for _, msg := range m.Next {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintDcSmartMerge(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func encodeFixed64DcSmartMerge(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32DcSmartMerge(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintDcSmartMerge(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *State) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Timestamp))
+	}
+	if m.Writer != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Writer))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *Conf) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.This)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	l = len(m.Cur)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *ConfReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Abort {
+		n += 2
+	}
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Rejected {
+		n += 2
+	}
+	return n
+}
+
+func (m *NewCur) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *NewCurReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.New {
+		n += 2
+	}
+	return n
+}
+
+func (m *Read) Size() (n int) {
+	var l int
+	_ = l
+	if m.Conf != nil {
+		l = m.Conf.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Keys != nil {
+		l = m.Keys.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Ballot != nil {
+		l = m.Ballot.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.AsOf != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.AsOf))
+	}
+	if m.Versions != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Versions))
+	}
+	return n
+}
+
+func (m *ReadReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.State != nil {
+		l = m.State.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.States) > 0 {
+		for _, e := range m.States {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Promise != nil {
+		l = m.Promise.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.Versions) > 0 {
+		for _, e := range m.Versions {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Compacted {
+		n += 2
+	}
+	return n
+}
+
+func (m *WriteS) Size() (n int) {
+	var l int
+	_ = l
+	if m.State != nil {
+		l = m.State.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Conf != nil {
+		l = m.Conf.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *WriteN) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Next != nil {
+		l = m.Next.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Keys != nil {
+		l = m.Keys.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *WriteNReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.States) > 0 {
+		for _, e := range m.States {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.LAState != nil {
+		l = m.LAState.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.LAValues) > 0 {
+		for _, e := range m.LAValues {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Promises) > 0 {
+		for _, e := range m.Promises {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.History) > 0 {
+		for _, e := range m.History {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Compacted) > 0 {
+		for _, e := range m.Compacted {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LAProposal) Size() (n int) {
+	var l int
+	_ = l
+	if m.Conf != nil {
+		l = m.Conf.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Prop != nil {
+		l = m.Prop.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *LAReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.LAState != nil {
+		l = m.LAState.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *NewState) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.States) > 0 {
+		for _, e := range m.States {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.LAState != nil {
+		l = m.LAState.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.LAValues) > 0 {
+		for _, e := range m.LAValues {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Promises) > 0 {
+		for _, e := range m.Promises {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.History) > 0 {
+		for _, e := range m.History {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Compacted) > 0 {
+		for _, e := range m.Compacted {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Write != nil {
+		l = m.Write.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *NewStateReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Rejected {
+		n += 2
+	}
+	return n
+}
+
+func (m *CV) Size() (n int) {
+	var l int
+	_ = l
+	if m.Rnd != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
+	}
+	if m.Val != nil {
+		l = m.Val.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *Prepare) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Rnd != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
+	}
+	return n
+}
+
+func (m *Promise) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Rnd != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
+	}
+	if m.Val != nil {
+		l = m.Val.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Dec != nil {
+		l = m.Dec.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *Propose) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Val != nil {
+		l = m.Val.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *Learn) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Dec != nil {
+		l = m.Dec.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Learned {
+		n += 2
+	}
+	return n
+}
+
+func (m *Proposal) Size() (n int) {
+	var l int
+	_ = l
+	if m.Prop != nil {
+		l = m.Prop.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *Ack) Size() (n int) {
+	var l int
+	_ = l
+	if m.Redirect {
+		n += 2
+	}
+	if m.Leader != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Leader))
+	}
+	l = len(m.LeaderAddr)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *Keys) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	l = len(m.End)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Range {
+		n += 2
+	}
+	return n
+}
+
+func (m *LAValue) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *LAValueProposal) Size() (n int) {
+	var l int
+	_ = l
+	if m.Conf != nil {
+		l = m.Conf.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	l = len(m.Instance)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Prop != nil {
+		l = m.Prop.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *LAValueReply) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.State != nil {
+		l = m.State.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *LAInstance) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Instance)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.State != nil {
+		l = m.State.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *DWriteN) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Keys != nil {
+		l = m.Keys.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *DReadReply) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.States) > 0 {
+		for _, e := range m.States {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DNewState) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.States) > 0 {
+		for _, e := range m.States {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DNewStateReply) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SpSnProp) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Level != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Level))
+	}
+	if m.Prop != nil {
+		l = m.Prop.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *SpSnReply) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Vals) > 0 {
+		for _, e := range m.Vals {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Ballot) Size() (n int) {
+	var l int
+	_ = l
+	if m.Rnd != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
+	}
+	if m.Id != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Id))
+	}
+	l = len(m.Addr)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Lease != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Lease))
+	}
+	return n
+}
+
+func (m *Lease) Size() (n int) {
+	var l int
+	_ = l
+	if m.Granted {
+		n += 2
+	}
+	if m.Holder != nil {
+		l = m.Holder.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *StatusRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *RegisterStatus) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Timestamp))
+	}
+	if m.Writer != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Writer))
+	}
+	return n
+}
+
+func (m *PaxosStatus) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Rnd != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Rnd))
+	}
+	if m.Val != nil {
+		l = m.Val.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *ServerStatus) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	l = len(m.CurC)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.LAState != nil {
+		l = m.LAState.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Registers) > 0 {
+		for _, e := range m.Registers {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if len(m.Paxos) > 0 {
+		for _, e := range m.Paxos {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	if m.Uptime != 0 {
+		n += 1 + sovDcSmartMerge(uint64(m.Uptime))
+	}
+	return n
+}
+
+func (m *WatchRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	return n
+}
+
+func (m *WatchUpdate) Size() (n int) {
+	var l int
+	_ = l
+	if m.State != nil {
+		l = m.State.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if m.Cur != nil {
+		l = m.Cur.Size()
+		n += 1 + l + sovDcSmartMerge(uint64(l))
+	}
+	if len(m.Next) > 0 {
+		for _, e := range m.Next {
+			l = e.Size()
+			n += 1 + l + sovDcSmartMerge(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovDcSmartMerge(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozDcSmartMerge(x uint64) (n int) {
+	return sovDcSmartMerge(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *State) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&State{`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`Writer:` + fmt.Sprintf("%v", this.Writer) + `,`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Conf) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Conf{`,
+		`This:` + fmt.Sprintf("%v", this.This) + `,`,
+		`Cur:` + fmt.Sprintf("%v", this.Cur) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConfReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConfReply{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Abort:` + fmt.Sprintf("%v", this.Abort) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Rejected:` + fmt.Sprintf("%v", this.Rejected) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NewCur) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NewCur{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NewCurReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NewCurReply{`,
+		`New:` + fmt.Sprintf("%v", this.New) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Read) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Read{`,
+		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
+		`Keys:` + strings.Replace(fmt.Sprintf("%v", this.Keys), "Keys", "Keys", 1) + `,`,
+		`Ballot:` + strings.Replace(fmt.Sprintf("%v", this.Ballot), "State", "State", 1) + `,`,
+		`AsOf:` + fmt.Sprintf("%v", this.AsOf) + `,`,
+		`Versions:` + fmt.Sprintf("%v", this.Versions) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReadReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ReadReply{`,
+		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
+		`States:` + strings.Replace(fmt.Sprintf("%v", this.States), "State", "State", 1) + `,`,
+		`Promise:` + strings.Replace(fmt.Sprintf("%v", this.Promise), "State", "State", 1) + `,`,
+		`Versions:` + strings.Replace(fmt.Sprintf("%v", this.Versions), "State", "State", 1) + `,`,
+		`Compacted:` + fmt.Sprintf("%v", this.Compacted) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WriteS) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WriteS{`,
+		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
+		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WriteN) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WriteN{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Keys:` + strings.Replace(fmt.Sprintf("%v", this.Keys), "Keys", "Keys", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WriteNReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WriteNReply{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
+		`States:` + strings.Replace(fmt.Sprintf("%v", this.States), "State", "State", 1) + `,`,
+		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`LAValues:` + strings.Replace(fmt.Sprintf("%v", this.LAValues), "LAInstance", "LAInstance", 1) + `,`,
+		`Promises:` + strings.Replace(fmt.Sprintf("%v", this.Promises), "State", "State", 1) + `,`,
+		`History:` + strings.Replace(fmt.Sprintf("%v", this.History), "State", "State", 1) + `,`,
+		`Compacted:` + strings.Replace(fmt.Sprintf("%v", this.Compacted), "State", "State", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LAProposal) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LAProposal{`,
+		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
+		`Prop:` + strings.Replace(fmt.Sprintf("%v", this.Prop), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LAReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LAReply{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
+		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NewState) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NewState{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`States:` + strings.Replace(fmt.Sprintf("%v", this.States), "State", "State", 1) + `,`,
+		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`LAValues:` + strings.Replace(fmt.Sprintf("%v", this.LAValues), "LAInstance", "LAInstance", 1) + `,`,
+		`Promises:` + strings.Replace(fmt.Sprintf("%v", this.Promises), "State", "State", 1) + `,`,
+		`History:` + strings.Replace(fmt.Sprintf("%v", this.History), "State", "State", 1) + `,`,
+		`Compacted:` + strings.Replace(fmt.Sprintf("%v", this.Compacted), "State", "State", 1) + `,`,
+		`Write:` + strings.Replace(fmt.Sprintf("%v", this.Write), "State", "State", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NewStateReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NewStateReply{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Rejected:` + fmt.Sprintf("%v", this.Rejected) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CV) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CV{`,
+		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
+		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Prepare) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Prepare{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Promise) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Promise{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
+		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "CV", "CV", 1) + `,`,
+		`Dec:` + strings.Replace(fmt.Sprintf("%v", this.Dec), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Propose) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Propose{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "CV", "CV", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Learn) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Learn{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Dec:` + strings.Replace(fmt.Sprintf("%v", this.Dec), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Learned:` + fmt.Sprintf("%v", this.Learned) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Proposal) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Proposal{`,
+		`Prop:` + strings.Replace(fmt.Sprintf("%v", this.Prop), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Ack) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Ack{`,
+		`Redirect:` + fmt.Sprintf("%v", this.Redirect) + `,`,
+		`Leader:` + fmt.Sprintf("%v", this.Leader) + `,`,
+		`LeaderAddr:` + fmt.Sprintf("%v", this.LeaderAddr) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Keys) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Keys{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`Range:` + fmt.Sprintf("%v", this.Range) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LAValue) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LAValue{`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LAValueProposal) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LAValueProposal{`,
+		`Conf:` + strings.Replace(fmt.Sprintf("%v", this.Conf), "Conf", "Conf", 1) + `,`,
+		`Instance:` + fmt.Sprintf("%v", this.Instance) + `,`,
+		`Prop:` + strings.Replace(fmt.Sprintf("%v", this.Prop), "LAValue", "LAValue", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LAValueReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LAValueReply{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "ConfReply", "ConfReply", 1) + `,`,
+		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "LAValue", "LAValue", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LAInstance) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LAInstance{`,
+		`Instance:` + fmt.Sprintf("%v", this.Instance) + `,`,
+		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "LAValue", "LAValue", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DWriteN) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DWriteN{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Keys:` + strings.Replace(fmt.Sprintf("%v", this.Keys), "Keys", "Keys", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DReadReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DReadReply{`,
+		`States:` + strings.Replace(fmt.Sprintf("%v", this.States), "State", "State", 1) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DNewState) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DNewState{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`States:` + strings.Replace(fmt.Sprintf("%v", this.States), "State", "State", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DNewStateReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DNewStateReply{`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpSnProp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SpSnProp{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`Level:` + fmt.Sprintf("%v", this.Level) + `,`,
+		`Prop:` + strings.Replace(fmt.Sprintf("%v", this.Prop), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpSnReply) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SpSnReply{`,
+		`Vals:` + strings.Replace(fmt.Sprintf("%v", this.Vals), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Ballot) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Ballot{`,
+		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Addr:` + fmt.Sprintf("%v", this.Addr) + `,`,
+		`Lease:` + fmt.Sprintf("%v", this.Lease) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Lease) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Lease{`,
+		`Granted:` + fmt.Sprintf("%v", this.Granted) + `,`,
+		`Holder:` + strings.Replace(fmt.Sprintf("%v", this.Holder), "Ballot", "Ballot", 1) + `,`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StatusRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StatusRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RegisterStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RegisterStatus{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`Writer:` + fmt.Sprintf("%v", this.Writer) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PaxosStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PaxosStatus{`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`Rnd:` + fmt.Sprintf("%v", this.Rnd) + `,`,
+		`Val:` + strings.Replace(fmt.Sprintf("%v", this.Val), "CV", "CV", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ServerStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ServerStatus{`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`CurC:` + fmt.Sprintf("%v", this.CurC) + `,`,
+		`LAState:` + strings.Replace(fmt.Sprintf("%v", this.LAState), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Registers:` + strings.Replace(fmt.Sprintf("%v", this.Registers), "RegisterStatus", "RegisterStatus", 1) + `,`,
+		`Paxos:` + strings.Replace(fmt.Sprintf("%v", this.Paxos), "PaxosStatus", "PaxosStatus", 1) + `,`,
+		`Uptime:` + fmt.Sprintf("%v", this.Uptime) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WatchRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WatchRequest{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WatchUpdate) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WatchUpdate{`,
+		`State:` + strings.Replace(fmt.Sprintf("%v", this.State), "State", "State", 1) + `,`,
+		`Cur:` + strings.Replace(fmt.Sprintf("%v", this.Cur), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`Next:` + strings.Replace(fmt.Sprintf("%v", this.Next), "Blueprint", "blueprints.Blueprint", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringDcSmartMerge(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *State) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: State: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: State: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Writer", wireType)
+			}
+			m.Writer = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Writer |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Conf) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Conf: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Conf: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field This", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.This = append(m.This[:0], dAtA[iNdEx:postIndex]...)
+			if m.This == nil {
+				m.This = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cur = append(m.Cur[:0], dAtA[iNdEx:postIndex]...)
+			if m.Cur == nil {
+				m.Cur = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConfReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConfReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConfReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Abort", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Abort = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rejected", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Rejected = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NewCur) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NewCur: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NewCur: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NewCurReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NewCurReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NewCurReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field New", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.New = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Read) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Read: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Read: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Conf == nil {
+				m.Conf = &Conf{}
+			}
+			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Keys == nil {
+				m.Keys = &Keys{}
+			}
+			if err := m.Keys.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ballot", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Ballot == nil {
+				m.Ballot = &State{}
+			}
+			if err := m.Ballot.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AsOf", wireType)
+			}
+			m.AsOf = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AsOf |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Versions", wireType)
+			}
+			m.Versions = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Versions |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReadReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReadReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReadReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.State == nil {
+				m.State = &State{}
+			}
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &ConfReply{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field States", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.States = append(m.States, &State{})
+			if err := m.States[len(m.States)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Promise", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Promise == nil {
+				m.Promise = &State{}
+			}
+			if err := m.Promise.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Versions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Versions = append(m.Versions, &State{})
+			if err := m.Versions[len(m.Versions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compacted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Compacted = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteS) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteS: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteS: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.State == nil {
+				m.State = &State{}
+			}
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Conf == nil {
+				m.Conf = &Conf{}
+			}
+			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteN) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteN: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteN: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Next == nil {
+				m.Next = &blueprints.Blueprint{}
+			}
+			if err := m.Next.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Keys == nil {
+				m.Keys = &Keys{}
+			}
+			if err := m.Keys.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WriteNReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WriteNReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WriteNReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &ConfReply{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field States", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.States = append(m.States, &State{})
+			if err := m.States[len(m.States)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LAState == nil {
+				m.LAState = &blueprints.Blueprint{}
+			}
+			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LAValues", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LAValues = append(m.LAValues, &LAInstance{})
+			if err := m.LAValues[len(m.LAValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Promises", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Promises = append(m.Promises, &State{})
+			if err := m.Promises[len(m.Promises)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field History", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.History = append(m.History, &State{})
+			if err := m.History[len(m.History)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compacted", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compacted = append(m.Compacted, &State{})
+			if err := m.Compacted[len(m.Compacted)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LAProposal) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LAProposal: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LAProposal: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Conf == nil {
+				m.Conf = &Conf{}
+			}
+			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prop", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Prop == nil {
+				m.Prop = &blueprints.Blueprint{}
+			}
+			if err := m.Prop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LAReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LAReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LAReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &ConfReply{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LAState == nil {
+				m.LAState = &blueprints.Blueprint{}
+			}
+			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NewState) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NewState: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NewState: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field States", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.States = append(m.States, &State{})
+			if err := m.States[len(m.States)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LAState == nil {
+				m.LAState = &blueprints.Blueprint{}
+			}
+			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LAValues", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LAValues = append(m.LAValues, &LAInstance{})
+			if err := m.LAValues[len(m.LAValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Promises", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Promises = append(m.Promises, &State{})
+			if err := m.Promises[len(m.Promises)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field History", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.History = append(m.History, &State{})
+			if err := m.History[len(m.History)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compacted", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compacted = append(m.Compacted, &State{})
+			if err := m.Compacted[len(m.Compacted)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Write", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Write == nil {
+				m.Write = &State{}
+			}
+			if err := m.Write.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NewStateReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NewStateReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NewStateReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rejected", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Rejected = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CV) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CV: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CV: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
+			}
+			m.Rnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rnd |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Val == nil {
+				m.Val = &blueprints.Blueprint{}
+			}
+			if err := m.Val.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Prepare) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Prepare: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Prepare: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
+			}
+			m.Rnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rnd |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Promise) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Promise: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Promise: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
+			}
+			m.Rnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rnd |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Val == nil {
+				m.Val = &CV{}
+			}
+			if err := m.Val.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Dec == nil {
+				m.Dec = &blueprints.Blueprint{}
+			}
+			if err := m.Dec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Propose) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Propose: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Propose: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Val == nil {
+				m.Val = &CV{}
+			}
+			if err := m.Val.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Learn) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Learn: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Learn: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Dec == nil {
+				m.Dec = &blueprints.Blueprint{}
+			}
+			if err := m.Dec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Learned", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Learned = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Proposal) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prop", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Prop == nil {
+				m.Prop = &blueprints.Blueprint{}
+			}
+			if err := m.Prop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *State) Unmarshal(dAtA []byte) error {
+func (m *Ack) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5187,17 +12862,56 @@ func (m *State) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: State: wiretype end group for non-group")
+			return fmt.Errorf("proto: Ack: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: State: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Ack: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Redirect", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Redirect = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+			}
+			m.Leader = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Leader |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaderAddr", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5207,28 +12921,105 @@ func (m *State) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
-			if m.Value == nil {
-				m.Value = []byte{}
+			m.LeaderAddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Keys) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
 			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Keys: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Keys: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
-			m.Timestamp = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5238,16 +13029,26 @@ func (m *State) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= (int32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.End = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Writer", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
 			}
-			m.Writer = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5257,11 +13058,12 @@ func (m *State) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Writer |= (uint32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Range = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -5283,7 +13085,7 @@ func (m *State) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Conf) Unmarshal(dAtA []byte) error {
+func (m *LAValue) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5306,17 +13108,17 @@ func (m *Conf) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Conf: wiretype end group for non-group")
+			return fmt.Errorf("proto: LAValue: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Conf: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LAValue: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field This", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			m.This = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5326,16 +13128,26 @@ func (m *Conf) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.This |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			m.Cur = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5345,11 +13157,23 @@ func (m *Conf) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Cur |= (uint32(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -5371,7 +13195,7 @@ func (m *Conf) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ConfReply) Unmarshal(dAtA []byte) error {
+func (m *LAValueProposal) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5394,15 +13218,15 @@ func (m *ConfReply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ConfReply: wiretype end group for non-group")
+			return fmt.Errorf("proto: LAValueProposal: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ConfReply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LAValueProposal: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5426,18 +13250,18 @@ func (m *ConfReply) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Cur == nil {
-				m.Cur = &blueprints.Blueprint{}
+			if m.Conf == nil {
+				m.Conf = &Conf{}
 			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Abort", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Instance", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5447,15 +13271,24 @@ func (m *ConfReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Abort = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Instance = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Prop", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5479,8 +13312,10 @@ func (m *ConfReply) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Next = append(m.Next, &blueprints.Blueprint{})
-			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Prop == nil {
+				m.Prop = &LAValue{}
+			}
+			if err := m.Prop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5505,7 +13340,7 @@ func (m *ConfReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NewCur) Unmarshal(dAtA []byte) error {
+func (m *LAValueReply) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5528,10 +13363,10 @@ func (m *NewCur) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NewCur: wiretype end group for non-group")
+			return fmt.Errorf("proto: LAValueReply: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NewCur: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LAValueReply: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5561,17 +13396,17 @@ func (m *NewCur) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Cur == nil {
-				m.Cur = &blueprints.Blueprint{}
+				m.Cur = &ConfReply{}
 			}
 			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
 			}
-			m.CurC = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5581,11 +13416,25 @@ func (m *NewCur) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CurC |= (uint32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.State == nil {
+				m.State = &LAValue{}
+			}
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -5607,7 +13456,7 @@ func (m *NewCur) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NewCurReply) Unmarshal(dAtA []byte) error {
+func (m *LAInstance) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5630,17 +13479,46 @@ func (m *NewCurReply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NewCurReply: wiretype end group for non-group")
+			return fmt.Errorf("proto: LAInstance: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NewCurReply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LAInstance: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field New", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Instance", wireType)
 			}
-			var v int
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Instance = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5650,12 +13528,25 @@ func (m *NewCurReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.New = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.State == nil {
+				m.State = &LAValue{}
+			}
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -5677,7 +13568,7 @@ func (m *NewCurReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Read) Unmarshal(dAtA []byte) error {
+func (m *DWriteN) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5700,15 +13591,46 @@ func (m *Read) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Read: wiretype end group for non-group")
+			return fmt.Errorf("proto: DWriteN: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Read: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DWriteN: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5732,10 +13654,41 @@ func (m *Read) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Conf == nil {
-				m.Conf = &Conf{}
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Keys == nil {
+				m.Keys = &Keys{}
+			}
+			if err := m.Keys.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5760,7 +13713,7 @@ func (m *Read) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReadReply) Unmarshal(dAtA []byte) error {
+func (m *DReadReply) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5783,15 +13736,15 @@ func (m *ReadReply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReadReply: wiretype end group for non-group")
+			return fmt.Errorf("proto: DReadReply: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReadReply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DReadReply: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field States", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5815,16 +13768,14 @@ func (m *ReadReply) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.State == nil {
-				m.State = &State{}
-			}
-			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.States = append(m.States, &State{})
+			if err := m.States[len(m.States)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5848,10 +13799,8 @@ func (m *ReadReply) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Cur == nil {
-				m.Cur = &ConfReply{}
-			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5876,7 +13825,7 @@ func (m *ReadReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WriteS) Unmarshal(dAtA []byte) error {
+func (m *DNewState) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5899,17 +13848,17 @@ func (m *WriteS) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WriteS: wiretype end group for non-group")
+			return fmt.Errorf("proto: DNewState: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WriteS: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DNewState: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -5919,28 +13868,26 @@ func (m *WriteS) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.State == nil {
-				m.State = &State{}
-			}
-			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field States", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5964,10 +13911,8 @@ func (m *WriteS) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Conf == nil {
-				m.Conf = &Conf{}
-			}
-			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.States = append(m.States, &State{})
+			if err := m.States[len(m.States)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5992,7 +13937,7 @@ func (m *WriteS) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WriteN) Unmarshal(dAtA []byte) error {
+func (m *DNewStateReply) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6015,32 +13960,13 @@ func (m *WriteN) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WriteN: wiretype end group for non-group")
+			return fmt.Errorf("proto: DNewStateReply: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WriteN: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DNewStateReply: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
-			}
-			m.CurC = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowDcSmartMerge
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CurC |= (uint32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
 			}
@@ -6066,10 +13992,8 @@ func (m *WriteN) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Next == nil {
-				m.Next = &blueprints.Blueprint{}
-			}
-			if err := m.Next.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6094,7 +14018,7 @@ func (m *WriteN) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WriteNReply) Unmarshal(dAtA []byte) error {
+func (m *SpSnProp) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6117,17 +14041,17 @@ func (m *WriteNReply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WriteNReply: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpSnProp: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WriteNReply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpSnProp: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6137,30 +14061,28 @@ func (m *WriteNReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Cur == nil {
-				m.Cur = &ConfReply{}
-			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
 			}
-			var msglen int
+			m.Level = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6170,28 +14092,14 @@ func (m *WriteNReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Level |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthDcSmartMerge
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.State == nil {
-				m.State = &State{}
-			}
-			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Prop", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6215,10 +14123,10 @@ func (m *WriteNReply) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.LAState == nil {
-				m.LAState = &blueprints.Blueprint{}
+			if m.Prop == nil {
+				m.Prop = &blueprints.Blueprint{}
 			}
-			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Prop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6243,7 +14151,7 @@ func (m *WriteNReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LAProposal) Unmarshal(dAtA []byte) error {
+func (m *SpSnReply) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6266,48 +14174,15 @@ func (m *LAProposal) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LAProposal: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpSnReply: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LAProposal: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpSnReply: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Conf", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowDcSmartMerge
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthDcSmartMerge
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Conf == nil {
-				m.Conf = &Conf{}
-			}
-			if err := m.Conf.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prop", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Vals", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6331,10 +14206,8 @@ func (m *LAProposal) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Prop == nil {
-				m.Prop = &blueprints.Blueprint{}
-			}
-			if err := m.Prop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Vals = append(m.Vals, &blueprints.Blueprint{})
+			if err := m.Vals[len(m.Vals)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6359,7 +14232,7 @@ func (m *LAProposal) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LAReply) Unmarshal(dAtA []byte) error {
+func (m *Ballot) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6382,17 +14255,36 @@ func (m *LAReply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LAReply: wiretype end group for non-group")
+			return fmt.Errorf("proto: Ballot: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LAReply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Ballot: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
+			}
+			m.Rnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rnd |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
-			var msglen int
+			m.Id = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6402,30 +14294,16 @@ func (m *LAReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Id |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthDcSmartMerge
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Cur == nil {
-				m.Cur = &ConfReply{}
-			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Addr", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6435,25 +14313,40 @@ func (m *LAReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.LAState == nil {
-				m.LAState = &blueprints.Blueprint{}
+			m.Addr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lease", wireType)
 			}
-			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Lease = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Lease |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -6475,7 +14368,7 @@ func (m *LAReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NewState) Unmarshal(dAtA []byte) error {
+func (m *Lease) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6498,17 +14391,17 @@ func (m *NewState) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NewState: wiretype end group for non-group")
+			return fmt.Errorf("proto: Lease: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NewState: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Lease: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Granted", wireType)
 			}
-			m.CurC = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6518,14 +14411,15 @@ func (m *NewState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CurC |= (uint32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Granted = bool(v != 0)
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Holder", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6549,16 +14443,16 @@ func (m *NewState) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.State == nil {
-				m.State = &State{}
+			if m.Holder == nil {
+				m.Holder = &Ballot{}
 			}
-			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Holder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6582,10 +14476,10 @@ func (m *NewState) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.LAState == nil {
-				m.LAState = &blueprints.Blueprint{}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
 			}
-			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6610,7 +14504,7 @@ func (m *NewState) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NewStateReply) Unmarshal(dAtA []byte) error {
+func (m *StatusRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6633,17 +14527,67 @@ func (m *NewStateReply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NewStateReply: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatusRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NewStateReply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegisterStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcSmartMerge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegisterStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegisterStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6653,30 +14597,26 @@ func (m *NewStateReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Cur == nil {
-				m.Cur = &blueprints.Blueprint{}
-			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Key = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
-			var msglen int
+			m.Timestamp = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6686,23 +14626,30 @@ func (m *NewStateReply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Timestamp |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthDcSmartMerge
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Writer", wireType)
 			}
-			m.Next = append(m.Next, &blueprints.Blueprint{})
-			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Writer = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Writer |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -6724,7 +14671,7 @@ func (m *NewStateReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CV) Unmarshal(dAtA []byte) error {
+func (m *PaxosStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6747,13 +14694,44 @@ func (m *CV) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CV: wiretype end group for non-group")
+			return fmt.Errorf("proto: PaxosStatus: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CV: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PaxosStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
 			}
@@ -6772,7 +14750,7 @@ func (m *CV) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 2:
+		case 3:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
 			}
@@ -6799,7 +14777,7 @@ func (m *CV) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Val == nil {
-				m.Val = &blueprints.Blueprint{}
+				m.Val = &CV{}
 			}
 			if err := m.Val.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -6826,7 +14804,7 @@ func (m *CV) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Prepare) Unmarshal(dAtA []byte) error {
+func (m *ServerStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6849,17 +14827,17 @@ func (m *Prepare) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Prepare: wiretype end group for non-group")
+			return fmt.Errorf("proto: ServerStatus: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Prepare: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ServerStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
 			}
-			m.CurC = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6869,16 +14847,30 @@ func (m *Prepare) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CurC |= (uint32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
+			}
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
 			}
-			m.Rnd = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6888,64 +14880,26 @@ func (m *Prepare) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Rnd |= (uint32(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Promise) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowDcSmartMerge
-			}
-			if iNdEx >= l {
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			m.CurC = append(m.CurC[:0], dAtA[iNdEx:postIndex]...)
+			if m.CurC == nil {
+				m.CurC = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Promise: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Promise: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LAState", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6969,18 +14923,18 @@ func (m *Promise) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Cur == nil {
-				m.Cur = &blueprints.Blueprint{}
+			if m.LAState == nil {
+				m.LAState = &blueprints.Blueprint{}
 			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.LAState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rnd", wireType)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
 			}
-			m.Rnd = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -6990,14 +14944,26 @@ func (m *Promise) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Rnd |= (uint32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+			if msglen < 0 {
+				return ErrInvalidLengthDcSmartMerge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Registers", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7021,16 +14987,14 @@ func (m *Promise) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Val == nil {
-				m.Val = &CV{}
-			}
-			if err := m.Val.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Registers = append(m.Registers, &RegisterStatus{})
+			if err := m.Registers[len(m.Registers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Dec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Paxos", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7054,13 +15018,30 @@ func (m *Promise) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Dec == nil {
-				m.Dec = &blueprints.Blueprint{}
-			}
-			if err := m.Dec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Paxos = append(m.Paxos, &PaxosStatus{})
+			if err := m.Paxos[len(m.Paxos)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uptime", wireType)
+			}
+			m.Uptime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcSmartMerge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Uptime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
@@ -7082,7 +15063,7 @@ func (m *Promise) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Propose) Unmarshal(dAtA []byte) error {
+func (m *WatchRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7105,36 +15086,17 @@ func (m *Propose) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Propose: wiretype end group for non-group")
+			return fmt.Errorf("proto: WatchRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Propose: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WatchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CurC", wireType)
-			}
-			m.CurC = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowDcSmartMerge
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CurC |= (uint32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowDcSmartMerge
@@ -7144,24 +15106,20 @@ func (m *Propose) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthDcSmartMerge
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Val == nil {
-				m.Val = &CV{}
-			}
-			if err := m.Val.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Key = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7184,7 +15142,7 @@ func (m *Propose) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Learn) Unmarshal(dAtA []byte) error {
+func (m *WatchUpdate) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7207,15 +15165,15 @@ func (m *Learn) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Learn: wiretype end group for non-group")
+			return fmt.Errorf("proto: WatchUpdate: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Learn: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WatchUpdate: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7239,16 +15197,16 @@ func (m *Learn) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Cur == nil {
-				m.Cur = &blueprints.Blueprint{}
+			if m.State == nil {
+				m.State = &State{}
 			}
-			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Dec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cur", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7272,86 +15230,16 @@ func (m *Learn) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Dec == nil {
-				m.Dec = &blueprints.Blueprint{}
+			if m.Cur == nil {
+				m.Cur = &blueprints.Blueprint{}
 			}
-			if err := m.Dec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Cur.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Learned", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowDcSmartMerge
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Learned = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthDcSmartMerge
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Proposal) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowDcSmartMerge
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prop", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7375,10 +15263,8 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Prop == nil {
-				m.Prop = &blueprints.Blueprint{}
-			}
-			if err := m.Prop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Next = append(m.Next, &blueprints.Blueprint{})
+			if err := m.Next[len(m.Next)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -7403,56 +15289,6 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Ack) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowDcSmartMerge
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Ack: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Ack: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipDcSmartMerge(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthDcSmartMerge
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
 func skipDcSmartMerge(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -7561,53 +15397,94 @@ var (
 func init() { proto1.RegisterFile("dc-smartmerge.proto", fileDescriptorDcSmartMerge) }
 
 var fileDescriptorDcSmartMerge = []byte{
-	// 764 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x54, 0xcd, 0x6e, 0xd3, 0x4a,
-	0x18, 0x8d, 0xe3, 0x24, 0x4d, 0xbe, 0x24, 0xed, 0xed, 0xdc, 0x7b, 0xab, 0x60, 0x54, 0x2b, 0x0c,
-	0x15, 0xaa, 0x44, 0x49, 0x44, 0x00, 0x55, 0xc0, 0x86, 0x34, 0x88, 0x6e, 0xd2, 0xa8, 0x4a, 0xaa,
-	0xc0, 0x06, 0x09, 0xc7, 0x19, 0xd2, 0xa8, 0x49, 0x6c, 0xc6, 0x63, 0x52, 0x76, 0x7d, 0x04, 0x1e,
-	0x83, 0x17, 0xe0, 0x1d, 0xd8, 0x20, 0x75, 0xc9, 0xb2, 0x35, 0x1b, 0x96, 0x3c, 0x02, 0xf2, 0xcc,
-	0xf8, 0xa7, 0x0d, 0x58, 0x59, 0xb0, 0xb2, 0x3d, 0x3e, 0xe7, 0xcc, 0x39, 0xdf, 0x7c, 0xdf, 0xc0,
-	0xbf, 0x43, 0xf3, 0x9e, 0x33, 0x35, 0x28, 0x3b, 0x20, 0x74, 0x44, 0x6a, 0x36, 0xb5, 0x98, 0x85,
-	0xb2, 0xfc, 0xa1, 0x6d, 0x8d, 0xc6, 0xec, 0xd8, 0x1d, 0xd4, 0x4c, 0x6b, 0x5a, 0xa7, 0x64, 0x62,
-	0x0c, 0xea, 0x23, 0x8b, 0xba, 0x53, 0x47, 0x3e, 0x04, 0x58, 0xdb, 0x5d, 0x40, 0x45, 0x7a, 0xf5,
-	0xc1, 0xc4, 0x25, 0x36, 0x1d, 0xcf, 0x98, 0x13, 0x7b, 0x15, 0x44, 0xfc, 0x18, 0xb2, 0x3d, 0x66,
-	0x30, 0x82, 0xca, 0x90, 0xed, 0x1b, 0x13, 0x97, 0x54, 0x94, 0xaa, 0xb2, 0x5d, 0x42, 0xeb, 0x50,
-	0x38, 0x1a, 0x4f, 0x89, 0xc3, 0x8c, 0xa9, 0x5d, 0x49, 0x57, 0x95, 0xed, 0x2c, 0x5a, 0x85, 0xdc,
-	0x4b, 0x3a, 0x66, 0x84, 0x56, 0xd4, 0xaa, 0xb2, 0x5d, 0xc6, 0xb7, 0x20, 0xd3, 0xb2, 0x66, 0x6f,
-	0x51, 0x09, 0x32, 0x47, 0xc7, 0x63, 0x87, 0x13, 0xcb, 0xa8, 0x08, 0x6a, 0xcb, 0xa5, 0x9c, 0x52,
-	0xc6, 0x26, 0x14, 0x7c, 0x48, 0x97, 0xd8, 0x93, 0x0f, 0x08, 0x8b, 0x3f, 0x3e, 0xac, 0xd8, 0xf8,
-	0xbf, 0x16, 0xb3, 0xb2, 0x17, 0xbc, 0xfa, 0x2e, 0x9a, 0x03, 0x8b, 0x32, 0xce, 0xcf, 0xa3, 0xdb,
-	0x90, 0xe9, 0x90, 0x53, 0x56, 0x51, 0xab, 0xea, 0x1f, 0x39, 0xf8, 0x09, 0xe4, 0x3a, 0x64, 0xde,
-	0x72, 0xe9, 0x52, 0x3b, 0x94, 0x20, 0xd3, 0x72, 0x69, 0x4b, 0x1a, 0xd4, 0xa0, 0x28, 0xb8, 0xc2,
-	0x62, 0x11, 0xd4, 0x0e, 0x99, 0x73, 0x81, 0xbc, 0x9f, 0xaf, 0x4b, 0x8c, 0x21, 0xba, 0x21, 0x72,
-	0x4a, 0xd9, 0xa2, 0x28, 0x5c, 0xcd, 0x5f, 0xc2, 0xfb, 0x50, 0xf0, 0x21, 0x82, 0x7c, 0x53, 0x96,
-	0x52, 0x02, 0x4b, 0x12, 0x28, 0xca, 0xbb, 0x19, 0x95, 0xa5, 0xd8, 0xf8, 0x27, 0xa6, 0xc1, 0xb9,
-	0xf8, 0x99, 0xac, 0x6d, 0x2f, 0x59, 0x25, 0xb0, 0x92, 0x5e, 0xb4, 0xf2, 0x54, 0x2a, 0x74, 0xc2,
-	0x84, 0xe2, 0x3c, 0x82, 0x12, 0xa6, 0x13, 0x8a, 0x82, 0xdf, 0x41, 0x51, 0x90, 0x45, 0x92, 0xcd,
-	0x78, 0x1d, 0x17, 0xcc, 0x46, 0x16, 0xd3, 0xbf, 0xb1, 0x78, 0x07, 0x56, 0xda, 0x4d, 0xf1, 0x5b,
-	0x4d, 0xda, 0xb2, 0x0d, 0xd0, 0x6e, 0x1e, 0x52, 0xcb, 0xb6, 0x1c, 0x63, 0x92, 0x50, 0x63, 0x3f,
-	0x80, 0x0f, 0x4b, 0x0e, 0x70, 0xe8, 0xef, 0xba, 0x94, 0xf9, 0x98, 0xbf, 0x44, 0xc5, 0xd7, 0x90,
-	0xef, 0x90, 0xb9, 0xc8, 0x74, 0xb5, 0xa2, 0x7f, 0x25, 0xfe, 0x2b, 0x28, 0x07, 0xf2, 0xcb, 0x4f,
-	0x47, 0x74, 0x96, 0x09, 0xe3, 0xf0, 0x08, 0xd2, 0xad, 0xbe, 0xdf, 0xc9, 0xdd, 0xd9, 0x50, 0x3a,
-	0xc6, 0xa0, 0xf6, 0x8d, 0x49, 0x72, 0xde, 0x2d, 0x58, 0x39, 0xa4, 0xc4, 0x36, 0xe8, 0xf5, 0xb8,
-	0x52, 0x49, 0xcc, 0xcb, 0x7b, 0x1f, 0x65, 0x4d, 0xc7, 0x0e, 0x59, 0xca, 0x70, 0x9c, 0x8b, 0x36,
-	0x84, 0x0b, 0x51, 0x96, 0x42, 0x70, 0x30, 0x7d, 0x5f, 0xe8, 0x39, 0x31, 0x2b, 0x99, 0x24, 0x77,
-	0x75, 0xbe, 0xaf, 0x6d, 0x39, 0xd7, 0xdd, 0x6d, 0xc4, 0xa3, 0x45, 0xa2, 0xf8, 0x0d, 0x64, 0xdb,
-	0xc4, 0xa0, 0xb3, 0xa5, 0x6c, 0x4a, 0x07, 0x49, 0xf5, 0x41, 0x6b, 0xb0, 0xc2, 0x05, 0xc9, 0x90,
-	0x27, 0xc8, 0xe3, 0x3a, 0xe4, 0xc3, 0xf6, 0x0d, 0x7a, 0x34, 0x69, 0x17, 0x9c, 0x05, 0xb5, 0x69,
-	0x9e, 0x34, 0xbe, 0xaa, 0xb0, 0xde, 0x3b, 0x30, 0x66, 0xc3, 0x96, 0x35, 0x73, 0xba, 0x64, 0x34,
-	0x76, 0x18, 0xa1, 0xe8, 0xae, 0xbc, 0x6c, 0xe2, 0xad, 0xaf, 0x05, 0xdd, 0x1b, 0xde, 0x31, 0x38,
-	0x73, 0xf6, 0xb9, 0xa2, 0xa0, 0x1a, 0x64, 0xf9, 0xb8, 0xa2, 0xb2, 0x04, 0x88, 0xbb, 0x43, 0x5b,
-	0xbc, 0x57, 0x04, 0xfe, 0x21, 0x14, 0xc4, 0x78, 0x93, 0x53, 0x76, 0x95, 0xd3, 0xd1, 0xd0, 0x95,
-	0xcf, 0x38, 0xeb, 0x3e, 0xe4, 0x7a, 0x84, 0xf9, 0xf7, 0x6a, 0x40, 0x11, 0x57, 0x65, 0x48, 0x89,
-	0xdd, 0x9c, 0x11, 0x45, 0x0c, 0x35, 0x5a, 0x97, 0x98, 0x68, 0xc6, 0xb5, 0xd5, 0x70, 0x29, 0x4e,
-	0xd9, 0x85, 0x7c, 0x8f, 0x30, 0x31, 0x3c, 0x6b, 0x91, 0x30, 0x5f, 0xd0, 0xfe, 0xbb, 0xb6, 0x10,
-	0x27, 0x36, 0x00, 0xf6, 0x09, 0x0b, 0xba, 0x31, 0x10, 0x97, 0x3d, 0xac, 0x45, 0xdf, 0xfc, 0xbf,
-	0xe4, 0xec, 0x40, 0xae, 0x69, 0x9a, 0xc4, 0x66, 0x31, 0x3c, 0xef, 0x2a, 0x2d, 0x98, 0x62, 0x7e,
-	0xc6, 0x12, 0xbd, 0x05, 0xea, 0x8b, 0xf9, 0x30, 0x74, 0x15, 0x06, 0x01, 0xb9, 0xd0, 0x34, 0x4f,
-	0x70, 0x6a, 0x6f, 0xe7, 0xfc, 0x52, 0x4f, 0x7d, 0xbb, 0xd4, 0x53, 0x17, 0x97, 0xba, 0x72, 0xe6,
-	0xe9, 0xca, 0x27, 0x4f, 0x57, 0xbe, 0x78, 0xba, 0x72, 0xee, 0xe9, 0xca, 0x85, 0xa7, 0x2b, 0x3f,
-	0x3c, 0x3d, 0xf5, 0xd3, 0xd3, 0x95, 0x8f, 0xdf, 0xf5, 0xd4, 0x20, 0xc7, 0xa9, 0x0f, 0x7e, 0x05,
-	0x00, 0x00, 0xff, 0xff, 0xfc, 0x14, 0xf7, 0x0f, 0xf3, 0x07, 0x00, 0x00,
+	// 1419 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xdd, 0x57, 0x4b, 0x73, 0xdb, 0x54,
+	0x14, 0x8e, 0x9f, 0xb1, 0x8f, 0xed, 0x3c, 0xd4, 0x26, 0x63, 0xd4, 0xc6, 0xb4, 0x4a, 0x86, 0xe9,
+	0x40, 0x49, 0x8a, 0x69, 0xc8, 0x4c, 0x19, 0x18, 0x9c, 0xb8, 0xaf, 0xc1, 0x4d, 0x32, 0x76, 0x9b,
+	0x2c, 0x60, 0x81, 0x62, 0xdd, 0x26, 0x2e, 0xb6, 0x24, 0x24, 0x39, 0x6d, 0x58, 0xe5, 0x27, 0xf0,
+	0x33, 0xf8, 0x03, 0x0c, 0x7f, 0x81, 0x65, 0x67, 0xd8, 0xb0, 0x6c, 0xcb, 0x86, 0x61, 0xc5, 0x8e,
+	0x2d, 0xe7, 0xbe, 0xa4, 0x2b, 0x3b, 0x51, 0xdc, 0x2e, 0x59, 0x68, 0xec, 0xab, 0x7b, 0xcf, 0xeb,
+	0x3b, 0xf7, 0x7c, 0xe7, 0x08, 0x2e, 0x59, 0xdd, 0x8f, 0xfd, 0x81, 0xe9, 0x05, 0x8f, 0x88, 0x77,
+	0x48, 0x56, 0x5d, 0xcf, 0x09, 0x1c, 0x2d, 0xc7, 0x7e, 0xf4, 0x95, 0xc3, 0x5e, 0x70, 0x34, 0x3c,
+	0x58, 0xed, 0x3a, 0x83, 0x35, 0x8f, 0xf4, 0xcd, 0x83, 0xb5, 0x43, 0xc7, 0x1b, 0x0e, 0x7c, 0xf1,
+	0xc3, 0x0f, 0xeb, 0x1b, 0x63, 0xa7, 0x22, 0x7d, 0x6b, 0x07, 0xfd, 0x21, 0x71, 0xbd, 0x9e, 0x1d,
+	0xf8, 0xca, 0x5f, 0x2e, 0x68, 0xdc, 0x83, 0x5c, 0x27, 0x30, 0x03, 0xa2, 0x55, 0x20, 0xb7, 0x67,
+	0xe2, 0x6e, 0x35, 0x75, 0x2d, 0x75, 0xa3, 0xac, 0xcd, 0x43, 0xf1, 0x71, 0x6f, 0x40, 0xfc, 0xc0,
+	0x1c, 0xb8, 0xd5, 0x34, 0xbe, 0xca, 0x69, 0x33, 0x90, 0xdf, 0xf7, 0x7a, 0x01, 0xf1, 0xaa, 0x19,
+	0x5c, 0x57, 0xb4, 0x12, 0x64, 0xbe, 0x26, 0x27, 0xd5, 0x2c, 0x2e, 0x8a, 0xc6, 0x75, 0xc8, 0x6e,
+	0x39, 0xf6, 0x53, 0xad, 0x0c, 0xd9, 0xc7, 0x47, 0x3d, 0x5f, 0x68, 0xc1, 0x23, 0x5b, 0x43, 0x8f,
+	0xc9, 0x97, 0x8d, 0x21, 0x14, 0xe9, 0x91, 0x36, 0x71, 0xfb, 0x27, 0x9a, 0xc1, 0x77, 0xe8, 0xb1,
+	0x52, 0x7d, 0x61, 0x55, 0xf1, 0x6b, 0x53, 0xfe, 0xa5, 0x2e, 0x35, 0x0e, 0x1c, 0x2f, 0x60, 0xf2,
+	0x05, 0x6d, 0x19, 0xb2, 0xdb, 0xe4, 0x45, 0x80, 0xd6, 0x33, 0xe7, 0xcb, 0xcc, 0x41, 0xa1, 0x4d,
+	0x9e, 0x91, 0x6e, 0x40, 0x2c, 0xe6, 0x59, 0xc1, 0xb8, 0x03, 0xf9, 0x6d, 0xf2, 0x1c, 0x8d, 0x4d,
+	0x64, 0x13, 0xfd, 0xc7, 0x33, 0x5b, 0xc2, 0x65, 0x1d, 0x4a, 0x5c, 0x96, 0x3b, 0x8d, 0xe1, 0xe0,
+	0x92, 0x29, 0x28, 0x18, 0x3f, 0x42, 0xb6, 0x4d, 0x4c, 0x4b, 0x7b, 0x8f, 0x47, 0x2e, 0xd4, 0x96,
+	0x38, 0xae, 0xab, 0x0c, 0x0c, 0xdc, 0x42, 0x84, 0x7c, 0xa6, 0x2c, 0xda, 0xa2, 0xaf, 0xb4, 0xab,
+	0x90, 0xdf, 0x34, 0xfb, 0x7d, 0x27, 0x60, 0x60, 0x96, 0xea, 0x65, 0xb1, 0xc9, 0x93, 0x81, 0x5e,
+	0x34, 0xfc, 0x9d, 0xa7, 0x2c, 0x82, 0x1c, 0x8d, 0x69, 0x8f, 0x78, 0x7e, 0xcf, 0xb1, 0xfd, 0x6a,
+	0x8e, 0x42, 0x6f, 0xfc, 0x9a, 0x82, 0x22, 0x35, 0xce, 0xdd, 0xba, 0x22, 0x72, 0x28, 0x5c, 0x88,
+	0xab, 0x5a, 0x8a, 0x52, 0x50, 0xaa, 0xcf, 0x29, 0xde, 0x71, 0x59, 0xf4, 0x83, 0x9d, 0xf3, 0x05,
+	0xac, 0xa3, 0xc2, 0xd3, 0xbb, 0x9e, 0x33, 0xe8, 0xf9, 0x84, 0xb9, 0x32, 0xba, 0x5d, 0x8b, 0x39,
+	0x36, 0x2e, 0x3e, 0x4f, 0x33, 0x3e, 0x70, 0x4d, 0x96, 0x8d, 0x3c, 0x43, 0xed, 0x2b, 0x71, 0x89,
+	0x3a, 0xc9, 0x5e, 0x4b, 0x50, 0xd3, 0x63, 0xa0, 0x1a, 0x7b, 0x42, 0xc3, 0x76, 0x98, 0x2b, 0x7e,
+	0xd7, 0xe4, 0xf5, 0x48, 0x27, 0xa5, 0x57, 0x66, 0x24, 0x33, 0x96, 0x11, 0xe3, 0xdf, 0x14, 0x94,
+	0xb8, 0x62, 0x8e, 0xcc, 0x92, 0x7a, 0x5b, 0x92, 0x80, 0x4b, 0x9f, 0x11, 0xf9, 0x07, 0x30, 0xdd,
+	0x6a, 0xf0, 0xf0, 0x32, 0x49, 0xfe, 0x2c, 0x43, 0xa1, 0xd5, 0x60, 0x75, 0xe7, 0x23, 0xc2, 0x54,
+	0xcf, 0xbc, 0xd0, 0xd3, 0x6a, 0x3c, 0xb4, 0xb1, 0xfa, 0xec, 0x2e, 0x83, 0x59, 0x64, 0xe1, 0x6c,
+	0x98, 0x31, 0x4b, 0x0f, 0x7a, 0x7e, 0xe0, 0x78, 0x27, 0x08, 0xf2, 0xf8, 0xf6, 0xfb, 0x6a, 0x16,
+	0xa6, 0xc7, 0x0f, 0x18, 0x2d, 0x80, 0x56, 0x03, 0x2d, 0xb8, 0x8e, 0x6f, 0xf6, 0x93, 0xee, 0x33,
+	0x42, 0x4c, 0x8f, 0x25, 0x42, 0x6c, 0xec, 0xd2, 0xd0, 0x27, 0x82, 0x50, 0x01, 0x29, 0x51, 0xe3,
+	0x69, 0x1a, 0x0a, 0x58, 0x77, 0x61, 0x69, 0x28, 0x49, 0xff, 0xff, 0x64, 0x81, 0xd6, 0x03, 0xbb,
+	0x7e, 0xd5, 0xc2, 0x78, 0x3d, 0x18, 0xcf, 0xa0, 0x22, 0x11, 0x98, 0x9c, 0x3f, 0xa3, 0x8a, 0x98,
+	0x90, 0x30, 0x33, 0xac, 0x44, 0xd7, 0x21, 0xbd, 0xb5, 0x47, 0xb9, 0xae, 0x6d, 0x5b, 0xcc, 0x40,
+	0x85, 0x5a, 0x43, 0x78, 0x92, 0xb3, 0xb4, 0x42, 0xb9, 0x82, 0xb8, 0xa6, 0x37, 0x9a, 0x23, 0xa1,
+	0x29, 0xcd, 0x98, 0xeb, 0x38, 0x64, 0x94, 0x89, 0x42, 0x50, 0x65, 0xb5, 0x45, 0xee, 0x05, 0x4f,
+	0x65, 0x51, 0x5e, 0xa7, 0x3d, 0xaa, 0xa8, 0x49, 0xba, 0x82, 0xa1, 0xce, 0xf1, 0x6e, 0x8d, 0xd9,
+	0xc5, 0x1b, 0x3e, 0xea, 0xdd, 0xa2, 0x1a, 0x5a, 0xa4, 0xd4, 0xf8, 0x0e, 0x72, 0x2d, 0x62, 0x7a,
+	0xf6, 0x44, 0x6e, 0x0a, 0x0f, 0x12, 0xa9, 0x67, 0x16, 0x2f, 0x23, 0x55, 0x18, 0xe2, 0xbc, 0xc6,
+	0x2e, 0x14, 0x2f, 0x3a, 0x59, 0x59, 0x49, 0x56, 0x8c, 0xcf, 0x21, 0xd3, 0xe8, 0x7e, 0xcf, 0x33,
+	0x66, 0xf5, 0x3c, 0xcc, 0x19, 0x6f, 0x45, 0xb4, 0x33, 0xa3, 0x6a, 0x8b, 0x78, 0x02, 0x28, 0x0d,
+	0x0b, 0x9a, 0xad, 0x1b, 0x96, 0xc5, 0xbb, 0x75, 0x11, 0xad, 0x31, 0xe6, 0x93, 0x5d, 0x9b, 0x0a,
+	0x16, 0xe9, 0xe2, 0xae, 0x80, 0xb7, 0x48, 0xdb, 0x6d, 0xdb, 0xb4, 0x0f, 0x89, 0x70, 0x8f, 0x15,
+	0x0f, 0x2b, 0x0a, 0xd6, 0xd4, 0x4f, 0x5c, 0x22, 0x84, 0xc2, 0x49, 0x81, 0xf7, 0xc8, 0x6f, 0x61,
+	0x56, 0x9c, 0x9b, 0x84, 0x42, 0xd0, 0x79, 0x59, 0x51, 0xc2, 0xec, 0x55, 0x11, 0x3a, 0x4f, 0xeb,
+	0x4c, 0x58, 0x78, 0x4c, 0x25, 0x72, 0x53, 0x59, 0xfc, 0x9d, 0x88, 0x52, 0x96, 0x64, 0x53, 0x49,
+	0x9f, 0xa9, 0xed, 0x0b, 0xca, 0x74, 0x61, 0x45, 0xab, 0xbe, 0xf0, 0xd0, 0x2e, 0x10, 0xdf, 0x87,
+	0xe9, 0xe6, 0x05, 0xbd, 0x27, 0xf3, 0x4e, 0xbd, 0x67, 0x07, 0xa0, 0x19, 0xf5, 0xf3, 0x88, 0xd4,
+	0x52, 0x67, 0xf0, 0xc4, 0x24, 0xb6, 0x8c, 0x0d, 0x28, 0x36, 0xdf, 0x85, 0x32, 0xb1, 0xf8, 0x67,
+	0x9a, 0x71, 0xa6, 0x91, 0xf6, 0x52, 0x49, 0xf6, 0x5a, 0x50, 0xe8, 0xb8, 0x1d, 0x9b, 0x26, 0x72,
+	0xc4, 0x5c, 0x85, 0xd6, 0xd1, 0x31, 0xe9, 0x8b, 0xab, 0xb9, 0x1c, 0xcb, 0xf6, 0x39, 0xda, 0x6e,
+	0x41, 0x91, 0x6a, 0x0b, 0xed, 0x23, 0xfa, 0x7e, 0xb2, 0xfd, 0x2f, 0xe5, 0x38, 0x15, 0xe7, 0x2d,
+	0x80, 0xf4, 0x43, 0xc9, 0x1e, 0x74, 0xa6, 0x0a, 0xcb, 0x81, 0xbb, 0x65, 0x8a, 0xb9, 0x26, 0x63,
+	0x7c, 0x23, 0x96, 0xb4, 0x4a, 0xef, 0x7b, 0xa6, 0x4d, 0xd9, 0x90, 0xd7, 0xd6, 0x12, 0xe4, 0x1f,
+	0x38, 0x7d, 0x59, 0x5b, 0xa5, 0x7a, 0x45, 0xc0, 0x25, 0xcc, 0x09, 0x76, 0x48, 0x0c, 0x67, 0x16,
+	0x2a, 0x14, 0xcf, 0xa1, 0xdf, 0x26, 0x3f, 0x60, 0x8b, 0x09, 0x70, 0x08, 0x9a, 0x69, 0x93, 0x43,
+	0x6c, 0x16, 0xc4, 0xe3, 0x1b, 0xf1, 0xaa, 0xbc, 0x78, 0xf6, 0x46, 0x0d, 0xa5, 0x5d, 0xf3, 0x85,
+	0xe3, 0x0b, 0xf1, 0xf3, 0x09, 0xf7, 0x3c, 0xd2, 0x34, 0xfe, 0x4e, 0x41, 0xb9, 0x43, 0xbc, 0xe3,
+	0xd0, 0x85, 0xb7, 0x9e, 0x8e, 0xdf, 0xa2, 0xbd, 0xf2, 0x1b, 0x94, 0x4d, 0xaa, 0x8e, 0x1b, 0x74,
+	0xa2, 0xe5, 0x98, 0xc8, 0xfe, 0xba, 0x20, 0xbc, 0x1d, 0xc1, 0xea, 0x3a, 0xe4, 0x58, 0xec, 0xa2,
+	0xcd, 0x6a, 0xe2, 0x94, 0x8a, 0x07, 0xc2, 0xf5, 0xc4, 0x0d, 0x10, 0x43, 0xec, 0xb4, 0x34, 0xbd,
+	0x57, 0xa0, 0xbc, 0x6f, 0x06, 0xdd, 0x23, 0x91, 0x80, 0x18, 0xdc, 0x86, 0x8f, 0x73, 0x1f, 0xdd,
+	0x7c, 0xe2, 0x5a, 0xa2, 0x0f, 0x9f, 0x3f, 0x97, 0x1a, 0xea, 0x34, 0x7d, 0x41, 0xb8, 0x49, 0xdf,
+	0x29, 0xf5, 0xdf, 0xf3, 0x30, 0xdf, 0x79, 0x64, 0xda, 0x16, 0x72, 0x97, 0x2f, 0x03, 0xd4, 0x3e,
+	0x12, 0xdf, 0x14, 0xa5, 0x30, 0x72, 0xd3, 0xd2, 0xe7, 0x94, 0x05, 0x2b, 0x09, 0x23, 0x7b, 0xfa,
+	0x4b, 0x35, 0xa5, 0xad, 0x8a, 0x81, 0x41, 0x93, 0x57, 0x92, 0x0f, 0xd6, 0xfa, 0x18, 0x2b, 0x8a,
+	0xf3, 0xb7, 0xa1, 0xc8, 0xc9, 0x0b, 0x9d, 0x8b, 0xcb, 0x6c, 0xeb, 0x5a, 0x6c, 0xa9, 0x4a, 0x7d,
+	0x82, 0x74, 0x41, 0x02, 0xfa, 0xf9, 0x24, 0x45, 0xf8, 0x17, 0x51, 0x28, 0xa2, 0x7c, 0x20, 0x45,
+	0x22, 0x7c, 0x9e, 0xd4, 0xa2, 0x31, 0x4a, 0xf6, 0x06, 0x3d, 0xe2, 0x54, 0x55, 0x64, 0x03, 0xf9,
+	0x83, 0x04, 0x1c, 0xe3, 0xd9, 0x48, 0x31, 0x7b, 0xa1, 0x5f, 0x1e, 0x79, 0xa1, 0x0a, 0xd6, 0x01,
+	0xee, 0x93, 0x40, 0x8e, 0x14, 0x52, 0xb9, 0x18, 0x44, 0xf4, 0x68, 0xcd, 0xf6, 0x85, 0xcc, 0x4d,
+	0xc8, 0x37, 0xba, 0x5d, 0xe2, 0x06, 0xca, 0x79, 0x36, 0x1a, 0xe8, 0x32, 0xd9, 0xac, 0x51, 0x8b,
+	0xd3, 0x2b, 0x90, 0xb9, 0xf7, 0xdc, 0x0a, 0xbd, 0x0a, 0x03, 0x01, 0xf1, 0x02, 0x5b, 0xb2, 0x31,
+	0xa5, 0x61, 0x41, 0xf2, 0x20, 0x79, 0xc7, 0x5c, 0x8c, 0x77, 0x8e, 0x50, 0xe8, 0x52, 0xfc, 0xbd,
+	0x1a, 0xc9, 0x3a, 0xf6, 0x80, 0x28, 0x3f, 0xd2, 0x33, 0xf1, 0x4a, 0x97, 0x48, 0x36, 0x47, 0x6f,
+	0xc1, 0x1d, 0x64, 0xfa, 0x10, 0x3a, 0x99, 0xfa, 0x90, 0xc2, 0xf5, 0x85, 0xd1, 0x37, 0xaa, 0xec,
+	0x67, 0x50, 0xa2, 0x3c, 0xbb, 0x63, 0x93, 0xce, 0x11, 0x72, 0x99, 0x0c, 0x51, 0x32, 0x79, 0x78,
+	0x93, 0x42, 0x32, 0x16, 0x72, 0x1f, 0x42, 0xee, 0x6e, 0x1f, 0xc7, 0x0f, 0x2d, 0x4e, 0x86, 0x2a,
+	0x7c, 0x21, 0xd8, 0xeb, 0xbc, 0xdd, 0x60, 0x51, 0x5e, 0x56, 0x2a, 0x29, 0xe4, 0xc2, 0x10, 0x11,
+	0x95, 0x8b, 0x10, 0xcf, 0xdb, 0x78, 0xb9, 0x69, 0x51, 0x6a, 0x72, 0x5f, 0xad, 0xdf, 0xe8, 0xba,
+	0x46, 0x75, 0x6b, 0x4c, 0xdd, 0x4a, 0x6d, 0xde, 0x7c, 0xf9, 0xba, 0x36, 0xf5, 0x07, 0x3e, 0xaf,
+	0x5e, 0xd7, 0x52, 0xa7, 0x6f, 0x6a, 0xa9, 0x9f, 0xf1, 0xf9, 0x0d, 0x9f, 0x97, 0xf8, 0xbc, 0xc2,
+	0xe7, 0xaf, 0x37, 0xb5, 0xa9, 0x7f, 0xf0, 0xf7, 0xa7, 0x3f, 0x6b, 0x53, 0x07, 0x79, 0xa6, 0xe4,
+	0xd3, 0xff, 0x00, 0x30, 0x41, 0x46, 0x9a, 0x7f, 0x11, 0x00, 0x00,
 }
@@ -0,0 +1,493 @@
+package proto
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// The quorum functions generated by gorums only get the replies, but not the nodes that sent them.
+// Quorums of nodes with different vote weights need both. WeightedConfiguration therefore runs the
+// quorum calls with its own loop, using the generated calls to the single nodes.
+
+// WeightedQuorumSpec is like QuorumSpec, but the quorum functions also get the ids of the nodes,
+// that sent the replies: nids[i] is the id of the node that sent replies[i].
+type WeightedQuorumSpec interface {
+	ReadQF(replies []*ReadReply, nids []uint32) (*ReadReply, bool)
+	WriteQF(replies []*ConfReply, nids []uint32) (*ConfReply, bool)
+	WriteNextQF(replies []*WriteNReply, nids []uint32) (*WriteNReply, bool)
+	SetCurQF(replies []*NewCurReply, nids []uint32) (*NewCurReply, bool)
+	LAPropQF(replies []*LAReply, nids []uint32) (*LAReply, bool)
+	LAPropValueQF(replies []*LAValueReply, nids []uint32) (*LAValueReply, bool)
+	SetStateQF(replies []*NewStateReply, nids []uint32) (*NewStateReply, bool)
+	GetPromiseQF(replies []*Promise, nids []uint32) (*Promise, bool)
+	AcceptQF(replies []*Learn, nids []uint32) (*Learn, bool)
+	DWriteNextQF(replies []*DReadReply, nids []uint32) (*DReadReply, bool)
+	DSetStateQF(replies []*DNewStateReply, nids []uint32) (*DNewStateReply, bool)
+	SpSnOneShotQF(replies []*SpSnReply, nids []uint32) (*SpSnReply, bool)
+	ElectQF(replies []*Lease, nids []uint32) (*Lease, bool)
+}
+
+// A WeightedConfiguration is a set of nodes, on which quorum calls are invoked,
+// using the quorum functions of a WeightedQuorumSpec.
+type WeightedConfiguration struct {
+	nodes []*Node
+	qspec WeightedQuorumSpec
+	hedge *hedge
+}
+
+// hedge describes the nodes contacted by a hedged configuration, if no quorum was reached after a delay.
+type hedge struct {
+	nodes  []*Node
+	qspec  WeightedQuorumSpec // Used for all replies, once the nodes are contacted.
+	delay  time.Duration
+	report func(sent, won bool)
+}
+
+// NewWeightedConfiguration returns a configuration of the nodes ids, that uses the quorum functions of qspec.
+// Unlike NewConfiguration, the configuration is not cached, such that the same nodes can be used with
+// different quorum specifications.
+func (m *Manager) NewWeightedConfiguration(ids []uint32, qspec WeightedQuorumSpec) (*WeightedConfiguration, error) {
+	if len(ids) == 0 {
+		return nil, IllegalConfigError("need at least one node")
+	}
+	m.Lock()
+	defer m.Unlock()
+	nodes := make([]*Node, 0, len(ids))
+	for _, nid := range ids {
+		node, found := m.lookup[nid]
+		if !found {
+			return nil, NodeNotFoundError(nid)
+		}
+		nodes = append(nodes, node)
+	}
+	return &WeightedConfiguration{nodes: nodes, qspec: qspec}, nil
+}
+
+// NodeIDs returns the ids of the nodes in the configuration.
+func (c *WeightedConfiguration) NodeIDs() []uint32 {
+	ids := make([]uint32, len(c.nodes))
+	for i, node := range c.nodes {
+		ids[i] = node.ID()
+	}
+	return ids
+}
+
+// Hedged returns a configuration, that invokes quorum calls on the nodes of c, and if they did not reach a quorum
+// within delay, also on the nodes of full that are not in c. The replies of all nodes are then passed to the
+// quorum functions of full, such that the nodes contacted first and the hedge together form one quorum.
+// Thus the quorum functions of full should not depend on replies, that nodes not in full sent in earlier calls.
+// When a quorum call returns, report is called with whether the hedge was sent, and whether a reply of the hedge
+// completed the quorum. If all nodes of full are in c, Hedged returns c.
+func (c *WeightedConfiguration) Hedged(full *WeightedConfiguration, delay time.Duration, report func(sent, won bool)) *WeightedConfiguration {
+	in := make(map[uint32]bool, len(c.nodes))
+	for _, n := range c.nodes {
+		in[n.id] = true
+	}
+	h := &hedge{qspec: full.qspec, delay: delay, report: report}
+	for _, n := range full.nodes {
+		if !in[n.id] {
+			h.nodes = append(h.nodes, n)
+		}
+	}
+	if len(h.nodes) == 0 {
+		return c
+	}
+	return &WeightedConfiguration{nodes: c.nodes, qspec: c.qspec, hedge: h}
+}
+
+type nodeReply struct {
+	nid   uint32
+	reply interface{}
+	err   error
+}
+
+// quorumCall invokes call on all nodes in the configuration, and passes each reply, its sender and the quorum
+// specification to use to qf, until qf reports a quorum. It returns the ids of the nodes that replied,
+// also those that returned an error. If c is hedged, see Hedged, the hedge is sent after its delay,
+// unless all nodes contacted first replied before.
+func (c *WeightedConfiguration) quorumCall(ctx context.Context, call func(n *Node) (interface{}, error),
+	qf func(qs WeightedQuorumSpec, reply interface{}, nid uint32) bool) (ids []uint32, err error) {
+
+	size := len(c.nodes)
+	if c.hedge != nil {
+		size += len(c.hedge.nodes)
+	}
+	replyChan := make(chan nodeReply, size)
+	send := func(nodes []*Node) {
+		for _, n := range nodes {
+			go func(n *Node) {
+				reply, err := call(n)
+				replyChan <- nodeReply{n.id, reply, err}
+			}(n)
+		}
+	}
+	send(c.nodes)
+
+	var timeout <-chan time.Time
+	var sent, won bool
+	qs, sentCount := c.qspec, len(c.nodes)
+	if c.hedge != nil {
+		timer := time.NewTimer(c.hedge.delay)
+		defer timer.Stop()
+		timeout = timer.C
+		hedged := make(map[uint32]bool, len(c.hedge.nodes))
+		for _, n := range c.hedge.nodes {
+			hedged[n.id] = true
+		}
+		defer func() { c.hedge.report(sent, won) }()
+		defer func() { won = err == nil && len(ids) > 0 && hedged[ids[len(ids)-1]] }()
+	}
+
+	ids = make([]uint32, 0, size)
+	var errCount, replyCount int
+	for {
+		select {
+		case r := <-replyChan:
+			ids = append(ids, r.nid)
+			if r.err != nil {
+				errCount++
+				break
+			}
+			replyCount++
+			if qf(qs, r.reply, r.nid) {
+				return ids, nil
+			}
+		case <-timeout:
+			send(c.hedge.nodes)
+			qs, sentCount, sent = c.hedge.qspec, size, true
+			continue
+		case <-ctx.Done():
+			return ids, QuorumCallError{ctx.Err().Error(), errCount, replyCount}
+		}
+
+		if errCount+replyCount == sentCount {
+			return ids, QuorumCallError{"incomplete call", errCount, replyCount}
+		}
+	}
+}
+
+// Read invokes a Read quorum call on configuration c.
+func (c *WeightedConfiguration) Read(ctx context.Context, args *Read) (*ReadReply_, error) {
+	var (
+		reply   = new(ReadReply_)
+		replies []*ReadReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan readReply, 1)
+			callGRPCRead(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*ReadReply))
+			nids = append(nids, nid)
+			reply.ReadReply, quorum = qs.ReadQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// Write invokes a Write quorum call on configuration c.
+func (c *WeightedConfiguration) Write(ctx context.Context, args *WriteS) (*WriteReply, error) {
+	var (
+		reply   = new(WriteReply)
+		replies []*ConfReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan writeReply, 1)
+			callGRPCWrite(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*ConfReply))
+			nids = append(nids, nid)
+			reply.ConfReply, quorum = qs.WriteQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// WriteNext invokes a WriteNext quorum call on configuration c.
+func (c *WeightedConfiguration) WriteNext(ctx context.Context, args *WriteN) (*WriteNextReply, error) {
+	var (
+		reply   = new(WriteNextReply)
+		replies []*WriteNReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan writeNextReply, 1)
+			callGRPCWriteNext(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*WriteNReply))
+			nids = append(nids, nid)
+			reply.WriteNReply, quorum = qs.WriteNextQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// SetCur invokes a SetCur quorum call on configuration c.
+func (c *WeightedConfiguration) SetCur(ctx context.Context, args *NewCur) (*SetCurReply, error) {
+	var (
+		reply   = new(SetCurReply)
+		replies []*NewCurReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan setCurReply, 1)
+			callGRPCSetCur(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*NewCurReply))
+			nids = append(nids, nid)
+			reply.NewCurReply, quorum = qs.SetCurQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// LAProp invokes a LAProp quorum call on configuration c.
+func (c *WeightedConfiguration) LAProp(ctx context.Context, args *LAProposal) (*LAPropReply, error) {
+	var (
+		reply   = new(LAPropReply)
+		replies []*LAReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan lAPropReply, 1)
+			callGRPCLAProp(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*LAReply))
+			nids = append(nids, nid)
+			reply.LAReply, quorum = qs.LAPropQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// LAPropValue invokes a LAPropValue quorum call on configuration c.
+func (c *WeightedConfiguration) LAPropValue(ctx context.Context, args *LAValueProposal) (*LAPropValueReply, error) {
+	var (
+		reply   = new(LAPropValueReply)
+		replies []*LAValueReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan lAPropValueReply, 1)
+			callGRPCLAPropValue(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*LAValueReply))
+			nids = append(nids, nid)
+			reply.LAValueReply, quorum = qs.LAPropValueQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// SetState invokes a SetState quorum call on configuration c.
+func (c *WeightedConfiguration) SetState(ctx context.Context, args *NewState) (*SetStateReply, error) {
+	var (
+		reply   = new(SetStateReply)
+		replies []*NewStateReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan setStateReply, 1)
+			callGRPCSetState(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*NewStateReply))
+			nids = append(nids, nid)
+			reply.NewStateReply, quorum = qs.SetStateQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// GetPromise invokes a GetPromise quorum call on configuration c.
+func (c *WeightedConfiguration) GetPromise(ctx context.Context, args *Prepare) (*GetPromiseReply, error) {
+	var (
+		reply   = new(GetPromiseReply)
+		replies []*Promise
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan getPromiseReply, 1)
+			callGRPCGetPromise(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*Promise))
+			nids = append(nids, nid)
+			reply.Promise, quorum = qs.GetPromiseQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// Accept invokes a Accept quorum call on configuration c.
+func (c *WeightedConfiguration) Accept(ctx context.Context, args *Propose) (*AcceptReply, error) {
+	var (
+		reply   = new(AcceptReply)
+		replies []*Learn
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan acceptReply, 1)
+			callGRPCAccept(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*Learn))
+			nids = append(nids, nid)
+			reply.Learn, quorum = qs.AcceptQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// DWriteNext invokes a DWriteNext quorum call on configuration c.
+func (c *WeightedConfiguration) DWriteNext(ctx context.Context, args *DWriteN) (*DWriteNextReply, error) {
+	var (
+		reply   = new(DWriteNextReply)
+		replies []*DReadReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan dWriteNextReply, 1)
+			callGRPCDWriteNext(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*DReadReply))
+			nids = append(nids, nid)
+			reply.DReadReply, quorum = qs.DWriteNextQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// DSetState invokes a DSetState quorum call on configuration c.
+func (c *WeightedConfiguration) DSetState(ctx context.Context, args *DNewState) (*DSetStateReply, error) {
+	var (
+		reply   = new(DSetStateReply)
+		replies []*DNewStateReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan dSetStateReply, 1)
+			callGRPCDSetState(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*DNewStateReply))
+			nids = append(nids, nid)
+			reply.DNewStateReply, quorum = qs.DSetStateQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// SpSnOneShot invokes a SpSnOneShot quorum call on configuration c.
+func (c *WeightedConfiguration) SpSnOneShot(ctx context.Context, args *SpSnProp) (*SpSnOneShotReply, error) {
+	var (
+		reply   = new(SpSnOneShotReply)
+		replies []*SpSnReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan spSnOneShotReply, 1)
+			callGRPCSpSnOneShot(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*SpSnReply))
+			nids = append(nids, nid)
+			reply.SpSnReply, quorum = qs.SpSnOneShotQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// Elect invokes a Elect quorum call on configuration c.
+func (c *WeightedConfiguration) Elect(ctx context.Context, args *Ballot) (*ElectReply, error) {
+	var (
+		reply   = new(ElectReply)
+		replies []*Lease
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx,
+		func(n *Node) (interface{}, error) {
+			rc := make(chan electReply, 1)
+			callGRPCElect(ctx, n, args, rc)
+			r := <-rc
+			return r.reply, r.err
+		},
+		func(qs WeightedQuorumSpec, rep interface{}, nid uint32) bool {
+			replies = append(replies, rep.(*Lease))
+			nids = append(nids, nid)
+			reply.Lease, quorum = qs.ElectQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
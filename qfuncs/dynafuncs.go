@@ -0,0 +1,55 @@
+package qfuncs
+
+import (
+	pr "github.com/relab/smartmerge/proto"
+)
+
+// DWriteNextQF merges the states and the successor views reported by a read-write quorum.
+func (qs *SMQuorumSpec) DWriteNextQF(replies []*pr.DReadReply, nids []uint32) (*pr.DReadReply, bool) {
+	// Return false, if not enough replies yet.
+	// This rpc is both reading and writing.
+	if qs.size(nids) < qs.rwq {
+		return nil, false
+	}
+
+	lastrep := new(pr.DReadReply)
+	for _, rep := range replies {
+		lastrep.States = pr.MergeStates(lastrep.States, rep.GetStates())
+		lastrep.Next = GetBlueprintSlice(lastrep.Next, rep)
+	}
+
+	return lastrep, true
+}
+
+// DSetStateQF returns the successor views reported by a write quorum.
+func (qs *SMQuorumSpec) DSetStateQF(replies []*pr.DNewStateReply, nids []uint32) (*pr.DNewStateReply, bool) {
+	// Return false, if not enough replies yet.
+	if qs.size(nids) < qs.wq {
+		return nil, false
+	}
+
+	lastrep := new(pr.DNewStateReply)
+	for _, rep := range replies {
+		lastrep.Next = GetBlueprintSlice(lastrep.Next, rep)
+	}
+
+	return lastrep, true
+}
+
+// SpSnOneShotQF returns all values proposed at the level, reported by a read-write quorum.
+func (qs *SMQuorumSpec) SpSnOneShotQF(replies []*pr.SpSnReply, nids []uint32) (*pr.SpSnReply, bool) {
+	// Return false, if not enough replies yet.
+	// This rpc is both reading and writing.
+	if qs.size(nids) < qs.rwq {
+		return nil, false
+	}
+
+	lastrep := new(pr.SpSnReply)
+	for _, rep := range replies {
+		for _, v := range rep.GetVals() {
+			lastrep.Vals = addLearned(lastrep.Vals, v)
+		}
+	}
+
+	return lastrep, true
+}
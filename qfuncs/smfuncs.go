@@ -3,6 +3,7 @@ package qfuncs
 import (
 	"github.com/golang/glog"
 	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/lattice"
 	pr "github.com/relab/smartmerge/proto"
 )
 
@@ -13,15 +14,30 @@ type SMQuorumSpec struct {
 	rq  int //read-quorum size
 	wq  int //write-quorum size
 	rwq int //size including both read and write quorum
+
+	// weights holds the vote weight of each node. If weights is nil, every node has weight 1.
+	// Otherwise, all sizes above are weights, and replies from nodes not in weights are not counted.
+	weights map[uint32]int
 }
 
 func NewSMQSpec(q, n int) *SMQuorumSpec {
+	return NewFlexSMQSpec(ReadQuorum(q, n), WriteQuorum(q, n), n)
+}
+
+// NewFlexSMQSpec returns a quorum spec with read quorums of size rq and write quorums of size wq.
+// Read and write quorums intersect, if rq + wq > n. Calls that both read and write
+// wait for the larger of the two.
+func NewFlexSMQSpec(rq, wq, n int) *SMQuorumSpec {
+	rwq := wq
+	if rq > wq {
+		rwq = rq
+	}
 	return &SMQuorumSpec{
-		q:   q,
+		q:   wq,
 		n:   n,
-		rq:  ReadQuorum(q, n),
-		wq:  WriteQuorum(q, n),
-		rwq: MaxQuorum(q, n),
+		rq:  rq,
+		wq:  wq,
+		rwq: rwq,
 	}
 }
 
@@ -48,8 +64,40 @@ func MaxQuorum(q, n int) int {
 	return ReadQuorum(q, n)
 }
 
+// NewWeightedSMQSpec returns a quorum spec, where a quorum is a set of nodes with enough weight.
+// rq and wq are the weights of a read and a write quorum. Every read quorum
+// intersects every write quorum, if rq + wq is larger than the sum of all weights.
+func NewWeightedSMQSpec(weights map[uint32]int, rq, wq int) *SMQuorumSpec {
+	n := 0
+	for _, w := range weights {
+		n += w
+	}
+	qs := NewFlexSMQSpec(rq, wq, n)
+	qs.weights = weights
+	return qs
+}
+
+// SMQSpecFromBP returns a quorum spec with the read and write quorum sizes of b.
 func SMQSpecFromBP(b *bp.Blueprint) *SMQuorumSpec {
-	return NewSMQSpec(b.Quorum(), b.NSize())
+	return NewFlexSMQSpec(b.ReadQuorum(), b.Quorum(), b.NSize())
+}
+
+// WeightedSMQSpecFromBP returns a quorum spec using the vote weights of the nodes in b.
+// If all nodes in b have weight 1, it is equivalent to SMQSpecFromBP.
+func WeightedSMQSpecFromBP(b *bp.Blueprint) *SMQuorumSpec {
+	return NewWeightedSMQSpec(b.Weights(), b.WeightedReadQuorum(), b.WeightedQuorum())
+}
+
+// size returns the number, or the sum of the weights, of the nodes nids.
+func (qs *SMQuorumSpec) size(nids []uint32) int {
+	if qs.weights == nil {
+		return len(nids)
+	}
+	w := 0
+	for _, id := range nids {
+		w += qs.weights[id]
+	}
+	return w
 }
 
 // ConfResponder is an interface that wraps all messages that return a ConfReply.
@@ -89,14 +137,14 @@ func handleConfReply(old *pr.ConfReply, cr *pr.ConfReply) *pr.ConfReply {
 	return old
 }
 
-func (qs *SMQuorumSpec) FwdQF(replies []*pr.Ack) (*pr.Ack, bool) {
-	if len(replies) < qs.rq {
+func (qs *SMQuorumSpec) FwdQF(replies []*pr.Ack, nids []uint32) (*pr.Ack, bool) {
+	if qs.size(nids) < qs.rq {
 		return nil, false
 	}
 	return replies[0], true
 }
 
-func (qs *SMQuorumSpec) ReadQF(replies []*pr.ReadReply) (*pr.ReadReply, bool) {
+func (qs *SMQuorumSpec) ReadQF(replies []*pr.ReadReply, nids []uint32) (*pr.ReadReply, bool) {
 
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
@@ -108,7 +156,7 @@ func (qs *SMQuorumSpec) ReadQF(replies []*pr.ReadReply) (*pr.ReadReply, bool) {
 	}
 
 	// Return false, if not enough replies yet.
-	if len(replies) < qs.rq {
+	if qs.size(nids) < qs.rq {
 		if glog.V(7) {
 			glog.Infoln("Not enough ReadSReplies yet.")
 		}
@@ -120,13 +168,19 @@ func (qs *SMQuorumSpec) ReadQF(replies []*pr.ReadReply) (*pr.ReadReply, bool) {
 		if lastrep.GetState().Compare(rep.GetState()) == 1 {
 			lastrep.State = rep.GetState()
 		}
+		lastrep.States = pr.MergeStates(lastrep.States, rep.GetStates())
+		if lastrep.GetPromise().Compare(rep.GetPromise()) == 1 {
+			lastrep.Promise = rep.GetPromise()
+		}
+		lastrep.Versions = pr.MergeVersions(lastrep.Versions, rep.GetVersions())
+		lastrep.Compacted = lastrep.Compacted || (rep != nil && rep.Compacted)
 		lastrep.Cur = handleConfResponder(lastrep.Cur, rep) // I think the assignment can be omitted.
 	}
 
 	return lastrep, true
 }
 
-func (qs *SMQuorumSpec) WriteQF(replies []*pr.ConfReply) (*pr.ConfReply, bool) {
+func (qs *SMQuorumSpec) WriteQF(replies []*pr.ConfReply, nids []uint32) (*pr.ConfReply, bool) {
 
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
@@ -139,7 +193,7 @@ func (qs *SMQuorumSpec) WriteQF(replies []*pr.ConfReply) (*pr.ConfReply, bool) {
 
 	// Return false, if not enough replies yet.
 	// This rpc is both reading and writing.
-	if len(replies) < qs.rwq {
+	if qs.size(nids) < qs.rwq {
 		if glog.V(7) {
 			glog.Infoln("Not enough WriteSReplies yet.")
 		}
@@ -148,13 +202,14 @@ func (qs *SMQuorumSpec) WriteQF(replies []*pr.ConfReply) (*pr.ConfReply, bool) {
 
 	lastrep = new(pr.ConfReply)
 	for _, rep := range replies {
+		lastrep.Rejected = lastrep.Rejected || (rep != nil && rep.Rejected)
 		lastrep = handleConfReply(lastrep, rep)
 	}
 
 	return lastrep, true
 }
 
-func (qs *SMQuorumSpec) WriteNextQF(replies []*pr.WriteNReply) (*pr.WriteNReply, bool) {
+func (qs *SMQuorumSpec) WriteNextQF(replies []*pr.WriteNReply, nids []uint32) (*pr.WriteNReply, bool) {
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
 	if checkConfResponder(lastrep) {
@@ -166,25 +221,34 @@ func (qs *SMQuorumSpec) WriteNextQF(replies []*pr.WriteNReply) (*pr.WriteNReply,
 
 	// Return false, if not enough replies yet.
 	// This rpc is both reading and writing.
-	if len(replies) < qs.rwq {
+	if qs.size(nids) < qs.rwq {
 		return nil, false
 	}
 
 	lastrep = new(pr.WriteNReply)
 	for _, rep := range replies {
-		if lastrep.GetState().Compare(rep.GetState()) == 1 {
-			lastrep.State = rep.GetState()
-		}
+		lastrep.States = pr.MergeStates(lastrep.States, rep.GetStates())
+		lastrep.Promises = pr.MergeStates(lastrep.Promises, rep.GetPromises())
+		lastrep.History = pr.MergeVersions(lastrep.History, rep.GetHistory())
+		lastrep.Compacted = pr.MergeStates(lastrep.Compacted, rep.GetCompacted())
 		lastrep.LAState = lastrep.GetLAState().Merge(rep.GetLAState())
+		las, err := lattice.MergeInstances(lastrep.LAValues, rep.GetLAValues())
+		if err != nil {
+			// Without these states, a reconfiguration could lose agreed values.
+			// The call fails, since no quorum of valid replies can be reached.
+			glog.Errorln("WriteN returned invalid lattice agreement states:", err)
+			return nil, false
+		}
+		lastrep.LAValues = las
 		lastrep.Cur = handleConfResponder(lastrep.Cur, rep)
 	}
 
 	return lastrep, true
 }
 
-func (qs *SMQuorumSpec) SetCurQF(replies []*pr.NewCurReply) (*pr.NewCurReply, bool) {
+func (qs *SMQuorumSpec) SetCurQF(replies []*pr.NewCurReply, nids []uint32) (*pr.NewCurReply, bool) {
 	// Return false, if not enough replies yet.
-	if len(replies) < qs.wq {
+	if qs.size(nids) < qs.wq {
 		return nil, false
 	}
 
@@ -196,7 +260,7 @@ func (qs *SMQuorumSpec) SetCurQF(replies []*pr.NewCurReply) (*pr.NewCurReply, bo
 	return replies[0], true
 }
 
-func (qs *SMQuorumSpec) LAPropQF(replies []*pr.LAReply) (*pr.LAReply, bool) {
+func (qs *SMQuorumSpec) LAPropQF(replies []*pr.LAReply, nids []uint32) (*pr.LAReply, bool) {
 
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
@@ -209,7 +273,7 @@ func (qs *SMQuorumSpec) LAPropQF(replies []*pr.LAReply) (*pr.LAReply, bool) {
 
 	// Return false, if not enough replies yet.
 	// This rpc is both reading and writing.
-	if len(replies) < qs.rwq {
+	if qs.size(nids) < qs.rwq {
 		return nil, false
 	}
 
@@ -222,7 +286,51 @@ func (qs *SMQuorumSpec) LAPropQF(replies []*pr.LAReply) (*pr.LAReply, bool) {
 	return lastrep, true
 }
 
-func (qs *SMQuorumSpec) SetStateQF(replies []*pr.NewStateReply) (*pr.NewStateReply, bool) {
+// LAPropValueQF merges the states of the lattice agreement instance, returned by servers that did not accept the proposal.
+func (qs *SMQuorumSpec) LAPropValueQF(replies []*pr.LAValueReply, nids []uint32) (*pr.LAValueReply, bool) {
+
+	// Stop RPC if new current configuration reported.
+	lastrep := replies[len(replies)-1]
+	if checkConfResponder(lastrep) {
+		if glog.V(3) {
+			glog.Infoln("LAPropValue reported new Cur.")
+		}
+		return lastrep, true
+	}
+
+	// Return false, if not enough replies yet.
+	// This rpc is both reading and writing.
+	if qs.size(nids) < qs.rwq {
+		return nil, false
+	}
+
+	lastrep = new(pr.LAValueReply)
+	var st lattice.Value
+	for _, rep := range replies {
+		lastrep.Cur = handleConfResponder(lastrep.Cur, rep)
+		v, err := lattice.Unmarshal(rep.GetState())
+		if err != nil {
+			// Without this state, the proposer could learn a value that is not comparable to others.
+			// The call fails, since no quorum of valid replies can be reached.
+			glog.Errorln("LAPropValue returned invalid state:", err)
+			return nil, false
+		}
+		if st != nil && v != nil && st.Type() != v.Type() {
+			glog.Errorf("LAPropValue returned states of types %s and %s.\n", st.Type(), v.Type())
+			return nil, false
+		}
+		st = lattice.Merge(st, v)
+	}
+	var err error
+	if lastrep.State, err = lattice.Marshal(st); err != nil {
+		glog.Errorln("could not marshal merged state:", err)
+		return nil, false
+	}
+
+	return lastrep, true
+}
+
+func (qs *SMQuorumSpec) SetStateQF(replies []*pr.NewStateReply, nids []uint32) (*pr.NewStateReply, bool) {
 
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
@@ -231,16 +339,19 @@ func (qs *SMQuorumSpec) SetStateQF(replies []*pr.NewStateReply) (*pr.NewStateRep
 	}
 
 	// Return false, if not enough replies yet.
-	if len(replies) < qs.rwq {
+	if qs.size(nids) < qs.rwq {
 		return nil, false
 	}
 
 	next := make([]*bp.Blueprint, 0, 1)
+	rejected := false
 	for _, rep := range replies {
 		next = GetBlueprintSlice(next, rep)
+		rejected = rejected || (rep != nil && rep.Rejected)
 	}
 
 	lastrep.Next = next
+	lastrep.Rejected = rejected
 
 	return lastrep, true
 }
@@ -252,7 +363,7 @@ type NextReport interface {
 	GetNext() []*bp.Blueprint
 }
 
-func (qs *SMQuorumSpec) GetPromiseQF(replies []*pr.Promise) (*pr.Promise, bool) {
+func (qs *SMQuorumSpec) GetPromiseQF(replies []*pr.Promise, nids []uint32) (*pr.Promise, bool) {
 
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
@@ -262,7 +373,7 @@ func (qs *SMQuorumSpec) GetPromiseQF(replies []*pr.Promise) (*pr.Promise, bool)
 
 	// Return false, if not enough replies yet.
 	// This rpc is both reading and writing.
-	if len(replies) < qs.rq {
+	if qs.size(nids) < qs.rq {
 		return nil, false
 	}
 
@@ -290,7 +401,7 @@ func (qs *SMQuorumSpec) GetPromiseQF(replies []*pr.Promise) (*pr.Promise, bool)
 	return lastrep, true
 }
 
-func (qs *SMQuorumSpec) AcceptQF(replies []*pr.Learn) (*pr.Learn, bool) {
+func (qs *SMQuorumSpec) AcceptQF(replies []*pr.Learn, nids []uint32) (*pr.Learn, bool) {
 
 	// Stop RPC if new current configuration reported.
 	lastrep := replies[len(replies)-1]
@@ -300,7 +411,7 @@ func (qs *SMQuorumSpec) AcceptQF(replies []*pr.Learn) (*pr.Learn, bool) {
 
 	// Return false, if not enough replies yet.
 	// This rpc is both reading and writing.
-	if len(replies) < qs.rwq {
+	if qs.size(nids) < qs.rwq {
 		return nil, false
 	}
 
@@ -320,6 +431,31 @@ func (qs *SMQuorumSpec) AcceptQF(replies []*pr.Learn) (*pr.Learn, bool) {
 
 }
 
+// ElectQF grants the lease, if all servers in a quorum granted it.
+// Otherwise it returns the holder with the largest round, and the newest current configuration reported.
+func (qs *SMQuorumSpec) ElectQF(replies []*pr.Lease, nids []uint32) (*pr.Lease, bool) {
+	// Return false, if not enough replies yet.
+	// Any two leases need to intersect.
+	if qs.size(nids) < qs.rwq {
+		return nil, false
+	}
+
+	lastrep := &pr.Lease{Granted: true}
+	for _, rep := range replies {
+		if !rep.Granted {
+			lastrep.Granted = false
+		}
+		if h := rep.GetHolder(); h != nil && (lastrep.Holder == nil || h.Rnd > lastrep.Holder.Rnd) {
+			lastrep.Holder = h
+		}
+		if c := rep.GetCur(); c != nil && (lastrep.Cur == nil || lastrep.Cur.LearnedCompare(c) == 1) {
+			lastrep.Cur = c
+		}
+	}
+
+	return lastrep, true
+}
+
 func GetBlueprintSlice(next []*bp.Blueprint, rep NextReport) []*bp.Blueprint {
 	repNext := rep.GetNext()
 	if repNext == nil {
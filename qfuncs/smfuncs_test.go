@@ -5,10 +5,11 @@ import (
 	"testing"
 
 	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/lattice"
 	pr "github.com/relab/smartmerge/proto"
 )
 
-var qspec pr.QuorumSpec
+var qspec pr.WeightedQuorumSpec
 
 var one = uint32(2)
 var n11 = &bp.Node{Id: one, Version: one}
@@ -24,3 +25,125 @@ func TestQspecFromBP(t *testing.T) {
 	fmt.Printf("%#v\n", qs)
 	fmt.Printf("b1.Quorum %d, b1.Size %d", b1.Quorum(), b1.Size())
 }
+
+func TestWeightedQF(t *testing.T) {
+	// Node 1 has weight 3, nodes 2, 3 and 4 weight 1.
+	wb := &bp.Blueprint{Nodes: []*bp.Node{
+		{Id: 1, Weight: 3}, {Id: 2}, {Id: 3}, {Id: 4},
+	}, FaultTolerance: 1}
+	qs := WeightedSMQSpecFromBP(wb)
+	// Total weight 6, write quorum 4, read quorum 3.
+
+	rep := &pr.NewCurReply{New: true}
+	if _, ok := qs.SetCurQF([]*pr.NewCurReply{rep, rep, rep}, []uint32{2, 3, 4}); ok {
+		t.Error("three light nodes formed a write quorum")
+	}
+	if _, ok := qs.SetCurQF([]*pr.NewCurReply{rep, rep}, []uint32{1, 2}); !ok {
+		t.Error("heavy node and one light node did not form a write quorum")
+	}
+
+	read := new(pr.ReadReply)
+	if _, ok := qs.ReadQF([]*pr.ReadReply{read, read}, []uint32{2, 3}); ok {
+		t.Error("two light nodes formed a read quorum")
+	}
+	if _, ok := qs.ReadQF([]*pr.ReadReply{read}, []uint32{1}); !ok {
+		t.Error("heavy node did not form a read quorum")
+	}
+	if _, ok := qs.ReadQF([]*pr.ReadReply{read, read, read}, []uint32{2, 3, 4}); !ok {
+		t.Error("three light nodes did not form a read quorum")
+	}
+}
+
+func TestFlexQF(t *testing.T) {
+	// 5 nodes, write quorum 2, read quorum 4.
+	qs := NewFlexSMQSpec(4, 2, 5)
+	rep := &pr.NewCurReply{New: true}
+	if _, ok := qs.SetCurQF([]*pr.NewCurReply{rep, rep}, []uint32{1, 2}); !ok {
+		t.Error("two nodes did not form a write quorum")
+	}
+	read := new(pr.ReadReply)
+	if _, ok := qs.ReadQF([]*pr.ReadReply{read, read, read}, []uint32{1, 2, 3}); ok {
+		t.Error("three nodes formed a read quorum")
+	}
+	conf := new(pr.ConfReply)
+	if _, ok := qs.WriteQF([]*pr.ConfReply{conf, conf, conf}, []uint32{1, 2, 3}); ok {
+		t.Error("three nodes formed a read and write quorum")
+	}
+}
+
+func TestLAPropValueQF(t *testing.T) {
+	qs := NewFlexSMQSpec(2, 2, 3)
+	a, _ := lattice.Marshal(lattice.NewSet("a"))
+	b, _ := lattice.Marshal(lattice.NewSet("b"))
+	reps := []*pr.LAValueReply{{State: a}, {}, {State: b}}
+	if _, ok := qs.LAPropValueQF(reps[:1], []uint32{1}); ok {
+		t.Error("a single reply formed a quorum")
+	}
+	rep, ok := qs.LAPropValueQF(reps, []uint32{1, 2, 3})
+	if !ok {
+		t.Fatal("three replies did not form a quorum")
+	}
+	if st, _ := lattice.Unmarshal(rep.State); !lattice.Equal(st, lattice.NewSet("a", "b")) {
+		t.Errorf("got state %v, expected [a b]", st)
+	}
+	if rep, _ = qs.LAPropValueQF(reps[1:2], []uint32{2, 3}); rep.State != nil {
+		t.Errorf("got state %v, although all accepted", rep.State)
+	}
+}
+
+func TestLAPropValueQFInvalidStates(t *testing.T) {
+	qs := NewFlexSMQSpec(2, 2, 3)
+	a, _ := lattice.Marshal(lattice.NewSet("a"))
+	m, _ := lattice.Marshal(lattice.Max(1))
+	bad := &pr.LAValue{Type: "unknown", Value: []byte("b")}
+	for _, st := range []*pr.LAValue{bad, m} {
+		reps := []*pr.LAValueReply{{State: a}, {State: st}}
+		if rep, ok := qs.LAPropValueQF(reps, []uint32{1, 2}); ok {
+			t.Errorf("got reply %v, although the states %v and %v cannot be merged", rep, a, st)
+		}
+	}
+}
+
+func TestWriteNextQFInvalidLAValues(t *testing.T) {
+	qs := NewFlexSMQSpec(2, 2, 3)
+	a, _ := lattice.Marshal(lattice.NewSet("a"))
+	bad := &pr.LAValue{Type: "unknown", Value: []byte("b")}
+	reps := []*pr.WriteNReply{
+		{LAValues: []*pr.LAInstance{{Instance: "s", State: a}}},
+		{LAValues: []*pr.LAInstance{{Instance: "s", State: bad}}},
+	}
+	if rep, ok := qs.WriteNextQF(reps, []uint32{1, 2}); ok {
+		t.Errorf("got reply %v, although the lattice agreement states cannot be merged", rep)
+	}
+}
+
+func TestSpSnOneShotQF(t *testing.T) {
+	qs := NewFlexSMQSpec(2, 2, 3)
+	b2 := &bp.Blueprint{Nodes: []*bp.Node{n11, {Id: 3}}, FaultTolerance: one, Epoch: one}
+	reps := []*pr.SpSnReply{{Vals: []*bp.Blueprint{b1}}, {Vals: []*bp.Blueprint{b2, b1}}}
+	if _, ok := qs.SpSnOneShotQF(reps[:1], []uint32{1}); ok {
+		t.Error("a single reply formed a quorum")
+	}
+	rep, ok := qs.SpSnOneShotQF(reps, []uint32{1, 2})
+	if !ok {
+		t.Fatal("two replies did not form a quorum")
+	}
+	if len(rep.Vals) != 2 || !rep.Vals[0].Equals(b1) || !rep.Vals[1].Equals(b2) {
+		t.Errorf("got values %v, expected b1 and b2 sorted by id", rep.Vals)
+	}
+}
+
+func TestElectQF(t *testing.T) {
+	qs := NewFlexSMQSpec(2, 2, 3)
+	b := &pr.Ballot{Rnd: 2, Id: 1}
+	reps := []*pr.Lease{{Granted: true, Holder: b}, {Holder: &pr.Ballot{Rnd: 3, Id: 2}}, {Granted: true, Holder: b}}
+	if _, ok := qs.ElectQF(reps[:1], []uint32{1}); ok {
+		t.Error("a single reply formed a quorum")
+	}
+	if rep, _ := qs.ElectQF(reps[:2], []uint32{1, 2}); rep.Granted || rep.Holder.Id != 2 {
+		t.Errorf("got %v, expected the lease of 2", rep)
+	}
+	if rep, _ := qs.ElectQF([]*pr.Lease{reps[0], reps[2]}, []uint32{1, 3}); !rep.Granted || rep.Holder != b {
+		t.Errorf("got %v, expected the lease to be granted", rep)
+	}
+}
@@ -0,0 +1,55 @@
+package regserver
+
+import (
+	"sort"
+
+	"github.com/golang/glog"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// prepare handles a Read with a ballot, the first phase of a conditional write.
+// The ballot is promised, if it is larger than the promised ballot and the stored state of the register.
+// Afterwards Write rejects states with a smaller (Timestamp, Writer) for the register.
+// The reply contains the state and the largest promise, such that the client can tell whether its ballot was promised.
+func (rs *RegServer) prepare(rr *pb.Read) (*pb.ReadReply, error) {
+	rs.Lock()
+	defer rs.Unlock()
+	glog.V(5).Infoln("Handling ReadS with ballot")
+
+	cr := rs.handleConf(rr.GetConf(), nil, nil)
+	if cr != nil && cr.Abort {
+		return &pb.ReadReply{Cur: cr}, nil
+	}
+
+	b := rr.Ballot
+	st := rs.state(b.Key)
+	if rs.Promises[b.Key].Compare(b) == 1 && st.Compare(b) == 1 {
+		p := &pb.State{Key: b.Key, Timestamp: b.Timestamp, Writer: b.Writer}
+		if err := rs.commit(&StateUpdate{Promises: map[string]*pb.State{b.Key: p}}); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.ReadReply{State: st, Cur: cr, Promise: rs.Promises[b.Key]}, nil
+}
+
+// promises returns the promised ballots of all registers selected by ks, sorted by key.
+// Unlike states, registers without a promise are omitted.
+func (rs *RegServer) promises(ks *pb.Keys) []*pb.State {
+	return selectStates(rs.Promises, ks)
+}
+
+// selectStates returns the states in m of all registers selected by ks, sorted by key.
+func selectStates(m map[string]*pb.State, ks *pb.Keys) []*pb.State {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		if ks.Contains(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	sts := make([]*pb.State, len(keys))
+	for i, key := range keys {
+		sts[i] = m[key]
+	}
+	return sts
+}
@@ -0,0 +1,121 @@
+package regserver
+
+import (
+	"errors"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// The RPCs below are used by the DynaStore and SpSnStore algorithms.
+// Unlike SmartMerge, these servers do not know the current configuration.
+// Instead they store, for every view, the successor views the clients recorded.
+
+// restrictedKey is the context key, that marks calls by clients, that may not propose views, see Restrict.
+type restrictedKey struct{}
+
+// Restrict returns the context for a call by a client, that may pass on the successors of views it learned,
+// but not propose new successors. DWriteNext rejects such calls, if they contain a successor, that is not yet
+// recorded at the server. SetCur ignores such calls, if the new current configuration is not recorded at the server.
+func Restrict(ctx context.Context) context.Context {
+	return context.WithValue(ctx, restrictedKey{}, true)
+}
+
+// DWriteNext implements the DWriteNext RPC.
+// It adds the blueprints in wn.Next to the successors of the view wn.CurC,
+// and returns all successors, together with the registers selected by wn.Keys.
+// Recording and reading is atomic, such that either a concurrent DSetState
+// reports the new successors, or its states are returned.
+func (rs *RegServer) DWriteNext(ctx context.Context, wn *pb.DWriteN) (*pb.DReadReply, error) {
+	rs.Lock()
+	defer rs.Unlock()
+	glog.V(5).Infoln("Handling DWriteNext")
+
+	c := string(wn.CurC)
+	next, changed := addViews(rs.DNext[c], wn.Next)
+	if changed && ctx.Value(restrictedKey{}) != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "DWriteNext: only operators may propose new successors")
+	}
+	if changed {
+		if err := rs.commit(&StateUpdate{DNext: map[string][]*bp.Blueprint{c: next}}); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.DReadReply{States: rs.states(wn.GetKeys()), Next: next}, nil
+}
+
+// DSetState implements the DSetState RPC.
+// It stores the register states, if they are newer, and returns the successors of the view ns.CurC.
+func (rs *RegServer) DSetState(ctx context.Context, ns *pb.DNewState) (*pb.DNewStateReply, error) {
+	rs.Lock()
+	defer rs.Unlock()
+	glog.V(5).Infoln("Handling DSetState")
+	if ns == nil {
+		return nil, errors.New("Empty DNewState message")
+	}
+
+	u := &StateUpdate{}
+	for _, st := range ns.GetStates() {
+		if st != nil {
+			rs.setState(st, u)
+		}
+	}
+	if len(u.RStates) > 0 {
+		if err := rs.commit(u); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.DNewStateReply{Next: rs.DNext[string(ns.CurC)]}, nil
+}
+
+// SpSnOneShot implements the SpSnOneShot RPC.
+// It adds the proposal to the given level of the speculating snapshot of the view p.CurC,
+// and returns all distinct values proposed at this level.
+func (rs *RegServer) SpSnOneShot(ctx context.Context, p *pb.SpSnProp) (*pb.SpSnReply, error) {
+	rs.Lock()
+	defer rs.Unlock()
+	glog.V(5).Infoln("Handling SpSnOneShot")
+	if p.Level == 0 || p.Prop == nil {
+		return nil, errors.New("SpSnOneShot needs a proposal and a level starting from 1")
+	}
+
+	c := string(p.CurC)
+	// A copy, such that the state is only changed by commit.
+	lvls := append([][]*bp.Blueprint(nil), rs.SpSn[c]...)
+	for len(lvls) < int(p.Level) {
+		lvls = append(lvls, nil)
+	}
+	vals, changed := addViews(lvls[p.Level-1], []*bp.Blueprint{p.Prop})
+	if changed {
+		lvls[p.Level-1] = vals
+		if err := rs.commit(&StateUpdate{SpSn: map[string][][]*bp.Blueprint{c: lvls}}); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.SpSnReply{Vals: vals}, nil
+}
+
+// addViews returns the list views, with the blueprints in add appended, that it did not contain.
+// The list views is not modified. changed reports, if a blueprint was appended.
+func addViews(views, add []*bp.Blueprint) (res []*bp.Blueprint, changed bool) {
+	res = views
+outer:
+	for _, a := range add {
+		for _, v := range res {
+			if v.LearnedEquals(a) {
+				continue outer
+			}
+		}
+		if !changed {
+			res = append(make([]*bp.Blueprint, 0, len(views)+len(add)), views...)
+			changed = true
+		}
+		res = append(res, a)
+	}
+	return res, changed
+}
@@ -0,0 +1,54 @@
+package regserver
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// Clock returns the current time, used for leader leases. It can be replaced in tests.
+var Clock = time.Now
+
+// Elect implements the Elect RPC.
+// The server grants a lease to at most one candidate at a time. A lease is renewed,
+// if it is requested again by the same candidate, with the same or a larger round.
+// After the lease ended, it is granted to the next ballot with a larger round.
+// Candidates that are not part of the current configuration are rejected.
+func (rs *RegServer) Elect(ctx context.Context, b *pb.Ballot) (*pb.Lease, error) {
+	rs.Lock()
+	defer rs.Unlock()
+	glog.V(5).Infoln("Handling Elect")
+
+	now := Clock()
+	if rs.Cur != nil && !contains(rs.Cur.Ids(), b.Id) {
+		return &pb.Lease{Holder: rs.Holder, Cur: rs.Cur}, nil
+	}
+
+	h := rs.Holder
+	if h == nil || (h.Id == b.Id && b.Rnd >= h.Rnd) || (b.Rnd > h.Rnd && !now.Before(rs.leaseEnd)) {
+		rs.Holder = b
+		rs.leaseEnd = now.Add(time.Duration(b.Lease))
+		return &pb.Lease{Granted: true, Holder: b}, nil
+	}
+	return &pb.Lease{Holder: h}, nil
+}
+
+// holder returns the ballot holding a valid lease, or nil.
+func (rs *RegServer) holder() *pb.Ballot {
+	if rs.Holder == nil || !Clock().Before(rs.leaseEnd) {
+		return nil
+	}
+	return rs.Holder
+}
+
+func contains(ids []uint32, id uint32) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package regserver
+
+import (
+	"bufio"
+	bbytes "bytes" // The tests use bytes as a variable name.
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+)
+
+const (
+	walName      = "state.wal"
+	snapName     = "state.snap"
+	snapTmpName  = "state.snap.tmp"
+	recHeaderLen = 8 // 4 bytes length, 4 bytes crc32 checksum.
+)
+
+// MaxRecordLen is the largest update, that is written to the log.
+// A larger length in a record header can only come from a corrupted log.
+var MaxRecordLen = 64 << 20
+
+var errRecordTooLarge = errors.New("update too large")
+
+// FileStorage stores the state of a RegServer in a directory.
+// Updates are appended to a write-ahead log, that is synced on every append.
+// A snapshot replaces the log, when it is written.
+type FileStorage struct {
+	dir string
+	wal *os.File
+}
+
+// OpenFileStorage opens the FileStorage in directory dir.
+// The directory is created if it does not exist.
+func OpenFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	wal, err := os.OpenFile(filepath.Join(dir, walName), os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStorage{dir: dir, wal: wal}, nil
+}
+
+// Append writes the update u to the log and syncs it to disk.
+func (fs *FileStorage) Append(u *StateUpdate) error {
+	var buf bbytes.Buffer
+	buf.Write(make([]byte, recHeaderLen))
+	if err := gob.NewEncoder(&buf).Encode(u); err != nil {
+		return err
+	}
+	rec := buf.Bytes()
+	if len(rec)-recHeaderLen > MaxRecordLen {
+		return errRecordTooLarge
+	}
+	binary.BigEndian.PutUint32(rec[0:4], uint32(len(rec)-recHeaderLen))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(rec[recHeaderLen:]))
+	if _, err := fs.wal.Write(rec); err != nil {
+		return err
+	}
+	return fs.wal.Sync()
+}
+
+// Snapshot writes s to a new snapshot file, and then truncates the log.
+// A crash between the two steps only leaves already applied updates in the log.
+func (fs *FileStorage) Snapshot(s *StateUpdate) error {
+	tmp := filepath.Join(fs.dir, snapTmpName)
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err = gob.NewEncoder(w).Encode(s); err == nil {
+		if err = w.Flush(); err == nil {
+			err = f.Sync()
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, filepath.Join(fs.dir, snapName)); err != nil {
+		return err
+	}
+	if err = fs.syncDir(); err != nil {
+		return err
+	}
+
+	if err = fs.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = fs.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return fs.wal.Sync()
+}
+
+// Recover reads the snapshot and the log. A torn or corrupted record at the end of the
+// log, e.g. from a crash during Append, is discarded. Recover fails, if a corrupted
+// record is followed by other records, since discarding them could lose stable updates.
+func (fs *FileStorage) Recover() (snap *StateUpdate, updates []*StateUpdate, err error) {
+	f, err := os.Open(filepath.Join(fs.dir, snapName))
+	switch {
+	case err == nil:
+		snap = new(StateUpdate)
+		err = gob.NewDecoder(bufio.NewReader(f)).Decode(snap)
+		f.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, nil, err
+	}
+
+	if _, err = fs.wal.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	fi, err := fs.wal.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	r := bufio.NewReader(fs.wal)
+	var good int64
+	header := make([]byte, recHeaderLen)
+	for {
+		if _, err = io.ReadFull(r, header); err != nil {
+			break
+		}
+		n := int64(binary.BigEndian.Uint32(header[0:4]))
+		end := good + recHeaderLen + n
+		if n > int64(MaxRecordLen) {
+			return nil, nil, fmt.Errorf("corrupted log after %d updates: %v", len(updates), errRecordTooLarge)
+		}
+		if end > size {
+			// The record was not completely written.
+			err = io.ErrUnexpectedEOF
+			break
+		}
+		data := make([]byte, n)
+		if _, err = io.ReadFull(r, data); err != nil {
+			return nil, nil, err
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+			err = errors.New("checksum mismatch")
+		} else {
+			u := new(StateUpdate)
+			if err = gob.NewDecoder(bbytes.NewReader(data)).Decode(u); err == nil {
+				updates = append(updates, u)
+				good = end
+				continue
+			}
+		}
+		if end < size {
+			return nil, nil, fmt.Errorf("corrupted log after %d updates: %v", len(updates), err)
+		}
+		break
+	}
+	if err != io.EOF {
+		glog.Warningf("Discarding log after %d updates: %v\n", len(updates), err)
+		if err = fs.wal.Truncate(good); err != nil {
+			return nil, nil, err
+		}
+	}
+	if _, err = fs.wal.Seek(good, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	return snap, updates, nil
+}
+
+// Close closes the log file.
+func (fs *FileStorage) Close() error {
+	return fs.wal.Close()
+}
+
+func (fs *FileStorage) syncDir() error {
+	d, err := os.Open(fs.dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
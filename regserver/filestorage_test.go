@@ -0,0 +1,220 @@
+package regserver
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/lattice"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+func newDurable(t *testing.T, dir string) *RegServer {
+	fs, err := OpenFileStorage(dir)
+	if err != nil {
+		t.Fatal("could not open storage:", err)
+	}
+	rs, err := NewRegServerFromStorage(fs, b1, b1.ID(), false)
+	if err != nil {
+		t.Fatal("could not recover:", err)
+	}
+	return rs
+}
+
+func TestFileStorageRecover(t *testing.T) {
+	for _, interval := range []int{1000, 2} {
+		dir, err := ioutil.TempDir("", "regserver")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		SnapshotInterval = interval
+
+		rs := newDurable(t, dir)
+		st := &pb.State{Value: []byte("x"), Timestamp: 2, Writer: 1}
+		rs.Write(ctx, &pb.WriteS{State: st, Conf: &pb.Conf{This: b1.ID(), Cur: b1.ID()}})
+		rs.WriteNext(ctx, &pb.WriteN{CurC: b1.ID(), Next: b12})
+		rs.LAProp(ctx, &pb.LAProposal{Conf: &pb.Conf{This: b1.ID(), Cur: b1.ID()}, Prop: b12})
+		lav, _ := lattice.Marshal(lattice.NewSet("a"))
+		rs.LAPropValue(ctx, &pb.LAValueProposal{Conf: &pb.Conf{This: b1.ID(), Cur: b1.ID()}, Instance: "s", Prop: lav})
+		rs.GetPromise(ctx, &pb.Prepare{CurC: b12.ID(), Rnd: 257})
+		rs.Accept(ctx, &pb.Propose{CurC: b12.ID(), Val: &pb.CV{Rnd: 257, Val: b123}})
+		rs.SetCur(ctx, &pb.NewCur{Cur: b12, CurC: b12.ID()})
+		rs.DWriteNext(ctx, &pb.DWriteN{CurC: b1.ID(), Next: []*bp.Blueprint{b12}})
+		rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Level: 2, Prop: b123})
+		for ts, v := range []string{"a", "b"} {
+			rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte(v), Timestamp: int32(ts + 1), Writer: 1, Key: "h"}, Conf: &pb.Conf{This: b12.ID(), Cur: b12.ID()}})
+		}
+		if err = rs.CloseStorage(); err != nil {
+			t.Fatal(err)
+		}
+
+		rec := newDurable(t, dir)
+		if rec.RStates[""].Compare(st) != 0 || string(rec.RStates[""].Value) != "x" {
+			t.Errorf("interval %d: recovered RState %v, want %v", interval, rec.RStates[""], st)
+		}
+		if !rec.Cur.Equals(b12) || string(rec.CurC) != string(b12.ID()) {
+			t.Errorf("interval %d: recovered Cur %v, want %v", interval, rec.Cur, b12)
+		}
+		if !rec.LAState.Equals(b12) {
+			t.Errorf("interval %d: recovered LAState %v, want %v", interval, rec.LAState, b12)
+		}
+		if st, err := lattice.Unmarshal(rec.LAValues["s"]); err != nil || !lattice.Equal(st, lattice.NewSet("a")) {
+			t.Errorf("interval %d: recovered LAValues %v", interval, rec.LAValues)
+		}
+		if !rec.NextMap[string(b1.ID())].Equals(b12) {
+			t.Errorf("interval %d: recovered NextMap %v", interval, rec.NextMap)
+		}
+		if rec.Rnd[string(b12.ID())] != 257 || !rec.Val[string(b12.ID())].Val.Equals(b123) {
+			t.Errorf("interval %d: recovered Rnd %v and Val %v", interval, rec.Rnd, rec.Val)
+		}
+		if next := rec.DNext[string(b1.ID())]; len(next) != 1 || !next[0].Equals(b12) {
+			t.Errorf("interval %d: recovered DNext %v", interval, rec.DNext)
+		}
+		if lvls := rec.SpSn[string(b1.ID())]; len(lvls) != 2 || len(lvls[1]) != 1 || !lvls[1][0].Equals(b123) {
+			t.Errorf("interval %d: recovered SpSn %v", interval, rec.SpSn)
+		}
+		if h := rec.History["h"]; len(h) != 1 || string(h[0].Value) != "a" {
+			t.Errorf("interval %d: recovered History %v", interval, rec.History)
+		}
+		rec.CloseStorage()
+	}
+	SnapshotInterval = 1000
+}
+
+func TestFileStorageTornWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rs := newDurable(t, dir)
+	st := &pb.State{Value: nil, Timestamp: 1, Writer: 1}
+	rs.Write(ctx, &pb.WriteS{State: st, Conf: &pb.Conf{This: b1.ID(), Cur: b1.ID()}})
+	rs.CloseStorage()
+
+	// Simulate a crash in the middle of an append.
+	f, err := os.OpenFile(filepath.Join(dir, walName), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 1, 0, 1, 2})
+	f.Close()
+
+	rec := newDurable(t, dir)
+	if rec.RStates[""].Compare(st) != 0 {
+		t.Error("did not recover the state before the torn write")
+	}
+	rec.Write(ctx, &pb.WriteS{State: &pb.State{Timestamp: 2}, Conf: &pb.Conf{This: b1.ID(), Cur: b1.ID()}})
+	rec.CloseStorage()
+
+	rec = newDurable(t, dir)
+	if rec.RStates[""].Timestamp != 2 {
+		t.Error("update after a torn write was lost")
+	}
+	if rec.Cur == nil || !rec.Cur.Equals(b1) {
+		t.Error("initial configuration was not used")
+	}
+	rec.CloseStorage()
+}
+
+func TestFileStorageCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rs := newDurable(t, dir)
+	for ts := 1; ts <= 2; ts++ {
+		rs.Write(ctx, &pb.WriteS{State: &pb.State{Timestamp: int32(ts), Writer: 1}, Conf: &pb.Conf{This: b1.ID(), Cur: b1.ID()}})
+	}
+	rs.CloseStorage()
+
+	// Corrupt the first of the two records.
+	wal := filepath.Join(dir, walName)
+	data, err := ioutil.ReadFile(wal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[recHeaderLen+1] ^= 0xff
+	if err = ioutil.WriteFile(wal, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs, err := OpenFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewRegServerFromStorage(fs, b1, b1.ID(), false); err == nil {
+		t.Error("recovered from a log with a corrupted record before the last one")
+	}
+	fs.Close()
+	if fi, err := os.Stat(wal); err != nil || fi.Size() != int64(len(data)) {
+		t.Error("log was truncated, although the corrupted record was not the last one")
+	}
+
+	// A header, that claims a too large record.
+	data[recHeaderLen+1] ^= 0xff
+	data = append(data, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0)
+	if err = ioutil.WriteFile(wal, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs, err = OpenFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewRegServerFromStorage(fs, b1, b1.ID(), false); err == nil {
+		t.Error("recovered from a log with a too large record")
+	}
+	fs.Close()
+}
+
+// failingStorage is a Storage, whose appends fail.
+type failingStorage struct{}
+
+var errFailing = errors.New("storage failed")
+
+func (failingStorage) Append(u *StateUpdate) error                    { return errFailing }
+func (failingStorage) Snapshot(s *StateUpdate) error                  { return errFailing }
+func (failingStorage) Recover() (*StateUpdate, []*StateUpdate, error) { return nil, nil, nil }
+func (failingStorage) Close() error                                   { return nil }
+
+// TestFailedPersist checks, that updates are not applied, or passed to subscribers, if they cannot be persisted.
+func TestFailedPersist(t *testing.T) {
+	rs, err := NewRegServerFromStorage(failingStorage{}, b1, b1.ID(), false)
+	if err != nil {
+		t.Fatal("could not recover:", err)
+	}
+	var us []*pb.WatchUpdate
+	rs.Subscribe("", func(u *pb.WatchUpdate) { us = append(us, u) })
+	conf := &pb.Conf{This: b1.ID(), Cur: b1.ID()}
+
+	if _, err := rs.Write(ctx, &pb.WriteS{State: &pb.State{Timestamp: 2, Writer: 1}, Conf: conf}); err != errFailing {
+		t.Errorf("Write returned error %v", err)
+	}
+	if _, err := rs.WriteNext(ctx, &pb.WriteN{CurC: b1.ID(), Next: b12}); err != errFailing {
+		t.Errorf("WriteNext returned error %v", err)
+	}
+	if _, err := rs.SetCur(ctx, &pb.NewCur{Cur: b12, CurC: b12.ID()}); err != errFailing {
+		t.Errorf("SetCur returned error %v", err)
+	}
+	if _, err := rs.Read(ctx, &pb.Read{Conf: conf, Ballot: &pb.State{Timestamp: 3, Writer: 1}}); err != errFailing {
+		t.Errorf("Read with ballot returned error %v", err)
+	}
+	rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Level: 1, Prop: b12})
+	rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Level: 1, Prop: b123})
+
+	if rr, _ := rs.Read(ctx, &pb.Read{Conf: conf}); rr.State.Timestamp != 0 {
+		t.Errorf("read returned the state %v, that was not persisted", rr.State)
+	}
+	if !rs.Cur.Equals(b1) || len(rs.Next) != 0 || len(rs.Promises) != 0 || len(rs.SpSn) != 0 || len(rs.History) != 0 {
+		t.Errorf("changes were applied, that were not persisted: Cur %v, Next %v, Promises %v, SpSn %v", rs.Cur, rs.Next, rs.Promises, rs.SpSn)
+	}
+	if len(us) != 1 {
+		t.Errorf("subscriber got updates %v, that were not persisted", us[1:])
+	}
+}
@@ -0,0 +1,137 @@
+package regserver
+
+import (
+	"sort"
+
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// HistorySize is the number of earlier versions a RegServer retains for each register,
+// in addition to its current state. Older versions are discarded.
+var HistorySize = 16
+
+// setState adds st to u as the state of its register, if st is larger than the current state, including the
+// changes already in u. The replaced state is added to the history of the register. It returns whether st was added.
+func (rs *RegServer) setState(st *pb.State, u *StateUpdate) bool {
+	old, written := u.RStates[st.Key]
+	if !written {
+		old, written = rs.RStates[st.Key]
+	}
+	if rs.updatedState(st.Key, u).Compare(st) != 1 {
+		return false
+	}
+	if u.RStates == nil {
+		u.RStates = make(map[string]*pb.State)
+	}
+	u.RStates[st.Key] = st
+	if written {
+		rs.addVersions(st.Key, []*pb.State{old}, nil, u)
+	}
+	return true
+}
+
+// updatedState returns the state of the register key, including the changes in u.
+func (rs *RegServer) updatedState(key string, u *StateUpdate) *pb.State {
+	if st, ok := u.RStates[key]; ok {
+		return st
+	}
+	return rs.state(key)
+}
+
+// addVersions adds the versions vs, sorted from oldest to newest, to the history of the register key.
+// The largest discarded version is raised to c, if c is larger. Versions that are not older than the current state,
+// or not newer than the largest discarded version, are left out. If more than HistorySize versions remain,
+// the oldest are discarded. The new history is added to u, and the state, history and discarded version
+// are taken from u, if it already changed them.
+func (rs *RegServer) addVersions(key string, vs []*pb.State, c *pb.State, u *StateUpdate) {
+	cur := rs.updatedState(key, u)
+	disc, ok := u.Compacted[key]
+	if !ok {
+		disc = rs.Compacted[key]
+	}
+	if disc.Compare(c) == 1 {
+		disc = c
+	}
+	old, ok := u.History[key]
+	if !ok {
+		old = rs.History[key]
+	}
+	merged := pb.MergeVersions(old, vs)
+	h := make([]*pb.State, 0, len(merged))
+	for _, v := range merged {
+		if v.Compare(cur) == 1 && disc.Compare(v) == 1 {
+			h = append(h, v)
+		}
+	}
+	if n := len(h) - HistorySize; n > 0 {
+		disc = h[n-1]
+		h = h[n:]
+	}
+
+	if u.History == nil {
+		u.History = make(map[string][]*pb.State)
+	}
+	u.History[key] = h
+	if disc != nil {
+		if u.Compacted == nil {
+			u.Compacted = make(map[string]*pb.State)
+		}
+		u.Compacted[key] = disc
+	}
+}
+
+// asOf returns the latest version of the register key with a timestamp smaller or equal to ts.
+// It returns compacted, and no state, if this version may have been discarded from the history.
+func (rs *RegServer) asOf(key string, ts int32) (st *pb.State, compacted bool) {
+	if st := rs.state(key); st.Timestamp <= ts {
+		return st, false
+	}
+	h := rs.History[key]
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].Timestamp <= ts {
+			// All discarded versions are older.
+			return h[i], false
+		}
+	}
+	if rs.Compacted[key] != nil {
+		return nil, true
+	}
+	// The register was not yet written as of ts.
+	return &pb.State{Value: make([]byte, 0), Key: key}, false
+}
+
+// versions returns up to n recent versions of the register key, including the current state, sorted from oldest to newest.
+// A register that was never written has no versions.
+func (rs *RegServer) versions(key string, n uint32) []*pb.State {
+	st, written := rs.RStates[key]
+	if !written || n == 0 {
+		return nil
+	}
+	vs := make([]*pb.State, 0, len(rs.History[key])+1)
+	vs = append(append(vs, rs.History[key]...), st)
+	if len(vs) > int(n) {
+		vs = vs[len(vs)-int(n):]
+	}
+	return vs
+}
+
+// history returns the earlier versions of all registers selected by ks, sorted by key, and from oldest to newest.
+func (rs *RegServer) history(ks *pb.Keys) []*pb.State {
+	keys := make([]string, 0, len(rs.History))
+	for key := range rs.History {
+		if ks.Contains(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var vs []*pb.State
+	for _, key := range keys {
+		vs = append(vs, rs.History[key]...)
+	}
+	return vs
+}
+
+// compacted returns the largest discarded versions of all registers selected by ks, sorted by key.
+func (rs *RegServer) compacted(ks *pb.Keys) []*pb.State {
+	return selectStates(rs.Compacted, ks)
+}
@@ -1,12 +1,16 @@
 package regserver
 
 import (
+	bbytes "bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/lattice"
 	l "github.com/relab/smartmerge/leader"
 	pb "github.com/relab/smartmerge/proto"
 	"golang.org/x/net/context"
@@ -14,16 +18,30 @@ import (
 
 type RegServer struct {
 	sync.RWMutex
-	Cur     *bp.Blueprint            //Blueprint of the last installed configuration.
-	CurC    uint32                   //Hash of the last installed configuration.
-	LAState *bp.Blueprint            //Used only for SM-Lattice agreement
-	RState  *pb.State                //Value timestamp stored.
-	Next    []*bp.Blueprint          // A list of new blueprints
-	NextMap map[uint32]*bp.Blueprint //Used only for Consensus based
-	Rnd     map[uint32]uint32        //Used only for Consensus based
-	Val     map[uint32]*pb.CV        //Used only for Consensus based
-	noabort bool                     //
-	Leader  *l.Leader
+	Cur       *bp.Blueprint                //Blueprint of the last installed configuration.
+	CurC      []byte                       //Id of the last installed configuration, see Blueprint.ID.
+	LAState   *bp.Blueprint                //Used only for SM-Lattice agreement
+	LAValues  map[string]*pb.LAValue       //States of the lattice agreement instances, indexed by instance.
+	RStates   map[string]*pb.State         //Value timestamp stored, for each register key.
+	Promises  map[string]*pb.State         //The largest ballot promised to conditional writes, for each register key.
+	History   map[string][]*pb.State       //Earlier versions of each register, sorted from oldest to newest, see HistorySize.
+	Compacted map[string]*pb.State         //The largest version discarded from the history, for each register key.
+	Next      []*bp.Blueprint              // A list of new blueprints
+	NextMap   map[string]*bp.Blueprint     //Used only for Consensus based, indexed by configuration id.
+	Rnd       map[string]uint32            //Used only for Consensus based, indexed by configuration id.
+	Val       map[string]*pb.CV            //Used only for Consensus based, indexed by configuration id.
+	DNext     map[string][]*bp.Blueprint   //Used only for DynaStore and SpSnStore, the successors of each view, indexed by view id.
+	SpSn      map[string][][]*bp.Blueprint //Used only for SpSnStore, the values proposed at each level of a speculating snapshot, indexed by view id.
+	noabort   bool                         //
+	Leader    *l.Leader
+	Holder    *pb.Ballot //The ballot holding the leader lease, see Elect. Leases are not persisted.
+	leaseEnd  time.Time  //The time the lease of Holder ends.
+	started   time.Time  //The time the server was created, see Status.
+
+	watchers []*watcher // Subscriptions to changes, see Subscribe.
+
+	store  Storage // Stable storage, nil if the state is kept only in memory.
+	logged int     // Number of updates appended since the last snapshot.
 }
 
 // PrintState prints the RegServers state, for debugging.
@@ -33,24 +51,34 @@ func (rs *RegServer) PrintState(op string) {
 	fmt.Println("Cur ", rs.Cur)
 	fmt.Println("CurC ", rs.CurC)
 	fmt.Println("LAState ", rs.LAState)
-	fmt.Println("RState ", rs.RState)
+	fmt.Println("LAValues ", rs.LAValues)
+	fmt.Println("RStates ", rs.RStates)
 	fmt.Println("Next", rs.Next)
+	fmt.Println("DNext", rs.DNext)
 }
 
 func NewRegServer(noabort bool) *RegServer {
 	rs := &RegServer{}
 	rs.RWMutex = sync.RWMutex{}
-	rs.RState = &pb.State{Value: make([]byte, 0), Timestamp: int32(0), Writer: uint32(0)}
+	rs.RStates = make(map[string]*pb.State)
+	rs.Promises = make(map[string]*pb.State)
+	rs.History = make(map[string][]*pb.State)
+	rs.Compacted = make(map[string]*pb.State)
+	rs.LAValues = make(map[string]*pb.LAValue)
 	rs.Next = make([]*bp.Blueprint, 0, 5)
-	rs.NextMap = make(map[uint32]*bp.Blueprint, 5)
-	rs.Rnd = make(map[uint32]uint32, 5)
-	rs.Val = make(map[uint32]*pb.CV, 5)
+	rs.NextMap = make(map[string]*bp.Blueprint, 5)
+	rs.Rnd = make(map[string]uint32, 5)
+	rs.Val = make(map[string]*pb.CV, 5)
+	rs.DNext = make(map[string][]*bp.Blueprint)
+	rs.SpSn = make(map[string][][]*bp.Blueprint)
 	rs.noabort = noabort
+	rs.started = Clock()
 	return rs
 }
 
 // NewRegServerWithCur creates a new RegServer with a specific initial configuration.
-func NewRegServerWithCur(cur *bp.Blueprint, curc uint32, noabort bool) *RegServer {
+// curc must be cur.ID().
+func NewRegServerWithCur(cur *bp.Blueprint, curc []byte, noabort bool) *RegServer {
 	rs := NewRegServer(noabort)
 	rs.Cur = cur
 	rs.CurC = curc
@@ -58,15 +86,62 @@ func NewRegServerWithCur(cur *bp.Blueprint, curc uint32, noabort bool) *RegServe
 	return rs
 }
 
+// state returns the state of the register key.
+// A register that was never written has an initial, empty state.
+func (rs *RegServer) state(key string) *pb.State {
+	if st, ok := rs.RStates[key]; ok {
+		return st
+	}
+	return &pb.State{Value: make([]byte, 0), Timestamp: int32(0), Writer: uint32(0), Key: key}
+}
+
+// states returns the states of all registers selected by ks, sorted by key.
+func (rs *RegServer) states(ks *pb.Keys) []*pb.State {
+	if ks == nil {
+		return []*pb.State{rs.state("")}
+	}
+	if !ks.Range {
+		return []*pb.State{rs.state(ks.Key)}
+	}
+	keys := make([]string, 0, len(rs.RStates))
+	for key := range rs.RStates {
+		if ks.Contains(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	sts := make([]*pb.State, len(keys))
+	for i, key := range keys {
+		sts[i] = rs.RStates[key]
+	}
+	return sts
+}
+
+// laInstances returns the states of all lattice agreement instances, sorted by instance.
+func (rs *RegServer) laInstances() []*pb.LAInstance {
+	insts := make([]string, 0, len(rs.LAValues))
+	for inst := range rs.LAValues {
+		insts = append(insts, inst)
+	}
+	sort.Strings(insts)
+	las := make([]*pb.LAInstance, len(insts))
+	for i, inst := range insts {
+		las[i] = &pb.LAInstance{Instance: inst, State: rs.LAValues[inst]}
+	}
+	return las
+}
+
 // handleConf updates the information about
 // blueprints/ configuraitons stored at the server and returns
 // all configurations larger than the current one.
-func (rs *RegServer) handleConf(conf *pb.Conf, n *bp.Blueprint) (cr *pb.ConfReply) {
-	if conf == nil || (conf.This < rs.CurC && !rs.noabort) {
+// A new blueprint n is added to the list of next blueprints in u, which must be committed.
+func (rs *RegServer) handleConf(conf *pb.Conf, n *bp.Blueprint, u *StateUpdate) (cr *pb.ConfReply) {
+	if conf == nil || (bbytes.Compare(conf.This, rs.CurC) < 0 && !rs.noabort) {
 		//The client is using an outdated configuration, abort.
 		return &pb.ConfReply{Cur: rs.Cur, Abort: true}
 	}
 
+	all := rs.Next
 	if n != nil {
 		found := false
 		for _, nxt := range rs.Next {
@@ -76,19 +151,19 @@ func (rs *RegServer) handleConf(conf *pb.Conf, n *bp.Blueprint) (cr *pb.ConfRepl
 			}
 		}
 		if !found {
-			rs.Next = append(rs.Next, n)
+			all = append(append(make([]*bp.Blueprint, 0, len(rs.Next)+1), rs.Next...), n)
+			u.Next, u.NextSet = all, true
 		}
 	}
 
-	next := make([]*bp.Blueprint, 0, len(rs.Next))
-	this := int(conf.This)
-	for _, nxt := range rs.Next {
-		if nxt.Len() > this {
+	next := make([]*bp.Blueprint, 0, len(all))
+	for _, nxt := range all {
+		if bbytes.Compare(nxt.ID(), conf.This) > 0 {
 			next = append(next, nxt)
 		}
 	}
 
-	if conf.Cur < rs.CurC {
+	if bbytes.Compare(conf.Cur, rs.CurC) < 0 {
 		// Inform the client of the new current configuration
 		return &pb.ConfReply{Cur: rs.Cur, Abort: false, Next: next}
 	}
@@ -99,17 +174,29 @@ func (rs *RegServer) handleConf(conf *pb.Conf, n *bp.Blueprint) (cr *pb.ConfRepl
 	return nil
 }
 
-func (rs *RegServer) Read(ctx context.Context, rr *pb.Conf) (*pb.ReadReply, error) {
+// Read implements the Read RPC, that returns the state of one or a range of registers.
+func (rs *RegServer) Read(ctx context.Context, rr *pb.Read) (*pb.ReadReply, error) {
+	if rr.GetBallot() != nil {
+		return rs.prepare(rr)
+	}
 	rs.RLock()
 	defer rs.RUnlock()
 	glog.V(5).Infoln("Handling ReadS")
 
-	cr := rs.handleConf(rr, nil)
+	cr := rs.handleConf(rr.GetConf(), nil, nil)
 	if cr != nil && cr.Abort {
 		return &pb.ReadReply{Cur: cr}, nil
 	}
 
-	return &pb.ReadReply{State: rs.RState, Cur: cr}, nil
+	if ks := rr.GetKeys(); ks != nil && ks.Range {
+		return &pb.ReadReply{States: rs.states(rr.GetKeys()), Cur: cr}, nil
+	}
+	st := rs.states(rr.GetKeys())[0]
+	rep := &pb.ReadReply{State: st, Cur: cr, Versions: rs.versions(st.Key, rr.Versions)}
+	if rr.AsOf > 0 {
+		rep.State, rep.Compacted = rs.asOf(st.Key, rr.AsOf)
+	}
+	return rep, nil
 }
 
 // Write implements the Write RPC, that updates the stored register state.
@@ -119,15 +206,28 @@ func (rs *RegServer) Write(ctx context.Context, wr *pb.WriteS) (*pb.ConfReply, e
 	defer rs.Unlock()
 	glog.V(5).Infoln("Handling WriteS")
 
-	// Update state, if new request has larger timestamp.
-	if rs.RState.Compare(wr.GetState()) == 1 {
-		rs.RState = wr.GetState()
+	rejected := false
+	if st := wr.GetState(); st != nil {
+		switch {
+		case rs.Promises[st.Key].Compare(st) == -1:
+			// A conditional write was promised a larger ballot.
+			rejected = true
+		case rs.state(st.Key).Compare(st) == 1:
+			// Update state, if new request has larger timestamp.
+			u := &StateUpdate{}
+			rs.setState(st, u)
+			if err := rs.commit(u); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if crepl := rs.handleConf(wr.GetConf(), nil); crepl != nil {
-		return crepl, nil
+	crepl := rs.handleConf(wr.GetConf(), nil, nil)
+	if crepl == nil {
+		crepl = &pb.ConfReply{}
 	}
-	return &pb.ConfReply{}, nil
+	crepl.Rejected = rejected
+	return crepl, nil
 }
 
 // WriteNext implements the WriteNext RPC.
@@ -137,14 +237,28 @@ func (rs *RegServer) WriteNext(ctx context.Context, wr *pb.WriteN) (*pb.WriteNRe
 	defer rs.Unlock()
 	glog.V(5).Infoln("Handling WriteN")
 
-	cr := rs.handleConf(&pb.Conf{This: wr.CurC, Cur: wr.CurC}, wr.Next)
+	u := &StateUpdate{}
+	cr := rs.handleConf(&pb.Conf{This: wr.CurC, Cur: wr.CurC}, wr.Next, u)
 	if cr != nil && cr.Abort {
 		return &pb.WriteNReply{Cur: cr}, nil
 	}
 
-	rs.NextMap[wr.CurC] = wr.Next // This is nor necessary for sm, but only for running Consensus using norecontact.
+	if wr.Next != nil {
+		// This is nor necessary for sm, but only for running Consensus using norecontact.
+		u.NextMap = map[string]*bp.Blueprint{string(wr.CurC): wr.Next}
+	}
+	if u.NextSet || u.NextMap != nil {
+		if err := rs.commit(u); err != nil {
+			return nil, err
+		}
+	}
 
-	return &pb.WriteNReply{Cur: cr, State: rs.RState, LAState: rs.LAState}, nil
+	ks := wr.GetKeys()
+	if ks == nil {
+		ks = &pb.Keys{Range: true}
+	}
+	return &pb.WriteNReply{Cur: cr, States: rs.states(ks), LAState: rs.LAState, LAValues: rs.laInstances(), Promises: rs.promises(ks),
+		History: rs.history(ks), Compacted: rs.compacted(ks)}, nil
 }
 
 // LAProp implements the LAProp RPC.
@@ -155,20 +269,21 @@ func (rs *RegServer) LAProp(ctx context.Context, lap *pb.LAProposal) (lar *pb.LA
 	defer rs.Unlock()
 	glog.V(5).Infoln("Handling LAProp")
 
-	cr := rs.handleConf(lap.GetConf(), nil)
+	cr := rs.handleConf(lap.GetConf(), nil, nil)
 	if cr != nil && cr.Abort {
 		return &pb.LAReply{Cur: cr}, nil
 	}
 
-	if rs.LAState.Compare(lap.Prop) == 1 {
+	st, accepted := lattice.Accept(lattice.Blueprint{Blueprint: rs.LAState}, lattice.Blueprint{Blueprint: lap.Prop})
+	if err := rs.commit(&StateUpdate{LAState: st.(lattice.Blueprint).Blueprint}); err != nil {
+		return nil, err
+	}
+	if accepted {
 		glog.V(6).Infoln("LAState Accepted")
-		//Accept
-		rs.LAState = lap.Prop
 		return &pb.LAReply{Cur: cr}, nil
 	}
 
 	//Not Accepted, try again.
-	rs.LAState = rs.LAState.Merge(lap.Prop)
 	if cr != nil {
 		// In this case, we don't need to send the next values, since the client first has to solve LA in this configuration.
 		cr.Next = nil
@@ -176,9 +291,54 @@ func (rs *RegServer) LAProp(ctx context.Context, lap *pb.LAProposal) (lar *pb.LA
 	return &pb.LAReply{Cur: cr, LAState: rs.LAState}, nil
 }
 
+// LAPropValue implements the LAPropValue RPC.
+// LAPropValue is the acceptor of the lattice agreement instance given in the proposal, see lattice.Accept.
+// If the proposal is not accepted, the new state of the instance is returned.
+func (rs *RegServer) LAPropValue(ctx context.Context, lap *pb.LAValueProposal) (*pb.LAValueReply, error) {
+	rs.Lock()
+	defer rs.Unlock()
+	glog.V(5).Infoln("Handling LAPropValue")
+
+	cr := rs.handleConf(lap.GetConf(), nil, nil)
+	if cr != nil && cr.Abort {
+		return &pb.LAValueReply{Cur: cr}, nil
+	}
+
+	old, err := lattice.Unmarshal(rs.LAValues[lap.Instance])
+	if err != nil {
+		return nil, err
+	}
+	prop, err := lattice.Unmarshal(lap.GetProp())
+	if err != nil {
+		return nil, err
+	}
+	if old != nil && prop != nil && old.Type() != prop.Type() {
+		return nil, fmt.Errorf("instance %q has type %s, but the proposal has type %s", lap.Instance, old.Type(), prop.Type())
+	}
+
+	st, accepted := lattice.Accept(old, prop)
+	lav, err := lattice.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+	if err := rs.commit(&StateUpdate{LAValues: map[string]*pb.LAValue{lap.Instance: lav}}); err != nil {
+		return nil, err
+	}
+	if accepted {
+		return &pb.LAValueReply{Cur: cr}, nil
+	}
+
+	if cr != nil {
+		// The client first has to solve LA in this configuration.
+		cr.Next = nil
+	}
+	return &pb.LAValueReply{Cur: cr, State: lav}, nil
+}
+
 // SetState implements the SetState RPC.
-// SetState updates the register and lattice agreement state.
+// SetState updates the registers and lattice agreement state.
 // This method is used to transfer state to a new configuration.
+// Like in Write, the written state is rejected, if a larger ballot was promised for its register.
 func (rs *RegServer) SetState(ctx context.Context, ns *pb.NewState) (*pb.NewStateReply, error) {
 	rs.Lock()
 	defer rs.Unlock()
@@ -187,24 +347,94 @@ func (rs *RegServer) SetState(ctx context.Context, ns *pb.NewState) (*pb.NewStat
 		return nil, errors.New("Empty NewState message")
 	}
 
-	rs.LAState = rs.LAState.Merge(ns.LAState)
-	if rs.RState.Compare(ns.State) == 1 {
-		rs.RState = ns.State
+	u := &StateUpdate{LAState: rs.LAState.Merge(ns.LAState)}
+	for _, st := range ns.GetStates() {
+		if st != nil {
+			rs.setState(st, u)
+		}
+	}
+	// The history is added after the states, such that it only retains versions older than the new states.
+	hist := make(map[string][]*pb.State)
+	for _, v := range ns.GetHistory() {
+		if v != nil {
+			hist[v.Key] = append(hist[v.Key], v)
+		}
+	}
+	disc := make(map[string]*pb.State)
+	for _, c := range ns.GetCompacted() {
+		if c != nil {
+			disc[c.Key] = c
+		}
+	}
+	for key, vs := range hist {
+		rs.addVersions(key, vs, disc[key], u)
+	}
+	for key, c := range disc {
+		if _, ok := hist[key]; !ok {
+			rs.addVersions(key, nil, c, u)
+		}
+	}
+	promised := make(map[string]*pb.State)
+	for _, p := range ns.GetPromises() {
+		if p == nil {
+			continue
+		}
+		old, ok := promised[p.Key]
+		if !ok {
+			old = rs.Promises[p.Key]
+		}
+		if old.Compare(p) == 1 {
+			promised[p.Key] = p
+		}
+	}
+	// Unlike the transferred states, the written state is checked against the promises, like in Write.
+	rejected := false
+	if st := ns.GetWrite(); st != nil {
+		p, ok := promised[st.Key]
+		if !ok {
+			p = rs.Promises[st.Key]
+		}
+		if p.Compare(st) == -1 {
+			// A conditional write was promised a larger ballot.
+			rejected = true
+		} else {
+			rs.setState(st, u)
+		}
+	}
+	lachanged := make(map[string]*pb.LAValue)
+	for _, la := range ns.GetLAValues() {
+		cur, ok := lachanged[la.Instance]
+		if !ok {
+			cur = rs.LAValues[la.Instance]
+		}
+		old := []*pb.LAInstance{{Instance: la.Instance, State: cur}}
+		m, err := lattice.MergeInstances(old, []*pb.LAInstance{la})
+		if err != nil {
+			// Nothing is stored, such that the transfer is not installed with partial state.
+			glog.Errorln("Could not merge lattice agreement state:", err)
+			return nil, err
+		}
+		if !m[0].State.Equal(cur) {
+			lachanged[la.Instance] = m[0].State
+		}
+	}
+	u.Promises, u.LAValues = promised, lachanged
+	if err := rs.commit(u); err != nil {
+		return nil, err
 	}
 
-	if rs.CurC > ns.CurC {
-		return &pb.NewStateReply{Cur: rs.Cur}, nil
+	if bbytes.Compare(rs.CurC, ns.CurC) > 0 {
+		return &pb.NewStateReply{Cur: rs.Cur, Rejected: rejected}, nil
 	}
 
 	next := make([]*bp.Blueprint, 0, len(rs.Next))
-	this := int(ns.CurC)
 	for _, nxt := range rs.Next {
-		if nxt.Len() > this {
+		if bbytes.Compare(nxt.ID(), ns.CurC) > 0 {
 			next = append(next, nxt)
 		}
 	}
 
-	return &pb.NewStateReply{Next: next}, nil
+	return &pb.NewStateReply{Next: next, Rejected: rejected}, nil
 }
 
 // GetPromise implements the GetPromise RPC.
@@ -214,22 +444,25 @@ func (rs *RegServer) GetPromise(ctx context.Context, pre *pb.Prepare) (*pb.Promi
 	defer rs.Unlock()
 	glog.V(5).Infoln("Handling Prepare")
 
-	if pre.CurC < rs.CurC {
+	if bbytes.Compare(pre.CurC, rs.CurC) < 0 {
 		return &pb.Promise{Cur: rs.Cur}, nil
 	}
 
-	if rs.NextMap[pre.CurC] != nil {
+	c := string(pre.CurC)
+	if rs.NextMap[c] != nil {
 		// Something was decided already
-		return &pb.Promise{Dec: rs.NextMap[pre.CurC]}, nil
+		return &pb.Promise{Dec: rs.NextMap[c]}, nil
 	}
 
-	if rnd, ok := rs.Rnd[pre.CurC]; !ok || pre.Rnd > rnd {
+	if rnd, ok := rs.Rnd[c]; !ok || pre.Rnd > rnd {
 		// A Prepare in a new and higher round.
-		rs.Rnd[pre.CurC] = pre.Rnd
-		return &pb.Promise{Val: rs.Val[pre.CurC]}, nil
+		if err := rs.commit(&StateUpdate{Rnd: map[string]uint32{c: pre.Rnd}}); err != nil {
+			return nil, err
+		}
+		return &pb.Promise{Val: rs.Val[c]}, nil
 	}
 
-	return &pb.Promise{Rnd: rs.Rnd[pre.CurC], Val: rs.Val[pre.CurC]}, nil
+	return &pb.Promise{Rnd: rs.Rnd[c], Val: rs.Val[c]}, nil
 }
 
 // Accept implements the Accept RPC.
@@ -239,22 +472,28 @@ func (rs *RegServer) Accept(ctx context.Context, pro *pb.Propose) (lrn *pb.Learn
 	defer rs.Unlock()
 	glog.V(5).Infoln("Handling Accept")
 
-	if pro.CurC < rs.CurC {
+	if bbytes.Compare(pro.CurC, rs.CurC) < 0 {
 		return &pb.Learn{Cur: rs.Cur}, nil
 	}
 
-	if rs.NextMap[pro.CurC] != nil {
+	c := string(pro.CurC)
+	if rs.NextMap[c] != nil {
 		// This instance is decided already
-		return &pb.Learn{Dec: rs.NextMap[pro.CurC]}, nil
+		return &pb.Learn{Dec: rs.NextMap[c]}, nil
 	}
 
-	if rs.Rnd[pro.CurC] > pro.Val.Rnd {
+	if rs.Rnd[c] > pro.Val.Rnd {
 		// Accept in old round.
 		return &pb.Learn{Learned: false}, nil
 	}
 
-	rs.Rnd[pro.CurC] = pro.Val.Rnd
-	rs.Val[pro.CurC] = pro.Val
+	err = rs.commit(&StateUpdate{
+		Rnd: map[string]uint32{c: pro.Val.Rnd},
+		Val: map[string]*pb.CV{c: pro.Val},
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &pb.Learn{Learned: true}, nil
 }
 
@@ -265,7 +504,7 @@ func (rs *RegServer) SetCur(ctx context.Context, nc *pb.NewCur) (*pb.NewCurReply
 	defer rs.Unlock()
 	//defer rs.PrintState("SetCur")
 
-	if nc.CurC == rs.CurC {
+	if bbytes.Equal(nc.CurC, rs.CurC) {
 		return &pb.NewCurReply{New: false}, nil
 	}
 
@@ -273,32 +512,60 @@ func (rs *RegServer) SetCur(ctx context.Context, nc *pb.NewCur) (*pb.NewCurReply
 		return &pb.NewCurReply{New: false}, nil
 	}
 
-	glog.V(3).Infoln("New Current Conf: ", nc.GetCur())
-	rs.Cur = nc.Cur
-	rs.CurC = nc.CurC
+	if ctx.Value(restrictedKey{}) != nil && !rs.recorded(nc.Cur) {
+		// Only install configurations, that an operator proposed, see Restrict.
+		glog.V(3).Infoln("Ignoring unrecorded current configuration from restricted client: ", nc.GetCur())
+		return &pb.NewCurReply{New: false}, nil
+	}
 
+	glog.V(3).Infoln("New Current Conf: ", nc.GetCur())
 	newNext := make([]*bp.Blueprint, 0, len(rs.Next))
 	for _, blp := range rs.Next {
-		if uint32(blp.Len()) > rs.CurC {
+		if bbytes.Compare(blp.ID(), nc.CurC) > 0 {
 			newNext = append(newNext, blp)
 		}
 	}
-	rs.Next = newNext
-
+	if err := rs.commit(&StateUpdate{Cur: nc.Cur, CurC: nc.CurC, Next: newNext, NextSet: true}); err != nil {
+		return nil, err
+	}
 	return &pb.NewCurReply{New: true}, nil
 }
 
+// recorded returns true, if blp was proposed as a next configuration, or decided by consensus, at this server.
+func (rs *RegServer) recorded(blp *bp.Blueprint) bool {
+	for _, nxt := range rs.Next {
+		if blp.LearnedEquals(nxt) {
+			return true
+		}
+	}
+	for _, dec := range rs.NextMap {
+		if blp.LearnedEquals(dec) {
+			return true
+		}
+	}
+	return false
+}
+
 // Fwd implements the Fwd RPC.
 // Fwd receives a reconfiguration request and forwards it to the leader (client)
-// located at the same server.
+// located at the same server, if it is elected. Otherwise the request is redirected
+// to the holder of the leader lease granted by this server.
 func (rs *RegServer) Fwd(ctx context.Context, p *pb.Proposal) (*pb.Ack, error) {
-	if rs.Leader == nil {
-		glog.Errorln("Received Fwd request but have no leader.")
-		return nil, errors.New("Not implemented.")
+	rs.RLock()
+	leader, holder := rs.Leader, rs.holder()
+	rs.RUnlock()
+
+	if leader != nil && leader.IsLeader() {
+		glog.V(4).Infoln("Handling Reconf Proposal")
+		leader.Propose(p.GetProp())
+		return &pb.Ack{}, nil
+	}
+	if holder != nil && (leader == nil || holder.Id != leader.Id) {
+		glog.V(4).Infoln("Redirecting Reconf Proposal to", holder.Id)
+		return &pb.Ack{Redirect: true, Leader: holder.Id, LeaderAddr: holder.Addr}, nil
 	}
-	glog.V(4).Infoln("Handling Reconf Proposal")
-	rs.Leader.Propose(p.GetProp())
-	return &pb.Ack{}, nil
+	glog.Errorln("Received Fwd request but no leader is elected.")
+	return nil, errors.New("No leader elected.")
 }
 
 // AddLeader informs the server about a RPC client located at the same machine.
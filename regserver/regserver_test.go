@@ -3,8 +3,10 @@ package regserver
 import (
 	"encoding/binary"
 	"testing"
+	"time"
 
 	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/lattice"
 	pb "github.com/relab/smartmerge/proto"
 	"golang.org/x/net/context"
 	//"google.golang.org/grpc"
@@ -14,6 +16,9 @@ var ctx = context.Background()
 var bytes = make([]byte, 64)
 
 var one = uint32(1)
+
+// low is smaller than the id of any blueprint.
+var low = []byte{}
 var two = uint32(2)
 var tre = uint32(3)
 
@@ -51,11 +56,11 @@ func TestSetState(t *testing.T) {
 	//Perfectly normal SetState
 	stest, err := rs.SetState(ctx, &pb.NewState{
 		//Cur:     b2,
-		CurC:    uint32(b2.Len()),
-		State:   &pb.State{Value: nil, Timestamp: 2, Writer: 0},
+		CurC:    b2.ID(),
+		States:  []*pb.State{&pb.State{Value: nil, Timestamp: 2, Writer: 0}},
 		LAState: b1,
 	})
-	if err != nil || rs.RState.Compare(&pb.State{Value: nil, Timestamp: 2, Writer: 0}) != 0 || !rs.LAState.Equals(b1) {
+	if err != nil || rs.RStates[""].Compare(&pb.State{Value: nil, Timestamp: 2, Writer: 0}) != 0 || !rs.LAState.Equals(b1) {
 		t.Error("first write did not work.")
 	}
 	if len(stest.Next) != 2 {
@@ -65,11 +70,11 @@ func TestSetState(t *testing.T) {
 	// Set state in Cur.
 	stest, _ = rs.SetState(ctx, &pb.NewState{
 		//Cur:     b2,
-		CurC:    uint32(b2.Len()),
-		State:   &pb.State{Value: nil, Timestamp: 2, Writer: 1},
+		CurC:    b2.ID(),
+		States:  []*pb.State{&pb.State{Value: nil, Timestamp: 2, Writer: 1}},
 		LAState: b2,
 	})
-	if rs.RState.Compare(&pb.State{Value: nil, Timestamp: 2, Writer: 1}) != 0 || !rs.LAState.Equals(b12) {
+	if rs.RStates[""].Compare(&pb.State{Value: nil, Timestamp: 2, Writer: 1}) != 0 || !rs.LAState.Equals(b12) {
 		t.Error("did not set state correctly")
 	}
 	if len(stest.Next) != 2 {
@@ -82,10 +87,10 @@ func TestSetState(t *testing.T) {
 	// Clean next on set state
 	stest, _ = rs.SetState(ctx, &pb.NewState{
 		//Cur:     b12,
-		CurC:    uint32(b12.Len()),
+		CurC:    b12.ID(),
 		LAState: b12x,
 	})
-	if rs.RState.Compare(&pb.State{Value: nil, Timestamp: 2, Writer: 1}) != 0 || !rs.LAState.Equals(b12x) {
+	if rs.RStates[""].Compare(&pb.State{Value: nil, Timestamp: 2, Writer: 1}) != 0 || !rs.LAState.Equals(b12x) {
 		t.Error("did not set state correctly")
 	}
 	if len(rs.Next) != 2 {
@@ -99,16 +104,16 @@ func TestSetState(t *testing.T) {
 	}
 
 	rs.Cur = b12.Copy()
-	rs.CurC = uint32(b12.Len())
+	rs.CurC = b12.ID()
 
 	// Set state in old cur
 	stest, _ = rs.SetState(ctx, &pb.NewState{
 		//Cur:     b2,
-		CurC:    uint32(b2.Len()),
-		State:   &pb.State{Value: nil, Timestamp: 3, Writer: 0},
+		CurC:    b2.ID(),
+		States:  []*pb.State{&pb.State{Value: nil, Timestamp: 3, Writer: 0}},
 		LAState: b123,
 	})
-	if rs.RState.Compare(&pb.State{Value: nil, Timestamp: 3, Writer: 0}) != 0 || !rs.LAState.Equals(b123) {
+	if rs.RStates[""].Compare(&pb.State{Value: nil, Timestamp: 3, Writer: 0}) != 0 || !rs.LAState.Equals(b123) {
 		t.Error("did not set state correctly")
 	}
 	if len(rs.Next) != 2 {
@@ -138,10 +143,10 @@ func TestLAProp(t *testing.T) {
 	}
 
 	rs.Cur = b2
-	rs.CurC = uint32(b2.Len())
+	rs.CurC = b2.ID()
 
 	//Can abort
-	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: b12x, Conf: &pb.Conf{This: one, Cur: one}})
+	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: b12x, Conf: &pb.Conf{This: low, Cur: low}})
 	if rs.LAState != b12 {
 		t.Error("did write on abort")
 	}
@@ -150,7 +155,7 @@ func TestLAProp(t *testing.T) {
 	}
 
 	//Does not abort, but return cur, does not write old value.
-	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: b2, Conf: &pb.Conf{This: uint32(b2.Len()), Cur: uint32(b2.Len())}})
+	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: b2, Conf: &pb.Conf{This: b2.ID(), Cur: b2.ID()}})
 	if stest.Cur.Abort {
 		t.Errorf("laprop did not return correct cur, Abort was %v, Cur was %v.", stest.Cur.Abort, stest.Cur.Cur)
 	}
@@ -165,7 +170,7 @@ func TestLAProp(t *testing.T) {
 	// If noabort is true, does not abort, but sends cur, state and next.
 	rs.Next = []*bp.Blueprint{b12, b12x}
 	rs.noabort = true
-	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: by, Conf: &pb.Conf{Cur: one, This: one}})
+	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: by, Conf: &pb.Conf{Cur: low, This: low}})
 	if stest.Cur.Abort || stest.Cur.Cur != b2 {
 		t.Error("laprop did not return correct cur.")
 	}
@@ -177,7 +182,7 @@ func TestLAProp(t *testing.T) {
 	}
 
 	// Only send next that is large.
-	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: bx, Conf: &pb.Conf{Cur: uint32(b12.Len()), This: uint32(b12.Len())}})
+	stest, _ = rs.LAProp(ctx, &pb.LAProposal{Prop: bx, Conf: &pb.Conf{Cur: b12.ID(), This: b12.ID()}})
 	if stest.Cur.Cur != nil || stest.Cur.Abort {
 		t.Errorf("laprop did not return correct cur, did get %v expecting nil.", stest.Cur)
 	}
@@ -190,6 +195,79 @@ func TestLAProp(t *testing.T) {
 
 }
 
+func TestLAPropValue(t *testing.T) {
+	rs := NewRegServer(false)
+	prop := func(inst string, v lattice.Value, c *pb.Conf) (*pb.LAValueReply, error) {
+		lav, err := lattice.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rs.LAPropValue(ctx, &pb.LAValueProposal{Instance: inst, Prop: lav, Conf: c})
+	}
+
+	// The initial state accepts.
+	stest, err := prop("s", lattice.NewSet("a"), &pb.Conf{})
+	if err != nil || stest.State != nil {
+		t.Errorf("did not accept, error %v, state %v", err, stest.GetState())
+	}
+
+	// An incomparable proposal is merged and returned.
+	stest, err = prop("s", lattice.NewSet("b"), &pb.Conf{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := lattice.Unmarshal(stest.State); !lattice.Equal(st, lattice.NewSet("a", "b")) {
+		t.Errorf("returned state %v, expected [a b]", st)
+	}
+
+	// Instances are independent.
+	if stest, err = prop("m", lattice.Max(3), &pb.Conf{}); err != nil || stest.State != nil {
+		t.Errorf("other instance did not accept, error %v, state %v", err, stest.GetState())
+	}
+
+	// The type of an instance does not change.
+	if _, err = prop("s", lattice.Max(3), &pb.Conf{}); err == nil {
+		t.Error("accepted a proposal of another type")
+	}
+
+	// Aborts in an old configuration, without changing the state.
+	rs.Cur = b2
+	rs.CurC = b2.ID()
+	stest, _ = prop("s", lattice.NewSet("c"), &pb.Conf{This: low, Cur: low})
+	if !stest.Cur.Abort || stest.Cur.Cur != b2 {
+		t.Error("did not abort")
+	}
+	if st, _ := lattice.Unmarshal(rs.LAValues["s"]); !lattice.Equal(st, lattice.NewSet("a", "b")) {
+		t.Errorf("state changed to %v on abort", st)
+	}
+}
+
+// TestLAValuesTransfer checks that WriteNext returns the states of all lattice agreement instances,
+// and SetState merges them.
+func TestLAValuesTransfer(t *testing.T) {
+	rs := NewRegServer(false)
+	a, _ := lattice.Marshal(lattice.NewSet("a"))
+	b, _ := lattice.Marshal(lattice.NewSet("b"))
+	rs.LAPropValue(ctx, &pb.LAValueProposal{Instance: "s", Prop: a, Conf: &pb.Conf{}})
+
+	wn, err := rs.WriteNext(ctx, &pb.WriteN{Next: b12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wn.LAValues) != 1 || wn.LAValues[0].Instance != "s" || !wn.LAValues[0].State.Equal(a) {
+		t.Errorf("WriteNext returned %v", wn.LAValues)
+	}
+
+	rs2 := NewRegServer(false)
+	rs2.LAPropValue(ctx, &pb.LAValueProposal{Instance: "s", Prop: b, Conf: &pb.Conf{}})
+	if _, err = rs2.SetState(ctx, &pb.NewState{LAValues: wn.LAValues}); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := lattice.Unmarshal(rs2.LAValues["s"]); !lattice.Equal(st, lattice.NewSet("a", "b")) {
+		t.Errorf("SetState resulted in state %v, expected [a b]", st)
+	}
+}
+
 func TestWriteNext(t *testing.T) {
 	rs := NewRegServer(false)
 	var bytes = make([]byte, 64)
@@ -206,12 +284,12 @@ func TestWriteNext(t *testing.T) {
 	}
 
 	rs.Cur = b2
-	rs.CurC = uint32(b2.Len())
+	rs.CurC = b2.ID()
 	rs.LAState = b12x
-	rs.RState = s
+	rs.RStates[""] = s
 
 	//Can abort
-	stest, _ = rs.WriteNext(ctx, &pb.WriteN{Next: b12x, CurC: one})
+	stest, _ = rs.WriteNext(ctx, &pb.WriteN{Next: b12x, CurC: low})
 	if len(rs.Next) != 1 {
 		t.Error("did write next on abort")
 	}
@@ -220,11 +298,11 @@ func TestWriteNext(t *testing.T) {
 	}
 
 	//Does not abort, does not write duplicate next.
-	stest, _ = rs.WriteNext(ctx, &pb.WriteN{Next: b12, CurC: uint32(b2.Len())})
+	stest, _ = rs.WriteNext(ctx, &pb.WriteN{Next: b12, CurC: b2.ID()})
 	if stest.Cur.Cur != nil || stest.Cur.Abort {
 		t.Errorf("writeN did not return correct cur, instead %v.", stest.Cur)
 	}
-	if stest.State != s {
+	if stest.States[0] != s {
 		t.Error("writeN did not return state")
 	}
 	if len(rs.Next) != 1 {
@@ -239,11 +317,11 @@ func TestWriteNext(t *testing.T) {
 
 	// If noabort is true, does not abort, but sends cur, state and next.
 	rs.noabort = true
-	stest, _ = rs.WriteNext(ctx, &pb.WriteN{Next: b12x, CurC: one})
+	stest, _ = rs.WriteNext(ctx, &pb.WriteN{Next: b12x, CurC: low})
 	if stest.Cur.Abort || stest.Cur.Cur != b2 {
 		t.Error("writeN did not return correct cur.")
 	}
-	if stest.State != s {
+	if stest.States[0] != s {
 		t.Error("writeN returned wrong state")
 	}
 	if len(stest.Cur.Next) != 2 {
@@ -257,7 +335,7 @@ func TestWriteNext(t *testing.T) {
 	}
 
 	// Only send next that is large.
-	stest, _ = rs.WriteNext(ctx, &pb.WriteN{CurC: uint32(b12.Len())})
+	stest, _ = rs.WriteNext(ctx, &pb.WriteN{CurC: b12.ID()})
 	if stest.Cur.Cur != nil || stest.Cur.Abort {
 		t.Error("writeN did not return correct cur.")
 	}
@@ -277,17 +355,17 @@ func TestWriteWrite(t *testing.T) {
 	if err != nil {
 		t.Error("Did return error")
 	}
-	if rs.RState != s {
+	if rs.RStates[""] != s {
 		t.Error("did not write")
 	}
 
 	s0 := &pb.State{Value: nil, Timestamp: 1, Writer: 0}
 	rs.Cur = b2
-	rs.CurC = uint32(b2.Len())
+	rs.CurC = b2.ID()
 
 	//Can abort
-	stest, _ = rs.Write(ctx, &pb.WriteS{State: s0, Conf: &pb.Conf{Cur: one, This: one}})
-	if rs.RState == s0 {
+	stest, _ = rs.Write(ctx, &pb.WriteS{State: s0, Conf: &pb.Conf{Cur: low, This: low}})
+	if rs.RStates[""] == s0 {
 		t.Error("did write value with smaller timestamp")
 	}
 	if !stest.Abort || stest.Cur != b2 {
@@ -296,11 +374,11 @@ func TestWriteWrite(t *testing.T) {
 
 	//Does not abort, but sends cur, and new state.
 	s2 := &pb.State{Value: nil, Timestamp: 2, Writer: 1}
-	stest, _ = rs.Write(ctx, &pb.WriteS{State: s2, Conf: &pb.Conf{This: uint32(b2.Len()) + 1, Cur: uint32(b2.Len())}})
+	stest, _ = rs.Write(ctx, &pb.WriteS{State: s2, Conf: &pb.Conf{This: append(b2.ID(), 0), Cur: b2.ID()}})
 	if stest.Abort || stest.Cur != nil {
 		t.Errorf("writeS did not return correct cur, instead %v, abort was %v.", stest.Cur, stest.Abort)
 	}
-	if rs.RState != s2 {
+	if rs.RStates[""] != s2 {
 		t.Error("writeS did not write")
 	}
 
@@ -308,11 +386,11 @@ func TestWriteWrite(t *testing.T) {
 	s3 := &pb.State{Value: nil, Timestamp: 3, Writer: 0}
 	rs.noabort = true
 	rs.Next = []*bp.Blueprint{b12, b12x}
-	stest, _ = rs.Write(ctx, &pb.WriteS{State: s3, Conf: &pb.Conf{Cur: one, This: one}})
+	stest, _ = rs.Write(ctx, &pb.WriteS{State: s3, Conf: &pb.Conf{Cur: low, This: low}})
 	if stest.Abort || stest.Cur != b2 {
 		t.Error("writeS did not return correct cur.")
 	}
-	if rs.RState != s3 {
+	if rs.RStates[""] != s3 {
 		t.Error("writeS returned wrong state")
 	}
 	if len(stest.Next) != 2 {
@@ -320,7 +398,7 @@ func TestWriteWrite(t *testing.T) {
 	}
 
 	// Only send next that is large.
-	stest, _ = rs.Write(ctx, &pb.WriteS{Conf: &pb.Conf{Cur: uint32(b12.Len()), This: uint32(b12.Len())}})
+	stest, _ = rs.Write(ctx, &pb.WriteS{Conf: &pb.Conf{Cur: b12.ID(), This: b12.ID()}})
 	if stest.Cur != nil {
 		t.Error("writeS did not return correct cur.")
 	}
@@ -336,7 +414,7 @@ func TestWriteRead(t *testing.T) {
 	s := &pb.State{Value: bytes, Timestamp: 2, Writer: 0}
 
 	// Test it returns no error
-	stest, err := rs.Read(ctx, &pb.Conf{})
+	stest, err := rs.Read(ctx, &pb.Read{Conf: &pb.Conf{}})
 	if err != nil {
 		t.Error("Did return error")
 	}
@@ -344,18 +422,18 @@ func TestWriteRead(t *testing.T) {
 		t.Errorf("Direct ReadS returned: %v\n Should return: %v", stest, &pb.State{Value: make([]byte, 0), Timestamp: int32(0), Writer: uint32(0)})
 	}
 
-	rs.RState = s
+	rs.RStates[""] = s
 	rs.Cur = b2
-	rs.CurC = uint32(b2.Len())
+	rs.CurC = b2.ID()
 
 	//Can abort
-	stest, _ = rs.Read(ctx, &pb.Conf{Cur: one, This: one})
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{Cur: low, This: low}})
 	if !stest.Cur.Abort || stest.Cur.Cur != b2 {
 		t.Error("read S did return correct abort")
 	}
 
 	//Does not abort, but sends cur, and new state.
-	stest, _ = rs.Read(ctx, &pb.Conf{This: uint32(b2.Len()), Cur: uint32(b2.Len())})
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{This: b2.ID(), Cur: b2.ID()}})
 	if stest.Cur != nil {
 		//if stest.Cur.Abort || stest.Cur.Cur != b2 {
 		t.Errorf("read S did not return correct cur, but.")
@@ -367,7 +445,7 @@ func TestWriteRead(t *testing.T) {
 	// If noabort is true, does not abort, but sends cur, state and next.
 	rs.noabort = true
 	rs.Next = []*bp.Blueprint{b12, b12x}
-	stest, _ = rs.Read(ctx, &pb.Conf{Cur: one, This: one})
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{Cur: low, This: low}})
 	if stest.Cur.Abort || stest.Cur.Cur != b2 {
 		t.Error("read S did not return correct cur.")
 	}
@@ -379,7 +457,7 @@ func TestWriteRead(t *testing.T) {
 	}
 
 	// Only send next that is large.
-	stest, _ = rs.Read(ctx, &pb.Conf{Cur: uint32(b12.Len()), This: uint32(b12.Len())})
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{Cur: b12.ID(), This: b12.ID()}})
 	if stest.Cur.Cur != nil {
 		t.Errorf("read S did not return correct cur, instead %v.", stest.Cur)
 	}
@@ -391,3 +469,412 @@ func TestWriteRead(t *testing.T) {
 	}
 
 }
+
+func TestKeys(t *testing.T) {
+	rs := NewRegServer(false)
+	sa := &pb.State{Value: []byte("a"), Timestamp: 1, Writer: 0, Key: "a"}
+	sb := &pb.State{Value: []byte("b"), Timestamp: 2, Writer: 0, Key: "b"}
+	sc := &pb.State{Value: []byte("c"), Timestamp: 1, Writer: 0, Key: "c"}
+	rs.Write(ctx, &pb.WriteS{State: sa})
+	rs.Write(ctx, &pb.WriteS{State: sb})
+
+	// Single key
+	stest, _ := rs.Read(ctx, &pb.Read{Conf: &pb.Conf{}, Keys: &pb.Keys{Key: "b"}})
+	if stest.State != sb {
+		t.Error("read did not return the state of key b")
+	}
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{}})
+	if stest.State.Timestamp != 0 || stest.State.Key != "" {
+		t.Error("read of default key did not return initial state")
+	}
+
+	// SetState with several keys, only larger states are installed.
+	rs.SetState(ctx, &pb.NewState{States: []*pb.State{
+		&pb.State{Timestamp: 0, Key: "a"},
+		sc,
+	}})
+	if rs.RStates["a"] != sa || rs.RStates["c"] != sc {
+		t.Error("setstate did not set states correctly")
+	}
+
+	// Range
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{}, Keys: &pb.Keys{Key: "b", Range: true}})
+	if len(stest.States) != 2 || stest.States[0] != sb || stest.States[1] != sc {
+		t.Errorf("range read returned %v", stest.States)
+	}
+	stest, _ = rs.Read(ctx, &pb.Read{Conf: &pb.Conf{}, Keys: &pb.Keys{Key: "a", End: "c", Range: true}})
+	if len(stest.States) != 2 || stest.States[0] != sa || stest.States[1] != sb {
+		t.Errorf("range read returned %v", stest.States)
+	}
+
+	// WriteNext returns all keys.
+	wtest, _ := rs.WriteNext(ctx, &pb.WriteN{Next: b12})
+	if len(wtest.States) != 3 {
+		t.Errorf("writeN returned %d states", len(wtest.States))
+	}
+
+	merged := pb.MergeStates(wtest.States, []*pb.State{&pb.State{Timestamp: 3, Key: "b"}, &pb.State{Key: "d"}})
+	if len(merged) != 4 || merged[1].Timestamp != 3 || merged[0] != sa || merged[3].Key != "d" {
+		t.Errorf("mergestates returned %v", merged)
+	}
+}
+
+func TestRestrictedSetCur(t *testing.T) {
+	rs := NewRegServer(false)
+	rs.SetCur(ctx, &pb.NewCur{Cur: b1, CurC: b1.ID()})
+
+	// A restricted client cannot install a configuration, that was not proposed.
+	rep, err := rs.SetCur(Restrict(ctx), &pb.NewCur{Cur: b12, CurC: b12.ID()})
+	if err != nil || rep.New || rs.Cur != b1 {
+		t.Errorf("SetCur returned %v and %v, and installed %v, want b1 installed", rep, err, rs.Cur)
+	}
+
+	// It can pass on a proposed configuration.
+	rs.WriteNext(ctx, &pb.WriteN{CurC: b1.ID(), Next: b12})
+	rep, err = rs.SetCur(Restrict(ctx), &pb.NewCur{Cur: b12, CurC: b12.ID()})
+	if err != nil || !rep.New || rs.Cur != b12 {
+		t.Errorf("SetCur returned %v and %v, and installed %v, want b12 installed", rep, err, rs.Cur)
+	}
+}
+
+func TestDynaRPCs(t *testing.T) {
+	rs := NewRegServer(false)
+	rs.RStates["a"] = &pb.State{Value: []byte("x"), Timestamp: 1, Key: "a"}
+
+	rep, err := rs.DWriteNext(ctx, &pb.DWriteN{CurC: b1.ID(), Keys: &pb.Keys{Key: "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Next) != 0 || len(rep.States) != 1 || string(rep.States[0].Value) != "x" {
+		t.Errorf("DWriteNext returned %v", rep)
+	}
+
+	rs.DWriteNext(ctx, &pb.DWriteN{CurC: b1.ID(), Next: []*bp.Blueprint{b12}})
+	rep, _ = rs.DWriteNext(ctx, &pb.DWriteN{CurC: b1.ID(), Next: []*bp.Blueprint{b12, b123}})
+	if len(rep.Next) != 2 || len(rs.DNext[string(b1.ID())]) != 2 {
+		t.Errorf("DWriteNext recorded successors %v, expected b12 and b123", rep.Next)
+	}
+
+	// A newer state is stored, and the successors are reported.
+	st := &pb.State{Value: []byte("y"), Timestamp: 2, Key: "a"}
+	srep, err := rs.DSetState(ctx, &pb.DNewState{CurC: b1.ID(), States: []*pb.State{st}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srep.Next) != 2 || rs.RStates["a"] != st {
+		t.Errorf("DSetState returned %v and stored %v", srep.Next, rs.RStates["a"])
+	}
+	srep, _ = rs.DSetState(ctx, &pb.DNewState{CurC: b12.ID(), States: []*pb.State{{Value: []byte("z"), Timestamp: 1, Key: "a"}}})
+	if len(srep.Next) != 0 || rs.RStates["a"] != st {
+		t.Errorf("DSetState returned %v and stored %v", srep.Next, rs.RStates["a"])
+	}
+
+	// Speculating snapshot returns all values proposed at a level.
+	sp, err := rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Level: 1, Prop: b12})
+	if err != nil || len(sp.Vals) != 1 {
+		t.Errorf("SpSnOneShot returned %v, %v", sp, err)
+	}
+	sp, _ = rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Level: 1, Prop: bx})
+	if len(sp.Vals) != 2 {
+		t.Errorf("SpSnOneShot returned %d values, expected 2", len(sp.Vals))
+	}
+	sp, _ = rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Level: 2, Prop: b12})
+	if len(sp.Vals) != 1 {
+		t.Errorf("SpSnOneShot returned %d values on level 2, expected 1", len(sp.Vals))
+	}
+	if _, err = rs.SpSnOneShot(ctx, &pb.SpSnProp{CurC: b1.ID(), Prop: b12}); err == nil {
+		t.Error("SpSnOneShot accepted level 0")
+	}
+}
+
+func TestElect(t *testing.T) {
+	now := time.Now()
+	Clock = func() time.Time { return now }
+	defer func() { Clock = time.Now }()
+
+	rs := NewRegServerWithCur(b123, b123.ID(), false)
+	lease := int64(time.Second)
+	l, _ := rs.Elect(ctx, &pb.Ballot{Rnd: 1, Id: 1, Lease: lease})
+	if !l.Granted {
+		t.Error("first ballot was not granted")
+	}
+	l, _ = rs.Elect(ctx, &pb.Ballot{Rnd: 5, Id: 2, Lease: lease})
+	if l.Granted || l.Holder.Id != 1 {
+		t.Errorf("granted a second lease, or reported wrong holder %v", l.Holder)
+	}
+	if l, _ = rs.Elect(ctx, &pb.Ballot{Rnd: 1, Id: 1, Lease: lease}); !l.Granted {
+		t.Error("lease was not renewed")
+	}
+
+	now = now.Add(2 * time.Second)
+	if rs.holder() != nil {
+		t.Errorf("lease of %v did not end", rs.Holder)
+	}
+	if l, _ = rs.Elect(ctx, &pb.Ballot{Rnd: 1, Id: 2, Lease: lease}); l.Granted {
+		t.Error("granted lease to a ballot with an old round")
+	}
+	if l, _ = rs.Elect(ctx, &pb.Ballot{Rnd: 2, Id: 2, Lease: lease}); !l.Granted || rs.holder().Id != 2 {
+		t.Error("did not grant the lease after it ended")
+	}
+
+	// Node 4 is not in the current configuration.
+	now = now.Add(2 * time.Second)
+	if l, _ = rs.Elect(ctx, &pb.Ballot{Rnd: 9, Id: 4, Lease: lease}); l.Granted || !l.Cur.Equals(b123) {
+		t.Errorf("granted lease to a node outside the configuration, or returned Cur %v", l.Cur)
+	}
+}
+
+func TestFwdRedirect(t *testing.T) {
+	rs := NewRegServer(false)
+	if _, err := rs.Fwd(ctx, &pb.Proposal{Prop: b12}); err == nil {
+		t.Error("Fwd succeeded without a leader")
+	}
+
+	rs.Elect(ctx, &pb.Ballot{Rnd: 1, Id: 2, Addr: "host:2", Lease: int64(time.Hour)})
+	ack, err := rs.Fwd(ctx, &pb.Proposal{Prop: b12})
+	if err != nil || !ack.Redirect || ack.Leader != 2 || ack.LeaderAddr != "host:2" {
+		t.Errorf("Fwd returned %v, %v, expected a redirect to 2", ack, err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Now()
+	Clock = func() time.Time { return now }
+	defer func() { Clock = time.Now }()
+
+	rs := NewRegServerWithCur(b12, b12.ID(), false)
+	rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte("x"), Timestamp: 2, Writer: 5, Key: "k"}, Conf: &pb.Conf{This: b12.ID(), Cur: b12.ID()}})
+	rs.WriteNext(ctx, &pb.WriteN{CurC: b12.ID(), Next: b123})
+	// WriteNext decides the consensus instance in b12, the one in b123 is pending.
+	rs.GetPromise(ctx, &pb.Prepare{CurC: b12.ID(), Rnd: 2})
+	rs.GetPromise(ctx, &pb.Prepare{CurC: b123.ID(), Rnd: 3})
+	now = now.Add(time.Minute)
+
+	st, err := rs.Status(ctx, &pb.StatusRequest{})
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if string(st.CurC) != string(b12.ID()) || time.Duration(st.Uptime) != time.Minute {
+		t.Errorf("got CurC %v and uptime %v", st.CurC, time.Duration(st.Uptime))
+	}
+	if len(st.Next) != 1 || !st.Next[0].LearnedEquals(b123) {
+		t.Errorf("got Next %v, want [%v]", st.Next, b123)
+	}
+	var found bool
+	for _, r := range st.Registers {
+		if r.Key == "k" {
+			found = r.Timestamp == 2 && r.Writer == 5
+		}
+	}
+	if !found {
+		t.Errorf("register k not reported correctly: %v", st.Registers)
+	}
+	if len(st.Paxos) != 1 || st.Paxos[0].Rnd != 3 || string(st.Paxos[0].CurC) != string(b123.ID()) {
+		t.Errorf("got pending consensus instances %v, want round 3 in %v", st.Paxos, b123)
+	}
+
+	rs.SetCur(ctx, &pb.NewCur{Cur: b123, CurC: b123.ID()})
+	if st, _ = rs.Status(ctx, &pb.StatusRequest{}); len(st.Paxos) != 1 || len(st.Next) != 0 {
+		t.Errorf("got pending instances %v and Next %v after SetCur", st.Paxos, st.Next)
+	}
+}
+
+func TestPromise(t *testing.T) {
+	conf := &pb.Conf{This: b12.ID(), Cur: b12.ID()}
+	rs := NewRegServerWithCur(b12, b12.ID(), false)
+	rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte("x"), Timestamp: 2, Writer: 1, Key: "k"}, Conf: conf})
+
+	// Ballots not larger than the stored state are not promised.
+	rr, _ := rs.Read(ctx, &pb.Read{Conf: conf, Ballot: &pb.State{Timestamp: 2, Writer: 1, Key: "k"}})
+	if rr.Promise != nil || rr.State.Timestamp != 2 {
+		t.Errorf("got promise %v and state %v for a ballot equal to the state", rr.Promise, rr.State)
+	}
+	rr, _ = rs.Read(ctx, &pb.Read{Conf: conf, Ballot: &pb.State{Timestamp: 4, Writer: 2, Key: "k"}})
+	if (rr.Promise == nil || rr.Promise.Timestamp != 4) || string(rr.State.Value) != "x" {
+		t.Errorf("got promise %v and state %v, want ballot 4 and value x", rr.Promise, rr.State)
+	}
+	rr, _ = rs.Read(ctx, &pb.Read{Conf: conf, Ballot: &pb.State{Timestamp: 3, Writer: 3, Key: "k"}})
+	if rr.Promise == nil || rr.Promise.Timestamp != 4 {
+		t.Errorf("a smaller ballot replaced the promise: %v", rr.Promise)
+	}
+
+	// Writes with smaller ballots are rejected, also write-backs of the stored state.
+	for _, st := range []*pb.State{{Value: []byte("y"), Timestamp: 3, Writer: 5}, {Value: []byte("x"), Timestamp: 2, Writer: 1}} {
+		st.Key = "k"
+		if cr, _ := rs.Write(ctx, &pb.WriteS{State: st, Conf: conf}); !cr.Rejected {
+			t.Errorf("write with ballot %d was not rejected", st.Timestamp)
+		}
+	}
+	if cr, _ := rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte("z"), Timestamp: 4, Writer: 2, Key: "k"}, Conf: conf}); cr.Rejected {
+		t.Error("write with the promised ballot was rejected")
+	}
+	if cr, _ := rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte("y"), Timestamp: 1, Writer: 1, Key: "other"}, Conf: conf}); cr.Rejected {
+		t.Error("write to another register was rejected")
+	}
+	if st := rs.state("k"); string(st.Value) != "z" {
+		t.Errorf("got value %q, want z", st.Value)
+	}
+
+	// Reconfigurations move the promises.
+	wn, _ := rs.WriteNext(ctx, &pb.WriteN{CurC: b12.ID(), Next: b123})
+	if len(wn.Promises) != 1 || wn.Promises[0].Key != "k" || wn.Promises[0].Timestamp != 4 {
+		t.Fatalf("WriteNext returned promises %v", wn.Promises)
+	}
+	rs2 := NewRegServerWithCur(b12, b12.ID(), false)
+	rs2.SetState(ctx, &pb.NewState{CurC: b123.ID(), States: wn.States, Promises: wn.Promises})
+	if cr, _ := rs2.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte("y"), Timestamp: 3, Writer: 5, Key: "k"}, Conf: &pb.Conf{This: b123.ID(), Cur: b123.ID()}}); !cr.Rejected {
+		t.Error("the promise was not moved by SetState")
+	}
+
+	// Unlike the moved states, a state written with SetState is checked against the promises, also those it carries.
+	rs3 := NewRegServerWithCur(b12, b12.ID(), false)
+	old := &pb.State{Value: []byte("x"), Timestamp: 2, Writer: 1, Key: "k"}
+	w := &pb.State{Value: []byte("y"), Timestamp: 3, Writer: 5, Key: "k"}
+	if ns, _ := rs3.SetState(ctx, &pb.NewState{CurC: b123.ID(), States: []*pb.State{old}, Promises: wn.Promises, Write: w}); !ns.Rejected {
+		t.Error("state written with SetState was not rejected")
+	}
+	if st := rs3.state("k"); string(st.Value) != "x" {
+		t.Errorf("got value %q after a rejected SetState, want the moved value x", st.Value)
+	}
+	w = &pb.State{Value: []byte("y"), Timestamp: 5, Writer: 5, Key: "k"}
+	if ns, _ := rs3.SetState(ctx, &pb.NewState{CurC: b123.ID(), Write: w}); ns.Rejected {
+		t.Error("state written with a larger ballot was rejected")
+	}
+	if st := rs3.state("k"); string(st.Value) != "y" {
+		t.Errorf("got value %q, want y", st.Value)
+	}
+}
+
+// timestamps returns the timestamps of the versions vs.
+func timestamps(vs []*pb.State) []int32 {
+	ts := make([]int32, len(vs))
+	for i, v := range vs {
+		ts[i] = v.Timestamp
+	}
+	return ts
+}
+
+func equalTimestamps(vs []*pb.State, ts ...int32) bool {
+	if len(vs) != len(ts) {
+		return false
+	}
+	for i, v := range vs {
+		if v.Timestamp != ts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHistory(t *testing.T) {
+	defer func(n int) { HistorySize = n }(HistorySize)
+	HistorySize = 2
+	conf := &pb.Conf{This: b12.ID(), Cur: b12.ID()}
+	rs := NewRegServerWithCur(b12, b12.ID(), false)
+	for _, ts := range []int32{2, 6, 4, 8, 10} {
+		rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte{byte('0' + ts)}, Timestamp: ts, Writer: 1, Key: "k"}, Conf: conf})
+	}
+	// Version 4 was never stored, and version 2 was discarded.
+	if !equalTimestamps(rs.History["k"], 6, 8) || rs.Compacted["k"].Timestamp != 2 {
+		t.Errorf("got history %v and compacted %v", timestamps(rs.History["k"]), rs.Compacted["k"])
+	}
+
+	for _, tc := range []struct {
+		key       string
+		asOf      int32
+		want      int32
+		compacted bool
+	}{
+		{"k", 100, 10, false},
+		{"k", 9, 8, false},
+		{"k", 7, 6, false},
+		{"k", 5, 0, true},
+		{"other", 5, 0, false},
+	} {
+		rr, _ := rs.Read(ctx, &pb.Read{Conf: conf, Keys: &pb.Keys{Key: tc.key}, AsOf: tc.asOf})
+		switch {
+		case rr.Compacted != tc.compacted:
+			t.Errorf("read of %s as of %d returned compacted %v", tc.key, tc.asOf, rr.Compacted)
+		case !tc.compacted && (rr.State == nil || rr.State.Timestamp != tc.want):
+			t.Errorf("read of %s as of %d returned %v, want timestamp %d", tc.key, tc.asOf, rr.State, tc.want)
+		}
+	}
+
+	rr, _ := rs.Read(ctx, &pb.Read{Conf: conf, Keys: &pb.Keys{Key: "k"}, Versions: 2})
+	if !equalTimestamps(rr.Versions, 8, 10) || rr.State.Timestamp != 10 {
+		t.Errorf("got versions %v and state %v, want versions 8 and 10", timestamps(rr.Versions), rr.State)
+	}
+	if rr, _ = rs.Read(ctx, &pb.Read{Conf: conf, Keys: &pb.Keys{Key: "other"}, Versions: 2}); len(rr.Versions) != 0 {
+		t.Errorf("got versions %v for a register that was never written", rr.Versions)
+	}
+
+	// Reconfigurations move the history, merged with the history at the new server.
+	wn, _ := rs.WriteNext(ctx, &pb.WriteN{CurC: b12.ID(), Next: b123})
+	if !equalTimestamps(wn.History, 6, 8) || !equalTimestamps(wn.Compacted, 2) {
+		t.Fatalf("WriteNext returned history %v and compacted %v", timestamps(wn.History), timestamps(wn.Compacted))
+	}
+	rs2 := NewRegServerWithCur(b123, b123.ID(), false)
+	conf2 := &pb.Conf{This: b123.ID(), Cur: b123.ID()}
+	for _, ts := range []int32{3, 7} {
+		rs2.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte{byte('0' + ts)}, Timestamp: ts, Writer: 2, Key: "k"}, Conf: conf2})
+	}
+	rs2.SetState(ctx, &pb.NewState{CurC: b123.ID(), States: wn.States, History: wn.History, Compacted: wn.Compacted})
+	// Versions 3 and 6 are discarded.
+	if !equalTimestamps(rs2.History["k"], 7, 8) || rs2.Compacted["k"].Timestamp != 6 {
+		t.Errorf("got history %v and compacted %v after SetState", timestamps(rs2.History["k"]), rs2.Compacted["k"])
+	}
+	if rr, _ = rs2.Read(ctx, &pb.Read{Conf: conf2, Keys: &pb.Keys{Key: "k"}, AsOf: 7}); rr.Compacted || string(rr.State.Value) != "7" {
+		t.Errorf("read as of 7 returned %v after SetState", rr.State)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	conf := &pb.Conf{This: b12.ID(), Cur: b12.ID()}
+	rs := NewRegServerWithCur(b12, b12.ID(), false)
+	rs.Write(ctx, &pb.WriteS{State: &pb.State{Value: []byte("2"), Timestamp: 2, Writer: 1, Key: "k"}, Conf: conf})
+
+	var us []*pb.WatchUpdate
+	cancel := rs.Subscribe("k", func(u *pb.WatchUpdate) { us = append(us, u) })
+	if len(us) != 1 || us[0].State.Timestamp != 2 || !us[0].Cur.LearnedEquals(b12) {
+		t.Fatalf("got initial updates %v, want the state with timestamp 2 and the current configuration", us)
+	}
+
+	// Smaller states, and other registers, are not passed on.
+	for _, st := range []*pb.State{{Timestamp: 1, Writer: 1, Key: "k"}, {Timestamp: 3, Writer: 1, Key: "other"}, {Timestamp: 4, Writer: 1, Key: "k"}} {
+		rs.Write(ctx, &pb.WriteS{State: st, Conf: conf})
+	}
+	if len(us) != 2 || us[1].State.Timestamp != 4 || us[1].Cur != nil {
+		t.Fatalf("got updates %v after writes, want the state with timestamp 4", us[1:])
+	}
+
+	rs.WriteNext(ctx, &pb.WriteN{CurC: b12.ID(), Next: b123})
+	if len(us) != 3 || len(us[2].Next) != 1 || !us[2].Next[0].LearnedEquals(b123) {
+		t.Fatalf("got updates %v after WriteNext, want the next configuration", us[2:])
+	}
+	rs.SetCur(ctx, &pb.NewCur{Cur: b123, CurC: b123.ID()})
+	if len(us) != 4 || !us[3].Cur.LearnedEquals(b123) || len(us[3].Next) != 0 {
+		t.Fatalf("got updates %v after SetCur, want the new current configuration", us[3:])
+	}
+
+	// Pending updates are merged by the Watch RPC.
+	m := mergeUpdates(mergeUpdates(mergeUpdates(nil, us[0]), us[1]), us[2])
+	if m.State.Timestamp != 4 || !m.Cur.LearnedEquals(b12) || len(m.Next) != 1 {
+		t.Errorf("merged updates to %v, want the state with timestamp 4, and the last configurations", m)
+	}
+	// The configurations are kept, when a state update follows them.
+	m = mergeUpdates(mergeUpdates(nil, us[2]), us[1])
+	if m.State.Timestamp != 4 || !m.Cur.LearnedEquals(b12) || len(m.Next) != 1 || !m.Next[0].LearnedEquals(b123) {
+		t.Errorf("merged configuration and state updates to %v, want the state with timestamp 4, and the configurations", m)
+	}
+	// A server without Cur sends only Next.
+	m = mergeUpdates(us[1], &pb.WatchUpdate{Next: []*bp.Blueprint{b123}})
+	if m.State.Timestamp != 4 || m.Cur != nil || len(m.Next) != 1 {
+		t.Errorf("merged an update with only Next to %v", m)
+	}
+
+	cancel()
+	rs.Write(ctx, &pb.WriteS{State: &pb.State{Timestamp: 5, Writer: 1, Key: "k"}, Conf: &pb.Conf{This: b123.ID(), Cur: b123.ID()}})
+	if len(us) != 4 {
+		t.Errorf("got updates %v after the subscription ended", us[4:])
+	}
+}
@@ -0,0 +1,78 @@
+package regserver
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/golang/glog"
+	pb "github.com/relab/smartmerge/proto"
+	grpc "google.golang.org/grpc"
+)
+
+// Server is a handle for a RegServer served by its own grpc server.
+// Several Servers can run in the same process.
+type Server struct {
+	*RegServer
+	addr string
+	opts []grpc.ServerOption
+
+	mu         sync.Mutex
+	lis        net.Listener
+	grpcServer *grpc.Server
+}
+
+// NewServer creates a handle, that serves rs on the listen address addr, e.g. ":10000".
+// Use port 0 to let the operating system choose a port, and Addr to find it.
+// The server is not started before calling Start.
+func NewServer(addr string, rs *RegServer, opts ...grpc.ServerOption) *Server {
+	return &Server{RegServer: rs, addr: addr, opts: opts}
+}
+
+// Start starts listening and serving the RegServer in the background.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grpcServer != nil {
+		return errors.New("Server is already running.")
+	}
+
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		glog.Errorf("failed to listen on %s: %v\n", s.addr, err)
+		return err
+	}
+
+	s.lis = lis
+	s.grpcServer = grpc.NewServer(s.opts...)
+	pb.RegisterSMandConsRegisterServer(s.grpcServer, s.RegServer)
+	go s.grpcServer.Serve(lis)
+	glog.V(2).Infoln("Serving on", lis.Addr())
+	return nil
+}
+
+// Addr returns the address the server listens on, or nil if it is not running.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lis == nil {
+		return nil
+	}
+	return s.lis.Addr()
+}
+
+// Stop stops the grpc server, and closes the listener. The RegServer's storage is left open,
+// such that the Server can be started again. The storage is closed by its owner, see CloseStorage.
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grpcServer == nil {
+		return errors.New("Server is not running.")
+	}
+
+	// Stop also closes the listener.
+	s.grpcServer.Stop()
+	s.grpcServer = nil
+	s.lis = nil
+	return nil
+}
@@ -0,0 +1,166 @@
+package regserver
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/qfuncs"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestSeveralServers(t *testing.T) {
+	srvs := make([]*Server, 3)
+	for i := range srvs {
+		srvs[i] = NewServer("localhost:0", NewRegServer(false))
+		if err := srvs[i].Start(); err != nil {
+			t.Fatal("could not start server:", err)
+		}
+		if err := srvs[i].Start(); err == nil {
+			t.Error("started a running server twice")
+		}
+	}
+
+	for i, srv := range srvs {
+		srv.RStates[""] = &pb.State{Timestamp: int32(i + 1)}
+
+		conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+		if err != nil {
+			t.Fatal("could not dial server:", err)
+		}
+		client := pb.NewSMandConsRegisterClient(conn)
+		rr, err := client.Read(ctx, &pb.Read{Conf: &pb.Conf{}})
+		if err != nil {
+			t.Fatal("read returned error:", err)
+		}
+		if rr.State.Timestamp != int32(i+1) {
+			t.Errorf("server %d returned state of another server: %v", i, rr.State)
+		}
+		conn.Close()
+	}
+
+	for _, srv := range srvs {
+		if err := srv.Stop(); err != nil {
+			t.Error("could not stop server:", err)
+		}
+		if srv.Addr() != nil {
+			t.Error("stopped server still has an address")
+		}
+	}
+}
+
+func TestWeightedQuorumCall(t *testing.T) {
+	addrs := make([]string, 3)
+	for i := range addrs {
+		srv := NewServer("localhost:0", NewRegServer(false))
+		if err := srv.Start(); err != nil {
+			t.Fatal("could not start server:", err)
+		}
+		defer srv.Stop()
+		addrs[i] = srv.Addr().String()
+	}
+	mgr, err := pb.NewManager(addrs, pb.WithGrpcDialOptions(grpc.WithInsecure()))
+	if err != nil {
+		t.Fatal("could not create manager:", err)
+	}
+	defer mgr.Close()
+	ids := mgr.NodeIDs()
+	// The first node has weight 3, the others weight 1.
+	qs := qfuncs.NewWeightedSMQSpec(map[uint32]int{ids[0]: 3, ids[1]: 1, ids[2]: 1}, 3, 3)
+
+	light, err := mgr.NewWeightedConfiguration(ids[1:], qs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = light.Read(ctx, &pb.Read{Conf: &pb.Conf{}}); err == nil {
+		t.Error("two light nodes formed a read quorum")
+	}
+	heavy, err := mgr.NewWeightedConfiguration(ids[:1], qs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr, err := heavy.Read(ctx, &pb.Read{Conf: &pb.Conf{}}); err != nil || len(rr.NodeIDs) != 1 || rr.NodeIDs[0] != ids[0] {
+		t.Errorf("heavy node did not form a read quorum: %v, %v", rr, err)
+	}
+}
+
+func TestRestartServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rs := newDurable(t, dir)
+	defer rs.CloseStorage()
+	srv := NewServer("localhost:0", rs)
+	if err := srv.Start(); err != nil {
+		t.Fatal("could not start server:", err)
+	}
+	if err := srv.Stop(); err != nil {
+		t.Fatal("could not stop server:", err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal("could not restart server:", err)
+	}
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal("could not dial server:", err)
+	}
+	defer conn.Close()
+	client := pb.NewSMandConsRegisterClient(conn)
+	conf := &pb.Conf{This: b1.ID(), Cur: b1.ID()}
+	if _, err = client.Write(ctx, &pb.WriteS{State: &pb.State{Timestamp: 2, Writer: 1}, Conf: conf}); err != nil {
+		t.Fatal("write returned error:", err)
+	}
+	rs.CloseStorage()
+
+	if rec := newDurable(t, dir); rec.RStates[""].Timestamp != 2 {
+		t.Error("write to the restarted server was not persisted")
+	} else {
+		rec.CloseStorage()
+	}
+}
+
+func TestWatchRPC(t *testing.T) {
+	srv := NewServer("localhost:0", NewRegServerWithCur(b12, b12.ID(), false))
+	if err := srv.Start(); err != nil {
+		t.Fatal("could not start server:", err)
+	}
+	defer srv.Stop()
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal("could not dial server:", err)
+	}
+	defer conn.Close()
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := pb.NewSMandConsRegisterClient(conn).Watch(wctx, &pb.WatchRequest{Key: "k"})
+	if err != nil {
+		t.Fatal("watch returned error:", err)
+	}
+	if u, err := stream.Recv(); err != nil || u.State.Timestamp != 0 || !u.Cur.LearnedEquals(b12) {
+		t.Fatalf("got initial update %v, %v", u, err)
+	}
+
+	conf := &pb.Conf{This: b12.ID(), Cur: b12.ID()}
+	for ts := int32(1); ts <= 3; ts++ {
+		srv.Write(ctx, &pb.WriteS{State: &pb.State{Timestamp: ts, Writer: 1, Key: "k"}, Conf: conf})
+	}
+	// Updates may be merged, but the last state is sent.
+	for ts := int32(0); ts < 3; {
+		u, err := stream.Recv()
+		if err != nil {
+			t.Fatal("stream ended:", err)
+		}
+		if u.State.Timestamp <= ts {
+			t.Fatalf("got state %v after timestamp %d", u.State, ts)
+		}
+		ts = u.State.Timestamp
+	}
+}
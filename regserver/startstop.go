@@ -4,61 +4,83 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"net"
 	"sync"
 
 	bp "github.com/relab/smartmerge/blueprints"
-	pb "github.com/relab/smartmerge/proto"
 	grpc "google.golang.org/grpc"
 )
 
-// For now, I don't imagine needing to start several servers.
-// We therefore do this globally, instead of simply returning the grpcServer.
-var grpcServer *grpc.Server
+// The functions below manage a single, global server.
+// Use Server to run several servers in one process.
+var defaultServer *Server
 var mu sync.Mutex
-var haveServer = false
 
-// Stop stops the grpc server.
+// StateDir is the directory where the server keeps its state on stable storage.
+// If StateDir is empty, the state is kept only in memory.
+var StateDir = ""
+
+// ServerOptions are the options of the grpc server started by StartAt, e.g. the transport credentials for TLS.
+var ServerOptions []grpc.ServerOption
+
+// Stop stops the grpc server, and closes the storage opened by StartAt.
 func Stop() error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if haveServer == false {
+	if defaultServer == nil {
 		log.Println("Tried to stop grpc-server, but no server was found.")
 		return errors.New("No grpc server found.")
 	}
 
-	grpcServer.Stop()
-	haveServer = false
-	return nil
+	err := defaultServer.Stop()
+	if cerr := defaultServer.CloseStorage(); err == nil {
+		err = cerr
+	}
+	defaultServer = nil
+	return err
 }
 
 // Start a RegServer, as grpc server.
 func Start(port int, noabort bool) (*RegServer, error) {
-	return StartInConf(port, nil, uint32(0), noabort)
+	return StartInConf(port, nil, nil, noabort)
 }
 
 // StartInConf starts a RegServer, as grpc server with special initial configuration.
-func StartInConf(port int, init *bp.Blueprint, initC uint32, noabort bool) (*RegServer, error) {
+// If StateDir is set, the state stored there is recovered, and takes precedence over init.
+func StartInConf(port int, init *bp.Blueprint, initC []byte, noabort bool) (*RegServer, error) {
+	return StartAt(fmt.Sprintf(":%d", port), init, initC, noabort)
+}
+
+// StartAt is like StartInConf, but listens on the address addr, e.g. "localhost:10000".
+func StartAt(addr string, init *bp.Blueprint, initC []byte, noabort bool) (*RegServer, error) {
 	mu.Lock()
 	defer mu.Unlock()
-	if haveServer == true {
+	if defaultServer != nil {
 		log.Println("Abort start of grpc server, since old server exists.")
 		return nil, errors.New("There already exists an old server.")
 	}
 
-	rs := NewRegServerWithCur(init, initC, noabort)
-
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+	var rs *RegServer
+	if StateDir == "" {
+		rs = NewRegServerWithCur(init, initC, noabort)
+	} else {
+		fs, err := OpenFileStorage(StateDir)
+		if err != nil {
+			return nil, err
+		}
+		rs, err = NewRegServerFromStorage(fs, init, initC, noabort)
+		if err != nil {
+			fs.Close()
+			return nil, err
+		}
 	}
 
-	var opts []grpc.ServerOption
-	grpcServer = grpc.NewServer(opts...)
-	pb.RegisterSMandConsRegisterServer(grpcServer, rs)
-	go grpcServer.Serve(lis)
-	haveServer = true
+	srv := NewServer(addr, rs, ServerOptions...)
+	if err := srv.Start(); err != nil {
+		rs.CloseStorage()
+		return nil, err
+	}
+	defaultServer = srv
 
 	return rs, nil
 }
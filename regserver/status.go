@@ -0,0 +1,57 @@
+package regserver
+
+import (
+	bbytes "bytes"
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+	bp "github.com/relab/smartmerge/blueprints"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// Status implements the Status RPC. It returns the state of the server, for monitoring.
+// Unlike PrintState, it can be called remotely.
+func (rs *RegServer) Status(ctx context.Context, req *pb.StatusRequest) (*pb.ServerStatus, error) {
+	rs.RLock()
+	defer rs.RUnlock()
+	glog.V(5).Infoln("Handling Status")
+
+	st := &pb.ServerStatus{
+		Cur:     rs.Cur,
+		CurC:    rs.CurC,
+		LAState: rs.LAState,
+		Next:    append([]*bp.Blueprint(nil), rs.Next...),
+		Uptime:  int64(Clock().Sub(rs.started)),
+	}
+
+	keys := make([]string, 0, len(rs.RStates))
+	for k := range rs.RStates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := rs.RStates[k]
+		st.Registers = append(st.Registers, &pb.RegisterStatus{Key: k, Timestamp: s.Timestamp, Writer: s.Writer})
+	}
+
+	// Consensus instances are pending, if they run in the current or a later configuration, and are not decided.
+	cs := make([]string, 0, len(rs.Rnd))
+	for c := range rs.Rnd {
+		cs = append(cs, c)
+	}
+	for c := range rs.Val {
+		if _, ok := rs.Rnd[c]; !ok {
+			cs = append(cs, c)
+		}
+	}
+	sort.Strings(cs)
+	for _, c := range cs {
+		if rs.NextMap[c] != nil || bbytes.Compare([]byte(c), rs.CurC) < 0 {
+			continue
+		}
+		st.Paxos = append(st.Paxos, &pb.PaxosStatus{CurC: []byte(c), Rnd: rs.Rnd[c], Val: rs.Val[c]})
+	}
+	return st, nil
+}
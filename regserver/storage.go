@@ -0,0 +1,183 @@
+package regserver
+
+import (
+	"github.com/golang/glog"
+	bp "github.com/relab/smartmerge/blueprints"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// SnapshotInterval is the number of updates appended to the storage,
+// before the RegServer replaces the log with a snapshot of its state.
+var SnapshotInterval = 1000
+
+// Storage is a stable storage backend for a RegServer.
+type Storage interface {
+	// Append durably records an update. It must not return before the update is stable.
+	Append(u *StateUpdate) error
+	// Snapshot durably stores a complete copy of the state, and discards all updates appended earlier.
+	Snapshot(s *StateUpdate) error
+	// Recover returns the last snapshot (or nil) and all updates appended after it, in order.
+	Recover() (snap *StateUpdate, updates []*StateUpdate, err error)
+	// Close releases the resources held by the storage.
+	Close() error
+}
+
+// StateUpdate describes a change to the state of a RegServer.
+// Only fields that are set are applied. A snapshot is a StateUpdate with all fields set.
+type StateUpdate struct {
+	Cur     *bp.Blueprint
+	CurC    []byte
+	LAState *bp.Blueprint
+	RStates map[string]*pb.State // The registers that were changed.
+	// The promises of conditional writes that were changed.
+	Promises map[string]*pb.State
+	// The complete retained history of the changed registers.
+	History map[string][]*pb.State
+	// The largest discarded versions that were changed.
+	Compacted map[string]*pb.State
+	// The lattice agreement instances that were changed.
+	LAValues map[string]*pb.LAValue
+	Next     []*bp.Blueprint // The complete list of next blueprints.
+	NextSet  bool            // Next was changed, also if it became empty.
+	NextMap  map[string]*bp.Blueprint
+	Rnd      map[string]uint32
+	Val      map[string]*pb.CV
+	DNext    map[string][]*bp.Blueprint   // The complete list of successors of the changed views.
+	SpSn     map[string][][]*bp.Blueprint // All levels of the changed speculating snapshots.
+}
+
+// NewRegServerFromStorage creates a new RegServer, that persists its state in store.
+// The state previously stored in store is recovered. If there is no such state,
+// the RegServer starts in the initial configuration cur.
+func NewRegServerFromStorage(store Storage, cur *bp.Blueprint, curc []byte, noabort bool) (*RegServer, error) {
+	rs := NewRegServerWithCur(cur, curc, noabort)
+	snap, updates, err := store.Recover()
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		rs.apply(snap)
+	}
+	for _, u := range updates {
+		rs.apply(u)
+	}
+	glog.V(3).Infof("Recovered state from snapshot and %d updates.\n", len(updates))
+	rs.store = store
+	rs.logged = len(updates)
+	return rs, nil
+}
+
+// apply installs the fields set in u.
+func (rs *RegServer) apply(u *StateUpdate) {
+	if u.Cur != nil {
+		rs.Cur = u.Cur
+		rs.CurC = u.CurC
+	}
+	if u.LAState != nil {
+		rs.LAState = u.LAState
+	}
+	for key, st := range u.RStates {
+		rs.RStates[key] = st
+	}
+	for key, p := range u.Promises {
+		rs.Promises[key] = p
+	}
+	for key, h := range u.History {
+		rs.History[key] = h
+	}
+	for key, c := range u.Compacted {
+		rs.Compacted[key] = c
+	}
+	for inst, lav := range u.LAValues {
+		rs.LAValues[inst] = lav
+	}
+	if u.NextSet {
+		rs.Next = append(make([]*bp.Blueprint, 0, len(u.Next)), u.Next...)
+	}
+	for c, blp := range u.NextMap {
+		rs.NextMap[c] = blp
+	}
+	for c, rnd := range u.Rnd {
+		rs.Rnd[c] = rnd
+	}
+	for c, cv := range u.Val {
+		rs.Val[c] = cv
+	}
+	for c, next := range u.DNext {
+		rs.DNext[c] = next
+	}
+	for c, lvls := range u.SpSn {
+		rs.SpSn[c] = lvls
+	}
+}
+
+// snapshot returns the complete state of the RegServer.
+func (rs *RegServer) snapshot() *StateUpdate {
+	s := &StateUpdate{
+		Cur:       rs.Cur,
+		CurC:      rs.CurC,
+		LAState:   rs.LAState,
+		RStates:   rs.RStates,
+		Promises:  rs.Promises,
+		History:   rs.History,
+		Compacted: rs.Compacted,
+		LAValues:  rs.LAValues,
+		Next:      rs.Next,
+		NextSet:   true,
+		NextMap:   make(map[string]*bp.Blueprint, len(rs.NextMap)),
+		Rnd:       rs.Rnd,
+		Val:       make(map[string]*pb.CV, len(rs.Val)),
+		DNext:     rs.DNext,
+		SpSn:      rs.SpSn,
+	}
+	for c, blp := range rs.NextMap {
+		if blp != nil {
+			s.NextMap[c] = blp
+		}
+	}
+	for c, cv := range rs.Val {
+		if cv != nil {
+			s.Val[c] = cv
+		}
+	}
+	return s
+}
+
+// commit writes the update u to stable storage, and then applies it to the state of the RegServer,
+// and passes the changes to the subscribers. If u cannot be written, the state is left unchanged.
+// Handlers therefore collect their changes in u, instead of changing the state directly.
+// This must be called while holding the write lock, and before replying to the client.
+func (rs *RegServer) commit(u *StateUpdate) error {
+	if rs.store != nil {
+		if err := rs.store.Append(u); err != nil {
+			glog.Errorln("Could not persist update:", err)
+			return err
+		}
+		rs.logged++
+	}
+	rs.apply(u)
+	rs.notify(u)
+
+	if rs.store == nil || rs.logged < SnapshotInterval {
+		return nil
+	}
+	if err := rs.store.Snapshot(rs.snapshot()); err != nil {
+		// The update is already in the log, so we can continue.
+		glog.Errorln("Could not write snapshot:", err)
+		return nil
+	}
+	rs.logged = 0
+	return nil
+}
+
+// CloseStorage closes the RegServer's storage backend, if it has one.
+func (rs *RegServer) CloseStorage() error {
+	rs.Lock()
+	defer rs.Unlock()
+	if rs.store == nil {
+		return nil
+	}
+	err := rs.store.Close()
+	rs.store = nil
+	return err
+}
@@ -0,0 +1,135 @@
+package regserver
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+	bp "github.com/relab/smartmerge/blueprints"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// watcher is a subscription to the changes of a register, see Subscribe.
+type watcher struct {
+	key string
+	f   func(*pb.WatchUpdate)
+}
+
+// Subscribe calls f with the current state of the register key, and the current and next configurations.
+// Afterwards, it calls f with the new state whenever the register advances, and with the configurations
+// whenever Cur changes through SetCur, or a new configuration is added to Next. f is called while holding
+// the lock of rs, and must not block, or access rs. The returned function ends the subscription.
+func (rs *RegServer) Subscribe(key string, f func(*pb.WatchUpdate)) (cancel func()) {
+	rs.Lock()
+	defer rs.Unlock()
+	w := &watcher{key, f}
+	rs.watchers = append(rs.watchers, w)
+	f(&pb.WatchUpdate{State: rs.state(key), Cur: rs.Cur, Next: rs.next()})
+
+	return func() {
+		rs.Lock()
+		defer rs.Unlock()
+		for i, x := range rs.watchers {
+			if x == w {
+				rs.watchers = append(rs.watchers[:i], rs.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notify passes the changes in the committed update u to the subscribers. rs must be locked.
+func (rs *RegServer) notify(u *StateUpdate) {
+	if len(rs.watchers) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(u.RStates))
+	for key := range u.RStates {
+		keys = append(keys, key)
+	}
+	// In a fixed order, such that simulations are reproducible.
+	sort.Strings(keys)
+	for _, key := range keys {
+		rs.notifyState(u.RStates[key])
+	}
+	if u.Cur != nil || u.NextSet {
+		rs.notifyConf()
+	}
+}
+
+// notifyState passes the new state st of its register to the subscribers. rs must be locked.
+func (rs *RegServer) notifyState(st *pb.State) {
+	for _, w := range rs.watchers {
+		if w.key == st.Key {
+			w.f(&pb.WatchUpdate{State: st})
+		}
+	}
+}
+
+// notifyConf passes the current and next configurations to all subscribers. rs must be locked.
+func (rs *RegServer) notifyConf() {
+	for _, w := range rs.watchers {
+		w.f(&pb.WatchUpdate{Cur: rs.Cur, Next: rs.next()})
+	}
+}
+
+// next returns a copy of Next, that can be used after rs is unlocked.
+func (rs *RegServer) next() []*bp.Blueprint {
+	return append([]*bp.Blueprint(nil), rs.Next...)
+}
+
+// Watch implements the Watch RPC. It sends the updates of a subscription to the register wr.Key,
+// until the stream is canceled by the client. If the client falls behind, pending updates are merged,
+// such that only the largest state, and the latest configurations, are sent.
+func (rs *RegServer) Watch(wr *pb.WatchRequest, stream pb.SMandConsRegister_WatchServer) error {
+	glog.V(5).Infoln("Handling Watch")
+	var mu sync.Mutex
+	var pending *pb.WatchUpdate
+	wake := make(chan struct{}, 1)
+	cancel := rs.Subscribe(wr.Key, func(u *pb.WatchUpdate) {
+		mu.Lock()
+		pending = mergeUpdates(pending, u)
+		mu.Unlock()
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	})
+	defer cancel()
+
+	for {
+		select {
+		case <-wake:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+		mu.Lock()
+		u := pending
+		pending = nil
+		mu.Unlock()
+		if u == nil {
+			// Woken up by an update, that was already sent together with an earlier one.
+			continue
+		}
+		if err := stream.Send(u); err != nil {
+			return err
+		}
+	}
+}
+
+// mergeUpdates merges the later update b into a, where a may be nil.
+func mergeUpdates(a, b *pb.WatchUpdate) *pb.WatchUpdate {
+	if a == nil {
+		return b
+	}
+	m := &pb.WatchUpdate{State: a.State, Cur: a.Cur, Next: a.Next}
+	if b.State != nil && m.State.Compare(b.State) == 1 {
+		m.State = b.State
+	}
+	if b.Cur != nil || b.Next != nil {
+		// b carries the configurations. Cur only changes to larger configurations,
+		// and Next only grows, until Cur changes. A server without Cur sends only Next.
+		m.Cur, m.Next = b.Cur, b.Next
+	}
+	return m
+}
@@ -0,0 +1,54 @@
+package rsm
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	conf "github.com/relab/smartmerge/confProvider"
+)
+
+// Counter is a replicated counter, an example of a state machine
+// with commutative commands. Each command adds a number to the counter.
+type Counter struct {
+	r *Replica
+
+	mu    sync.Mutex
+	value int64
+}
+
+// NewCounter returns a replica with id of the counter, stored in the lattice agreement instance inst.
+func NewCounter(a Agreer, cp conf.Provider, inst string, id uint32) *Counter {
+	c := new(Counter)
+	c.r = NewReplica(a, cp, inst, id, c)
+	return c
+}
+
+// Apply implements StateMachine.
+func (c *Counter) Apply(cmd []byte) {
+	n, _ := binary.Varint(cmd)
+	c.mu.Lock()
+	c.value += n
+	c.mu.Unlock()
+}
+
+// Add adds n to the counter, and returns the new value, together with the number of quorum calls.
+func (c *Counter) Add(ctx context.Context, n int64) (int64, int, error) {
+	var buf [binary.MaxVarintLen64]byte
+	cnt, err := c.r.Propose(ctx, buf[:binary.PutVarint(buf[:], n)])
+	return c.get(), cnt, err
+}
+
+// Value learns and returns the value of the counter, together with the number of quorum calls.
+// It includes all additions, that completed before.
+func (c *Counter) Value(ctx context.Context) (int64, int, error) {
+	cnt, err := c.r.Propose(ctx)
+	return c.get(), cnt, err
+}
+
+func (c *Counter) get() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
@@ -0,0 +1,117 @@
+/*
+Package rsm implements a replicated state machine for commutative commands,
+on top of generalized lattice agreement instead of consensus.
+
+The replicated log is a grow-only set of commands, agreed on by a lattice agreement instance.
+Every replica proposes its new commands together with the log it already learned,
+and learns a larger log, that is comparable to the logs learned by all other replicas.
+Replicas apply the commands in the order they learn them, which can differ between replicas.
+Thus the commands must commute, e.g. increments of a counter or insertions into a set.
+
+The log is kept by the servers and moved to new configurations by SmartMerge reconfigurations.
+*/
+package rsm
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	conf "github.com/relab/smartmerge/confProvider"
+	"github.com/relab/smartmerge/lattice"
+)
+
+// StateMachine is a state machine, whose commands commute.
+type StateMachine interface {
+	// Apply applies the command cmd. Each command is applied once per replica.
+	Apply(cmd []byte)
+}
+
+// Agreer solves lattice agreement for an instance. It is implemented by
+// smclient.SmClient and consclient.ConsClient.
+type Agreer interface {
+	Agree(ctx context.Context, cp conf.Provider, inst string, prop lattice.Value) (lattice.Value, int, error)
+}
+
+// Replica is the replica of a state machine at a client.
+type Replica struct {
+	a    Agreer
+	cp   conf.Provider
+	inst string
+	id   uint32
+	sm   StateMachine
+
+	mu  sync.Mutex
+	seq uint64
+	log lattice.Set // The learned commands, which are applied to sm.
+}
+
+// NewReplica returns a replica of the state machine sm, using the lattice agreement instance inst.
+// The id must be unique among all concurrently running replicas of the instance.
+// Sequence numbers start at the current time, such that a replica restarted
+// with the same id does not reuse the entries of its predecessor.
+func NewReplica(a Agreer, cp conf.Provider, inst string, id uint32, sm StateMachine) *Replica {
+	return &Replica{a: a, cp: cp, inst: inst, id: id, sm: sm, seq: uint64(time.Now().UnixNano())}
+}
+
+// Propose adds the commands cmds to the replicated log, and applies all commands learned so far.
+// When Propose returns, the commands are applied, together with all commands
+// proposed by operations that completed before. Propose without commands only learns new commands.
+// Propose returns the number of quorum calls.
+func (r *Replica) Propose(ctx context.Context, cmds ...[]byte) (int, error) {
+	r.mu.Lock()
+	prop := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		r.seq++
+		prop[i] = entry(r.id, r.seq, cmd)
+	}
+	log := r.log
+	r.mu.Unlock()
+
+	learned, cnt, err := r.a.Agree(ctx, r.cp, r.inst, lattice.NewSet(prop...).Merge(log))
+	if err != nil {
+		return cnt, err
+	}
+	r.learn(learned.(lattice.Set))
+	return cnt, nil
+}
+
+// Log returns the commands applied by the replica, in the order they are stored in the log.
+func (r *Replica) Log() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cmds := make([][]byte, len(r.log))
+	for i, e := range r.log {
+		cmds[i] = command(e)
+	}
+	return cmds
+}
+
+// learn applies the commands in log, that were not applied before.
+func (r *Replica) learn(log lattice.Set) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range log {
+		if !r.log.Contains(e) {
+			r.sm.Apply(command(e))
+		}
+	}
+	r.log = r.log.Merge(log).(lattice.Set)
+}
+
+// entry returns the log entry of the seq'th command of replica id.
+// The replica id and the sequence number make entries with equal commands unique.
+func entry(id uint32, seq uint64, cmd []byte) string {
+	e := make([]byte, 12+len(cmd))
+	binary.BigEndian.PutUint32(e, id)
+	binary.BigEndian.PutUint64(e[4:], seq)
+	copy(e[12:], cmd)
+	return string(e)
+}
+
+// command returns the command of a log entry.
+func command(e string) []byte {
+	return []byte(e[12:])
+}
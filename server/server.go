@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	//"strconv"
 	"os"
 	"os/signal"
@@ -12,17 +13,24 @@ import (
 
 	"github.com/golang/glog"
 
+	"github.com/relab/smartmerge/auth"
 	"github.com/relab/smartmerge/regserver"
+	"github.com/relab/smartmerge/tlsconf"
 )
 
 var (
 	port       = flag.Int("port", 10000, "this servers address ip:port.")
+	addr       = flag.String("addr", "", "the address to listen on, host:port. Overrides port.")
 	cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
 	gcoff      = flag.Bool("gcoff", false, "turn garbage collection off.")
 	alg        = flag.String("alg", "", "algorithm to use (sm | dyna | ssr | cons )")
 	allCores   = flag.Bool("all-cores", false, "use all available logical CPUs")
 
-	abort = flag.Bool("abort", false, "abort rpcs on outdated configurations.")
+	abort    = flag.Bool("abort", false, "abort rpcs on outdated configurations.")
+	stateDir = flag.String("statedir", "", "directory to persist the server state in. (Empty keeps state only in memory.)")
+
+	tlsFiles   = tlsconf.Flags()
+	authPolicy = flag.String("authpolicy", "", "file with the roles of clients, see package auth. (Empty allows all RPCs to every client.)")
 )
 
 func main() {
@@ -49,17 +57,31 @@ func main() {
 		runtime.GOMAXPROCS(cpus)
 	}
 
-	var err error
-	glog.Infoln("Starting Server with port: ", *port)
+	regserver.StateDir = *stateDir
+	opts, err := tlsFiles.ServerOptions()
+	if err != nil {
+		glog.Fatalln("Setting up TLS returned error", err)
+	}
+	regserver.ServerOptions = opts
+	if *authPolicy != "" {
+		pol, err := auth.LoadPolicy(*authPolicy)
+		if err != nil {
+			glog.Fatalln("Loading authorization policy returned error", err)
+		}
+		regserver.ServerOptions = append(regserver.ServerOptions, pol.ServerOptions()...)
+	}
+
+	if *addr == "" {
+		*addr = fmt.Sprintf(":%d", *port)
+	}
+
+	glog.Infoln("Starting Server with address: ", *addr)
 	switch *alg {
-	case "", "sm":
-		_, err = regserver.Start(*port, !(*abort))
-	case "dyna":
-		//_, err = regserver.StartDyna(*port)
-	case "ssr":
-		//_, err = regserver.StartSSR(*port)
-	case "cons":
-		_, err = regserver.Start(*port, !(*abort))
+	case "", "sm", "dyna", "ssr", "cons":
+		// The RegServer implements the RPCs of all algorithms.
+		_, err = regserver.StartAt(*addr, nil, nil, !(*abort))
+	default:
+		glog.Fatalf("Unknown algorithm %q, expected sm, dyna, ssr or cons.\n", *alg)
 	}
 
 	if err != nil {
@@ -0,0 +1,367 @@
+package sim
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"golang.org/x/net/context"
+
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/regserver"
+)
+
+// Configuration is a simulated configuration. It implements the quorum calls
+// of pb.WeightedConfiguration by sending messages through the simulated network.
+type Configuration struct {
+	net   *Network
+	ids   []uint32
+	qspec pb.WeightedQuorumSpec
+}
+
+// NewConfiguration returns a configuration of the nodes ids, using the quorum functions of qspec.
+// Together with conf.NewProviderFor, this allows to use the network as configuration provider.
+func (n *Network) NewConfiguration(ids []uint32, qspec pb.WeightedQuorumSpec) (conf.Configuration, error) {
+	if len(ids) == 0 {
+		return nil, pb.IllegalConfigError("need at least one node")
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := n.nodes[id]; !ok {
+			return nil, pb.NodeNotFoundError(id)
+		}
+	}
+	return &Configuration{net: n, ids: append([]uint32(nil), ids...), qspec: qspec}, nil
+}
+
+// Dial implements conf.Manager. Simulated nodes have no addresses,
+// thus Dial only checks that the node id was added to the network.
+func (n *Network) Dial(id uint32, addr string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.nodes[id]; !ok {
+		return pb.NodeNotFoundError(id)
+	}
+	return nil
+}
+
+// NodeIDs returns the ids of the nodes in the configuration.
+func (c *Configuration) NodeIDs() []uint32 {
+	return append([]uint32(nil), c.ids...)
+}
+
+// quorumCall sends args to all nodes in the configuration, and passes each reply and its sender to qf,
+// until qf reports a quorum. It returns the ids of the nodes that replied.
+func (c *Configuration) quorumCall(ctx context.Context, method string, args proto.Message,
+	invoke func(rs *regserver.RegServer, req proto.Message) (proto.Message, error),
+	qf func(reply proto.Message, nid uint32) bool) (ids []uint32, err error) {
+
+	call := c.net.send(c.ids, method, args, invoke)
+	defer c.net.finish(call)
+
+	ids = make([]uint32, 0, len(c.ids))
+	var errCount, replyCount int
+	for {
+		if ctx.Err() != nil {
+			return ids, pb.QuorumCallError{Reason: ctx.Err().Error(), ErrCount: errCount, ReplyCount: replyCount}
+		}
+		r := c.net.wait(call)
+		ids = append(ids, r.nid)
+		if r.err != nil {
+			errCount++
+		} else {
+			replyCount++
+			if qf(r.reply, r.nid) {
+				return ids, nil
+			}
+		}
+		if errCount+replyCount == len(c.ids) {
+			return ids, pb.QuorumCallError{Reason: "incomplete call", ErrCount: errCount, ReplyCount: replyCount}
+		}
+	}
+}
+
+// Read is the simulated Read quorum call.
+func (c *Configuration) Read(ctx context.Context, args *pb.Read) (*pb.ReadReply_, error) {
+	var (
+		reply   = new(pb.ReadReply_)
+		replies []*pb.ReadReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "Read", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.Read(ctx, req.(*pb.Read))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.ReadReply))
+			nids = append(nids, nid)
+			reply.ReadReply, quorum = c.qspec.ReadQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// Write is the simulated Write quorum call.
+func (c *Configuration) Write(ctx context.Context, args *pb.WriteS) (*pb.WriteReply, error) {
+	var (
+		reply   = new(pb.WriteReply)
+		replies []*pb.ConfReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "Write", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.Write(ctx, req.(*pb.WriteS))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.ConfReply))
+			nids = append(nids, nid)
+			reply.ConfReply, quorum = c.qspec.WriteQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// WriteNext is the simulated WriteNext quorum call.
+func (c *Configuration) WriteNext(ctx context.Context, args *pb.WriteN) (*pb.WriteNextReply, error) {
+	var (
+		reply   = new(pb.WriteNextReply)
+		replies []*pb.WriteNReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "WriteNext", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.WriteNext(ctx, req.(*pb.WriteN))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.WriteNReply))
+			nids = append(nids, nid)
+			reply.WriteNReply, quorum = c.qspec.WriteNextQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// SetCur is the simulated SetCur quorum call.
+func (c *Configuration) SetCur(ctx context.Context, args *pb.NewCur) (*pb.SetCurReply, error) {
+	var (
+		reply   = new(pb.SetCurReply)
+		replies []*pb.NewCurReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "SetCur", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.SetCur(ctx, req.(*pb.NewCur))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.NewCurReply))
+			nids = append(nids, nid)
+			reply.NewCurReply, quorum = c.qspec.SetCurQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// LAProp is the simulated LAProp quorum call.
+func (c *Configuration) LAProp(ctx context.Context, args *pb.LAProposal) (*pb.LAPropReply, error) {
+	var (
+		reply   = new(pb.LAPropReply)
+		replies []*pb.LAReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "LAProp", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.LAProp(ctx, req.(*pb.LAProposal))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.LAReply))
+			nids = append(nids, nid)
+			reply.LAReply, quorum = c.qspec.LAPropQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// LAPropValue is the simulated LAPropValue quorum call.
+func (c *Configuration) LAPropValue(ctx context.Context, args *pb.LAValueProposal) (*pb.LAPropValueReply, error) {
+	var (
+		reply   = new(pb.LAPropValueReply)
+		replies []*pb.LAValueReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "LAPropValue", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.LAPropValue(ctx, req.(*pb.LAValueProposal))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.LAValueReply))
+			nids = append(nids, nid)
+			reply.LAValueReply, quorum = c.qspec.LAPropValueQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// SetState is the simulated SetState quorum call.
+func (c *Configuration) SetState(ctx context.Context, args *pb.NewState) (*pb.SetStateReply, error) {
+	var (
+		reply   = new(pb.SetStateReply)
+		replies []*pb.NewStateReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "SetState", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.SetState(ctx, req.(*pb.NewState))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.NewStateReply))
+			nids = append(nids, nid)
+			reply.NewStateReply, quorum = c.qspec.SetStateQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// GetPromise is the simulated GetPromise quorum call.
+func (c *Configuration) GetPromise(ctx context.Context, args *pb.Prepare) (*pb.GetPromiseReply, error) {
+	var (
+		reply   = new(pb.GetPromiseReply)
+		replies []*pb.Promise
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "GetPromise", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.GetPromise(ctx, req.(*pb.Prepare))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.Promise))
+			nids = append(nids, nid)
+			reply.Promise, quorum = c.qspec.GetPromiseQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// Accept is the simulated Accept quorum call.
+func (c *Configuration) Accept(ctx context.Context, args *pb.Propose) (*pb.AcceptReply, error) {
+	var (
+		reply   = new(pb.AcceptReply)
+		replies []*pb.Learn
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "Accept", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.Accept(ctx, req.(*pb.Propose))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.Learn))
+			nids = append(nids, nid)
+			reply.Learn, quorum = c.qspec.AcceptQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// DWriteNext is the simulated DWriteNext quorum call.
+func (c *Configuration) DWriteNext(ctx context.Context, args *pb.DWriteN) (*pb.DWriteNextReply, error) {
+	var (
+		reply   = new(pb.DWriteNextReply)
+		replies []*pb.DReadReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "DWriteNext", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.DWriteNext(ctx, req.(*pb.DWriteN))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.DReadReply))
+			nids = append(nids, nid)
+			reply.DReadReply, quorum = c.qspec.DWriteNextQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// DSetState is the simulated DSetState quorum call.
+func (c *Configuration) DSetState(ctx context.Context, args *pb.DNewState) (*pb.DSetStateReply, error) {
+	var (
+		reply   = new(pb.DSetStateReply)
+		replies []*pb.DNewStateReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "DSetState", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.DSetState(ctx, req.(*pb.DNewState))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.DNewStateReply))
+			nids = append(nids, nid)
+			reply.DNewStateReply, quorum = c.qspec.DSetStateQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// SpSnOneShot is the simulated SpSnOneShot quorum call.
+func (c *Configuration) SpSnOneShot(ctx context.Context, args *pb.SpSnProp) (*pb.SpSnOneShotReply, error) {
+	var (
+		reply   = new(pb.SpSnOneShotReply)
+		replies []*pb.SpSnReply
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "SpSnOneShot", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.SpSnOneShot(ctx, req.(*pb.SpSnProp))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.SpSnReply))
+			nids = append(nids, nid)
+			reply.SpSnReply, quorum = c.qspec.SpSnOneShotQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
+
+// Elect is the simulated Elect quorum call.
+func (c *Configuration) Elect(ctx context.Context, args *pb.Ballot) (*pb.ElectReply, error) {
+	var (
+		reply   = new(pb.ElectReply)
+		replies []*pb.Lease
+		nids    []uint32
+		quorum  bool
+		err     error
+	)
+	reply.NodeIDs, err = c.quorumCall(ctx, "Elect", args,
+		func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+			return rs.Elect(ctx, req.(*pb.Ballot))
+		},
+		func(rep proto.Message, nid uint32) bool {
+			replies = append(replies, rep.(*pb.Lease))
+			nids = append(nids, nid)
+			reply.Lease, quorum = c.qspec.ElectQF(replies, nids)
+			return quorum
+		})
+	return reply, err
+}
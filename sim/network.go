@@ -0,0 +1,389 @@
+/*
+Package sim implements an in-process, simulated network for the
+quorum calls of the SmartMerge and consensus based clients.
+
+The nodes of the network are in-memory RegServers. Clients are run as
+actors, started with Go. Actors run one at a time: an actor runs until it
+waits for a reply to a quorum call, or returns. Then the scheduler
+delivers the next message. All choices of the scheduler are drawn from a
+pseudo random number generator with a given seed. A simulation with the
+same seed, options and actors is therefore reproducible.
+
+Actors must not start goroutines, that make quorum calls, on their own.
+They must use Go instead. For the clients in package smclient and the
+packages building on it, set smclient.Go to the network's Go method.
+*/
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/glog"
+	"github.com/relab/smartmerge/regserver"
+)
+
+var (
+	// ErrDeadlock is returned by Run, if actors are blocked, but no message is in flight.
+	ErrDeadlock = errors.New("sim: all actors are blocked outside of quorum calls")
+	// ErrDropped is reported to the caller for a lost request or reply, as if the call timed out.
+	ErrDropped = errors.New("sim: message was dropped")
+	// ErrCrashed is reported to the caller for a request to a crashed node.
+	ErrCrashed = errors.New("sim: node is crashed")
+)
+
+// Options specifies the faults the scheduler injects.
+type Options struct {
+	// MaxDelay is the maximal delay of a message, in steps of virtual time.
+	// Messages are delivered in the order of their delivery time,
+	// thus messages with different delays are reordered.
+	MaxDelay int
+	// DropRate is the probability that a request or its reply is lost.
+	DropRate float64
+	// DupRate is the probability that a request is delivered a second time.
+	DupRate float64
+	// CrashRate is the probability that a node crashes after a message was delivered.
+	CrashRate float64
+	// MaxCrashed bounds the number of nodes crashed at the same time due to CrashRate.
+	MaxCrashed int
+}
+
+// Event records a step of the simulation.
+type Event struct {
+	Time    int64
+	Node    uint32
+	Method  string // The quorum call, or "Crash" and "Restart".
+	Dup     bool   // The message was a duplicate.
+	Dropped bool
+	Crashed bool // The node was crashed when the message arrived.
+}
+
+type node struct {
+	rs      *regserver.RegServer
+	crashed bool
+	lastErr error // The last error returned to a caller.
+	latency int64 // The round trip time of the last reply.
+}
+
+type result struct {
+	nid   uint32
+	reply proto.Message
+	err   error
+}
+
+// call holds the replies to a quorum call, until the actor that made the call collects them.
+type call struct {
+	results []result
+	waiting bool
+	done    bool
+}
+
+type message struct {
+	at     int64
+	sent   int64
+	seq    uint64
+	to     uint32
+	method string
+	req    proto.Message
+	invoke func(rs *regserver.RegServer, req proto.Message) (proto.Message, error)
+	call   *call // nil for duplicates.
+}
+
+// Network is a simulated network of RegServers.
+type Network struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	rnd  *rand.Rand
+	opts Options
+
+	nodes map[uint32]*node
+	ids   []uint32 // Sorted ids of all nodes.
+
+	now     int64
+	seq     uint64
+	pending []*message
+	starts  []func() // Actors that have not been started yet.
+	actors  int      // Started actors, that did not yet return.
+	running int      // Started actors, that are not waiting for a reply.
+	inRun   bool
+	events  []Event
+}
+
+// NewNetwork creates an empty network, whose scheduler is seeded with seed.
+func NewNetwork(seed int64, opts Options) *Network {
+	n := &Network{
+		rnd:   rand.New(rand.NewSource(seed)),
+		opts:  opts,
+		nodes: make(map[uint32]*node),
+	}
+	n.cond = sync.NewCond(&n.mu)
+	return n
+}
+
+// AddNode adds the RegServer rs with the given id to the network.
+func (n *Network) AddNode(id uint32, rs *regserver.RegServer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.nodes[id]; !ok {
+		n.ids = append(n.ids, id)
+		sort.Sort(idSlice(n.ids))
+	}
+	n.nodes[id] = &node{rs: rs}
+}
+
+// Crash stops the node id from processing messages.
+func (n *Network) Crash(id uint32) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.setCrashed(id, true)
+}
+
+// Restart lets a crashed node process messages again. The node keeps the
+// state it had before the crash, as if recovered from stable storage.
+func (n *Network) Restart(id uint32) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.setCrashed(id, false)
+}
+
+// Crashed reports whether node id is crashed.
+func (n *Network) Crashed(id uint32) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	nd, ok := n.nodes[id]
+	return ok && nd.crashed
+}
+
+func (n *Network) setCrashed(id uint32, crashed bool) {
+	nd, ok := n.nodes[id]
+	if !ok || nd.crashed == crashed {
+		return
+	}
+	nd.crashed = crashed
+	ev := Event{Time: n.now, Node: id, Method: "Restart"}
+	if crashed {
+		ev.Method = "Crash"
+	}
+	n.events = append(n.events, ev)
+}
+
+// LastErr returns the last error returned by a call to node id.
+// Together with Latency, it implements conf.NodeMonitor.
+func (n *Network) LastErr(id uint32) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if nd, ok := n.nodes[id]; ok {
+		return nd.lastErr
+	}
+	return nil
+}
+
+// Latency returns the round trip time of the last successful call to node id.
+// One time step of the simulation counts as a millisecond.
+func (n *Network) Latency(id uint32) time.Duration {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if nd, ok := n.nodes[id]; ok {
+		return time.Duration(nd.latency) * time.Millisecond
+	}
+	return -1
+}
+
+// Events returns the steps of the simulation so far.
+func (n *Network) Events() []Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Event(nil), n.events...)
+}
+
+// Go adds an actor to the simulation. Actors are started by Run, in the order they were added.
+// Quorum calls on the network's configurations must only be made by actors.
+func (n *Network) Go(f func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.starts = append(n.starts, f)
+}
+
+// Run runs the simulation until all actors returned.
+// Messages still in flight at that time are discarded.
+func (n *Network) Run() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.inRun = true
+	defer func() { n.inRun = false }()
+
+	for {
+		for n.running > 0 {
+			n.cond.Wait()
+		}
+
+		switch {
+		case len(n.starts) > 0:
+			f := n.starts[0]
+			n.starts = n.starts[1:]
+			n.actors++
+			n.running++
+			go n.actor(f)
+		case n.actors == 0:
+			n.pending = nil
+			return nil
+		case len(n.pending) == 0:
+			return ErrDeadlock
+		default:
+			n.step()
+		}
+	}
+}
+
+func (n *Network) actor(f func()) {
+	defer func() {
+		n.mu.Lock()
+		n.actors--
+		n.running--
+		n.cond.Broadcast()
+		n.mu.Unlock()
+	}()
+	f()
+}
+
+// step delivers the next message. It must be called while holding the lock, and no actor running.
+func (n *Network) step() {
+	i := 0
+	for j, m := range n.pending {
+		if m.at < n.pending[i].at || (m.at == n.pending[i].at && m.seq < n.pending[i].seq) {
+			i = j
+		}
+	}
+	m := n.pending[i]
+	n.pending = append(n.pending[:i], n.pending[i+1:]...)
+	if m.at > n.now {
+		n.now = m.at
+	}
+
+	ev := Event{Time: n.now, Node: m.to, Method: m.method, Dup: m.call == nil}
+	res := result{nid: m.to}
+	nd := n.nodes[m.to]
+	switch {
+	case nd.crashed:
+		ev.Crashed = true
+		res.err = ErrCrashed
+	case n.rnd.Float64() < n.opts.DropRate:
+		ev.Dropped = true
+		res.err = ErrDropped
+		if n.rnd.Intn(2) == 0 {
+			// Only the reply is lost.
+			m.invoke(nd.rs, m.req)
+		}
+	default:
+		if m.call != nil && n.rnd.Float64() < n.opts.DupRate {
+			n.enqueue(&message{
+				to:     m.to,
+				method: m.method,
+				req:    proto.Clone(m.req),
+				invoke: m.invoke,
+			})
+		}
+		res.reply, res.err = m.invoke(nd.rs, m.req)
+		if res.err == nil {
+			res.reply = proto.Clone(res.reply)
+		}
+	}
+	if m.call != nil {
+		if res.err != nil {
+			// A new value for every error, like the errors of grpc calls.
+			nd.lastErr = fmt.Errorf("%s: %w", m.method, res.err)
+		} else {
+			nd.latency = n.now - m.sent
+		}
+	}
+	n.events = append(n.events, ev)
+	if glog.V(7) {
+		glog.Infof("sim: %+v\n", ev)
+	}
+
+	if c := m.call; c != nil && !c.done {
+		c.results = append(c.results, res)
+		if c.waiting {
+			c.waiting = false
+			n.running++
+			n.cond.Broadcast()
+		}
+	}
+
+	if n.opts.CrashRate > 0 && n.rnd.Float64() < n.opts.CrashRate {
+		var live []uint32
+		for _, id := range n.ids {
+			if !n.nodes[id].crashed {
+				live = append(live, id)
+			}
+		}
+		if len(n.ids)-len(live) < n.opts.MaxCrashed && len(live) > 0 {
+			n.setCrashed(live[n.rnd.Intn(len(live))], true)
+		}
+	}
+}
+
+// enqueue assigns a delivery time to m, and adds it to the pending messages.
+func (n *Network) enqueue(m *message) {
+	n.seq++
+	m.seq = n.seq
+	m.sent = n.now
+	m.at = n.now + 1 + int64(n.rnd.Intn(n.opts.MaxDelay+1))
+	n.pending = append(n.pending, m)
+}
+
+// send sends a copy of req to each of the nodes ids, and returns the call collecting the replies.
+func (n *Network) send(ids []uint32, method string, req proto.Message, invoke func(*regserver.RegServer, proto.Message) (proto.Message, error)) *call {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.inRun {
+		panic("sim: quorum call made outside of an actor")
+	}
+	c := new(call)
+	for _, id := range ids {
+		n.enqueue(&message{
+			to:     id,
+			method: method,
+			req:    proto.Clone(req),
+			invoke: invoke,
+			call:   c,
+		})
+	}
+	return c
+}
+
+// wait blocks the calling actor until a reply to c is delivered.
+func (n *Network) wait(c *call) result {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for len(c.results) == 0 {
+		c.waiting = true
+		n.running--
+		n.cond.Broadcast()
+		for c.waiting {
+			n.cond.Wait()
+		}
+	}
+	r := c.results[0]
+	c.results = c.results[1:]
+	return r
+}
+
+// finish discards all replies to c, that arrive after the quorum call returned.
+func (n *Network) finish(c *call) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	c.done = true
+	c.results = nil
+}
+
+type idSlice []uint32
+
+func (p idSlice) Len() int           { return len(p) }
+func (p idSlice) Less(i, j int) bool { return p[i] < p[j] }
+func (p idSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
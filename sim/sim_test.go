@@ -0,0 +1,1091 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	"github.com/relab/smartmerge/checker"
+	conf "github.com/relab/smartmerge/confProvider"
+	cc "github.com/relab/smartmerge/consclient"
+	"github.com/relab/smartmerge/controller"
+	"github.com/relab/smartmerge/doreconf"
+	dyna "github.com/relab/smartmerge/dynaclient"
+	"github.com/relab/smartmerge/lattice"
+	"github.com/relab/smartmerge/leader"
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/regserver"
+	"github.com/relab/smartmerge/rsm"
+	smc "github.com/relab/smartmerge/smclient"
+	ssr "github.com/relab/smartmerge/ssrclient"
+)
+
+// newNet returns a network with n nodes, and a blueprint including all of them.
+func newNet(seed int64, opts Options, n int) (*Network, *bp.Blueprint) {
+	net := NewNetwork(seed, opts)
+	smc.Go = net.Go
+	blp := &bp.Blueprint{FaultTolerance: uint32((n - 1) / 2), Epoch: 1}
+	for id := uint32(1); id <= uint32(n); id++ {
+		net.AddNode(id, regserver.NewRegServer(false))
+		blp.Nodes = append(blp.Nodes, &bp.Node{Id: id})
+	}
+	return net, blp
+}
+
+func provider(net *Network, id int) conf.Provider {
+	return &conf.ThriftyConfP{Provider: conf.NewProviderFor(net, id)}
+}
+
+// readWriteReconf writes and reads the register key, and removes node rem from the configuration in between.
+// It returns a log of the values it read.
+func readWriteReconf(net *Network, blp *bp.Blueprint, id uint32, key string, rem uint32) (log *[]string) {
+	log = new([]string)
+	net.Go(func() {
+		cp := provider(net, int(id))
+		c, err := smc.New(blp, id, cp)
+		if err != nil {
+			*log = append(*log, "new: "+err.Error())
+			return
+		}
+		for i := 0; i < 3; i++ {
+			c.Write(context.Background(), cp, key, []byte(fmt.Sprintf("%d%d", id, i)))
+			val, _, _ := c.Read(context.Background(), cp, key)
+			*log = append(*log, string(val))
+			if i == 1 {
+				target := c.Blueps[0].Copy()
+				target.Rem(rem)
+				if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+					*log = append(*log, "reconf: "+err.Error())
+				}
+			}
+		}
+	})
+	return log
+}
+
+func TestReadWriteReconf(t *testing.T) {
+	net, blp := newNet(1, Options{MaxDelay: 5}, 5)
+	la := readWriteReconf(net, blp, 1, "a", 5)
+	lb := readWriteReconf(net, blp, 2, "b", 4)
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for _, l := range []struct {
+		id  uint32
+		log []string
+	}{{1, *la}, {2, *lb}} {
+		want := []string{fmt.Sprintf("%d0", l.id), fmt.Sprintf("%d1", l.id), fmt.Sprintf("%d2", l.id)}
+		if !reflect.DeepEqual(l.log, want) {
+			t.Errorf("client %d read %v, expected %v", l.id, l.log, want)
+		}
+	}
+}
+
+func TestCrashMinority(t *testing.T) {
+	net, blp := newNet(2, Options{MaxDelay: 2}, 5)
+	net.Crash(2)
+	net.Crash(4)
+	la := readWriteReconf(net, blp, 1, "a", 4)
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if want := []string{"10", "11", "12"}; !reflect.DeepEqual(*la, want) {
+		t.Errorf("read %v, expected %v", *la, want)
+	}
+}
+
+func TestConsensusReconf(t *testing.T) {
+	net, blp := newNet(3, Options{MaxDelay: 3}, 5)
+	var vals [][]byte
+	for id := uint32(1); id <= 2; id++ {
+		id := id
+		net.Go(func() {
+			cp := provider(net, int(id))
+			c, err := cc.New(blp, id, cp)
+			if err != nil {
+				t.Errorf("could not create client %d: %v", id, err)
+				return
+			}
+			target := c.Blueps[0].Copy()
+			target.Rem(id + 3)
+			if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+				t.Errorf("client %d: reconf returned error: %v", id, err)
+			}
+			c.Write(context.Background(), cp, "", []byte{byte(id)})
+			val, _, _ := c.Read(context.Background(), cp, "")
+			vals = append(vals, val)
+		})
+	}
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(vals) != 2 || len(vals[1]) != 1 {
+		t.Fatalf("unexpected reads: %v", vals)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	opts := Options{MaxDelay: 4, DropRate: 0.05, DupRate: 0.1, CrashRate: 0.01, MaxCrashed: 1}
+	run := func(seed int64) ([]Event, []string) {
+		net, blp := newNet(seed, opts, 5)
+		la := readWriteReconf(net, blp, 1, "a", 5)
+		lb := readWriteReconf(net, blp, 2, "a", 4)
+		if err := net.Run(); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		return net.Events(), append(*la, *lb...)
+	}
+
+	ev1, log1 := run(42)
+	ev2, log2 := run(42)
+	if len(ev1) == 0 {
+		t.Fatal("no events recorded")
+	}
+	if !reflect.DeepEqual(ev1, ev2) {
+		t.Errorf("two runs with the same seed delivered different messages")
+	}
+	if !reflect.DeepEqual(log1, log2) {
+		t.Errorf("two runs with the same seed read different values: %v and %v", log1, log2)
+	}
+
+	ev3, _ := run(43)
+	if reflect.DeepEqual(ev1, ev3) {
+		t.Errorf("two runs with different seeds delivered the same messages")
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	net, blp := newNet(4, Options{MaxDelay: 3, DupRate: 0.5}, 4)
+	la := readWriteReconf(net, blp, 1, "a", 4)
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	dups := 0
+	for _, ev := range net.Events() {
+		if ev.Dup {
+			dups++
+		}
+	}
+	if dups == 0 {
+		t.Error("no message was duplicated")
+	}
+	if want := []string{"10", "11", "12"}; !reflect.DeepEqual(*la, want) {
+		t.Errorf("unexpected reads: %v", *la)
+	}
+}
+
+func TestQuorumUnreachable(t *testing.T) {
+	net, blp := newNet(5, Options{MaxDelay: 2}, 5)
+	var werr, rerr error
+	net.Go(func() {
+		cp := provider(net, 1)
+		c, err := smc.New(blp, 1, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		net.Crash(3)
+		net.Crash(4)
+		net.Crash(5)
+		_, werr = c.Write(context.Background(), cp, "a", []byte("x"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, rerr = c.Read(ctx, cp, "a")
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	var qerr *smc.QuorumError
+	if !errors.As(werr, &qerr) {
+		t.Fatalf("write returned %v, expected a QuorumError", werr)
+	}
+	if qerr.Method != "Read" || qerr.Size != 5 {
+		t.Errorf("write failed in %s of size %d, expected Read of size 5", qerr.Method, qerr.Size)
+	}
+	if !errors.As(rerr, &qerr) || !errors.Is(rerr, context.Canceled) {
+		t.Errorf("canceled read returned %v, expected a QuorumError caused by context.Canceled", rerr)
+	}
+}
+
+func TestMinSize(t *testing.T) {
+	net, blp := newNet(6, Options{MaxDelay: 2}, smc.MinSize)
+	var err error
+	net.Go(func() {
+		cp := provider(net, 1)
+		var c *smc.SmClient
+		if c, err = smc.New(blp, 1, cp); err != nil {
+			return
+		}
+		target := c.Blueps[0].Copy()
+		target.Rem(1)
+		_, err = c.Reconf(context.Background(), cp, target)
+	})
+	if rerr := net.Run(); rerr != nil {
+		t.Fatalf("Run returned error: %v", rerr)
+	}
+
+	var merr *smc.MinSizeError
+	if !errors.As(err, &merr) {
+		t.Fatalf("reconf returned %v, expected a MinSizeError", err)
+	}
+	if merr.Size != smc.MinSize-1 || merr.Min != smc.MinSize {
+		t.Errorf("got size %d and minimum %d, expected %d and %d", merr.Size, merr.Min, smc.MinSize-1, smc.MinSize)
+	}
+}
+
+// TestSharedClient runs concurrent operations on a single client, and checks that the history is linearizable.
+func TestSharedClient(t *testing.T) {
+	net, blp := newNet(7, Options{MaxDelay: 4}, 5)
+	var clock int64
+	rec := checker.NewRecorder()
+	rec.Clock = func() int64 { clock++; return clock }
+
+	var c *smc.SmClient
+	cp := provider(net, 1)
+	net.Go(func() {
+		var err error
+		if c, err = smc.New(blp, 1, cp); err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		for g := 0; g < 3; g++ {
+			g := g
+			net.Go(func() {
+				for i := 0; i < 4; i++ {
+					val := []byte(fmt.Sprintf("%d%d", g, i))
+					op := rec.Invoke(g, checker.Write, "", val)
+					_, err := c.Write(context.Background(), cp, "", val)
+					rec.Return(op, nil, err == nil)
+
+					op = rec.Invoke(g, checker.Read, "", nil)
+					val, _, err = c.Read(context.Background(), cp, "")
+					rec.Return(op, val, err == nil)
+
+					if g == 0 && i == 1 {
+						target := c.GetCur()
+						target.Rem(5)
+						if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+							t.Errorf("reconf returned error: %v", err)
+						}
+					}
+				}
+			})
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	h := rec.History()
+	for _, op := range h {
+		if op.Pending() {
+			t.Errorf("operation %v failed", op)
+		}
+	}
+	for _, v := range checker.Check(h) {
+		t.Error(v)
+	}
+	if cur := c.GetCur(); len(cur.Ids()) != 4 || len(c.Blueps) != 1 {
+		t.Errorf("client ended with blueprint %v and %d blueprints, expected 4 nodes and a single blueprint", cur.Ids(), len(c.Blueps))
+	}
+}
+
+// TestAgree runs concurrent lattice agreement on a grow-only set, and checks that all learned values are comparable.
+func TestAgree(t *testing.T) {
+	net, blp := newNet(8, Options{MaxDelay: 4}, 5)
+	learned := make([]lattice.Value, 4)
+	for id := 1; id <= len(learned); id++ {
+		id := id
+		net.Go(func() {
+			cp := provider(net, id)
+			c, err := smc.New(blp, uint32(id), cp)
+			if err != nil {
+				t.Errorf("could not create client: %v", err)
+				return
+			}
+			if learned[id-1], _, err = c.Agree(context.Background(), cp, "set", lattice.NewSet(fmt.Sprint(id))); err != nil {
+				t.Errorf("agree returned error: %v", err)
+			}
+		})
+	}
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for i, v := range learned {
+		if s, ok := v.(lattice.Set); !ok || !s.Contains(fmt.Sprint(i+1)) {
+			t.Errorf("client %d learned %v, which does not include its proposal", i+1, v)
+		}
+		for _, w := range learned[:i] {
+			if lattice.Compare(v, w) == 0 {
+				t.Errorf("learned incomparable values %v and %v", v, w)
+			}
+		}
+	}
+}
+
+// TestFlexibleReconf runs concurrent lattice agreement with two concurrent reconfigurations, in a configuration
+// with flexible quorums, where write quorums are smaller than read quorums. All learned values must be comparable,
+// and a client starting in the initial configuration must learn every proposal.
+func TestFlexibleReconf(t *testing.T) {
+	for seed := int64(30); seed < 35; seed++ {
+		net, blp := newNet(seed, Options{MaxDelay: 4}, 7)
+		blp.Nodes = blp.Nodes[:5]
+		blp.FaultTolerance, blp.ReadFaultTolerance = 3, 1
+		learned := make([]lattice.Value, 4)
+		for id := 1; id <= len(learned); id++ {
+			id := id
+			net.Go(func() {
+				cp := provider(net, id)
+				c, err := smc.New(blp, uint32(id), cp)
+				if err != nil {
+					t.Errorf("could not create client: %v", err)
+					return
+				}
+				if id > 2 {
+					target := c.GetCur()
+					target.Add(uint32(id + 3))
+					if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+						t.Errorf("reconf returned error: %v", err)
+					}
+				}
+				if learned[id-1], _, err = c.Agree(context.Background(), cp, "set", lattice.NewSet(fmt.Sprint(id))); err != nil {
+					t.Errorf("agree returned error: %v", err)
+				}
+			})
+		}
+		if err := net.Run(); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+
+		var last lattice.Value
+		net.Go(func() {
+			cp := provider(net, 5)
+			c, err := smc.New(blp, 5, cp)
+			if err != nil {
+				t.Errorf("could not create client: %v", err)
+				return
+			}
+			if last, _, err = c.Agree(context.Background(), cp, "set", lattice.NewSet()); err != nil {
+				t.Errorf("agree returned error: %v", err)
+			}
+		})
+		if err := net.Run(); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+
+		for i, v := range learned {
+			for _, w := range learned[:i] {
+				if lattice.Compare(v, w) == 0 {
+					t.Errorf("seed %d: learned incomparable values %v and %v", seed, v, w)
+				}
+			}
+		}
+		for id := 1; id <= len(learned); id++ {
+			if s, ok := last.(lattice.Set); !ok || !s.Contains(fmt.Sprint(id)) {
+				t.Errorf("seed %d: last client learned %v, which does not include proposal %d", seed, last, id)
+			}
+		}
+	}
+}
+
+// TestCounterReconf increments a replicated counter concurrently with a reconfiguration.
+// Then it moves to a configuration of new nodes, and checks that no increment was lost.
+func TestCounterReconf(t *testing.T) {
+	net, blp := newNet(9, Options{MaxDelay: 4}, 7)
+	blp.Nodes = blp.Nodes[:3]
+	var target *bp.Blueprint
+	for id := 1; id <= 3; id++ {
+		id := id
+		net.Go(func() {
+			cp := provider(net, id)
+			c, err := smc.New(blp, uint32(id), cp)
+			if err != nil {
+				t.Errorf("could not create client: %v", err)
+				return
+			}
+			cntr := rsm.NewCounter(c, cp, "counter", uint32(id))
+			for i := 0; i < 3; i++ {
+				if _, _, err := cntr.Add(context.Background(), int64(id)); err != nil {
+					t.Errorf("add returned error: %v", err)
+				}
+				if id == 1 && i == 0 {
+					target = c.GetCur()
+					target.Add(4)
+					target.Rem(1)
+					if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+						t.Errorf("reconf returned error: %v", err)
+					}
+				}
+			}
+		})
+	}
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	// Only the reconfiguration moves the counter to nodes 5, 6 and 7.
+	net.Go(func() {
+		cp := provider(net, 4)
+		c, err := smc.New(target, 4, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		target = c.GetCur()
+		for id := uint32(2); id <= 4; id++ {
+			target.Rem(id)
+			target.Add(id + 3)
+		}
+		if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+			t.Errorf("reconf returned error: %v", err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	var v int64
+	net.Go(func() {
+		cp := provider(net, 5)
+		c, err := smc.New(target, 5, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		if v, _, err = rsm.NewCounter(c, cp, "counter", 5).Value(context.Background()); err != nil {
+			t.Errorf("value returned error: %v", err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if v != 18 {
+		t.Errorf("counter has value %d, expected 18", v)
+	}
+}
+
+// TestVersions writes a register four times, and replaces all nodes after the second write.
+// The new nodes must retain the versions written before, up to regserver.HistorySize.
+func TestVersions(t *testing.T) {
+	net, blp := newNet(5, Options{MaxDelay: 3}, 6)
+	blp.Nodes = blp.Nodes[:3]
+	defer func(n int) { regserver.HistorySize = n }(regserver.HistorySize)
+	regserver.HistorySize = 2
+	var target *bp.Blueprint
+	net.Go(func() {
+		cp := provider(net, 1)
+		c, err := smc.New(blp, 1, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		for i := 1; i <= 4; i++ {
+			if _, err := c.Write(context.Background(), cp, "k", []byte(fmt.Sprintf("v%d", i))); err != nil {
+				t.Errorf("write returned error: %v", err)
+			}
+			if i == 2 {
+				target = c.GetCur()
+				for id := uint32(1); id <= 3; id++ {
+					target.Rem(id)
+					target.Add(id + 3)
+				}
+				if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+					t.Errorf("reconf returned error: %v", err)
+				}
+			}
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	net.Go(func() {
+		cp := provider(net, 2)
+		c, err := smc.New(target, 2, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		vs, _, err := c.Versions(context.Background(), cp, "k", 10)
+		if err != nil {
+			t.Errorf("versions returned error: %v", err)
+			return
+		}
+		// v1 was discarded.
+		var vals []string
+		for _, v := range vs {
+			vals = append(vals, string(v.Value))
+		}
+		if fmt.Sprint(vals) != "[v2 v3 v4]" {
+			t.Fatalf("got versions %v, want [v2 v3 v4]", vals)
+		}
+		if st, _, err := c.ReadAsOf(context.Background(), cp, "k", vs[1].Timestamp); err != nil || string(st.Value) != "v3" {
+			t.Errorf("read as of the timestamp of v3 returned %v, %v", st, err)
+		}
+		if _, _, err := c.ReadAsOf(context.Background(), cp, "k", vs[0].Timestamp-1); err != smc.ErrCompacted {
+			t.Errorf("read as of the timestamp of v1 returned error %v, want ErrCompacted", err)
+		}
+		if vs, _, err = c.Versions(context.Background(), cp, "k", 1); err != nil || len(vs) != 1 || string(vs[0].Value) != "v4" {
+			t.Errorf("got last version %v, %v, want v4", vs, err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+}
+
+// dynaClient is implemented by the DynaStore and SpSnStore clients.
+type dynaClient interface {
+	Read(ctx context.Context, cp conf.Provider, key string) ([]byte, int, error)
+	Write(ctx context.Context, cp conf.Provider, key string, val []byte) (int, error)
+	Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (int, error)
+	GetCur() *bp.Blueprint
+}
+
+// TestDynaReconf runs concurrent reads and writes with two concurrent reconfigurations
+// for DynaStore and SpSnStore. A client starting in the initial view then has to find the last view.
+func TestDynaReconf(t *testing.T) {
+	algs := []struct {
+		name string
+		new  func(blp *bp.Blueprint, id uint32, cp conf.Provider) (dynaClient, error)
+	}{
+		{"dyna", func(blp *bp.Blueprint, id uint32, cp conf.Provider) (dynaClient, error) { return dyna.New(blp, id, cp) }},
+		{"ssr", func(blp *bp.Blueprint, id uint32, cp conf.Provider) (dynaClient, error) { return ssr.New(blp, id, cp) }},
+	}
+	for _, alg := range algs {
+		for seed := int64(10); seed < 15; seed++ {
+			net, blp := newNet(seed, Options{MaxDelay: 4}, 5)
+			var clock int64
+			rec := checker.NewRecorder()
+			rec.Clock = func() int64 { clock++; return clock }
+
+			for id := 1; id <= 3; id++ {
+				id := id
+				net.Go(func() {
+					cp := provider(net, id)
+					c, err := alg.new(blp, uint32(id), cp)
+					if err != nil {
+						t.Errorf("%s: could not create client: %v", alg.name, err)
+						return
+					}
+					// Only written before the reconfigurations.
+					key := fmt.Sprint(id)
+					op := rec.Invoke(id, checker.Write, key, []byte(key))
+					_, err = c.Write(context.Background(), cp, key, []byte(key))
+					rec.Return(op, nil, err == nil)
+
+					for i := 0; i < 3; i++ {
+						val := []byte(fmt.Sprintf("%d%d", id, i))
+						op := rec.Invoke(id, checker.Write, "", val)
+						_, err := c.Write(context.Background(), cp, "", val)
+						rec.Return(op, nil, err == nil)
+
+						op = rec.Invoke(id, checker.Read, "", nil)
+						val, _, err = c.Read(context.Background(), cp, "")
+						rec.Return(op, val, err == nil)
+
+						if id <= 2 && i == 0 {
+							target := c.GetCur()
+							target.Rem(uint32(id + 3))
+							if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+								t.Errorf("%s: reconf returned error: %v", alg.name, err)
+							}
+						}
+					}
+				})
+			}
+			if err := net.Run(); err != nil {
+				t.Fatalf("%s: Run returned error: %v", alg.name, err)
+			}
+
+			// The removed nodes may only be needed to find the successors of the initial view.
+			// Their register states must have been moved.
+			net.Crash(4)
+			net.Crash(5)
+			var cur *bp.Blueprint
+			net.Go(func() {
+				cp := provider(net, 4)
+				c, err := alg.new(blp, 4, cp)
+				if err != nil {
+					t.Errorf("%s: could not create client: %v", alg.name, err)
+					return
+				}
+				for _, key := range []string{"", "1", "2", "3"} {
+					op := rec.Invoke(4, checker.Read, key, nil)
+					val, _, err := c.Read(context.Background(), cp, key)
+					rec.Return(op, val, err == nil)
+				}
+				cur = c.GetCur()
+			})
+			if err := net.Run(); err != nil {
+				t.Fatalf("%s: Run returned error: %v", alg.name, err)
+			}
+
+			h := rec.History()
+			for _, op := range h {
+				if op.Pending() {
+					t.Errorf("%s, seed %d: operation %v failed", alg.name, seed, op)
+				}
+			}
+			for _, v := range checker.Check(h) {
+				t.Errorf("%s, seed %d: %v", alg.name, seed, v)
+			}
+			if cur == nil || len(cur.Ids()) != 3 {
+				t.Errorf("%s, seed %d: new client ended in view %v, expected 3 nodes", alg.name, seed, cur.Ids())
+			}
+		}
+	}
+}
+
+// TestLeaderFailover elects a leader among the servers, crashes it, and checks that another server
+// is elected after the lease ended, which then removes the crashed server.
+func TestLeaderFailover(t *testing.T) {
+	net, blp := newNet(15, Options{MaxDelay: 3}, 5)
+	now := time.Now()
+	regserver.Clock = func() time.Time { return now }
+	defer func() { regserver.Clock = time.Now }()
+
+	ls := make([]*leader.Leader, 4)
+	elected := make([]bool, 4)
+	for id := 1; id <= 3; id++ {
+		id := id
+		net.Go(func() {
+			var err error
+			if ls[id], err = leader.New(blp, uint32(id), provider(net, id)); err != nil {
+				t.Errorf("could not create leader %d: %v", id, err)
+				return
+			}
+			if elected[id], err = ls[id].Campaign(context.Background()); err != nil {
+				t.Errorf("leader %d: campaign returned error: %v", id, err)
+			}
+		})
+	}
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	first := 0
+	for id := 1; id <= 3; id++ {
+		if elected[id] {
+			if first != 0 {
+				t.Fatalf("both %d and %d were elected", first, id)
+			}
+			first = id
+		}
+	}
+	if first == 0 {
+		// Split votes: The first to try again wins.
+		first = 1
+		net.Go(func() { elected[1], _ = ls[1].Campaign(context.Background()) })
+		if err := net.Run(); err != nil || !elected[1] {
+			t.Fatalf("no leader elected after split vote, error %v", err)
+		}
+	}
+
+	net.Crash(uint32(first))
+	next := first%3 + 1
+	net.Go(func() {
+		if ok, _ := ls[next].Campaign(context.Background()); ok {
+			t.Errorf("leader %d was elected during the lease of %d", next, first)
+		}
+		now = now.Add(2 * leader.LeaseDuration)
+		ok, err := ls[next].Campaign(context.Background())
+		if !ok || err != nil {
+			t.Errorf("leader %d was not elected after the lease ended: %v", next, err)
+			return
+		}
+		if h := ls[next].Known(); h.Id != uint32(next) || h.Rnd <= 1 {
+			t.Errorf("leader %d knows ballot %v", next, h)
+		}
+		target := ls[next].GetCur()
+		target.Rem(uint32(first))
+		if _, err := ls[next].Reconf(context.Background(), provider(net, next), target); err != nil {
+			t.Errorf("reconf returned error: %v", err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !ls[next].IsLeader() {
+		t.Errorf("leader %d lost its lease", next)
+	}
+}
+
+// TestController crashes nodes of the configuration, and checks that the controller replaces them
+// with spare nodes, but only after the threshold, not too often, and not without a spare node.
+func TestController(t *testing.T) {
+	net, blp := newNet(16, Options{MaxDelay: 3}, 6)
+	blp.Nodes = blp.Nodes[:4]
+	blp.FaultTolerance = 1
+	now := time.Now()
+
+	net.Go(func() {
+		cp := provider(net, 1)
+		c, err := smc.New(blp, 1, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		// The controller probes in parallel, but only actors may make quorum calls.
+		ctrl := controller.New(c, cp, func(ctx context.Context, id uint32) error {
+			if net.Crashed(id) {
+				return ErrCrashed
+			}
+			return nil
+		}, []uint32{5, 6})
+		ctrl.Clock = func() time.Time { return now }
+		step := func(want []uint32) {
+			t.Helper()
+			target, err := ctrl.Step(context.Background())
+			switch {
+			case err != nil:
+				t.Errorf("Step returned error: %v", err)
+			case want == nil && target != nil:
+				t.Errorf("Step reconfigured to %v", target.Ids())
+			case want != nil && (target == nil || !reflect.DeepEqual(c.GetCur().Ids(), want)):
+				t.Errorf("got configuration %v, want %v", c.GetCur().Ids(), want)
+			}
+		}
+
+		net.Crash(2)
+		step(nil)
+		if _, ok := ctrl.Suspected()[2]; !ok {
+			t.Errorf("crashed node 2 is not suspected")
+		}
+		now = now.Add(ctrl.Threshold)
+		step([]uint32{1, 3, 4, 5})
+		if sp := ctrl.Spares(); !reflect.DeepEqual(sp, []uint32{6}) {
+			t.Errorf("got spares %v, want 6", sp)
+		}
+
+		net.Crash(3)
+		step(nil)
+		now = now.Add(ctrl.Threshold)
+		// Rate limited.
+		step(nil)
+		now = now.Add(ctrl.MinInterval)
+		step([]uint32{1, 4, 5, 6})
+
+		net.Crash(4)
+		step(nil)
+		now = now.Add(ctrl.Threshold + ctrl.MinInterval)
+		// No spare left. Node 4 is not removed, which would shrink the configuration.
+		step(nil)
+		if _, ok := ctrl.Suspected()[4]; !ok {
+			t.Errorf("crashed node 4 is not suspected")
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+}
+
+// TestFDProvider crashes a node, that the client would choose for its quorums,
+// and checks that the failure detector makes the client avoid it afterwards, until it recovered.
+func TestFDProvider(t *testing.T) {
+	net, blp := newNet(17, Options{MaxDelay: 3}, 5)
+	var _ conf.NodeMonitor = net
+
+	fdp := conf.NewFDProviderFor(net, 1)
+	cp := &conf.ThriftyConfP{Provider: fdp}
+	// Client 1 starts choosing quorums at node 2.
+	net.Crash(2)
+	var before int
+	net.Go(func() {
+		c, err := smc.New(blp, 1, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		if _, err := c.Write(context.Background(), cp, "", []byte("1")); err != nil {
+			t.Errorf("write returned error: %v", err)
+		}
+		if s := fdp.Suspected(); !reflect.DeepEqual(s, []uint32{2}) {
+			t.Errorf("got suspected %v, want [2]", s)
+		}
+		cnf, ok := cp.WriteC(c.GetCur(), nil).(*conf.FDConfiguration)
+		if !ok || !reflect.DeepEqual(cnf.Suspected(), []uint32{2}) {
+			t.Errorf("configuration does not report the suspected node 2")
+		}
+
+		before = len(net.Events())
+		for i := 0; i < 5; i++ {
+			if _, err := c.Write(context.Background(), cp, "", []byte{byte(i)}); err != nil {
+				t.Errorf("write returned error: %v", err)
+			}
+			if _, _, err := c.Read(context.Background(), cp, ""); err != nil {
+				t.Errorf("read returned error: %v", err)
+			}
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	for _, ev := range net.Events()[before:] {
+		if ev.Node == 2 {
+			t.Fatalf("suspected node 2 was contacted: %+v", ev)
+		}
+	}
+
+	// After SuspicionTimeout, the recovered node is tried again by the client.
+	net.Restart(2)
+	timeout := conf.SuspicionTimeout
+	conf.SuspicionTimeout = 0
+	defer func() { conf.SuspicionTimeout = timeout }()
+	before = len(net.Events())
+	net.Go(func() {
+		c, err := smc.New(blp, 1, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		if _, err := c.Write(context.Background(), cp, "", []byte("2")); err != nil {
+			t.Errorf("write returned error: %v", err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	probed := false
+	for _, ev := range net.Events()[before:] {
+		probed = probed || (ev.Node == 2 && !ev.Crashed)
+	}
+	if s := fdp.Suspected(); !probed || len(s) != 0 {
+		t.Errorf("recovered node 2 was not tried again, got suspected %v", s)
+	}
+
+	// The node is trusted again, after it replied.
+	net.Go(func() {
+		cnf := cp.FullC(&bp.Blueprint{Nodes: []*bp.Node{{Id: 2}}})
+		if _, err := cnf.Read(context.Background(), &pb.Read{}); err != nil {
+			t.Errorf("read returned error: %v", err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if s := fdp.Suspected(); len(s) != 0 {
+		t.Errorf("got suspected %v after restart", s)
+	}
+}
+
+// TestCompareAndSwap increments a counter stored in a register with compare-and-swap,
+// by three concurrent clients, while one of them reconfigures. No increment may get lost or be duplicated.
+func TestCompareAndSwap(t *testing.T) {
+	net, blp := newNet(18, Options{MaxDelay: 4}, 5)
+	blp.Nodes = blp.Nodes[:3]
+	// Actors must not block. Competing clients are delayed by the network instead,
+	// which may take more attempts than a random backoff.
+	backoff, attempts := smc.Backoff, smc.SwapAttempts
+	smc.Backoff = func(context.Context, int) error { return nil }
+	smc.SwapAttempts = 1000
+	defer func() { smc.Backoff, smc.SwapAttempts = backoff, attempts }()
+
+	// Values are n:id, such that each is unique to its writer.
+	parse := func(val []byte) (n int) {
+		fmt.Sscanf(string(val), "%d:", &n)
+		return n
+	}
+	var swapped []int
+	unknown := 0 // Increments with unknown outcome.
+	var target *bp.Blueprint
+	for id := 1; id <= 3; id++ {
+		id := id
+		net.Go(func() {
+			cp := provider(net, id)
+			c, err := smc.New(blp, uint32(id), cp)
+			if err != nil {
+				t.Errorf("could not create client: %v", err)
+				return
+			}
+			cur, _, err := c.Read(context.Background(), cp, "cnt")
+			if err != nil {
+				t.Errorf("read returned error: %v", err)
+				return
+			}
+			for i := 0; i < 3; {
+				n := parse(cur) + 1
+				val, ok, _, err := c.CompareAndSwap(context.Background(), cp, "cnt", cur, []byte(fmt.Sprintf("%d:%d", n, id)))
+				switch {
+				case ok:
+					swapped = append(swapped, n)
+					i++
+				case err == smc.ErrUnknownOutcome:
+					unknown++
+				case err != nil && err != smc.ErrContention:
+					t.Errorf("compare-and-swap returned error: %v", err)
+					return
+				}
+				if err != smc.ErrContention {
+					cur = val
+				}
+				if id == 1 && i == 1 && target == nil {
+					target = c.GetCur()
+					target.Add(4)
+					target.Add(5)
+					target.Rem(1)
+					if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+						t.Errorf("reconf returned error: %v", err)
+					}
+				}
+			}
+		})
+	}
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	sort.Ints(swapped)
+	for i := 1; i < len(swapped); i++ {
+		if swapped[i] == swapped[i-1] {
+			t.Errorf("%d was swapped in twice: %v", swapped[i], swapped)
+		}
+	}
+	var final int
+	net.Go(func() {
+		cp := provider(net, 6)
+		c, err := smc.New(target, 6, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		val, _, err := c.Read(context.Background(), cp, "cnt")
+		if err != nil {
+			t.Errorf("read returned error: %v", err)
+		}
+		final = parse(val)
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	// Each value was written by one swap, or one with unknown outcome.
+	if final < len(swapped) || final > len(swapped)+unknown || swapped[len(swapped)-1] > final {
+		t.Errorf("counter is %d, after %v were swapped in, and %d swaps with unknown outcome", final, swapped, unknown)
+	}
+}
+
+// TestDoreconfCondWrite writes a register with a doreconf client, after a conditional write prepared
+// a larger ballot, but before it wrote. The doreconf write must take the promise into account,
+// such that the conditional write does not overwrite it based on the state it read before.
+func TestDoreconfCondWrite(t *testing.T) {
+	net, blp := newNet(19, Options{MaxDelay: 2}, 3)
+	var val []byte
+	var swapped bool
+	net.Go(func() {
+		cp := provider(net, 1)
+		d, err := doreconf.NewSM(blp, 1, cp)
+		if err != nil {
+			t.Errorf("could not create doreconf client: %v", err)
+			return
+		}
+		c, err := smc.New(blp, 2, cp)
+		if err != nil {
+			t.Errorf("could not create client: %v", err)
+			return
+		}
+		written := false
+		_, swapped, _, err = c.CondWrite(context.Background(), cp, "k", []byte("c"), func(cur *pb.State) bool {
+			if !written {
+				written = true
+				if _, err := d.Write(context.Background(), cp, "k", []byte("d")); err != nil {
+					t.Errorf("doreconf write returned error: %v", err)
+				}
+			}
+			return cur == nil || len(cur.Value) == 0
+		})
+		if err != nil && err != smc.ErrUnknownOutcome {
+			t.Errorf("conditional write returned error: %v", err)
+		}
+		if val, _, err = c.Read(context.Background(), cp, "k"); err != nil {
+			t.Errorf("read returned error: %v", err)
+		}
+	})
+	if err := net.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if swapped || string(val) != "d" {
+		t.Errorf("conditional write swapped: %t, read %q, expected the doreconf write d to remain", swapped, val)
+	}
+}
+
+// TestWatch watches a register, while it is written, and moved to disjoint servers.
+// The servers removed by the reconfiguration never learn the new current configuration,
+// thus the watcher has to follow the next configuration written to them.
+func TestWatch(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		net, blp := newNet(seed, Options{MaxDelay: 3}, 6)
+		blp.Nodes = blp.Nodes[:3]
+		target := blp.Copy()
+		for id := uint32(1); id <= 3; id++ {
+			target.Rem(id)
+			target.Add(id + 3)
+		}
+
+		net.Go(func() {
+			cp := provider(net, 1)
+			c, err := smc.New(blp, 1, cp)
+			if err != nil {
+				t.Errorf("could not create client: %v", err)
+				return
+			}
+			for i := 1; i <= 6; i++ {
+				if _, err := c.Write(context.Background(), cp, "k", []byte(fmt.Sprintf("v%d", i))); err != nil {
+					t.Errorf("write returned error: %v", err)
+				}
+				if i == 3 {
+					if _, err := c.Reconf(context.Background(), cp, target); err != nil {
+						t.Errorf("reconf returned error: %v", err)
+					}
+				}
+			}
+		})
+
+		var vals []string
+		var cur *bp.Blueprint
+		net.Go(func() {
+			cp := provider(net, 2)
+			c, err := smc.New(blp, 2, cp)
+			if err != nil {
+				t.Errorf("could not create client: %v", err)
+				return
+			}
+			w, err := c.Watch(context.Background(), cp, net, "k")
+			if err != nil {
+				t.Errorf("watch returned error: %v", err)
+				return
+			}
+			defer w.Close()
+			var last *pb.State
+			for last == nil || string(last.Value) != "v6" {
+				st, blp, err := w.Next()
+				if err != nil {
+					t.Errorf("next returned error: %v", err)
+					return
+				}
+				if blp != nil {
+					cur = blp
+					continue
+				}
+				if last != nil && last.Compare(st) != 1 {
+					t.Errorf("got state %v after %v", st, last)
+				}
+				last = st
+				vals = append(vals, string(st.Value))
+			}
+		})
+		if err := net.Run(); err != nil {
+			t.Fatalf("seed %d: Run returned error: %v, watched %v", seed, err, vals)
+		}
+		if !cur.LearnedEquals(target) {
+			t.Errorf("seed %d: watcher moved to configuration %v, want %v", seed, cur, target)
+		}
+	}
+}
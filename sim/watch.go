@@ -0,0 +1,102 @@
+package sim
+
+import (
+	"errors"
+
+	"github.com/gogo/protobuf/proto"
+	"golang.org/x/net/context"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/regserver"
+)
+
+// ErrClosed is returned by the Recv method of a closed watch stream.
+var ErrClosed = errors.New("sim: watch stream is closed")
+
+// watchStream is a simulated Watch stream. The first update of each node is the reply to
+// the Watch message, later updates are sent by the node as messages of their own. Like replies,
+// these can be delayed, dropped, or not delivered while the node is crashed. Each of these faults
+// ends the stream of the node.
+type watchStream struct {
+	net     *Network
+	call    *call
+	cancels []func() // Ends the subscriptions at the nodes.
+	closed  bool
+}
+
+// Watch implements conf.Subscriber. The streams end only when closed,
+// since the simulated network does not observe contexts.
+func (n *Network) Watch(ctx context.Context, blps []*bp.Blueprint, req *pb.WatchRequest) (conf.WatchStream, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.inRun {
+		panic("sim: watch stream opened outside of an actor")
+	}
+	s := &watchStream{net: n, call: new(call)}
+	for _, id := range conf.WatchIds(blps) {
+		if _, ok := n.nodes[id]; !ok {
+			s.call.results = append(s.call.results, result{nid: id, err: pb.NodeNotFoundError(id)})
+			continue
+		}
+		n.enqueue(&message{
+			to:     id,
+			method: "Watch",
+			req:    proto.Clone(req),
+			invoke: s.subscribe(id),
+			call:   s.call,
+		})
+	}
+	return s, nil
+}
+
+// subscribe returns the invoke function of the Watch message to node id. It subscribes to the register,
+// and returns the first update. Later updates are enqueued as messages from the node.
+func (s *watchStream) subscribe(id uint32) func(*regserver.RegServer, proto.Message) (proto.Message, error) {
+	return func(rs *regserver.RegServer, req proto.Message) (proto.Message, error) {
+		var first *pb.WatchUpdate
+		cancel := rs.Subscribe(req.(*pb.WatchRequest).Key, func(u *pb.WatchUpdate) {
+			if first == nil {
+				first = u
+				return
+			}
+			// Called during step, while holding the lock of the network.
+			s.net.enqueue(&message{
+				to:     id,
+				method: "WatchUpdate",
+				req:    proto.Clone(u),
+				invoke: func(_ *regserver.RegServer, u proto.Message) (proto.Message, error) { return u, nil },
+				call:   s.call,
+			})
+		})
+		s.cancels = append(s.cancels, cancel)
+		return first, nil
+	}
+}
+
+func (s *watchStream) Recv() (*conf.WatchReply, error) {
+	s.net.mu.Lock()
+	closed := s.closed
+	s.net.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+	r := s.net.wait(s.call)
+	wr := &conf.WatchReply{Node: r.nid, Err: r.err}
+	if r.err == nil {
+		wr.Update = r.reply.(*pb.WatchUpdate)
+	}
+	return wr, nil
+}
+
+func (s *watchStream) Close() {
+	s.net.mu.Lock()
+	defer s.net.mu.Unlock()
+	s.closed = true
+	s.call.done = true
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	s.cancels = nil
+}
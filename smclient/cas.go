@@ -0,0 +1,160 @@
+package smclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/golang/glog"
+
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// SwapAttempts is the number of times CondWrite starts over, when its ballot is rejected by competing operations.
+var SwapAttempts = 10
+
+// Backoff waits before CondWrite starts over after its attempt'th rejected attempt.
+// By default, it waits a random time below attempt milliseconds, such that competing
+// conditional writes do not reject each other forever. It can be replaced, e.g. in a simulated network.
+var Backoff = backoff
+
+// ErrContention is returned by CondWrite, if all its attempts were rejected.
+var ErrContention = errors.New("conditional write: ballot rejected by competing operations")
+
+// ErrUnknownOutcome is returned by CondWrite, if an attempt to write val was rejected by some servers,
+// and another operation changed the register afterwards. The attempt may have taken effect before.
+var ErrUnknownOutcome = errors.New("conditional write: outcome unknown, the value may have been written")
+
+// errRejected is returned by set, if a server rejected the state, since it promised a larger ballot to a conditional write.
+var errRejected = errors.New("write rejected: a larger ballot was promised")
+
+// CompareAndSwap writes val to the register key, if its current value equals old.
+// An empty old matches a register that was never written.
+// It returns the current value, which is val if the swap took place. See CondWrite for ErrUnknownOutcome.
+func (smc *SmClient) CompareAndSwap(ctx context.Context, cp conf.Provider, key string, old, val []byte) (cur []byte, swapped bool, cnt int, err error) {
+	st, swapped, cnt, err := smc.CondWrite(ctx, cp, key, val, func(st *pb.State) bool {
+		return bytes.Equal(value(st), old)
+	})
+	return value(st), swapped, cnt, err
+}
+
+// CondWrite writes val to the register key, if cond returns true for the current state of the register.
+// A register that was never written has an empty value and timestamp 0.
+// CondWrite returns the state of the register after the operation, and whether val was written.
+// With ErrUnknownOutcome, it also returns the current state.
+//
+// Conditional writes run the two phases of Paxos on the register, where the (Timestamp, Writer)
+// of a state is its ballot. The first phase reads the register in all configurations,
+// while the servers promise the ballot. The second phase writes val, or the current value if cond
+// returns false, with the ballot. Servers reject writes with a smaller ballot than they promised,
+// and reconfigurations move the promises to new configurations, together with the states.
+// Thus conditional writes are linearizable, also together with Read, Write and concurrent reconfigurations.
+// Like with other Paxos proposers, the outcome can be unknown: If an attempt was rejected after it stored val
+// at some servers, a competing operation can complete it. CondWrite then completes val itself,
+// if val is still the current value, and returns ErrUnknownOutcome if the register changed afterwards.
+// Values should therefore be unique to the writer, e.g. include its id.
+//
+// A failed conditional write, and a Read competing with one, store the current value with a new timestamp.
+// Conditions should therefore depend on the value, not the timestamp.
+// Reads and writes through Doreconf store their state with SetState, which rejects it like Write,
+// if a larger ballot was promised. They then write with a ballot as well.
+func (smc *SmClient) CondWrite(ctx context.Context, cp conf.Provider, key string, val []byte, cond func(cur *pb.State) bool) (st *pb.State, ok bool, cnt int, err error) {
+	if glog.V(5) {
+		glog.Infoln("starting CondWrite")
+	}
+	op := smc.Begin()
+	defer smc.End(op)
+
+	return op.condWrite(ctx, cp, key, val, cond)
+}
+
+func (smc *SmClient) condWrite(ctx context.Context, cp conf.Provider, key string, val []byte, cond func(cur *pb.State) bool) (st *pb.State, ok bool, cnt int, err error) {
+	var ts int32       // The largest timestamp seen.
+	var last *pb.State // The state written by the last, rejected attempt.
+	var lastOk bool    // Whether last contained val.
+	for i := 0; i < SwapAttempts; i++ {
+		if i > 0 {
+			if err = Backoff(ctx, i); err != nil {
+				return nil, false, cnt, err
+			}
+		}
+
+		ballot := &pb.State{Key: key, Timestamp: smc.timestamp(ts), Writer: smc.Id}
+		cur, prom, c, err := smc.prepare(ctx, cp, key, ballot)
+		cnt += c
+		if err != nil {
+			return nil, false, cnt, err
+		}
+		if prom.Compare(ballot) != 0 || cur.Compare(ballot) != 1 {
+			// Some server promised or stored a larger ballot.
+			glog.V(4).Infof("C%d: ballot %d rejected in CondWrite\n", smc.Id, ballot.Timestamp)
+			ts = maxTimestamp(ts, cur, prom)
+			continue
+		}
+
+		st = &pb.State{Key: key, Timestamp: ballot.Timestamp, Writer: smc.Id}
+		switch {
+		case lastOk && bytes.Equal(value(cur), val):
+			// The last attempt, or another conditional write completing it, stored val at some servers.
+			// It may not be chosen yet, and must be completed, not evaluated again.
+			st.Value, ok = val, true
+		case last != nil && cur.Compare(last) == 0:
+			// The last attempt stored the current value again. It must be completed as well.
+			st.Value, ok = last.Value, false
+		case lastOk:
+			// Another conditional write may have completed the last attempt, before the register changed again.
+			return cur, false, cnt, ErrUnknownOutcome
+		case cond(cur):
+			st.Value, ok = val, true
+		default:
+			st.Value, ok = value(cur), false
+		}
+
+		c, err = smc.set(ctx, cp, st)
+		cnt += c
+		if err == errRejected {
+			glog.V(4).Infof("C%d: write with ballot %d rejected in CondWrite\n", smc.Id, ballot.Timestamp)
+			last, lastOk = st, ok
+			ts = maxTimestamp(ts, cur, prom)
+			continue
+		}
+		if err != nil {
+			return nil, false, cnt, err
+		}
+		return st, ok, cnt, nil
+	}
+	if lastOk {
+		return nil, false, cnt, ErrUnknownOutcome
+	}
+	return nil, false, cnt, ErrContention
+}
+
+// maxTimestamp returns the largest timestamp of ts and the states sts.
+func maxTimestamp(ts int32, sts ...*pb.State) int32 {
+	for _, st := range sts {
+		if st != nil && st.Timestamp > ts {
+			ts = st.Timestamp
+		}
+	}
+	return ts
+}
+
+func backoff(ctx context.Context, attempt int) error {
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(attempt) * int64(time.Millisecond)))):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// value returns the value of st, or nil if st is nil.
+func value(st *pb.State) []byte {
+	if st == nil {
+		return nil
+	}
+	return st.Value
+}
@@ -0,0 +1,56 @@
+package smclient
+
+import (
+	"context"
+	"fmt"
+
+	bp "github.com/relab/smartmerge/blueprints"
+)
+
+// QuorumError is returned, if a quorum call did not succeed,
+// even after retrying with the full configuration,
+// or if the context of the operation was canceled or its deadline exceeded.
+type QuorumError struct {
+	Method string // The quorum call that failed.
+	Size   int    // The number of nodes in the configuration.
+	// Err is the error of the context, if it is done.
+	// Otherwise it is the error returned by the last quorum call.
+	Err error
+}
+
+func (e *QuorumError) Error() string {
+	return fmt.Sprintf("%s: quorum unreachable in configuration of size %d: %v", e.Method, e.Size, e.Err)
+}
+
+// Unwrap returns the underlying error, such that errors.Is(err, context.DeadlineExceeded) can be used.
+func (e *QuorumError) Unwrap() error { return e.Err }
+
+// NewQuorumError returns the error for a failed call to method in the configuration of blp.
+func NewQuorumError(ctx context.Context, method string, blp *bp.Blueprint, err error) error {
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return &QuorumError{Method: method, Size: len(blp.Ids()), Err: err}
+}
+
+// AbortError is returned by Reconf, if a newer configuration was installed
+// instead of the proposed one, and it does not include the proposal.
+type AbortError struct {
+	Prop *bp.Blueprint // The proposed blueprint.
+	Cur  *bp.Blueprint // The installed blueprint.
+}
+
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("reconfiguration aborted: a newer configuration of size %d was installed, that does not include the proposal", len(e.Cur.Ids()))
+}
+
+// MinSizeError is returned, if a reconfiguration was aborted
+// to avoid moving to a configuration with less than Min nodes.
+type MinSizeError struct {
+	Size int // The size of the rejected configuration.
+	Min  int
+}
+
+func (e *MinSizeError) Error() string {
+	return fmt.Sprintf("reconfiguration aborted: configuration of size %d is smaller than the minimum size %d", e.Size, e.Min)
+}
@@ -37,7 +37,7 @@ func (smc *SmClient) HandleOneCur(cur int, newCur *bp.Blueprint) int {
 		return cur
 	}
 	if glog.V(7) {
-		glog.Infof("Found new Cur with length %d, current has length %d\n", newCur.Len(), smc.Blueps[cur].Len())
+		glog.Infof("Found new Cur of size %d, current has size %d\n", newCur.NSize(), smc.Blueps[cur].NSize())
 	}
 	return smc.findorinsert(cur, newCur)
 }
@@ -55,7 +55,7 @@ func (smc *SmClient) HandleNewCur(cur int, newCur *pb.ConfReply) int {
 		return cur
 	}
 	if glog.V(3) {
-		glog.Infof("Found new Cur with length %d, current has length %d\n", newCur.Cur.Len(), smc.Blueps[cur].Len())
+		glog.Infof("Found new Cur of size %d, current has size %d\n", newCur.Cur.NSize(), smc.Blueps[cur].NSize())
 	}
 
 	return smc.findorinsert(cur, newCur.Cur)
@@ -97,7 +97,7 @@ func (smc *SmClient) findorinsert(i int, blp *bp.Blueprint) int {
 }
 
 func (smc *SmClient) insert(i int, blp *bp.Blueprint) {
-	glog.V(3).Infof("Inserting new blueprint with length %d at place %d\n", blp.Len(), i)
+	glog.V(3).Infof("Inserting new blueprint of size %d at place %d\n", blp.NSize(), i)
 
 	smc.Blueps = append(smc.Blueps, blp)
 
@@ -110,14 +110,20 @@ func (smc *SmClient) insert(i int, blp *bp.Blueprint) {
 	}
 }
 
+// setCurAsync calls SetCur without waiting for it to return.
+// It does not use the context of the operation, since it may return before SetCur.
+func (smc *SmClient) setCurAsync(cp conf.Provider, cur *bp.Blueprint) {
+	Go(func() { smc.SetCur(context.Background(), cp, cur) })
+}
+
 // SetCur informs the servers in the configuration, belonging to cur,
 // that this configuration is installed.
-func (smc *SmClient) SetCur(cp conf.Provider, cur *bp.Blueprint) {
+func (smc *SmClient) SetCur(ctx context.Context, cp conf.Provider, cur *bp.Blueprint) error {
 	cnf := cp.WriteC(cur, nil)
 
 	for j := 0; ; j++ {
-		_, err := cnf.SetCur(context.Background(), &pb.NewCur{
-			CurC: uint32(cur.Len()),
+		_, err := cnf.SetCur(ctx, &pb.NewCur{
+			CurC: cur.ID(),
 			Cur:  cur})
 
 		if err != nil && j == 0 {
@@ -126,13 +132,13 @@ func (smc *SmClient) SetCur(cp conf.Provider, cur *bp.Blueprint) {
 			cnf = cp.FullC(cur)
 		}
 
-		if err != nil && j == Retry {
-			glog.Errorf("C%d: error %v from NewCur after %d retries: ", smc.Id, err, Retry)
-			break
+		if err != nil && (j == Retry || ctx.Err() != nil) {
+			glog.Errorf("C%d: error %v from NewCur after %d retries: ", smc.Id, err, j)
+			return NewQuorumError(ctx, "SetCur", cur, err)
 		}
 
 		if err == nil {
-			break
+			return nil
 		}
 	}
 }
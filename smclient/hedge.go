@@ -0,0 +1,127 @@
+package smclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+)
+
+// Hedging makes reads and writes send hedged quorum calls: If the nodes chosen by the configuration
+// provider did not reach a quorum after a delay, the call is also sent to the other nodes of the configuration.
+// The replies of all nodes together form one quorum, such that each node receives a write only once.
+//
+// The delay adapts to the observed latency of the quorum calls, like the retransmission
+// timeout of TCP: it is the smoothed latency plus four times its mean deviation.
+//
+// Hedging uses timers and goroutines, and can therefore not be used on a simulated network.
+type Hedging struct {
+	Initial time.Duration // The delay used before a latency was observed.
+	Min     time.Duration // The delay is never smaller than Min.
+
+	mu     sync.Mutex
+	srtt   time.Duration // Smoothed latency.
+	rttvar time.Duration // Mean deviation of the latency.
+	calls  int
+	hedges int
+	wins   int
+}
+
+// NewHedging returns a hedging policy, that starts with the delay initial.
+func NewHedging(initial time.Duration) *Hedging {
+	return &Hedging{Initial: initial, Min: time.Millisecond}
+}
+
+// Delay returns the current hedge delay.
+func (h *Hedging) Delay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.srtt == 0 {
+		return h.Initial
+	}
+	d := h.srtt + 4*h.rttvar
+	if d < h.Min {
+		d = h.Min
+	}
+	return d
+}
+
+// Stats returns the number of hedged quorum calls, the number of times a hedge was sent,
+// and the number of times a reply of the hedge completed the quorum.
+func (h *Hedging) Stats() (calls, hedges, wins int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls, h.hedges, h.wins
+}
+
+// observe updates the latency estimate with lat.
+func (h *Hedging) observe(lat time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.srtt == 0 {
+		h.srtt, h.rttvar = lat, lat/2
+		return
+	}
+	dev := h.srtt - lat
+	if dev < 0 {
+		dev = -dev
+	}
+	h.rttvar = (3*h.rttvar + dev) / 4
+	h.srtt = (7*h.srtt + lat) / 8
+}
+
+func (h *Hedging) count(hedged, won bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if hedged {
+		h.hedges++
+	}
+	if won {
+		h.wins++
+	}
+}
+
+// hedge performs call on cnf. If the client uses hedging, and the nodes of cnf did not reach a quorum within
+// the hedge delay, the call is also sent to the nodes of the full configuration of blp, that were not contacted yet.
+// Their replies are combined with those of cnf toward one quorum of the full configuration, see pb.WeightedConfiguration.Hedged.
+// It returns the reply, and the number of quorum calls sent, counting the hedge as one.
+// Configurations that can not be hedged, e.g. on a simulated network, are not, see conf.Hedged.
+func (smc *SmClient) hedge(ctx context.Context, cp conf.Provider, blp *bp.Blueprint, cnf conf.Configuration,
+	call func(context.Context, conf.Configuration) (interface{}, error)) (rep interface{}, cnt int, err error) {
+	h := smc.Hedge
+	if h == nil {
+		rep, err = call(ctx, cnf)
+		return rep, 1, err
+	}
+	var sent, won bool
+	hedged := conf.Hedged(cnf, cp.FullC(blp), h.Delay(), func(s, w bool) {
+		sent, won = s, w
+		if s {
+			glog.V(4).Infof("C%d: hedge sent, completed the quorum: %t\n", smc.Id, w)
+		}
+	})
+	if hedged == nil {
+		rep, err = call(ctx, cnf)
+		return rep, 1, err
+	}
+
+	// Nodes, that did not reply before the quorum was reached, are not waited for.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	start := time.Now()
+	rep, err = call(ctx, hedged)
+	if err == nil && !won {
+		// If the hedge completed the quorum, the latency of the nodes contacted first is unknown.
+		h.observe(time.Since(start))
+	}
+	h.count(sent, won)
+	if sent {
+		return rep, 2, err
+	}
+	return rep, 1, err
+}
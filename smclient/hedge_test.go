@@ -0,0 +1,168 @@
+package smclient
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// slowNode is a server, that stores a single register, and replies to reads and writes after delay.
+// Other RPCs are not implemented.
+type slowNode struct {
+	pb.SMandConsRegisterServer
+
+	mu     sync.Mutex
+	delay  time.Duration
+	state  *pb.State
+	writes int
+}
+
+func (n *slowNode) wait(ctx context.Context) error {
+	n.mu.Lock()
+	d := n.delay
+	n.mu.Unlock()
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *slowNode) setDelay(d time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.delay = d
+}
+
+func (n *slowNode) Read(ctx context.Context, rr *pb.Read) (*pb.ReadReply, error) {
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return &pb.ReadReply{State: n.state, Cur: &pb.ConfReply{}}, nil
+}
+
+func (n *slowNode) Write(ctx context.Context, wr *pb.WriteS) (*pb.ConfReply, error) {
+	n.mu.Lock()
+	n.writes++
+	n.mu.Unlock()
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.state.Compare(wr.State) == 1 {
+		n.state = wr.State
+	}
+	return &pb.ConfReply{}, nil
+}
+
+func (n *slowNode) SetCur(ctx context.Context, nc *pb.NewCur) (*pb.NewCurReply, error) {
+	return &pb.NewCurReply{}, nil
+}
+
+func (n *slowNode) Writes() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.writes
+}
+
+func TestHedge(t *testing.T) {
+	for _, p := range []struct {
+		name        string
+		newProvider func(mgr *pb.Manager) conf.Provider
+	}{
+		{"thrifty", func(mgr *pb.Manager) conf.Provider { return conf.NewProvider(mgr, 1) }},
+		{"fd", func(mgr *pb.Manager) conf.Provider { return conf.NewFDProvider(mgr, 1) }},
+	} {
+		t.Run(p.name, func(t *testing.T) { testHedge(t, p.newProvider) })
+	}
+}
+
+func testHedge(t *testing.T, newProvider func(mgr *pb.Manager) conf.Provider) {
+	addrs := make([]string, 3)
+	nodes := make(map[string]*slowNode)
+	for i := range addrs {
+		l, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := grpc.NewServer()
+		defer srv.Stop()
+		addrs[i] = l.Addr().String()
+		nodes[addrs[i]] = &slowNode{}
+		pb.RegisterSMandConsRegisterServer(srv, nodes[addrs[i]])
+		go srv.Serve(l)
+	}
+	mgr, err := pb.NewManager(addrs, pb.WithGrpcDialOptions(grpc.WithInsecure(), grpc.WithBlock()))
+	if err != nil {
+		t.Fatal("could not create manager:", err)
+	}
+	defer mgr.Close()
+	blp := &bp.Blueprint{FaultTolerance: 1}
+	byID := make(map[uint32]*slowNode)
+	for _, id := range mgr.NodeIDs() {
+		blp.Nodes = append(blp.Nodes, &bp.Node{Id: id})
+		n, _ := mgr.Node(id)
+		byID[id] = nodes[n.Address()]
+	}
+
+	cp := newProvider(mgr)
+	c, err := New(blp, 1, cp)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	c.Hedge = NewHedging(100 * time.Millisecond)
+	// A floor well above the latency of local calls, such that fast calls are not hedged on a loaded machine.
+	c.Hedge.Min = 20 * time.Millisecond
+
+	// One of the nodes chosen for reads and writes is slow. Both the read and the write are hedged.
+	chosen := cp.WriteC(blp, nil).(interface{ NodeIDs() []uint32 }).NodeIDs()
+	if len(chosen) != 2 {
+		t.Fatalf("provider chose %v, want two nodes", chosen)
+	}
+	byID[chosen[0]].setDelay(time.Second)
+	start := time.Now()
+	if _, err := c.Write(context.Background(), cp, "", []byte("x")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("hedged write took %v", d)
+	}
+	if calls, hedges, wins := c.Hedge.Stats(); calls != 2 || hedges != 2 || wins != 2 {
+		t.Errorf("got %d calls, %d hedges and %d wins, want 2, 2 and 2", calls, hedges, wins)
+	}
+	// The hedge is only sent to the node not contacted first.
+	for id, n := range byID {
+		if w := n.Writes(); w != 1 {
+			t.Errorf("node %d received %d writes, want 1", id, w)
+		}
+	}
+
+	// Fast calls are not hedged, once the delay adapted.
+	byID[chosen[0]].setDelay(0)
+	for i := 0; i < 20; i++ {
+		c.Write(context.Background(), cp, "", []byte("y"))
+	}
+	if d := c.Hedge.Delay(); d >= c.Hedge.Initial {
+		t.Errorf("delay did not adapt to fast calls: %v", d)
+	}
+	_, h0, _ := c.Hedge.Stats()
+	val, _, err := c.Read(context.Background(), cp, "")
+	if err != nil || string(val) != "y" {
+		t.Errorf("got value %q and error %v, want \"y\" and nil", val, err)
+	}
+	if _, h1, _ := c.Hedge.Stats(); h1 != h0 {
+		t.Errorf("a fast call was hedged")
+	}
+}
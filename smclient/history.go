@@ -0,0 +1,64 @@
+package smclient
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// ErrCompacted is returned by ReadAsOf, if a server discarded versions of the register, that may be the requested one.
+var ErrCompacted = errors.New("read as of timestamp: version discarded from the history")
+
+// ReadAsOf returns the state of the register key as of the timestamp ts, i.e. the latest version with a smaller or equal timestamp.
+// Servers retain regserver.HistorySize earlier versions of each register. If the version as of ts may have been discarded, ErrCompacted is returned.
+// Like RRead, ReadAsOf does not write back the version it returns. A write with a timestamp smaller or equal to ts,
+// that completed before ReadAsOf started, is returned, or a later one.
+func (smc *SmClient) ReadAsOf(ctx context.Context, cp conf.Provider, key string, ts int32) (st *pb.State, cnt int, err error) {
+	if glog.V(5) {
+		glog.Infoln("starting ReadAsOf")
+	}
+	if ts < 1 {
+		// Timestamps of written states start at 1.
+		return &pb.State{Value: make([]byte, 0), Key: key}, 0, nil
+	}
+	op := smc.Begin()
+	defer smc.End(op)
+
+	rep, cnt, err := op.read(ctx, cp, &pb.Read{Keys: &pb.Keys{Key: key}, AsOf: ts})
+	if err != nil {
+		return nil, cnt, err
+	}
+	if rep.Compacted {
+		return nil, cnt, ErrCompacted
+	}
+	return rep.State, cnt, nil
+}
+
+// Versions returns up to n recent versions of the register key, sorted from oldest to newest.
+// The last version is at least as recent as the value a regular read returns.
+// A register that was never written has no versions.
+func (smc *SmClient) Versions(ctx context.Context, cp conf.Provider, key string, n int) (vs []*pb.State, cnt int, err error) {
+	if glog.V(5) {
+		glog.Infoln("starting Versions")
+	}
+	if n < 1 {
+		return nil, 0, nil
+	}
+	op := smc.Begin()
+	defer smc.End(op)
+
+	rep, cnt, err := op.read(ctx, cp, &pb.Read{Keys: &pb.Keys{Key: key}, Versions: uint32(n)})
+	if err != nil {
+		return nil, cnt, err
+	}
+	vs = rep.Versions
+	if len(vs) > n {
+		vs = vs[len(vs)-n:]
+	}
+	return vs, cnt, nil
+}
@@ -0,0 +1,140 @@
+package smclient
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	"github.com/relab/smartmerge/lattice"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// laReply is the reply of a lattice agreement quorum call.
+type laReply struct {
+	State   lattice.Value // The merged state of all acceptors, that did not accept the proposal, or nil.
+	Cur     *pb.ConfReply
+	NodeIDs []uint32
+}
+
+// laCall sends the proposal prop to the configuration cnf.
+type laCall func(ctx context.Context, cnf conf.Configuration, c *pb.Conf, prop lattice.Value) (*laReply, error)
+
+// Agree solves lattice agreement for the instance inst, proposing prop.
+// It returns the learned value, which includes prop and all values learned before.
+// All values learned for the same instance are comparable.
+// The instance states are moved to new configurations by reconfigurations,
+// together with the states of the registers.
+func (smc *SmClient) Agree(ctx context.Context, cp conf.Provider, inst string, prop lattice.Value) (lattice.Value, int, error) {
+	op := smc.Begin()
+	defer smc.End(op)
+	return op.agree(ctx, cp, "LAPropValue", prop, func(ctx context.Context, cnf conf.Configuration, c *pb.Conf, prop lattice.Value) (*laReply, error) {
+		lav, err := lattice.Marshal(prop)
+		if err != nil {
+			return nil, err
+		}
+		r, err := cnf.LAPropValue(ctx, &pb.LAValueProposal{Conf: c, Instance: inst, Prop: lav})
+		if err != nil {
+			return nil, err
+		}
+		st, err := lattice.Unmarshal(r.GetState())
+		if err != nil {
+			return nil, err
+		}
+		return &laReply{State: st, Cur: r.GetCur(), NodeIDs: r.NodeIDs}, nil
+	})
+}
+
+// lagree solves lattice agreement on blueprints, proposing the merge of prop and the current blueprint.
+func (smc *SmClient) lagree(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (dec *bp.Blueprint, cnt int, err error) {
+	v, cnt, err := smc.agree(ctx, cp, "LAProp", lattice.Blueprint{Blueprint: prop.Merge(smc.Blueps[0])}, func(ctx context.Context, cnf conf.Configuration, c *pb.Conf, prop lattice.Value) (*laReply, error) {
+		r, err := cnf.LAProp(ctx, &pb.LAProposal{Conf: c, Prop: prop.(lattice.Blueprint).Blueprint})
+		if err != nil {
+			return nil, err
+		}
+		rep := &laReply{Cur: r.GetCur(), NodeIDs: r.NodeIDs}
+		if la := r.GetLAState(); la != nil {
+			rep.State = lattice.Blueprint{Blueprint: la}
+		}
+		return rep, nil
+	})
+	if err != nil {
+		return nil, cnt, err
+	}
+	return v.(lattice.Blueprint).Blueprint, cnt, nil
+}
+
+// agree proposes prop in all configurations, starting with the current,
+// until it is accepted in the last one, see lattice.Propose.
+// call performs the quorum call method.
+func (smc *SmClient) agree(ctx context.Context, cp conf.Provider, method string, prop lattice.Value, call laCall) (dec lattice.Value, cnt int, err error) {
+	cur := 0
+	var rid []uint32
+	for i := 0; i < len(smc.Blueps); i++ {
+		if i < cur {
+			continue
+		}
+
+		var rep *laReply
+		prop, err = lattice.Propose(prop, func(prop lattice.Value) (lattice.Value, error) {
+			if i < cur {
+				// Moved to a newer configuration, while trying again.
+				rep = nil
+				return nil, nil
+			}
+			cnf := cp.WriteC(smc.Blueps[i], rid)
+			c := &pb.Conf{
+				This: smc.Blueps[i].ID(),
+				Cur:  smc.Blueps[cur].ID(),
+			}
+
+			rep = new(laReply)
+			var err error
+			for j := 0; cnf != nil; j++ {
+				rep, err = call(ctx, cnf, c, prop)
+				cnt++
+
+				if err != nil && j == 0 {
+					glog.Errorf("C%d: error from Optimized%s: %v\n", smc.Id, method, err)
+					// Try again with full configuration.
+					cnf = cp.FullC(smc.Blueps[i])
+				}
+
+				if err != nil && (j == Retry || ctx.Err() != nil) {
+					glog.Errorf("C%d: error %v from %s after %d retries: ", smc.Id, err, method, j)
+					return nil, NewQuorumError(ctx, method, smc.Blueps[i], err)
+				}
+
+				if err == nil {
+					break
+				}
+			}
+
+			if glog.V(4) {
+				glog.Infof("C%d: %s returned.\n", smc.Id, method)
+			}
+
+			cur = smc.HandleNewCur(cur, rep.Cur)
+			if lattice.Compare(rep.State, prop) != 1 {
+				if glog.V(3) {
+					glog.Infof("C%d: %s returned new state, try again.\n", smc.Id, method)
+				}
+				rid = nil
+			}
+			return rep.State, nil
+		})
+		if err != nil {
+			return nil, cnt, err
+		}
+
+		if rep != nil && len(smc.Blueps) > i+1 {
+			if c := rep.Cur; c == nil || !c.Abort {
+				rid = bp.Union(rid, rep.NodeIDs)
+			}
+		}
+	}
+
+	smc.SetNewCur(cur)
+	return prop, cnt, nil
+}
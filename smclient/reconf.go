@@ -1,7 +1,7 @@
 package smclient
 
 import (
-	"errors"
+	"fmt"
 
 	"golang.org/x/net/context"
 
@@ -9,42 +9,65 @@ import (
 
 	bp "github.com/relab/smartmerge/blueprints"
 	conf "github.com/relab/smartmerge/confProvider"
+	"github.com/relab/smartmerge/lattice"
 	pb "github.com/relab/smartmerge/proto"
 )
 
-func (smc *SmClient) Reconf(cp conf.Provider, prop *bp.Blueprint) (cnt int, err error) {
+// Reconf reconfigures to a configuration including the proposed blueprint prop.
+// The context ctx is used for all quorum calls of the reconfiguration.
+func (smc *SmClient) Reconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint) (cnt int, err error) {
+	op := smc.Begin()
+	defer smc.End(op)
+
 	//Proposed blueprint is already in place, or outdated.
-	if prop.Compare(smc.Blueps[0]) == 1 {
+	if prop.Compare(op.Blueps[0]) == 1 {
 		glog.V(3).Infof("C%d: Proposal is already in place.", smc.Id)
 		return 0, nil
 	}
 
-	_, cnt, err = smc.Doreconf(cp, prop, 0, nil)
+	_, cnt, err = op.doreconf(ctx, cp, prop, 0, "", nil)
+	if err == nil && prop.Compare(op.Blueps[0]) != 1 {
+		err = &AbortError{Prop: prop, Cur: op.GetCur()}
+	}
 	return
 }
 
 // Regular is: 0 for reconfiguration 1 for regular read, 2 for atomic read/write
-func (smc *SmClient) Doreconf(cp conf.Provider, prop *bp.Blueprint, regular int, val []byte) (rst *pb.State, cnt int, err error) {
+// Reads and writes access the register key. The states of all registers are moved to new configurations.
+func (smc *SmClient) Doreconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint, regular int, key string, val []byte) (rst *pb.State, cnt int, err error) {
+	op := smc.Begin()
+	defer smc.End(op)
+	return op.doreconf(ctx, cp, prop, regular, key, val)
+}
+
+func (smc *SmClient) doreconf(ctx context.Context, cp conf.Provider, prop *bp.Blueprint, regular int, key string, val []byte) (rst *pb.State, cnt int, err error) {
 	if glog.V(6) {
 		glog.Infof("C%d: Starting reconf\n", smc.Id)
 	}
 
 	if prop.Compare(smc.Blueps[0]) != 1 {
 		// A new blueprint was proposed. Need to solve Lattice Agreement:
-		prop, cnt, err = smc.lagree(cp, prop)
+		prop, cnt, err = smc.lagree(ctx, cp, prop)
 		if err != nil {
-			return nil, 0, err
+			return nil, cnt, err
 		}
 		if len(prop.Ids()) < MinSize {
 			glog.Errorf("Aborting Reconfiguration to avoid unacceptable configuration.")
-			return nil, cnt, errors.New("Abort before moving to unacceptable configuration.")
+			return nil, cnt, &MinSizeError{Size: len(prop.Ids()), Min: MinSize}
 		}
 	}
 
 	cur := 0
 	las := new(bp.Blueprint)
-	var wid []uint32 // Did already write to these processes.
-	var rid []uint32 // Did already read from these processes.
+	var sts []*pb.State       // States of all registers.
+	var lavs []*pb.LAInstance // States of all lattice agreement instances.
+	var prs []*pb.State       // Ballots promised to conditional writes, for all registers.
+	var hs []*pb.State        // Earlier versions of all registers.
+	var cps []*pb.State       // Largest discarded versions of all registers.
+	wval := val               // The value written, val is reset once it is written.
+	var rejected bool         // Whether a server rejected the written state, see CondWrite.
+	var wid []uint32          // Did already write to these processes.
+	var rid []uint32          // Did already read from these processes.
 
 forconfiguration:
 	for i := 0; i < len(smc.Blueps); i++ {
@@ -58,11 +81,11 @@ forconfiguration:
 				// If read or write operation: Need to read before writing.
 				var st *pb.State
 				var c int
-				st, cur, c, err = smc.Doread(cp, cur, i, rid)
+				st, cur, c, err = smc.Doread(ctx, cp, cur, i, rid, key)
+				cnt += c
 				if err != nil {
-					return nil, 0, err
+					return nil, cnt, err
 				}
-				cnt += c
 				if rst.Compare(st) == 1 {
 					rst = st
 				}
@@ -86,8 +109,8 @@ forconfiguration:
 			writeN := new(pb.WriteNextReply)
 
 			for j := 0; cnf != nil; j++ {
-				writeN, err = cnf.WriteNext(context.Background(), &pb.WriteN{
-					CurC: uint32(smc.Blueps[i].Len()),
+				writeN, err = cnf.WriteNext(ctx, &pb.WriteN{
+					CurC: smc.Blueps[i].ID(),
 					Next: prop,
 				})
 				cnt++
@@ -98,9 +121,9 @@ forconfiguration:
 					cnf = cp.FullC(smc.Blueps[i])
 				}
 
-				if err != nil && j == Retry {
-					glog.Errorf("C%d: error %v from WriteN after %d retries: ", smc.Id, err, Retry)
-					return nil, 0, err
+				if err != nil && (j == Retry || ctx.Err() != nil) {
+					glog.Errorf("C%d: error %v from WriteN after %d retries: ", smc.Id, err, j)
+					return nil, cnt, NewQuorumError(ctx, "WriteNext", smc.Blueps[i], err)
 				}
 
 				if err == nil {
@@ -109,15 +132,24 @@ forconfiguration:
 			}
 
 			if i > 0 && glog.V(3) {
-				glog.Infof("C%d: WriteN in Configuration with length %d\n ", smc.Id, smc.Blueps[i].Len())
+				glog.Infof("C%d: WriteN in Configuration of size %d\n ", smc.Id, smc.Blueps[i].NSize())
 			} else if glog.V(6) {
 				glog.Infoln("WriteN returned.")
 			}
 
 			cur = smc.HandleNewCur(cur, writeN.GetCur())
 			las = las.Merge(writeN.GetLAState())
-			if rst.Compare(writeN.GetState()) == 1 {
-				rst = writeN.GetState()
+			sts = pb.MergeStates(sts, writeN.GetStates())
+			prs = pb.MergeStates(prs, writeN.GetPromises())
+			hs = pb.MergeVersions(hs, writeN.GetHistory())
+			cps = pb.MergeStates(cps, writeN.GetCompacted())
+			if lavs, err = lattice.MergeInstances(lavs, writeN.GetLAValues()); err != nil {
+				// Installing the new configuration without these states could lose agreed values.
+				glog.Errorf("C%d: could not merge lattice agreement states: %v\n", smc.Id, err)
+				return nil, cnt, fmt.Errorf("reconfiguration aborted: %v", err)
+			}
+			if st := pb.FindState(writeN.GetStates(), key); rst.Compare(st) == 1 {
+				rst = st
 			}
 
 			if c := writeN.GetCur(); c == nil || !c.Abort {
@@ -126,17 +158,27 @@ forconfiguration:
 			}
 		} else if i > cur || regular > 1 {
 
-			rst = smc.WriteValue(&val, rst)
+			rst = smc.WriteValue(key, &val, rst)
+			states, write := sts, rst
+			if regular <= 1 && rst != nil {
+				// Only moved to the new configuration, not written.
+				states, write = pb.MergeStates(sts, []*pb.State{rst}), nil
+			}
 
 			cnf := cp.WriteC(smc.Blueps[i], nil)
 
 			var setS *pb.SetStateReply
 
 			for j := 0; ; j++ {
-				setS, err = cnf.SetState(context.Background(), &pb.NewState{
-					CurC:    uint32(smc.Blueps[i].Len()),
-					State:   rst,
-					LAState: las})
+				setS, err = cnf.SetState(ctx, &pb.NewState{
+					CurC:      smc.Blueps[i].ID(),
+					States:    states,
+					LAState:   las,
+					LAValues:  lavs,
+					Promises:  prs,
+					History:   hs,
+					Compacted: cps,
+					Write:     write})
 				cnt++
 
 				if err != nil && j == 0 {
@@ -145,9 +187,9 @@ forconfiguration:
 					cnf = cp.FullC(smc.Blueps[i])
 				}
 
-				if err != nil && j == Retry {
-					glog.Errorf("C%d: error %v from SetState after %d retries: ", smc.Id, err, Retry)
-					return nil, 0, err
+				if err != nil && (j == Retry || ctx.Err() != nil) {
+					glog.Errorf("C%d: error %v from SetState after %d retries: ", smc.Id, err, j)
+					return nil, cnt, NewQuorumError(ctx, "SetState", smc.Blueps[i], err)
 				}
 
 				if err == nil {
@@ -156,89 +198,36 @@ forconfiguration:
 			}
 
 			if i > 0 && glog.V(3) {
-				glog.Infof("C%d: Set State in Configuration with length %d\n ", smc.Id, smc.Blueps[i].Len())
+				glog.Infof("C%d: Set State in Configuration of size %d\n ", smc.Id, smc.Blueps[i].NSize())
 			} else if glog.V(6) {
 				glog.Infoln("Set state returned.")
 			}
 
 			cur = smc.HandleOneCur(i, setS.GetCur())
+			rejected = rejected || setS.Rejected
 			smc.HandleNext(i, setS.GetNext())
 		}
 	}
 
 	smc.SetNewCur(cur)
 	if cnt > 2 {
-		smc.SetCur(cp, smc.Blueps[0])
+		smc.SetCur(ctx, cp, smc.Blueps[0])
 		cnt++
 	}
-	return rst, cnt, nil
-}
-
-func (smc *SmClient) lagree(cp conf.Provider, prop *bp.Blueprint) (dec *bp.Blueprint, cnt int, err error) {
-	cur := 0
-	var rid []uint32
-	prop = prop.Merge(smc.Blueps[0])
-	for i := 0; i < len(smc.Blueps); i++ {
-		if i < cur {
-			continue
-		}
-
-		cnf := cp.WriteC(smc.Blueps[i], rid)
-
-		laProp := new(pb.LAPropReply)
-
-		for j := 0; cnf != nil; j++ {
-			laProp, err = cnf.LAProp(context.Background(), &pb.LAProposal{
-				Conf: &pb.Conf{
-					This: uint32(smc.Blueps[i].Len()),
-					Cur:  uint32(smc.Blueps[cur].Len())},
-				Prop: prop})
-			cnt++
-
-			if err != nil && j == 0 {
-				glog.Errorf("C%d: error from OptimizedLAProp: %v\n", smc.Id, err)
-				// Try again with full configuration.
-				cnf = cp.FullC(smc.Blueps[i])
-			}
-
-			if err != nil && j == Retry {
-				glog.Errorf("C%d: error %v from LAProp after %d retries: ", smc.Id, err, Retry)
-				return nil, 0, err
-			}
-
-			if err == nil {
-				break
-			}
-		}
-
-		if glog.V(4) {
-			glog.Infof("C%d: LAProp returned.\n", smc.Id)
-		}
-
-		cur = smc.HandleNewCur(cur, laProp.GetCur())
-		la := laProp.GetLAState()
-		if la != nil && !prop.LearnedEquals(la) {
-			if glog.V(3) {
-				glog.Infof("C%d: LAProp returned new state, try again.\n", smc.Id)
-			}
-			prop = la
-			i--
-			rid = nil
-			continue
-		}
-
-		if len(smc.Blueps) > i+1 {
-			if c := laProp.GetCur(); c == nil || !c.Abort {
-				rid = bp.Union(rid, laProp.NodeIDs)
-			}
+	if rejected {
+		// A conditional write promised a larger ballot. Write, or write back the value read, with a ballot.
+		var c int
+		rst, _, c, err = smc.condWrite(ctx, cp, key, wval, func(*pb.State) bool { return wval != nil })
+		cnt += c
+		if err != nil {
+			return nil, cnt, err
 		}
 	}
-
-	smc.SetNewCur(cur)
-	return prop, cnt, nil
+	return rst, cnt, nil
 }
 
-func (smc *SmClient) Doread(cp conf.Provider, curin, i int, rid []uint32) (st *pb.State, cur, cnt int, err error) {
+// Doread reads the register key in configuration i.
+func (smc *SmClient) Doread(ctx context.Context, cp conf.Provider, curin, i int, rid []uint32, key string) (st *pb.State, cur, cnt int, err error) {
 	cnf := cp.ReadC(smc.Blueps[i], rid)
 	if cnf == nil {
 		cnt++
@@ -246,9 +235,12 @@ func (smc *SmClient) Doread(cp conf.Provider, curin, i int, rid []uint32) (st *p
 	read := new(pb.ReadReply_)
 
 	for j := 0; cnf != nil; j++ {
-		read, err = cnf.Read(context.Background(), &pb.Conf{
-			This: uint32(smc.Blueps[i].Len()),
-			Cur:  uint32(smc.Blueps[i].Len()),
+		read, err = cnf.Read(ctx, &pb.Read{
+			Conf: &pb.Conf{
+				This: smc.Blueps[i].ID(),
+				Cur:  smc.Blueps[i].ID(),
+			},
+			Keys: &pb.Keys{Key: key},
 		})
 		cnt++
 
@@ -258,9 +250,9 @@ func (smc *SmClient) Doread(cp conf.Provider, curin, i int, rid []uint32) (st *p
 			cnf = cp.FullC(smc.Blueps[i])
 		}
 
-		if err != nil && j == Retry {
-			glog.Errorf("C%d: error %v from ReadS after %d retries: ", smc.Id, err, Retry)
-			return nil, 0, 0, err
+		if err != nil && (j == Retry || ctx.Err() != nil) {
+			glog.Errorf("C%d: error %v from ReadS after %d retries: ", smc.Id, err, j)
+			return nil, curin, cnt, NewQuorumError(ctx, "Read", smc.Blueps[i], err)
 		}
 
 		if err == nil {
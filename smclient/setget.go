@@ -10,7 +10,24 @@ import (
 	pb "github.com/relab/smartmerge/proto"
 )
 
-func (smc *SmClient) get(cp conf.Provider) (rs *pb.State, cnt int) {
+func (smc *SmClient) get(ctx context.Context, cp conf.Provider, key string) (rs *pb.State, cnt int, err error) {
+	rs, _, cnt, err = smc.prepare(ctx, cp, key, nil)
+	return rs, cnt, err
+}
+
+// prepare reads the register key like get. If ballot is not nil, the servers are asked to promise it,
+// and the largest promise is returned in prom, see CondWrite.
+func (smc *SmClient) prepare(ctx context.Context, cp conf.Provider, key string, ballot *pb.State) (rs, prom *pb.State, cnt int, err error) {
+	read, cnt, err := smc.read(ctx, cp, &pb.Read{Keys: &pb.Keys{Key: key}, Ballot: ballot})
+	if err != nil {
+		return nil, nil, cnt, err
+	}
+	return read.State, read.Promise, cnt, nil
+}
+
+// read sends the Read request args to all configurations, starting with the current one, and merges the replies.
+func (smc *SmClient) read(ctx context.Context, cp conf.Provider, args *pb.Read) (rep *pb.ReadReply, cnt int, err error) {
+	rep = new(pb.ReadReply)
 	cur := 0
 
 	// rid is used to store ids of nodes, that have already replied.
@@ -24,7 +41,7 @@ func (smc *SmClient) get(cp conf.Provider) (rs *pb.State, cnt int) {
 		}
 		if i > 0 && i == cur {
 			// Asynchronously notify the servers, that a new configuration was installed.
-			go smc.SetCur(cp, smc.Blueps[cur])
+			smc.setCurAsync(cp, smc.Blueps[cur])
 		}
 		smc.checkrid(i, rid, cp)
 
@@ -34,14 +51,21 @@ func (smc *SmClient) get(cp conf.Provider) (rs *pb.State, cnt int) {
 		}
 
 		read := new(pb.ReadReply_)
-		var err error
+		args.Conf = &pb.Conf{
+			This: smc.Blueps[i].ID(),
+			Cur:  smc.Blueps[cur].ID(),
+		}
 
 		for j := 0; cnf != nil; j++ {
-			read, err = cnf.Read(context.Background(), &pb.Conf{
-				This: uint32(smc.Blueps[i].Hash()),
-				Cur:  uint32(smc.Blueps[cur].Hash()),
+			var rep interface{}
+			var n int
+			rep, n, err = smc.hedge(ctx, cp, smc.Blueps[i], cnf, func(ctx context.Context, cnf conf.Configuration) (interface{}, error) {
+				return cnf.Read(ctx, args)
 			})
-			cnt++
+			cnt += n
+			if err == nil {
+				read = rep.(*pb.ReadReply_)
+			}
 
 			if err != nil && j == 0 {
 				glog.Errorln("error from OptimizedRead: ", err)
@@ -49,9 +73,9 @@ func (smc *SmClient) get(cp conf.Provider) (rs *pb.State, cnt int) {
 				cnf = cp.FullC(smc.Blueps[i])
 			}
 
-			if err != nil && j == Retry {
-				glog.Errorf("error %v from ReadS after %d retries.\n", err, Retry)
-				return nil, 0
+			if err != nil && (j == Retry || ctx.Err() != nil) {
+				glog.Errorf("error %v from ReadS after %d retries.\n", err, j)
+				return nil, cnt, NewQuorumError(ctx, "Read", smc.Blueps[i], err)
 			}
 
 			if err == nil {
@@ -66,9 +90,14 @@ func (smc *SmClient) get(cp conf.Provider) (rs *pb.State, cnt int) {
 		// Update list of blueprints.
 		cur = smc.HandleNewCur(cur, read.GetCur())
 
-		if rs.Compare(read.GetState()) == 1 {
-			rs = read.GetState()
+		if rep.State.Compare(read.GetState()) == 1 {
+			rep.State = read.GetState()
+		}
+		if rep.Promise.Compare(read.GetPromise()) == 1 {
+			rep.Promise = read.GetPromise()
 		}
+		rep.Versions = pb.MergeVersions(rep.Versions, read.GetVersions())
+		rep.Compacted = rep.Compacted || (read.ReadReply != nil && read.Compacted)
 
 		if len(smc.Blueps) > i+1 && (read.GetCur() == nil || !read.Cur.Abort) {
 			rid = bp.Union(rid, read.NodeIDs)
@@ -77,10 +106,10 @@ func (smc *SmClient) get(cp conf.Provider) (rs *pb.State, cnt int) {
 	}
 
 	smc.SetNewCur(cur)
-	return
+	return rep, cnt, nil
 }
 
-func (smc *SmClient) set(cp conf.Provider, rs *pb.State) (cnt int) {
+func (smc *SmClient) set(ctx context.Context, cp conf.Provider, rs *pb.State) (cnt int, err error) {
 	cur := 0
 	var rid []uint32
 	for i := 0; i < len(smc.Blueps); i++ {
@@ -90,7 +119,7 @@ func (smc *SmClient) set(cp conf.Provider, rs *pb.State) (cnt int) {
 		}
 
 		if i > 0 && i == cur {
-			go smc.SetCur(cp, smc.Blueps[cur])
+			smc.setCurAsync(cp, smc.Blueps[cur])
 		}
 		smc.checkrid(i, rid, cp)
 
@@ -100,17 +129,24 @@ func (smc *SmClient) set(cp conf.Provider, rs *pb.State) (cnt int) {
 		}
 
 		write := new(pb.WriteReply)
-		var err error
+		args := &pb.WriteS{
+			State: rs,
+			Conf: &pb.Conf{
+				This: smc.Blueps[i].ID(),
+				Cur:  smc.Blueps[cur].ID(),
+			},
+		}
 
 		for j := 0; cnf != nil; j++ {
-			write, err = cnf.Write(context.Background(), &pb.WriteS{
-				State: rs,
-				Conf: &pb.Conf{
-					This: uint32(smc.Blueps[i].Len()),
-					Cur:  uint32(smc.Blueps[cur].Len()),
-				},
+			var rep interface{}
+			var n int
+			rep, n, err = smc.hedge(ctx, cp, smc.Blueps[i], cnf, func(ctx context.Context, cnf conf.Configuration) (interface{}, error) {
+				return cnf.Write(ctx, args)
 			})
-			cnt++
+			cnt += n
+			if err == nil {
+				write = rep.(*pb.WriteReply)
+			}
 
 			if err != nil && j == 0 {
 				glog.Errorln("error from OptimizedWriteS: ", err)
@@ -118,9 +154,9 @@ func (smc *SmClient) set(cp conf.Provider, rs *pb.State) (cnt int) {
 				cnf = cp.FullC(smc.Blueps[i])
 			}
 
-			if err != nil && j == Retry {
-				glog.Errorf("error %v from WriteS after %d retries. \n", err, Retry)
-				return 0
+			if err != nil && (j == Retry || ctx.Err() != nil) {
+				glog.Errorf("error %v from WriteS after %d retries. \n", err, j)
+				return cnt, NewQuorumError(ctx, "Write", smc.Blueps[i], err)
 			}
 
 			if err == nil {
@@ -132,6 +168,10 @@ func (smc *SmClient) set(cp conf.Provider, rs *pb.State) (cnt int) {
 			glog.Infoln("Write returned, with replies from ", write.NodeIDs)
 		}
 
+		if write.ConfReply != nil && write.Rejected {
+			return cnt, errRejected
+		}
+
 		cur = smc.HandleNewCur(cur, write.ConfReply)
 
 		if len(smc.Blueps) > i+1 && (write.ConfReply == nil || !write.Abort) {
@@ -141,7 +181,7 @@ func (smc *SmClient) set(cp conf.Provider, rs *pb.State) (cnt int) {
 	}
 
 	smc.SetNewCur(cur)
-	return cnt
+	return cnt, nil
 }
 
 // checkrid checks whether one of the nodes that have already replies (stored in rids)
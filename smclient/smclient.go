@@ -10,6 +10,7 @@ package smclient
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"github.com/golang/glog"
 
@@ -21,10 +22,23 @@ import (
 const Retry = 1
 const MinSize = 3
 
+// Go runs f in a new goroutine. The clients use it for calls they do not wait for.
+// It can be replaced, e.g. to run f as an actor of a simulated network.
+var Go = func(f func()) { go f() }
+
 // The smartmerge client. Stores a list of blueprints and the Id.
+//
+// A client can be used by concurrent goroutines. Each operation works on its own copy of the
+// list of blueprints, obtained with Begin, and merges the blueprints it learned back with End.
+// Blueps should therefore not be accessed directly while operations are running, use GetCur instead.
 type SmClient struct {
 	Blueps []*bp.Blueprint
 	Id     uint32
+	Hedge  *Hedging // If not nil, reads and writes send hedged quorum calls.
+
+	mu   sync.Mutex // Protects Blueps and ts, if the client is shared by several operations.
+	ts   int32      // The largest timestamp written by this client.
+	root *SmClient  // The shared client, if this is the copy used by a single operation.
 }
 
 func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*SmClient, error) {
@@ -32,7 +46,7 @@ func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*SmClient, error)
 
 	glog.Infof("New Client with Id: %d\n", id)
 
-	_, err := cnf.SetCur(context.Background(), &pb.NewCur{Cur: initBlp, CurC: uint32(initBlp.Hash())})
+	_, err := cnf.SetCur(context.Background(), &pb.NewCur{Cur: initBlp, CurC: initBlp.ID()})
 	if err != nil {
 		glog.Errorln("initial SetCur returned error: ", err)
 		return nil, errors.New("Initial SetCur failed.")
@@ -43,17 +57,33 @@ func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*SmClient, error)
 	}, nil
 }
 
-//Atomic read
-func (smc *SmClient) Read(cp conf.Provider) (val []byte, cnt int) {
+//Atomic read of the register key.
+// The context ctx is used for all quorum calls of the operation.
+func (smc *SmClient) Read(ctx context.Context, cp conf.Provider, key string) (val []byte, cnt int, err error) {
 	if glog.V(5) {
 		glog.Infoln("starting Read")
 	}
-	rs, cnt := smc.get(cp)
+	op := smc.Begin()
+	defer smc.End(op)
+
+	rs, cnt, err := op.get(ctx, cp, key)
+	if err != nil {
+		return nil, cnt, err
+	}
 	if rs == nil {
-		return nil, cnt
+		return nil, cnt, nil
 	}
 
-	mcnt := smc.set(cp, rs)
+	mcnt, err := op.set(ctx, cp, rs)
+	if err == errRejected {
+		// A conditional write promised a larger ballot. Write back the value with a ballot, see CondWrite.
+		var c int
+		rs, _, c, err = op.condWrite(ctx, cp, key, nil, func(*pb.State) bool { return false })
+		mcnt += c
+	}
+	if err != nil {
+		return nil, cnt + mcnt, err
+	}
 
 	if glog.V(3) {
 		if cnt > 1 {
@@ -64,39 +94,53 @@ func (smc *SmClient) Read(cp conf.Provider) (val []byte, cnt int) {
 		}
 	}
 	if cnt > mcnt {
-		return rs.Value, cnt
+		return rs.Value, cnt, nil
 	}
-	return rs.Value, mcnt
+	return rs.Value, mcnt, nil
 }
 
-//Regular read
-func (smc *SmClient) RRead(cp conf.Provider) (val []byte, cnt int) {
+//Regular read of the register key.
+func (smc *SmClient) RRead(ctx context.Context, cp conf.Provider, key string) (val []byte, cnt int, err error) {
 	if glog.V(5) {
 		glog.Infoln("starting regular Read")
 	}
-	rs, cnt := smc.get(cp)
-	if rs == nil {
-		return nil, cnt
+	op := smc.Begin()
+	defer smc.End(op)
+
+	rs, cnt, err := op.get(ctx, cp, key)
+	if err != nil || rs == nil {
+		return nil, cnt, err
 	}
 	if glog.V(3) {
 		if cnt > 1 {
 			glog.Infof("get used %d accesses\n", cnt)
 		}
 	}
-	return rs.Value, cnt
+	return rs.Value, cnt, nil
 }
 
-func (smc *SmClient) Write(cp conf.Provider, val []byte) int {
+// Write writes val to the register key.
+func (smc *SmClient) Write(ctx context.Context, cp conf.Provider, key string, val []byte) (cnt int, err error) {
 	if glog.V(5) {
 		glog.Infoln("starting Write")
 	}
-	rs, cnt := smc.get(cp)
-	if rs == nil && cnt == 0 {
-		return 0
-	}
-	rs = smc.WriteValue(&val, rs)
+	op := smc.Begin()
+	defer smc.End(op)
 
-	mcnt := smc.set(cp, rs)
+	rs, cnt, err := op.get(ctx, cp, key)
+	if err != nil {
+		return cnt, err
+	}
+	v := val
+	rs = op.WriteValue(key, &v, rs)
+
+	mcnt, err := op.set(ctx, cp, rs)
+	if err == errRejected {
+		// A conditional write promised a larger ballot. Write with a ballot, see CondWrite.
+		var c int
+		_, _, c, err = op.condWrite(ctx, cp, key, val, func(*pb.State) bool { return val != nil })
+		mcnt += c
+	}
 	if glog.V(3) {
 		if cnt > 1 {
 			glog.Infof("get used %d accesses\n", cnt)
@@ -105,24 +149,77 @@ func (smc *SmClient) Write(cp conf.Provider, val []byte) int {
 			glog.Infof("set used %d accesses\n", mcnt)
 		}
 	}
-	return cnt + mcnt
+	return cnt + mcnt, err
 }
 
-// Given a state returned from a regular read or Get, and a value to be written,
+// Given a state of register key returned from a regular read or Get, and a value to be written,
 // getWriteValue finds the correct state to write.
 // The value is passed by pointer, and set to nil, to avoid reseting the write value.
-func (smc *SmClient) WriteValue(val *[]byte, st *pb.State) *pb.State {
+func (smc *SmClient) WriteValue(key string, val *[]byte, st *pb.State) *pb.State {
 	if val == nil || *val == nil {
 		return st
 	}
-	if st == nil {
-		return &pb.State{Value: *val, Timestamp: 1, Writer: smc.Id}
+	var ts int32
+	if st != nil {
+		ts = st.Timestamp
 	}
-	st = &pb.State{Value: *val, Timestamp: st.Timestamp + 1, Writer: smc.Id}
+	st = &pb.State{Value: *val, Timestamp: smc.timestamp(ts), Writer: smc.Id, Key: key}
 	*val = nil
 	return st
 }
 
+// timestamp returns a timestamp larger than ts, and larger than all timestamps used by the client before.
+// Thus concurrent writes of the same client never use the same timestamp.
+func (smc *SmClient) timestamp(ts int32) int32 {
+	c := smc.shared()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ts > ts {
+		ts = c.ts
+	}
+	c.ts = ts + 1
+	return c.ts
+}
+
+// GetCur returns a copy of the current blueprint.
 func (smc *SmClient) GetCur() *bp.Blueprint {
+	smc.mu.Lock()
+	defer smc.mu.Unlock()
 	return smc.Blueps[0].Copy()
 }
+
+func (smc *SmClient) shared() *SmClient {
+	if smc.root != nil {
+		return smc.root
+	}
+	return smc
+}
+
+// Begin returns a client for a single operation, holding a copy of the list of blueprints.
+// The operation can update its list without synchronization, and must call End when it is done.
+// If smc is already used by a single operation, Begin returns smc.
+func (smc *SmClient) Begin() *SmClient {
+	if smc.root != nil {
+		return smc
+	}
+	smc.mu.Lock()
+	defer smc.mu.Unlock()
+	return &SmClient{
+		Blueps: append([]*bp.Blueprint(nil), smc.Blueps...),
+		Id:     smc.Id,
+		Hedge:  smc.Hedge,
+		root:   smc,
+	}
+}
+
+// End merges the blueprints learned by the operation op, started with Begin, into the list of smc.
+// Blueprints older than the current blueprint of op are removed.
+func (smc *SmClient) End(op *SmClient) {
+	if op == smc {
+		return
+	}
+	smc.mu.Lock()
+	defer smc.mu.Unlock()
+	smc.HandleNext(0, op.Blueps)
+	smc.SetNewCur(smc.findorinsert(0, op.Blueps[0]))
+}
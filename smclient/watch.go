@@ -0,0 +1,159 @@
+package smclient
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// Watcher follows the changes of a register, see SmClient.Watch.
+// A Watcher must not be used by concurrent goroutines.
+type Watcher struct {
+	smc *SmClient // The client, whose list of blueprints is kept up to date.
+	ctx context.Context
+	cp  conf.Provider
+	sub conf.Subscriber
+	key string
+
+	stream conf.WatchStream
+	blps   []*bp.Blueprint // The configurations watched, starting with the current one.
+	ended  map[uint32]bool // The nodes, whose stream ended.
+	st     *pb.State       // The largest state read or received.
+	last   *pb.State       // The state last returned by Next.
+	moved  bool            // Whether the current configuration changed, since Next returned it.
+}
+
+// Watch starts watching the register key, without polling. The Watcher reads the register, and subscribes
+// to updates from the servers of all configurations the read traversed, using sub. sub must reach the same
+// servers as cp. The streams from a read quorum of the current configuration are merged. When the
+// configuration changes, or fewer streams remain, the Watcher reads the register again, and resubscribes.
+// The Watcher must be closed, when it is no longer used.
+func (smc *SmClient) Watch(ctx context.Context, cp conf.Provider, sub conf.Subscriber, key string) (*Watcher, error) {
+	if glog.V(5) {
+		glog.Infoln("starting Watch")
+	}
+	w := &Watcher{smc: smc, ctx: ctx, cp: cp, sub: sub, key: key}
+	if err := w.subscribe(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Next blocks until the register advances, or the current configuration changes. It returns the new state
+// of the register, or the new current configuration and no state. The first call returns the current state.
+// States are returned in increasing order, but intermediate states can be skipped, if the register
+// advances faster than the updates arrive. Like RRead, Next does not write back the state it returns.
+// A write, that completed before Next was called, is returned, or a later one.
+func (w *Watcher) Next() (st *pb.State, cur *bp.Blueprint, err error) {
+	for {
+		if w.moved {
+			w.moved = false
+			return nil, w.blps[0], nil
+		}
+		if w.st != nil && w.last.Compare(w.st) == 1 {
+			w.last = w.st
+			return w.st, nil, nil
+		}
+		if w.stream == nil {
+			// The last attempt to resubscribe failed.
+			if err = w.subscribe(); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		r, err := w.stream.Recv()
+		if err != nil {
+			return nil, nil, err
+		}
+		if w.handle(r) {
+			if err = w.subscribe(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+}
+
+// Close ends the streams of the Watcher.
+func (w *Watcher) Close() {
+	if w.stream != nil {
+		w.stream.Close()
+		w.stream = nil
+	}
+}
+
+// subscribe reads the register, and opens new streams to all configurations the read traversed.
+func (w *Watcher) subscribe() error {
+	w.Close()
+	op := w.smc.Begin()
+	defer w.smc.End(op)
+
+	st, _, err := op.get(w.ctx, w.cp, w.key)
+	if err != nil {
+		return err
+	}
+	w.merge(st)
+	blps := append([]*bp.Blueprint(nil), op.Blueps...)
+	if w.blps != nil && !w.blps[0].LearnedEquals(blps[0]) {
+		w.moved = true
+	}
+	w.blps = blps
+	w.ended = make(map[uint32]bool)
+
+	w.stream, err = w.sub.Watch(w.ctx, blps, &pb.WatchRequest{Key: w.key})
+	return err
+}
+
+// handle processes a reply from the streams. It returns true, if the Watcher must resubscribe.
+func (w *Watcher) handle(r *conf.WatchReply) (resubscribe bool) {
+	if w.ended[r.Node] {
+		// A late update from a subscription, that was already given up.
+		return false
+	}
+	if r.Err != nil {
+		glog.V(4).Infof("C%d: watch stream of node %d ended: %v\n", w.smc.Id, r.Node, r.Err)
+		w.ended[r.Node] = true
+		cur := w.blps[0]
+		ws, live := cur.Weights(), 0
+		for _, id := range cur.Ids() {
+			if !w.ended[id] {
+				live += ws[id]
+			}
+		}
+		return live < cur.WeightedReadQuorum()
+	}
+
+	u := r.Update
+	w.merge(u.State)
+	if u.Cur == nil && len(u.Next) == 0 {
+		return false
+	}
+	op := w.smc.Begin()
+	defer w.smc.End(op)
+	op.SetNewCur(op.HandleNewCur(0, &pb.ConfReply{Cur: u.Cur, Next: u.Next}))
+	return !sameBlueprints(w.blps, op.Blueps)
+}
+
+// merge raises the largest state to st, if st is larger.
+func (w *Watcher) merge(st *pb.State) {
+	if st != nil && w.st.Compare(st) == 1 {
+		w.st = st
+	}
+}
+
+// sameBlueprints returns true, if a and b hold the same blueprints.
+func sameBlueprints(a, b []*bp.Blueprint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].LearnedEquals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+/*
+Package ssrclient implements the client side of SpSnStore, the speculating snapshot register.
+
+SpSnStore traverses views like DynaStore, see package dynaclient. But instead of proposing
+the desired view directly as successor, clients solve speculating snapshot in the view they leave.
+Concurrent clients thereby agree on fewer successors, and visit fewer views.
+*/
+package ssrclient
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/golang/glog"
+
+	bp "github.com/relab/smartmerge/blueprints"
+	conf "github.com/relab/smartmerge/confProvider"
+	dyna "github.com/relab/smartmerge/dynaclient"
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// SSRClient is a SpSnStore client.
+// Reads, writes and reconfigurations are performed as in DynaStore.
+type SSRClient struct {
+	*dyna.DynaClient
+}
+
+// New returns a SpSnStore client, starting in the view initBlp.
+func New(initBlp *bp.Blueprint, id uint32, cp conf.Provider) (*SSRClient, error) {
+	return &SSRClient{dyna.NewWithNext(initBlp, id, spsn)}, nil
+}
+
+// spsn solves speculating snapshot in the view s, proposing desired.
+// On every level, the client proposes its value. If a quorum only knows this value, it is returned.
+// Otherwise the client merges all values proposed at this level, and proposes the result on the next level.
+func spsn(ctx context.Context, cp conf.Provider, s, desired *bp.Blueprint) (next []*bp.Blueprint, cnt int, err error) {
+	prop := desired
+	for level := uint32(1); ; level++ {
+		var rep *pb.SpSnOneShotReply
+		c, err := dyna.Call(ctx, cp, s, "SpSnOneShot", func(cnf conf.Configuration) (err error) {
+			rep, err = cnf.SpSnOneShot(ctx, &pb.SpSnProp{CurC: s.ID(), Level: level, Prop: prop})
+			return err
+		})
+		cnt += c
+		if err != nil {
+			return nil, cnt, err
+		}
+
+		vals := rep.GetVals()
+		if len(vals) == 1 && vals[0].LearnedEquals(prop) {
+			return []*bp.Blueprint{prop}, cnt, nil
+		}
+		for _, v := range vals {
+			prop = prop.Merge(v)
+		}
+		if glog.V(4) {
+			glog.Infof("SpSnOneShot returned %d values, trying level %d.\n", len(vals), level+1)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package tlsconf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Names of the files written by WriteTestCA.
+const (
+	CAFile         = "ca.pem"
+	ServerCertFile = "server.pem"
+	ServerKeyFile  = "server-key.pem"
+	ClientCertFile = "client.pem"
+	ClientKeyFile  = "client-key.pem"
+)
+
+// WriteTestCA creates a self-signed CA, a server certificate valid for hosts, and a client certificate,
+// and writes them to dir. hosts are host names or IP addresses, localhost and 127.0.0.1 are always included.
+// The server certificate is also valid as client certificate, such that servers can call other servers.
+// It is meant for local testing only: the keys are written unencrypted, and the certificates are valid for a year.
+// It returns the files to use for servers and clients.
+func WriteTestCA(dir string, hosts ...string) (server, client *Files, err error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, err
+	}
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	caTmpl := template("smartmerge test CA")
+	caTmpl.IsCA = true
+	caTmpl.BasicConstraintsValid = true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writePEM(filepath.Join(dir, CAFile), "CERTIFICATE", caDER, 0644); err != nil {
+		return nil, nil, err
+	}
+
+	srvTmpl := template("smartmerge server")
+	// Servers also use their certificate as client certificate, when calling other servers, see lserver.
+	srvTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	for _, h := range append([]string{"localhost", "127.0.0.1"}, hosts...) {
+		if ip := net.ParseIP(h); ip != nil {
+			srvTmpl.IPAddresses = append(srvTmpl.IPAddresses, ip)
+		} else {
+			srvTmpl.DNSNames = append(srvTmpl.DNSNames, h)
+		}
+	}
+	if err := writeLeaf(dir, ServerCertFile, ServerKeyFile, srvTmpl, caTmpl, caKey); err != nil {
+		return nil, nil, err
+	}
+
+	cliTmpl := template("smartmerge client")
+	cliTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if err := writeLeaf(dir, ClientCertFile, ClientKeyFile, cliTmpl, caTmpl, caKey); err != nil {
+		return nil, nil, err
+	}
+
+	ca := filepath.Join(dir, CAFile)
+	server = &Files{Cert: filepath.Join(dir, ServerCertFile), Key: filepath.Join(dir, ServerKeyFile), CA: ca}
+	client = &Files{Cert: filepath.Join(dir, ClientCertFile), Key: filepath.Join(dir, ClientKeyFile), CA: ca}
+	return server, client, nil
+}
+
+func template(cn string) *x509.Certificate {
+	serial, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"smartmerge"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+}
+
+// writeLeaf creates a key and a certificate from tmpl signed by the CA, and writes them to dir.
+func writeLeaf(dir, certFile, keyFile string, tmpl, ca *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(filepath.Join(dir, certFile), "CERTIFICATE", der, 0644); err != nil {
+		return err
+	}
+	return writePEM(filepath.Join(dir, keyFile), "EC PRIVATE KEY", kder, 0600)
+}
+
+func writePEM(file, typ string, der []byte, perm os.FileMode) error {
+	b := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	if err := ioutil.WriteFile(file, b, perm); err != nil {
+		return fmt.Errorf("tlsconf: writing %s: %v", file, err)
+	}
+	return nil
+}
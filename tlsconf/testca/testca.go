@@ -0,0 +1,36 @@
+// Command testca creates a CA, a server and a client certificate for local testing.
+//
+//	go run tlsconf/testca/testca.go -dir certs -hosts 10.0.0.1,10.0.0.2
+//
+// Servers are then started with -tlscert certs/server.pem -tlskey certs/server-key.pem -tlsca certs/ca.pem,
+// and clients with -tlscert certs/client.pem -tlskey certs/client-key.pem -tlsca certs/ca.pem.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/relab/smartmerge/tlsconf"
+)
+
+var (
+	dir   = flag.String("dir", "certs", "the directory to write the certificates and keys to.")
+	hosts = flag.String("hosts", "", "comma separated host names or IP addresses of the servers, in addition to localhost.")
+)
+
+func main() {
+	flag.Parse()
+	var hs []string
+	if *hosts != "" {
+		hs = strings.Split(*hosts, ",")
+	}
+	server, client, err := tlsconf.WriteTestCA(*dir, hs...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Creating certificates failed:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("server: -tlscert %s -tlskey %s -tlsca %s\n", server.Cert, server.Key, server.CA)
+	fmt.Printf("client: -tlscert %s -tlskey %s -tlsca %s\n", client.Cert, client.Key, client.CA)
+}
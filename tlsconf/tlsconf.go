@@ -0,0 +1,140 @@
+/*
+Package tlsconf creates the transport security options for the grpc servers and clients.
+
+Certificates, keys and certificate authorities are read from PEM files.
+If a server is given a CA file, it verifies client certificates against it (mutual TLS).
+For local testing, WriteTestCA creates a CA and certificates signed by it.
+*/
+package tlsconf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Files names the PEM files used to set up TLS.
+type Files struct {
+	Cert string // The certificate of this server or client.
+	Key  string // The private key belonging to Cert.
+	CA   string // The certificate of the CA, that signed the certificates of the other side.
+	// RequireClientCert makes servers reject clients without a certificate signed by CA.
+	// Otherwise, client certificates are verified only if presented.
+	RequireClientCert bool
+	// ServerName overrides the name clients expect in server certificates.
+	// By default, it is the host of the address dialed.
+	ServerName string
+}
+
+// Flags defines the command line flags -tlscert, -tlskey, -tlsca, -tlsclientauth and -tlsname,
+// and returns the Files they set. It must be called before flag.Parse.
+func Flags() *Files {
+	f := new(Files)
+	flag.StringVar(&f.Cert, "tlscert", "", "PEM file with the TLS certificate of this process. (Empty and no -tlsca disables TLS.)")
+	flag.StringVar(&f.Key, "tlskey", "", "PEM file with the private key of -tlscert.")
+	flag.StringVar(&f.CA, "tlsca", "", "PEM file with the CA certificate, used to verify the other side.")
+	flag.BoolVar(&f.RequireClientCert, "tlsclientauth", false, "servers only accept clients with a certificate signed by -tlsca.")
+	flag.StringVar(&f.ServerName, "tlsname", "", "the name expected in server certificates. (Default is the host dialed.)")
+	return f
+}
+
+// Enabled reports whether any TLS file was given. Without TLS, connections are not encrypted.
+func (f *Files) Enabled() bool {
+	return f != nil && (f.Cert != "" || f.Key != "" || f.CA != "")
+}
+
+// ServerConfig returns the TLS configuration for a server. Cert and Key are required.
+func (f *Files) ServerConfig() (*tls.Config, error) {
+	if f.Cert == "" || f.Key == "" {
+		return nil, errors.New("tlsconf: a server needs a certificate and a key")
+	}
+	cert, err := tls.LoadX509KeyPair(f.Cert, f.Key)
+	if err != nil {
+		return nil, fmt.Errorf("tlsconf: loading server certificate: %v", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if f.CA != "" {
+		if cfg.ClientCAs, err = loadPool(f.CA); err != nil {
+			return nil, err
+		}
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	if f.RequireClientCert {
+		if f.CA == "" {
+			return nil, errors.New("tlsconf: requiring client certificates needs a CA")
+		}
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// ClientConfig returns the TLS configuration for a client.
+// Without CA, the servers are verified against the system's certificate authorities.
+// Without Cert and Key, the client does not present a certificate.
+func (f *Files) ClientConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: f.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	var err error
+	if f.CA != "" {
+		if cfg.RootCAs, err = loadPool(f.CA); err != nil {
+			return nil, err
+		}
+	}
+	if f.Cert != "" || f.Key != "" {
+		cert, err := tls.LoadX509KeyPair(f.Cert, f.Key)
+		if err != nil {
+			return nil, fmt.Errorf("tlsconf: loading client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// ServerOptions returns the grpc server options for f.
+// If TLS is not enabled, no options are returned.
+func (f *Files) ServerOptions() ([]grpc.ServerOption, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	cfg, err := f.ServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(cfg))}, nil
+}
+
+// DialOption returns the grpc dial option for f.
+// If TLS is not enabled, the connection is insecure.
+func (f *Files) DialOption() (grpc.DialOption, error) {
+	if !f.Enabled() {
+		return grpc.WithInsecure(), nil
+	}
+	cfg, err := f.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
+}
+
+func loadPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("tlsconf: reading CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("tlsconf: no certificate found in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,83 @@
+package tlsconf
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	pb "github.com/relab/smartmerge/proto"
+	"github.com/relab/smartmerge/regserver"
+	"google.golang.org/grpc"
+)
+
+// status dials addr with the options opts, and calls Status.
+func status(addr string, opt grpc.DialOption) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := grpc.Dial(addr, opt)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = pb.NewSMandConsRegisterClient(conn).Status(ctx, &pb.StatusRequest{})
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	server, client, err := WriteTestCA(t.TempDir())
+	if err != nil {
+		t.Fatal("could not create test CA:", err)
+	}
+	server.RequireClientCert = true
+	opts, err := server.ServerOptions()
+	if err != nil {
+		t.Fatal("could not create server options:", err)
+	}
+	srv := regserver.NewServer("localhost:0", regserver.NewRegServer(false), opts...)
+	if err := srv.Start(); err != nil {
+		t.Fatal("could not start server:", err)
+	}
+	defer srv.Stop()
+	addr := srv.Addr().String()
+
+	sec, err := client.DialOption()
+	if err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	if err := status(addr, sec); err != nil {
+		t.Errorf("client with certificate was rejected: %v", err)
+	}
+
+	if err := status(addr, grpc.WithInsecure()); err == nil {
+		t.Error("server accepted a connection without TLS")
+	}
+
+	noCert := &Files{CA: client.CA}
+	if sec, err = noCert.DialOption(); err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	if err := status(addr, sec); err == nil {
+		t.Error("server accepted a client without certificate")
+	}
+
+	// Servers call other servers with their own certificate, like lserver.
+	if sec, err = server.DialOption(); err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	if err := status(addr, sec); err != nil {
+		t.Errorf("server certificate was rejected as client certificate: %v", err)
+	}
+
+	// A CA, that did not sign the server certificate.
+	_, other, err := WriteTestCA(t.TempDir())
+	if err != nil {
+		t.Fatal("could not create second test CA:", err)
+	}
+	if sec, err = other.DialOption(); err != nil {
+		t.Fatal("could not create dial option:", err)
+	}
+	if err := status(addr, sec); err == nil {
+		t.Error("client accepted a server certificate of another CA")
+	}
+}
@@ -15,17 +15,15 @@ func GetProcs(confFile string, prnt bool) (addrs []string, ids []uint32) {
 	fi, err := os.Open(confFile)
 	if err != nil {
 		if prnt {
-			fmt.Println("Could not open file %v.\n", confFile)
+			fmt.Printf("Could not open file %v.\n", confFile)
 		} else {
-			glog.Errorln("Could not open file %v.\n", confFile)
+			glog.Errorf("Could not open file %v.\n", confFile)
 		}
 		return nil, nil
 	}
 
 	defer fi.Close()
 
-	h := fnv.New32a()
-
 	addrs = make([]string, 0)
 	ids = make([]uint32, 0)
 
@@ -38,7 +36,7 @@ func GetProcs(confFile string, prnt bool) (addrs []string, ids []uint32) {
 
 	for scanner.Scan() {
 		s := strings.TrimSpace(scanner.Text())
-		_, err = net.ResolveTCPAddr("tcp", s)
+		id, err := NodeID(s)
 		if err != nil {
 			if prnt {
 				fmt.Println("Could not parse address: ", s)
@@ -48,8 +46,6 @@ func GetProcs(confFile string, prnt bool) (addrs []string, ids []uint32) {
 			return nil, nil
 		}
 
-		h.Write([]byte(s))
-		id := h.Sum32()
 		addrs = append(addrs, s)
 		ids = append(ids, id)
 
@@ -58,7 +54,18 @@ func GetProcs(confFile string, prnt bool) (addrs []string, ids []uint32) {
 		} else {
 			glog.Infof("ID %v Addr %v\n", id, s)
 		}
-		h.Reset()
 	}
 	return
 }
+
+// NodeID returns the id of the node with address addr: the FNV-1a hash of the resolved address.
+// The gorums manager assigns the same id to the node.
+func NodeID(addr string) (uint32, error) {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New32a()
+	h.Write([]byte(tcpAddr.String()))
+	return h.Sum32(), nil
+}
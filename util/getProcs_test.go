@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/relab/smartmerge/proto"
+)
+
+// TestNodeID checks, that NodeID returns the ids the gorums manager assigns, also for addresses,
+// that are not given in their resolved form.
+func TestNodeID(t *testing.T) {
+	addrs := []string{"localhost:11001", "127.0.0.1:11002"}
+	mgr, err := pb.NewManager(addrs, pb.WithGrpcDialOptions(grpc.WithInsecure()))
+	if err != nil {
+		t.Fatal("could not create manager:", err)
+	}
+	defer mgr.Close()
+	for _, addr := range addrs {
+		id, err := NodeID(addr)
+		if err != nil {
+			t.Fatalf("NodeID(%q) returned error: %v", addr, err)
+		}
+		if _, found := mgr.Node(id); !found {
+			t.Errorf("NodeID(%q) = %d, which is not the id of the node in the manager %v", addr, id, mgr.NodeIDs())
+		}
+	}
+}